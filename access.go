@@ -0,0 +1,79 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// WithAccessCounts counts the accesses to each value of the tree, so that
+// HottestKeys can report how skewed the workload is, for example to decide
+// whether a cache in front of the tree pays off. Accesses are the same as
+// for EvictLRU: Insert, Find, Get, FindEntry, FindInfo, FindMany, and
+// EditData.
+//
+// With sample 1, every access is counted. With a larger sample, each access
+// is counted with a probability of 1/sample, and the counts are scaled up
+// accordingly; this makes the counts approximate, but costs less time on a
+// hot path. The counts take memory of one map entry per accessed value.
+// Deleting a value discards its count. WithAccessCounts panics if sample is
+// less than 1.
+func WithAccessCounts(sample int) Option {
+	if sample < 1 {
+		panic(fmt.Sprintf("generictree: WithAccessCounts: sample %d is less than 1", sample))
+	}
+	return func(o *options) { o.accessSample = sample }
+}
+
+// accessCounts counts the accesses per value (see WithAccessCounts).
+type accessCounts[Value cmp.Ordered] struct {
+	counts map[Value]uint64
+	sample uint64
+}
+
+func newAccessCounts[Value cmp.Ordered](sample int) *accessCounts[Value] {
+	return &accessCounts[Value]{counts: map[Value]uint64{}, sample: uint64(sample)}
+}
+
+func (a *accessCounts[Value]) record(value Value) {
+	if a.sample == 1 || rand.Uint64N(a.sample) == 0 {
+		a.counts[value] += a.sample
+	}
+}
+
+// AccessCount is the number of accesses to a value, as returned by
+// HottestKeys.
+type AccessCount[Value any] struct {
+	Value Value
+	Count uint64
+}
+
+// HottestKeys returns the n values with the most accesses since the tree
+// was created or since ResetAccessCounts, most accessed first. Values with
+// the same count are in tree order. HottestKeys returns nil if the tree
+// does not count accesses (see WithAccessCounts).
+func (t *Tree[Value, Data]) HottestKeys(n int) []AccessCount[Value] {
+	if t.cfg == nil || t.cfg.access == nil || n <= 0 {
+		return nil
+	}
+	hot := make([]AccessCount[Value], 0, len(t.cfg.access.counts))
+	for v, c := range t.cfg.access.counts {
+		hot = append(hot, AccessCount[Value]{v, c})
+	}
+	slices.SortFunc(hot, func(a, b AccessCount[Value]) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return t.compare(a.Value, b.Value)
+	})
+	return hot[:min(n, len(hot))]
+}
+
+// ResetAccessCounts sets all access counts to zero, for example to count
+// the accesses of a new period of time.
+func (t *Tree[Value, Data]) ResetAccessCounts() {
+	if t.cfg != nil && t.cfg.access != nil {
+		clear(t.cfg.access.counts)
+	}
+}
@@ -0,0 +1,74 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTree_HottestKeys(t *testing.T) {
+	tree := New[string, int](WithAccessCounts(1))
+	for _, s := range []string{"a", "b", "c", "d"} {
+		tree.Insert(s, 0)
+	}
+	for range 5 {
+		tree.Find("c")
+	}
+	for range 2 {
+		tree.Find("a")
+		tree.Find("x") // absent values are not counted
+	}
+	tree.Insert("b", 1)
+	tree.EditData("b", func(d *int) { *d++ })
+
+	want := []AccessCount[string]{{"c", 6}, {"a", 3}, {"b", 3}}
+	if got := tree.HottestKeys(3); !slices.Equal(got, want) {
+		t.Errorf("HottestKeys(3) = %v, want %v", got, want)
+	}
+	if got := tree.HottestKeys(10); len(got) != 4 {
+		t.Errorf("HottestKeys(10) returned %d counts, want 4", len(got))
+	}
+
+	tree.Delete("c")
+	if got := tree.HottestKeys(1); !slices.Equal(got, []AccessCount[string]{{"a", 3}}) {
+		t.Errorf("HottestKeys(1) after deleting c = %v", got)
+	}
+	tree.ResetAccessCounts()
+	if got := tree.HottestKeys(1); len(got) != 0 {
+		t.Errorf("HottestKeys(1) after reset = %v", got)
+	}
+
+	if got := New[string, int]().HottestKeys(1); got != nil {
+		t.Errorf("HottestKeys without WithAccessCounts = %v, want nil", got)
+	}
+}
+
+func TestTree_HottestKeys_sampled(t *testing.T) {
+	tree := New[int, int](WithAccessCounts(10))
+	for v := range 10 {
+		tree.Insert(v, v)
+	}
+	// 9 gets 50% of 20000 lookups, the others about 5.6% each.
+	for i := range 20000 {
+		if i%2 == 0 {
+			tree.Find(9)
+		} else {
+			tree.Find(i / 2 % 9)
+		}
+	}
+	hot := tree.HottestKeys(1)
+	if len(hot) != 1 || hot[0].Value != 9 || hot[0].Count < 9000 || hot[0].Count > 11000 {
+		t.Errorf("HottestKeys(1) = %v, want about 10000 accesses to 9", hot)
+	}
+	if hot[0].Count%10 != 0 {
+		t.Errorf("sampled count %d is not a multiple of the sample", hot[0].Count)
+	}
+}
+
+func TestWithAccessCounts_panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithAccessCounts(0) did not panic")
+		}
+	}()
+	WithAccessCounts(0)
+}
@@ -0,0 +1,74 @@
+package generictree
+
+import "cmp"
+
+// An Allocator provides the nodes of a tree (see WithAllocator). The tree
+// calls Alloc for each new node and Free for the node of each deleted
+// entry, after which it no longer uses the node. Alloc must return a
+// cleared node; Free may keep the node for a later Alloc.
+//
+// The package provides HeapAllocator, the default; FreeList, which recycles
+// deleted nodes; and Arena, which allocates nodes in blocks. Nodes hold
+// pointers that the garbage collector must see, so an Allocator has to
+// take their memory from the Go heap; memory outside of it, such as a
+// memory-mapped file, is not possible. For trees that outgrow memory, see
+// SpillTree.
+type Allocator[Value cmp.Ordered, Data any] interface {
+	Alloc() *Node[Value, Data]
+	Free(n *Node[Value, Data])
+}
+
+// alloc returns a new node from a, or from the Go heap if a is nil.
+func alloc[Value cmp.Ordered, Data any](a Allocator[Value, Data]) *Node[Value, Data] {
+	if a == nil {
+		return new(Node[Value, Data])
+	}
+	return a.Alloc()
+}
+
+// HeapAllocator allocates each node on its own from the Go heap and leaves
+// deleted nodes to the garbage collector. Trees use it unless an option
+// sets another Allocator.
+type HeapAllocator[Value cmp.Ordered, Data any] struct{}
+
+// Alloc returns a new node.
+func (HeapAllocator[Value, Data]) Alloc() *Node[Value, Data] {
+	return new(Node[Value, Data])
+}
+
+// Free does nothing.
+func (HeapAllocator[Value, Data]) Free(*Node[Value, Data]) {}
+
+// Arena allocates nodes in blocks, which reduces the number of allocations
+// and places nodes that are created together next to each other in memory.
+// It does not reuse the nodes of deleted entries: the garbage collector
+// frees a block once none of its nodes is in use anymore. An Arena suits
+// trees that mostly grow. Like FreeList, it can be shared by several trees
+// of the same type, but it is not safe for concurrent use.
+type Arena[Value cmp.Ordered, Data any] struct {
+	block []Node[Value, Data] // the unused nodes of the current block
+	size  int
+}
+
+// NewArena returns an arena that allocates blocks of size nodes. It panics
+// if size is less than 1.
+func NewArena[Value cmp.Ordered, Data any](size int) *Arena[Value, Data] {
+	if size < 1 {
+		panic("generictree: NewArena: block size must be at least 1")
+	}
+	return &Arena[Value, Data]{size: size}
+}
+
+// Alloc returns the next node of the current block, allocating a new block
+// if the current one is used up.
+func (a *Arena[Value, Data]) Alloc() *Node[Value, Data] {
+	if len(a.block) == 0 {
+		a.block = make([]Node[Value, Data], a.size)
+	}
+	n := &a.block[0]
+	a.block = a.block[1:]
+	return n
+}
+
+// Free does nothing; see Arena.
+func (a *Arena[Value, Data]) Free(*Node[Value, Data]) {}
@@ -0,0 +1,66 @@
+package generictree
+
+import (
+	"testing"
+)
+
+// countingAllocator counts the nodes that a tree allocates and frees.
+type countingAllocator struct {
+	HeapAllocator[int, string]
+	allocs, frees int
+}
+
+func (c *countingAllocator) Alloc() *Node[int, string] {
+	c.allocs++
+	return c.HeapAllocator.Alloc()
+}
+
+func (c *countingAllocator) Free(n *Node[int, string]) {
+	c.frees++
+}
+
+func TestWithAllocator(t *testing.T) {
+	c := &countingAllocator{}
+	tree := New[int, string](WithAllocator[int, string](c))
+	for i := range 10 {
+		tree.Insert(i, "x")
+	}
+	tree.Insert(3, "y") // an update allocates no node
+	tree.Delete(4)
+	if c.allocs != 10 || c.frees != 1 {
+		t.Errorf("after inserts: %d allocs and %d frees, want 10 and 1", c.allocs, c.frees)
+	}
+	if err := tree.UnmarshalJSON([]byte(`[{"value":1,"data":"a"},{"value":2,"data":"b"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if c.allocs != 12 {
+		t.Errorf("after UnmarshalJSON: %d allocs, want 12", c.allocs)
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := NewArena[int, int](64)
+	tree := New[int, int](WithAllocator[int, int](arena))
+	i := 0
+	allocs := testing.AllocsPerRun(64, func() {
+		tree.Insert(i, i)
+		i++
+	})
+	// With treedebug, recording the operations allocates.
+	if allocs > 0.1 && !debugEnabled {
+		t.Errorf("Insert with an arena: %.2f allocations per entry", allocs)
+	}
+	for j := range i {
+		tree.Delete(j)
+	}
+	if err := tree.Validate(); err != nil || tree.Len() != 0 {
+		t.Errorf("after deleting all entries: Len() = %d, Validate() = %v", tree.Len(), err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewArena(0) did not panic")
+		}
+	}()
+	NewArena[int, int](0)
+}
@@ -0,0 +1,38 @@
+package generictree
+
+import "fmt"
+
+// Integer is the set of integer types that AppendAuto can generate keys of.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// AppendAuto inserts data under the key that follows the last key of t,
+// or under zero if t is empty, and returns the key. It takes O(log n) time,
+// which makes t usable as an append-only log whose entries can still be
+// looked up by key.
+//
+// AppendAuto returns an error wrapping ErrKeyOverflow if the last key is
+// the largest value of its type, and an error wrapping ErrKeyOrder if the
+// next key does not come after the last key in the tree's order, as in a
+// tree created with WithDescending. Like InsertStrict, it also returns an
+// error if the insertion would exceed a limit of the tree. In all of these
+// cases, the tree is left unchanged.
+func AppendAuto[Value Integer, Data any](t *Tree[Value, Data], data Data) (Value, error) {
+	last, _, ok := t.Max()
+	if !ok {
+		return 0, t.InsertStrict(0, data)
+	}
+	key := last + 1
+	if key < last {
+		return 0, fmt.Errorf("append after %v: %w", last, ErrKeyOverflow)
+	}
+	if t.compare(key, last) <= 0 {
+		return 0, fmt.Errorf("append after %v: %w", last, ErrKeyOrder)
+	}
+	if err := t.InsertStrict(key, data); err != nil {
+		return 0, err
+	}
+	return key, nil
+}
@@ -0,0 +1,46 @@
+package generictree
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAppendAuto(t *testing.T) {
+	tree := New[int, string]()
+	for i, s := range []string{"a", "b", "c"} {
+		key, err := AppendAuto(tree, s)
+		if err != nil || key != i {
+			t.Fatalf("AppendAuto(%q) = %d, %v, want %d, nil", s, key, err, i)
+		}
+	}
+	tree.Insert(10, "x")
+	if key, err := AppendAuto(tree, "d"); err != nil || key != 11 {
+		t.Errorf("AppendAuto after 10 = %d, %v, want 11, nil", key, err)
+	}
+	if data, ok := tree.Find(1); !ok || data != "b" {
+		t.Errorf("Find(1) = %q, %t, want \"b\", true", data, ok)
+	}
+
+	small := New[uint8, int]()
+	small.Insert(math.MaxUint8, 0)
+	if _, err := AppendAuto(small, 1); !errors.Is(err, ErrKeyOverflow) {
+		t.Errorf("AppendAuto after MaxUint8: got error %v, want ErrKeyOverflow", err)
+	}
+	if small.Len() != 1 {
+		t.Errorf("Len() after overflow = %d, want 1", small.Len())
+	}
+
+	desc := New[int, int](WithDescending())
+	desc.Insert(5, 0)
+	if _, err := AppendAuto(desc, 1); !errors.Is(err, ErrKeyOrder) {
+		t.Errorf("AppendAuto in descending tree: got error %v, want ErrKeyOrder", err)
+	}
+
+	limited := New[int, int](WithMaxEntries(1))
+	AppendAuto(limited, 0)
+	var limit *LimitError
+	if _, err := AppendAuto(limited, 1); !errors.As(err, &limit) {
+		t.Errorf("AppendAuto beyond WithMaxEntries: got error %v, want *LimitError", err)
+	}
+}
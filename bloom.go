@@ -0,0 +1,133 @@
+package generictree
+
+import (
+	"cmp"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// bloom is a counting Bloom filter over the values of a tree. It answers
+// "definitely not in the tree" without a search. Each entry increments k
+// counters, and deleting the entry decrements them again. A counter that
+// reaches its maximum stays there, which can only cause false positives.
+type bloom[Value cmp.Ordered] struct {
+	counts []uint8
+	k      int
+	seed   maphash.Seed
+}
+
+func newBloom[Value cmp.Ordered](expected int, falsePositiveRate float64) *bloom[Value] {
+	expected = max(expected, 1)
+	m := math.Ceil(-float64(expected) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(expected) * math.Ln2)
+	return &bloom[Value]{
+		counts: make([]uint8, max(int(m), 1)),
+		k:      max(int(k), 1),
+		seed:   maphash.MakeSeed(),
+	}
+}
+
+// index returns the position of the i-th counter of a value with hash h,
+// using double hashing.
+func (b *bloom[Value]) index(h uint64, i int) int {
+	return int((h + uint64(i)*(mix(h)|1)) % uint64(len(b.counts)))
+}
+
+func (b *bloom[Value]) add(v Value) {
+	h := b.hash(v)
+	for i := range b.k {
+		if j := b.index(h, i); b.counts[j] < math.MaxUint8 {
+			b.counts[j]++
+		}
+	}
+}
+
+func (b *bloom[Value]) remove(v Value) {
+	h := b.hash(v)
+	for i := range b.k {
+		if j := b.index(h, i); b.counts[j] > 0 && b.counts[j] < math.MaxUint8 {
+			b.counts[j]--
+		}
+	}
+}
+
+// mayContain reports false if v is definitely not in the tree.
+func (b *bloom[Value]) mayContain(v Value) bool {
+	h := b.hash(v)
+	for i := range b.k {
+		if b.counts[b.index(h, i)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hash returns a hash of v. Values that are equal according to cmp.Compare
+// have equal hashes.
+func (b *bloom[Value]) hash(v Value) uint64 {
+	switch x := any(v).(type) {
+	case string:
+		return maphash.String(b.seed, x)
+	case int:
+		return mix(uint64(x))
+	case int8:
+		return mix(uint64(x))
+	case int16:
+		return mix(uint64(x))
+	case int32:
+		return mix(uint64(x))
+	case int64:
+		return mix(uint64(x))
+	case uint:
+		return mix(uint64(x))
+	case uint8:
+		return mix(uint64(x))
+	case uint16:
+		return mix(uint64(x))
+	case uint32:
+		return mix(uint64(x))
+	case uint64:
+		return mix(x)
+	case uintptr:
+		return mix(uint64(x))
+	case float32:
+		return hashFloat(float64(x))
+	case float64:
+		return hashFloat(x)
+	}
+	// A named type; this is slower because reflect.ValueOf allocates.
+	switch r := reflect.ValueOf(v); r.Kind() {
+	case reflect.String:
+		return maphash.String(b.seed, r.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mix(uint64(r.Int()))
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(r.Float())
+	default:
+		return mix(r.Uint())
+	}
+}
+
+// mix is the finalizer of SplitMix64.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+func hashFloat(x float64) uint64 {
+	if x != x {
+		return 0 // cmp.Compare treats all NaNs as equal
+	}
+	return mix(math.Float64bits(x + 0)) // x + 0 turns -0 into +0
+}
+
+// Contains reports whether value is in the tree. With WithBloomFilter,
+// Contains answers most queries for absent values without searching the
+// tree.
+func (t *Tree[Value, Data]) Contains(value Value) bool {
+	return t.findNode(value) != nil
+}
@@ -0,0 +1,93 @@
+package generictree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithBloomFilter(t *testing.T) {
+	tree := New[int, int](WithBloomFilter(1000, 0.01))
+	for i := 0; i < 2000; i += 2 {
+		tree.Insert(i, i)
+	}
+	for i := 0; i < 1000; i += 4 {
+		tree.Delete(i)
+	}
+	for i := range 2000 {
+		want := i%2 == 0 && (i >= 1000 || i%4 != 0)
+		if got := tree.Contains(i); got != want {
+			t.Fatalf("Contains(%d) = %t, want %t", i, got, want)
+		}
+	}
+
+	// Absent values are mostly rejected by the filter.
+	f := tree.cfg.filter
+	positives := 0
+	for i := 2000; i < 12000; i++ {
+		if f.mayContain(i) {
+			positives++
+		}
+	}
+	if rate := float64(positives) / 10000; rate > 0.03 {
+		t.Errorf("false positive rate %.3f, want about 0.01", rate)
+	}
+
+	// Bulk operations keep the filter up to date.
+	if err := tree.UnmarshalJSON([]byte(`[{"value":5000,"data":1}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if !tree.Contains(5000) || tree.Contains(1000) {
+		t.Errorf("after UnmarshalJSON: Contains(5000) = %t, Contains(1000) = %t", tree.Contains(5000), tree.Contains(1000))
+	}
+	for i, c := range f.counts {
+		if c > 1 {
+			t.Fatalf("counter %d is %d after UnmarshalJSON", i, c)
+		}
+	}
+}
+
+type celsius float64
+
+func TestBloom_hash(t *testing.T) {
+	b := newBloom[float64](10, 0.01)
+	if b.hash(0) != b.hash(math.Copysign(0, -1)) {
+		t.Error("0 and -0 have different hashes")
+	}
+	if b.hash(math.NaN()) != b.hash(-math.NaN()) {
+		t.Error("NaNs have different hashes")
+	}
+	named := newBloom[celsius](10, 0.01)
+	named.add(21.5)
+	if !named.mayContain(21.5) || named.mayContain(22) {
+		t.Error("filter of a named type does not work")
+	}
+}
+
+func TestWithBloomFilter_comparator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New did not panic")
+		}
+	}()
+	New[int, int](WithComparator(func(a, b int) int { return a%10 - b%10 }), WithBloomFilter(10, 0.01))
+}
+
+func BenchmarkWithBloomFilter(b *testing.B) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{"plain", nil},
+		{"filter", []Option{WithBloomFilter(100000, 0.01)}},
+	} {
+		tree := New[int, int](tt.opts...)
+		for i := range 100000 {
+			tree.Insert(2*i, i)
+		}
+		b.Run(tt.name, func(b *testing.B) {
+			for i := range b.N {
+				tree.Find(2*(i%100000) + 1)
+			}
+		})
+	}
+}
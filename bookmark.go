@@ -0,0 +1,185 @@
+package generictree
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Token is an opaque pagination token, as returned by Bookmark, Page, and
+// Export. A token holds a value of the tree rather than a position, so a
+// token stays valid while entries are inserted or deleted: resuming from it
+// continues with the first entry after its value that is then in the tree.
+// Tokens are URL-safe strings and can be handed out to clients. The empty
+// token stands for the start of the tree.
+type Token string
+
+// bookmark is the decoded form of a Token. After is the value to resume
+// after, and Dups the number of entries with that value that were already
+// seen; -1 means all of them. Version is set by the application (see
+// Token.WithVersion).
+type bookmark[Value any] struct {
+	After   *Value
+	Dups    int
+	Version uint64
+}
+
+// tokenData is the encoded form of a Token, which is its JSON encoding in
+// URL-safe base64. After holds the value in the encoding of CodecFor,
+// which keeps floating-point values such as infinities exactly, and Type
+// names the type of the value, so that tokens of other trees are
+// rejected.
+type tokenData struct {
+	Type    string  `json:"type,omitempty"`
+	After   *[]byte `json:"after,omitempty"`
+	Dups    int     `json:"dups,omitempty"`
+	Version uint64  `json:"version,omitempty"`
+}
+
+func (b bookmark[Value]) token() (Token, error) {
+	d := tokenData{Dups: b.Dups, Version: b.Version}
+	if b.After != nil {
+		enc, err := CodecFor[Value]().Encode(*b.After)
+		if err != nil {
+			return "", fmt.Errorf("generictree: cannot encode token: %w", err)
+		}
+		d.Type, d.After = reflect.TypeFor[Value]().String(), &enc
+	}
+	return d.token(), nil
+}
+
+func (d tokenData) token() Token {
+	// tokenData holds no values that JSON cannot encode.
+	enc, _ := json.Marshal(d)
+	return Token(base64.RawURLEncoding.EncodeToString(enc))
+}
+
+func decodeTokenData(tok Token) (d tokenData, err error) {
+	if tok == "" {
+		return d, nil
+	}
+	enc, err := base64.RawURLEncoding.DecodeString(string(tok))
+	if err == nil {
+		err = json.Unmarshal(enc, &d)
+	}
+	if err != nil {
+		return d, fmt.Errorf("%w: %v", ErrToken, err)
+	}
+	return d, nil
+}
+
+func decodeBookmark[Value any](tok Token) (b bookmark[Value], err error) {
+	d, err := decodeTokenData(tok)
+	if err != nil {
+		return b, err
+	}
+	b.Dups, b.Version = d.Dups, d.Version
+	if d.After == nil {
+		return b, nil
+	}
+	if typ := reflect.TypeFor[Value]().String(); d.Type != typ {
+		return b, fmt.Errorf("%w: token of a %s tree, want %s", ErrToken, d.Type, typ)
+	}
+	v, err := CodecFor[Value]().Decode(*d.After)
+	if err != nil {
+		return b, fmt.Errorf("%w: %v", ErrToken, err)
+	}
+	b.After = &v
+	return b, nil
+}
+
+// WithVersion returns a copy of tok that also carries version, for
+// example the version of a Versioned tree that a listing started from.
+// The tree does not interpret the version.
+func (tok Token) WithVersion(version uint64) (Token, error) {
+	d, err := decodeTokenData(tok)
+	if err != nil {
+		return "", err
+	}
+	d.Version = version
+	return d.token(), nil
+}
+
+// Version returns the version that tok carries, or zero.
+func (tok Token) Version() (uint64, error) {
+	d, err := decodeTokenData(tok)
+	return d.Version, err
+}
+
+// Bookmark returns a token that resumes after value and all entries with
+// that value, whether or not value is in the tree. It panics if the codec
+// of Value (see CodecFor) cannot encode value, which the built-in codecs
+// always can.
+func (t *Tree[Value, Data]) Bookmark(value Value) Token {
+	tok, err := bookmark[Value]{After: &value, Dups: -1}.token()
+	if err != nil {
+		panic(err)
+	}
+	return tok
+}
+
+// ResumeFrom returns an iterator over the entries after the bookmark tok,
+// in tree order. It returns an error wrapping ErrToken if tok is not a
+// token of a tree with the same Value type. The tree must not be modified
+// during the iteration.
+func (t *Tree[Value, Data]) ResumeFrom(tok Token) (iter.Seq2[Value, Data], error) {
+	b, err := decodeBookmark[Value](tok)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(Value, Data) bool) {
+		c := t.Cursor()
+		for ok := t.seekAfter(c, b.After, b.Dups); ok; ok = c.Next() {
+			if !yield(c.Value(), c.Data()) {
+				return
+			}
+		}
+	}, nil
+}
+
+// Page returns up to n entries after the bookmark tok, in tree order, and
+// the token of the next page. next is empty if there are no more entries.
+// next keeps the version of tok. Page returns an error wrapping ErrToken
+// if tok is invalid, and the error of the codec of Value if it cannot
+// encode next.
+func (t *Tree[Value, Data]) Page(tok Token, n int) (page []KV[Value, Data], next Token, err error) {
+	b, err := decodeBookmark[Value](tok)
+	if err != nil {
+		return nil, "", err
+	}
+	c := t.Cursor()
+	ok := t.seekAfter(c, b.After, b.Dups)
+	for ; ok && len(page) < n; ok = c.Next() {
+		v := c.Value()
+		page = append(page, KV[Value, Data]{v, c.Data()})
+		if b.After != nil && t.compare(*b.After, v) == 0 && b.Dups >= 0 {
+			b.Dups++
+		} else {
+			b.After, b.Dups = &v, 1
+		}
+	}
+	if !ok {
+		return page, "", nil
+	}
+	if next, err = b.token(); err != nil {
+		return nil, "", err
+	}
+	return page, next, nil
+}
+
+// seekAfter positions c at the first entry after value, skipping the
+// first dups entries with value itself, or all of them if dups is
+// negative. A nil value positions c at the first entry. seekAfter reports
+// whether c is at an entry.
+func (t *Tree[Value, Data]) seekAfter(c *Cursor[Value, Data], value *Value, dups int) bool {
+	if value == nil {
+		return c.First()
+	}
+	ok := c.Seek(*value)
+	for i := 0; ok && (dups < 0 || i < dups) && t.compare(c.Value(), *value) == 0; i++ {
+		ok = c.Next()
+	}
+	return ok
+}
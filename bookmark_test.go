@@ -0,0 +1,131 @@
+package generictree
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTree_Page(t *testing.T) {
+	tree := New[int, string](WithAllowDuplicates())
+	for _, v := range []int{10, 20, 20, 20, 30, 40} {
+		tree.Insert(v, "")
+	}
+
+	page, next, err := tree.Page("", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values(page); !slices.Equal(got, []int{10, 20, 20}) {
+		t.Fatalf("first page = %v", got)
+	}
+
+	// Entries inserted before the bookmark do not shift the next page.
+	tree.Insert(5, "")
+	tree.Insert(15, "")
+	page, next, err = tree.Page(next, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values(page); !slices.Equal(got, []int{20, 30, 40}) {
+		t.Fatalf("second page = %v", got)
+	}
+	if next != "" {
+		t.Errorf("next token after the last page = %q, want empty", next)
+	}
+}
+
+func TestTree_ResumeFrom(t *testing.T) {
+	tree := New[string, int](WithAllowDuplicates())
+	for i, s := range []string{"a", "b", "b", "c", "d"} {
+		tree.Insert(s, i)
+	}
+	for _, tc := range []struct {
+		tok  Token
+		want []string
+	}{
+		{"", []string{"a", "b", "b", "c", "d"}},
+		{tree.Bookmark("b"), []string{"c", "d"}},
+		{tree.Bookmark("bb"), []string{"c", "d"}},
+		{tree.Bookmark("d"), nil},
+	} {
+		seq, err := tree.ResumeFrom(tc.tok)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for s := range seq {
+			got = append(got, s)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("ResumeFrom(%q) = %v, want %v", tc.tok, got, tc.want)
+		}
+	}
+
+	if _, err := tree.ResumeFrom("not a token"); !errors.Is(err, ErrToken) {
+		t.Errorf("ResumeFrom(invalid) error = %v, want ErrToken", err)
+	}
+	if _, _, err := tree.Page(New[int, int]().Bookmark(1), 1); !errors.Is(err, ErrToken) {
+		t.Errorf("Page(token of int tree) error = %v, want ErrToken", err)
+	}
+}
+
+// TestTree_Page_floats pages through infinities and NaN, which JSON cannot
+// encode.
+func TestTree_Page_floats(t *testing.T) {
+	tree := New[float64, int](WithDescending())
+	for i, v := range []float64{math.Inf(1), 1, math.Inf(-1), math.NaN()} {
+		tree.Insert(v, i)
+	}
+	var got []float64
+	var tok Token
+	for {
+		page, next, err := tree.Page(tok, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, values(page)...)
+		if next == "" {
+			break
+		}
+		tok = next
+	}
+	if want := tree.values(); !slices.EqualFunc(got, want, func(a, b float64) bool { return cmp.Compare(a, b) == 0 }) {
+		t.Errorf("paged through %v, want %v", got, want)
+	}
+}
+
+func TestToken_WithVersion(t *testing.T) {
+	tree := New[int, int]()
+	for i := range 5 {
+		tree.Insert(i, i)
+	}
+	tok, err := tree.Bookmark(1).WithVersion(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tok.Version(); err != nil || v != 7 {
+		t.Errorf("Version() = %d, %v, want 7", v, err)
+	}
+	page, next, err := tree.Page(tok, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values(page); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Page(tok, 2) = %v, want [2 3]", got)
+	}
+	if v, _ := next.Version(); v != 7 {
+		t.Errorf("next page has version %d, want 7", v)
+	}
+}
+
+// values returns the values of kvs.
+func values[Value, Data any](kvs []KV[Value, Data]) []Value {
+	vs := make([]Value, len(kvs))
+	for i, kv := range kvs {
+		vs[i] = kv.Value
+	}
+	return vs
+}
@@ -0,0 +1,127 @@
+package generictree
+
+import (
+	"iter"
+)
+
+// Bound is one end of a range of values: a value that belongs to the range
+// (Incl), a value that does not (Excl), or no limit at all (Unbounded).
+// Bounds let range queries express all kinds of intervals, for example the
+// closed interval [a, b] as
+//
+//	t.Between(Incl(a), Incl(b))
+//
+// without computing the successor of b, which is awkward for strings.
+type Bound[Value any] struct {
+	value Value
+	kind  boundKind
+}
+
+type boundKind uint8
+
+const (
+	unbounded boundKind = iota
+	inclusive
+	exclusive
+)
+
+// Incl returns a bound that includes value in the range.
+func Incl[Value any](value Value) Bound[Value] {
+	return Bound[Value]{value, inclusive}
+}
+
+// Excl returns a bound that excludes value from the range.
+func Excl[Value any](value Value) Bound[Value] {
+	return Bound[Value]{value, exclusive}
+}
+
+// Unbounded returns a bound that does not limit the range.
+func Unbounded[Value any]() Bound[Value] {
+	return Bound[Value]{}
+}
+
+// Value returns the value of the bound, and false if the bound is
+// unbounded.
+func (b Bound[Value]) Value() (Value, bool) {
+	return b.value, b.kind != unbounded
+}
+
+// Inclusive reports whether the bound includes its value in the range.
+func (b Bound[Value]) Inclusive() bool {
+	return b.kind == inclusive
+}
+
+// afterLo reports whether value respects the lower bound lo.
+func (t *Tree[Value, Data]) afterLo(lo Bound[Value], value Value) bool {
+	switch lo.kind {
+	case inclusive:
+		return t.compare(value, lo.value) >= 0
+	case exclusive:
+		return t.compare(value, lo.value) > 0
+	}
+	return true
+}
+
+// beforeHi reports whether value respects the upper bound hi.
+func (t *Tree[Value, Data]) beforeHi(hi Bound[Value], value Value) bool {
+	switch hi.kind {
+	case inclusive:
+		return t.compare(value, hi.value) <= 0
+	case exclusive:
+		return t.compare(value, hi.value) < 0
+	}
+	return true
+}
+
+// Between returns an iterator over the entries whose values lie between
+// the bounds lo and hi, in the tree's order. The tree must not be modified
+// during the iteration.
+func (t *Tree[Value, Data]) Between(lo, hi Bound[Value]) iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		t.ascendBetween(t.Root, lo, hi, yield)
+	}
+}
+
+// ascendBetween calls f for the entries of the subtree rooted at n that lie
+// between lo and hi. It skips the subtrees that lie outside of the bounds
+// and returns false as soon as f returns false.
+func (t *Tree[Value, Data]) ascendBetween(n *Node[Value, Data], lo, hi Bound[Value], f func(Value, Data) bool) bool {
+	if n == nil {
+		return true
+	}
+	afterLo, beforeHi := t.afterLo(lo, n.Value), t.beforeHi(hi, n.Value)
+	if afterLo && !t.ascendBetween(n.Left, lo, hi, f) {
+		return false
+	}
+	if afterLo && beforeHi && !f(n.Value, n.Data) {
+		return false
+	}
+	if beforeHi {
+		return t.ascendBetween(n.Right, lo, hi, f)
+	}
+	return true
+}
+
+// CountBetween returns the number of entries whose values lie between the
+// bounds lo and hi in O(log n) time.
+func (t *Tree[Value, Data]) CountBetween(lo, hi Bound[Value]) int {
+	below := t.countWhile(func(v Value) bool { return !t.afterLo(lo, v) })
+	upTo := t.countWhile(func(v Value) bool { return t.beforeHi(hi, v) })
+	return max(0, upTo-below)
+}
+
+// countWhile returns the number of entries at the start of the tree for
+// which pred is true. pred must be true for a prefix of the entries and
+// false for the rest.
+func (t *Tree[Value, Data]) countWhile(pred func(Value) bool) int {
+	count := 0
+	for n := t.Root; n != nil; {
+		if pred(n.Value) {
+			count += n.Left.count() + 1
+			n = n.Right
+		} else {
+			n = n.Left
+		}
+	}
+	return count
+}
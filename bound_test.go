@@ -0,0 +1,80 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTree_Between(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	tree := New[int, int](WithAllowDuplicates())
+	numeric := NewNumericTree[int, int]()
+	var model []int
+	for range 300 {
+		v := r.IntN(100)
+		tree.Insert(v, v)
+		if _, dup := numeric.Find(v); !dup {
+			numeric.Insert(v, v)
+		}
+		model = append(model, v)
+	}
+	slices.Sort(model)
+
+	bound := func(kind boundKind, v int) Bound[int] {
+		return [...]Bound[int]{Unbounded[int](), Incl(v), Excl(v)}[kind]
+	}
+	within := func(lo, hi Bound[int], v int) bool {
+		if x, ok := lo.Value(); ok && (v < x || v == x && !lo.Inclusive()) {
+			return false
+		}
+		if x, ok := hi.Value(); ok && (v > x || v == x && !hi.Inclusive()) {
+			return false
+		}
+		return true
+	}
+	for range 200 {
+		lo := bound(boundKind(r.IntN(3)), r.IntN(110)-5)
+		hi := bound(boundKind(r.IntN(3)), r.IntN(110)-5)
+		var want []int
+		sum := 0
+		for i, v := range model {
+			if within(lo, hi, v) {
+				want = append(want, v)
+				if i == 0 || model[i-1] != v {
+					sum += v
+				}
+			}
+		}
+		var got []int
+		for v := range tree.Between(lo, hi) {
+			got = append(got, v)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Between(%v, %v) = %v, want %v", lo, hi, got, want)
+		}
+		if n := tree.CountBetween(lo, hi); n != len(want) {
+			t.Fatalf("CountBetween(%v, %v) = %d, want %d", lo, hi, n, len(want))
+		}
+		if got := numeric.SumBetween(lo, hi); got != sum {
+			t.Fatalf("SumBetween(%v, %v) = %d, want %d", lo, hi, got, sum)
+		}
+	}
+}
+
+func TestTree_Between_strings(t *testing.T) {
+	tree := New[string, int]()
+	for i, s := range []string{"a", "ab", "abc", "b", "ba"} {
+		tree.Insert(s, i)
+	}
+	var got []string
+	for s := range tree.Between(Excl("a"), Incl("b")) {
+		got = append(got, s)
+	}
+	if want := []string{"ab", "abc", "b"}; !slices.Equal(got, want) {
+		t.Errorf("Between(Excl(a), Incl(b)) = %v, want %v", got, want)
+	}
+	if n := tree.CountBetween(Unbounded[string](), Unbounded[string]()); n != tree.Len() {
+		t.Errorf("CountBetween(Unbounded, Unbounded) = %d, want %d", n, tree.Len())
+	}
+}
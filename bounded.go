@@ -0,0 +1,200 @@
+package generictree
+
+import (
+	"cmp"
+	"container/list"
+)
+
+// EvictionPolicy selects the entry that a tree with a maximum size evicts
+// when an insertion makes it too large (see WithMaxSize).
+type EvictionPolicy int
+
+const (
+	// EvictMin evicts the first entry in the tree's order, so the tree
+	// keeps the largest values.
+	EvictMin EvictionPolicy = iota
+	// EvictMax evicts the last entry in the tree's order, so the tree
+	// keeps the smallest values.
+	EvictMax
+	// EvictLRU evicts the least recently accessed entry. Insert, Find, and
+	// Get count as accesses.
+	EvictLRU
+)
+
+// lru keeps the nodes of a tree in the order of their last access, the most
+// recent first.
+type lru[Value cmp.Ordered, Data any] struct {
+	order list.List
+	elems map[*Node[Value, Data]]*list.Element
+}
+
+func newLRU[Value cmp.Ordered, Data any]() *lru[Value, Data] {
+	return &lru[Value, Data]{elems: map[*Node[Value, Data]]*list.Element{}}
+}
+
+func (l *lru[Value, Data]) touch(n *Node[Value, Data]) {
+	if e, ok := l.elems[n]; ok {
+		l.order.MoveToFront(e)
+		return
+	}
+	l.elems[n] = l.order.PushFront(n)
+}
+
+func (l *lru[Value, Data]) remove(n *Node[Value, Data]) {
+	if e, ok := l.elems[n]; ok {
+		l.order.Remove(e)
+		delete(l.elems, n)
+	}
+}
+
+func (l *lru[Value, Data]) oldest() *Node[Value, Data] {
+	return l.order.Back().Value.(*Node[Value, Data])
+}
+
+func (l *lru[Value, Data]) clear() {
+	l.order.Init()
+	clear(l.elems)
+}
+
+// touch records an access to n if the tree evicts by access order or
+// counts accesses.
+func (t *Tree[Value, Data]) touch(n *Node[Value, Data]) {
+	if t.cfg == nil {
+		return
+	}
+	if t.cfg.lru != nil {
+		t.cfg.lru.touch(n)
+	}
+	if t.cfg.access != nil {
+		t.cfg.access.record(n.Value)
+	}
+}
+
+// adopt attaches and interns nodes that were added to the tree in bulk,
+// records them as accessed, and evicts entries until the tree is within its maximum size.
+func (t *Tree[Value, Data]) adopt() {
+	t.attachAll()
+	t.internAll()
+	if t.cfg == nil || t.cfg.maxSize == 0 {
+		return
+	}
+	if l := t.cfg.lru; l != nil {
+		t.Root.ascend(func(n *Node[Value, Data]) bool {
+			if _, ok := l.elems[n]; !ok {
+				l.touch(n)
+			}
+			return true
+		})
+	}
+	t.enforceMaxSize()
+}
+
+// enforceMaxSize evicts entries until the tree is within its maximum size.
+func (t *Tree[Value, Data]) enforceMaxSize() {
+	if t.cfg == nil || t.cfg.maxSize == 0 {
+		return
+	}
+	for t.Len() > t.cfg.maxSize {
+		var i int
+		switch t.cfg.eviction {
+		case EvictMin:
+			i = 0
+		case EvictMax:
+			i = t.Len() - 1
+		case EvictLRU:
+			i = t.indexOf(t.cfg.lru.oldest())
+		}
+		removed := t.deleteAt(i)
+		t.debug.record(t, "Delete", removed.Value)
+		t.afterDelete(removed)
+	}
+}
+
+// checkLimits returns a *LimitError if inserting value would exceed the
+// limits of WithMaxEntries or WithMaxHeight.
+func (t *Tree[Value, Data]) checkLimits(value Value) error {
+	if t.cfg == nil || t.cfg.maxEntries == 0 && t.cfg.maxHeight == 0 {
+		return nil
+	}
+	adds, height := t.insertHeight(value)
+	switch {
+	case !adds:
+		return nil
+	case t.cfg.maxEntries > 0 && t.Len() >= t.cfg.maxEntries:
+		return &LimitError{"entries", t.cfg.maxEntries}
+	case t.cfg.maxHeight > 0 && height > t.cfg.maxHeight:
+		return &LimitError{"height", t.cfg.maxHeight}
+	}
+	return nil
+}
+
+// insertHeight reports whether inserting value adds an entry, and the
+// height of the tree after the insertion. In an AVL tree, an insertion
+// raises the root only if every node on the search path is balanced:
+// below any other node, either the growth evens out the node, or a
+// rotation restores the height of its subtree. With a slack of k (see
+// WithRelaxedBalance), a node rises with its child on the path if that
+// child is at least as high as its sibling, and at most k-1 levels higher.
+func (t *Tree[Value, Data]) insertHeight(value Value) (adds bool, height int) {
+	depth, rises := 0, true
+	for n := t.Root; n != nil; depth++ {
+		c := t.compare(value, n.Value)
+		if c == 0 && !t.allowDuplicates() {
+			return false, t.Root.Height()
+		}
+		child, sibling := n.Right, n.Left
+		if c < 0 {
+			child, sibling = n.Left, n.Right
+		}
+		d := child.Height() - sibling.Height()
+		rises = rises && d >= 0 && d < t.slack()
+		n = child
+	}
+	switch {
+	case !t.balanced():
+		return true, max(t.Root.Height(), depth+1)
+	case rises:
+		return true, t.Root.Height() + 1
+	}
+	return true, t.Root.Height()
+}
+
+// indexOf returns the index of n in the tree's order.
+func (t *Tree[Value, Data]) indexOf(n *Node[Value, Data]) int {
+	i := t.Rank(n.Value)
+	if !t.allowDuplicates() {
+		return i
+	}
+	// Walk the entries with equal values to find n itself.
+	c := t.Cursor()
+	for ok := c.SeekIndex(i); ok && c.node() != n; ok = c.Next() {
+		i++
+	}
+	return i
+}
+
+// deleteAt removes the entry at index i in the tree's order and returns its
+// node, or nil if i is out of range.
+func (t *Tree[Value, Data]) deleteAt(i int) *Node[Value, Data] {
+	var removed *Node[Value, Data]
+	t.Root, removed = t.Root.deleteIndex(i, t.balancing())
+	return removed
+}
+
+// deleteIndex removes the node at index i of the subtree rooted at n. It
+// returns the new root of the subtree and the removed node, or nil if i is
+// out of range. Like deleteFunc, it rotates within ±b.limit, or not at all
+// if b.limit is 0.
+func (n *Node[Value, Data]) deleteIndex(i int, b balance) (root, removed *Node[Value, Data]) {
+	return n.deleteFunc(func(m *Node[Value, Data]) int {
+		left := m.Left.count()
+		switch {
+		case i < left:
+			return -1
+		case i > left:
+			i -= left + 1
+			return 1
+		}
+		return 0
+	}, b)
+}
@@ -0,0 +1,154 @@
+package generictree
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestWithMaxSize(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		policy  EvictionPolicy
+		want    []int
+		evicted []int
+	}{
+		{"EvictMin", EvictMin, []int{4, 5, 7}, []int{1, 3, 2}},
+		{"EvictMax", EvictMax, []int{1, 2, 3}, []int{7, 5, 4}},
+		// 5 is evicted before it is inserted again; 3 is evicted last
+		// because Find(3) counts as an access.
+		{"EvictLRU", EvictLRU, []int{2, 4, 5}, []int{5, 7, 1, 3}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var evicted []int
+			tree := New[int, string](WithMaxSize(3, c.policy), WithHooks(Hooks[int, string]{
+				OnDelete: func(v int, _ string) { evicted = append(evicted, v) },
+			}))
+			for _, v := range []int{5, 3, 7, 1} {
+				tree.Insert(v, "")
+			}
+			tree.Find(3)
+			tree.Insert(5, "again")
+			tree.Insert(4, "")
+			tree.Insert(2, "")
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.values(); !slices.Equal(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+			if !slices.Equal(evicted, c.evicted) {
+				t.Errorf("evicted %v, want %v", evicted, c.evicted)
+			}
+		})
+	}
+}
+
+func TestWithMaxSize_LRUDuplicates(t *testing.T) {
+	tree := New[int, int](WithMaxSize(3, EvictLRU), WithAllowDuplicates())
+	for i, v := range []int{1, 1, 1, 1} {
+		tree.Insert(v, i)
+	}
+	var data []int
+	for _, d := range tree.All() {
+		data = append(data, d)
+	}
+	if !slices.Equal(data, []int{1, 2, 3}) {
+		t.Errorf("got data %v, want the three latest entries", data)
+	}
+	if n := len(tree.cfg.lru.elems); n != 3 {
+		t.Errorf("access list has %d nodes, want 3", n)
+	}
+}
+
+func TestWithMaxSize_bulk(t *testing.T) {
+	tree := New[int, string](WithMaxSize(2, EvictLRU))
+	if err := tree.UnmarshalJSON([]byte(`[{"value":1},{"value":2},{"value":3}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Len() != 2 || tree.cfg.lru.order.Len() != 2 {
+		t.Errorf("after UnmarshalJSON: len %d, access list %d", tree.Len(), tree.cfg.lru.order.Len())
+	}
+	other := &Tree[int, string]{}
+	other.Insert(10, "")
+	if err := tree.Concat(other); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Len() != 2 {
+		t.Errorf("after Concat: len %d, want 2", tree.Len())
+	}
+}
+
+func TestWithMaxSize_negative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New did not panic")
+		}
+	}()
+	New[int, int](WithMaxSize(-1, EvictMin))
+}
+
+func TestWithMaxEntries(t *testing.T) {
+	tree := New[int, string](WithMaxEntries(3))
+	for i := range 3 {
+		if err := tree.InsertStrict(i, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := tree.InsertStrict(3, "a")
+	var limit *LimitError
+	if !errors.As(err, &limit) || limit.Limit != "entries" || limit.Max != 3 {
+		t.Fatalf("InsertStrict into a full tree: got %v", err)
+	}
+	tree.Insert(4, "a")
+	if tree.Len() != 3 {
+		t.Errorf("Insert into a full tree: Len = %d", tree.Len())
+	}
+	if err := tree.InsertStrict(1, "b"); err != nil {
+		t.Errorf("replacing data in a full tree: %v", err)
+	}
+	if d, _ := tree.Find(1); d != "b" {
+		t.Errorf("Find(1) = %q, want b", d)
+	}
+}
+
+func TestWithMaxHeight(t *testing.T) {
+	tree := New[int, int](WithNoBalance(), WithMaxHeight(4))
+	for i := range 10 {
+		tree.Insert(i, i)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("values = %v, want the first 4", got)
+	}
+	var limit *LimitError
+	if err := tree.InsertStrict(10, 0); !errors.As(err, &limit) || limit.Limit != "height" {
+		t.Errorf("InsertStrict = %v, want a height LimitError", err)
+	}
+	// Values that fit into a free slot above the limit still go in.
+	if err := tree.InsertStrict(-1, 0); err != nil {
+		t.Errorf("InsertStrict(-1) = %v", err)
+	}
+}
+
+func TestTree_insertHeight(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, opts := range [][]Option{
+		nil, {WithAllowDuplicates()}, {WithNoBalance()},
+		{WithRelaxedBalance(2)}, {WithRelaxedBalance(3), WithAllowDuplicates()},
+	} {
+		tree := New[int, int](opts...)
+		for range 2000 {
+			v := rng.IntN(500)
+			adds, height := tree.insertHeight(v)
+			n := tree.Len()
+			tree.Insert(v, v)
+			if got := tree.Len() > n; got != adds {
+				t.Fatalf("insertHeight(%d): adds = %v, want %v", v, adds, got)
+			}
+			if got := tree.Root.Height(); got != height {
+				t.Fatalf("insertHeight(%d): height = %d, want %d", v, height, got)
+			}
+		}
+	}
+}
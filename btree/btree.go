@@ -0,0 +1,430 @@
+// Package btree implements an in-memory B-tree, an alternative backend to
+// the AVL tree of package generictree.
+//
+// A B-tree stores many keys per node. This makes it shallower than a binary
+// tree and friendlier to CPU caches, which pays off for large maps.
+package btree
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// DefaultDegree is the minimum degree of a Tree created with a degree
+// less than 2, or of a zero Tree.
+const DefaultDegree = 16
+
+type item[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+}
+
+type node[K cmp.Ordered, V any] struct {
+	items    []item[K, V]
+	children []*node[K, V] // nil for leaves
+}
+
+// Tree is a B-tree. Every node except the root holds between degree-1 and
+// 2*degree-1 keys. The zero value is an empty tree of DefaultDegree,
+// ready to use.
+type Tree[K cmp.Ordered, V any] struct {
+	root   *node[K, V]
+	degree int
+	len    int
+}
+
+// New returns an empty tree of the given minimum degree.
+// If degree is less than 2, the tree uses DefaultDegree.
+func New[K cmp.Ordered, V any](degree int) *Tree[K, V] {
+	return &Tree[K, V]{degree: degree}
+}
+
+func (t *Tree[K, V]) minDegree() int {
+	if t.degree < 2 {
+		return DefaultDegree
+	}
+	return t.degree
+}
+
+// search returns the index of the first item in n whose key is not less
+// than key, and whether that item's key equals key.
+func (n *node[K, V]) search(key K) (int, bool) {
+	return slices.BinarySearchFunc(n.items, key, func(it item[K, V], key K) int {
+		return cmp.Compare(it.key, key)
+	})
+}
+
+func (n *node[K, V]) leaf() bool {
+	return n.children == nil
+}
+
+// Get returns the value stored for key and true,
+// or the zero value of V and false if key is not in the tree.
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := n.search(key)
+		if found {
+			return n.items[i].value, true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	var zero V
+	return zero, false
+}
+
+// Set stores value for key, replacing any previous value.
+func (t *Tree[K, V]) Set(key K, value V) {
+	d := t.minDegree()
+	if t.root == nil {
+		t.root = &node[K, V]{items: make([]item[K, V], 0, 2*d-1)}
+	}
+	if len(t.root.items) == 2*d-1 {
+		old := t.root
+		t.root = &node[K, V]{children: []*node[K, V]{old}}
+		t.root.splitChild(0, d)
+	}
+	if t.root.insertNonFull(item[K, V]{key, value}, d) {
+		t.len++
+	}
+}
+
+// splitChild splits the full child at index i into two nodes
+// and moves the median item up into n.
+func (n *node[K, V]) splitChild(i, d int) {
+	y := n.children[i]
+	median := y.items[d-1]
+	z := &node[K, V]{items: make([]item[K, V], d-1, 2*d-1)}
+	copy(z.items, y.items[d:])
+	clear(y.items[d-1:])
+	y.items = y.items[:d-1]
+	if !y.leaf() {
+		z.children = make([]*node[K, V], d, 2*d)
+		copy(z.children, y.children[d:])
+		clear(y.children[d:])
+		y.children = y.children[:d]
+	}
+	n.items = slices.Insert(n.items, i, median)
+	n.children = slices.Insert(n.children, i+1, z)
+}
+
+// insertNonFull inserts it into the subtree rooted at n, which must not be
+// full. It returns false if it replaced an existing item.
+func (n *node[K, V]) insertNonFull(it item[K, V], d int) bool {
+	for {
+		i, found := n.search(it.key)
+		if found {
+			n.items[i] = it
+			return false
+		}
+		if n.leaf() {
+			n.items = slices.Insert(n.items, i, it)
+			return true
+		}
+		if len(n.children[i].items) == 2*d-1 {
+			n.splitChild(i, d)
+			switch c := cmp.Compare(it.key, n.items[i].key); {
+			case c == 0:
+				n.items[i] = it
+				return false
+			case c > 0:
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// Delete removes key and returns its value and true,
+// or the zero value of V and false if key is not in the tree.
+func (t *Tree[K, V]) Delete(key K) (V, bool) {
+	value, ok := t.Get(key)
+	if !ok {
+		return value, false
+	}
+	t.root.delete(key, t.minDegree())
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	t.len--
+	return value, true
+}
+
+// delete removes key from the subtree rooted at n. key must be present.
+// Every node that delete descends into has at least d items, so that
+// removing one item never leaves a node with too few items.
+func (n *node[K, V]) delete(key K, d int) {
+	for {
+		i, found := n.search(key)
+		if n.leaf() {
+			n.items = slices.Delete(n.items, i, i+1)
+			return
+		}
+		if found {
+			switch {
+			case len(n.children[i].items) >= d:
+				// Replace the item by its predecessor.
+				pred := n.children[i].max()
+				n.items[i] = pred
+				key, n = pred.key, n.children[i]
+			case len(n.children[i+1].items) >= d:
+				// Replace the item by its successor.
+				succ := n.children[i+1].min()
+				n.items[i] = succ
+				key, n = succ.key, n.children[i+1]
+			default:
+				n.merge(i)
+				n = n.children[i]
+			}
+			continue
+		}
+		if len(n.children[i].items) == d-1 {
+			i = n.grow(i, d)
+		}
+		n = n.children[i]
+	}
+}
+
+// grow makes sure that the child at index i has at least d items, by
+// borrowing an item from a sibling or merging with a sibling. It returns
+// the index of the child that now holds the keys of the former child i.
+func (n *node[K, V]) grow(i, d int) int {
+	child := n.children[i]
+	switch {
+	case i > 0 && len(n.children[i-1].items) >= d:
+		// Rotate an item from the left sibling through n into child.
+		left := n.children[i-1]
+		child.items = slices.Insert(child.items, 0, n.items[i-1])
+		n.items[i-1] = left.items[len(left.items)-1]
+		left.items[len(left.items)-1] = item[K, V]{}
+		left.items = left.items[:len(left.items)-1]
+		if !left.leaf() {
+			child.children = slices.Insert(child.children, 0, left.children[len(left.children)-1])
+			left.children[len(left.children)-1] = nil
+			left.children = left.children[:len(left.children)-1]
+		}
+	case i < len(n.children)-1 && len(n.children[i+1].items) >= d:
+		// Rotate an item from the right sibling through n into child.
+		right := n.children[i+1]
+		child.items = append(child.items, n.items[i])
+		n.items[i] = right.items[0]
+		right.items = slices.Delete(right.items, 0, 1)
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+	case i < len(n.children)-1:
+		n.merge(i)
+	default:
+		n.merge(i - 1)
+		i--
+	}
+	return i
+}
+
+// merge merges the child at index i+1 and the item at index i
+// into the child at index i.
+func (n *node[K, V]) merge(i int) {
+	left, right := n.children[i], n.children[i+1]
+	left.items = append(left.items, n.items[i])
+	left.items = append(left.items, right.items...)
+	if !left.leaf() {
+		left.children = append(left.children, right.children...)
+	}
+	n.items = slices.Delete(n.items, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+func (n *node[K, V]) min() item[K, V] {
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n.items[0]
+}
+
+func (n *node[K, V]) max() item[K, V] {
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return n.items[len(n.items)-1]
+}
+
+// Min returns the smallest key and its value.
+// ok is false if the tree is empty.
+func (t *Tree[K, V]) Min() (key K, value V, ok bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	it := t.root.min()
+	return it.key, it.value, true
+}
+
+// Max returns the largest key and its value.
+// ok is false if the tree is empty.
+func (t *Tree[K, V]) Max() (key K, value V, ok bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	it := t.root.max()
+	return it.key, it.value, true
+}
+
+// Range calls f for each key in the half-open interval [lo, hi),
+// in ascending order, until f returns false.
+func (t *Tree[K, V]) Range(lo, hi K, f func(K, V) bool) {
+	if t.root != nil {
+		t.root.ascendRange(lo, hi, f)
+	}
+}
+
+// ascendRange returns false if the iteration is done, either because f
+// returned false or because a key not less than hi was reached.
+func (n *node[K, V]) ascendRange(lo, hi K, f func(K, V) bool) bool {
+	i, _ := n.search(lo)
+	for ; i <= len(n.items); i++ {
+		if !n.leaf() && !n.children[i].ascendRange(lo, hi, f) {
+			return false
+		}
+		if i == len(n.items) {
+			break
+		}
+		it := n.items[i]
+		if it.key >= hi || !f(it.key, it.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree[K, V]) Len() int {
+	return t.len
+}
+
+// Validate checks the B-tree invariants: the search order, the number of
+// items and children per node, and that all leaves are at the same depth.
+// It returns an error describing the first violation found, or nil.
+func (t *Tree[K, V]) Validate() error {
+	if t.root == nil {
+		if t.len != 0 {
+			return fmt.Errorf("empty tree has Len() %d", t.len)
+		}
+		return nil
+	}
+	_, size, err := t.root.validate(t.minDegree(), true, nil, nil)
+	if err != nil {
+		return err
+	}
+	if size != t.len {
+		return fmt.Errorf("tree has %d items but Len() is %d", size, t.len)
+	}
+	return nil
+}
+
+// validate returns the depth of the leaves and the number of items in the subtree.
+func (n *node[K, V]) validate(d int, root bool, lo, hi *K) (depth, size int, err error) {
+	if len(n.items) > 2*d-1 || (!root && len(n.items) < d-1) || len(n.items) == 0 {
+		return 0, 0, fmt.Errorf("node with %d items violates the degree %d", len(n.items), d)
+	}
+	for i, it := range n.items {
+		if (i > 0 && it.key <= n.items[i-1].key) || (lo != nil && it.key <= *lo) || (hi != nil && it.key >= *hi) {
+			return 0, 0, fmt.Errorf("key %v violates the search order", it.key)
+		}
+	}
+	size = len(n.items)
+	if n.leaf() {
+		return 1, size, nil
+	}
+	if len(n.children) != len(n.items)+1 {
+		return 0, 0, fmt.Errorf("node with %d items has %d children", len(n.items), len(n.children))
+	}
+	for i, c := range n.children {
+		clo, chi := lo, hi
+		if i > 0 {
+			clo = &n.items[i-1].key
+		}
+		if i < len(n.items) {
+			chi = &n.items[i].key
+		}
+		cd, cs, err := c.validate(d, false, clo, chi)
+		if err != nil {
+			return 0, 0, err
+		}
+		if i > 0 && cd != depth {
+			return 0, 0, fmt.Errorf("leaves at depths %d and %d", depth, cd)
+		}
+		depth = cd
+		size += cs
+	}
+	return depth + 1, size, nil
+}
+
+// LoadSorted replaces the contents of the tree with the keys and their
+// values, in O(n) time. The keys must be in strictly ascending order, and
+// there must be one value per key; otherwise, LoadSorted panics.
+//
+// LoadSorted builds the tree of the least possible height and spreads the
+// items evenly over the nodes of each level, so that later insertions
+// rarely split a node right away.
+func (t *Tree[K, V]) LoadSorted(keys []K, values []V) {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("btree: LoadSorted: %d keys but %d values", len(keys), len(values)))
+	}
+	items := make([]item[K, V], len(keys))
+	for i, k := range keys {
+		if i > 0 && keys[i-1] >= k {
+			panic(fmt.Sprintf("btree: LoadSorted: key %v after %v", k, keys[i-1]))
+		}
+		items[i] = item[K, V]{k, values[i]}
+	}
+	t.root, t.len = nil, len(items)
+	if len(items) == 0 {
+		return
+	}
+	// caps[h] is the number of items that a subtree of height h+1 can hold.
+	d := t.minDegree()
+	caps := []int{2*d - 1}
+	for caps[len(caps)-1] < len(items) {
+		caps = append(caps, 2*d-1+2*d*caps[len(caps)-1])
+	}
+	t.root = load(items, caps, d, true)
+}
+
+// load returns a subtree of height len(caps) that holds items.
+func load[K cmp.Ordered, V any](items []item[K, V], caps []int, d int, root bool) *node[K, V] {
+	h := len(caps) - 1
+	if h == 0 {
+		return &node[K, V]{items: slices.Clip(items)}
+	}
+	// As few children as possible, but at least d, or 2 for the root.
+	c := (len(items) + 1 + caps[h-1]) / (caps[h-1] + 1)
+	if root {
+		c = max(c, 2)
+	} else {
+		c = max(c, d)
+	}
+	n := &node[K, V]{
+		items:    make([]item[K, V], 0, c-1),
+		children: make([]*node[K, V], 0, c),
+	}
+	size, extra := (len(items)-c+1)/c, (len(items)-c+1)%c
+	for i := range c {
+		m := size
+		if i < extra {
+			m++
+		}
+		n.children = append(n.children, load(items[:m], caps[:h], d, false))
+		if i < c-1 {
+			n.items = append(n.items, items[m])
+			items = items[m+1:]
+		}
+	}
+	return n
+}
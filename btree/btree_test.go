@@ -0,0 +1,64 @@
+package btree_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+	"github.com/appliedgo/generictree/btree"
+	"github.com/appliedgo/generictree/conformance"
+)
+
+var _ generictree.SortedMap[int, string] = (*btree.Tree[int, string])(nil)
+
+func TestTree_SortedMap(t *testing.T) {
+	// Small degrees exercise splits and merges much more often.
+	for _, degree := range []int{2, 3, 5, btree.DefaultDegree} {
+		t.Run(fmt.Sprintf("degree%d", degree), func(t *testing.T) {
+			conformance.Run(t, func() conformance.Map { return btree.New[int, string](degree) })
+		})
+	}
+}
+
+func BenchmarkTree(b *testing.B) {
+	conformance.Benchmark(b, func() conformance.Map { return btree.New[int, string](0) })
+}
+
+func TestTree_LoadSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, btree.DefaultDegree} {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 7, 8, 31, 32, 33, 100, 1000, 5000} {
+			keys := make([]int, n)
+			values := make([]string, n)
+			for i := range n {
+				keys[i], values[i] = 2*i, fmt.Sprint(i)
+			}
+			tree := btree.New[int, string](degree)
+			tree.Set(-1, "replaced")
+			tree.LoadSorted(keys, values)
+			if err := tree.Validate(); err != nil {
+				t.Fatalf("degree %d, %d keys: %v", degree, n, err)
+			}
+			i := 0
+			tree.Range(-10, 2*n, func(k int, v string) bool {
+				if k != 2*i || v != fmt.Sprint(i) {
+					t.Fatalf("degree %d, %d keys: entry %d is %d, %q", degree, n, i, k, v)
+				}
+				i++
+				return true
+			})
+			if i != n || tree.Len() != n {
+				t.Fatalf("degree %d, %d keys: Range visited %d keys, Len() = %d", degree, n, i, tree.Len())
+			}
+			// The loaded tree stays valid under updates.
+			for k := range n {
+				tree.Set(2*k+1, "")
+				if k%3 == 0 {
+					tree.Delete(2 * k)
+				}
+			}
+			if err := tree.Validate(); err != nil {
+				t.Fatalf("degree %d, %d keys, after updates: %v", degree, n, err)
+			}
+		}
+	}
+}
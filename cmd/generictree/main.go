@@ -0,0 +1,95 @@
+// Command generictree runs the demo from the article
+// "How I turned a binary search tree into a generic data structure".
+//
+// It instantiates the generic tree with strings, integers, and even
+// trees as search values and payload data.
+package main
+
+import (
+	"fmt"
+
+	"github.com/appliedgo/generictree"
+)
+
+/*
+## How to use the new generic tree type
+
+Now is the moment where I can instantiate the generic `Tree[Value, Data]` type into something tangible like `Tree[int,string]`.
+
+*/
+//
+func main() {
+	values := []string{"d", "b", "g", "g", "c", "e", "a", "h", "f", "i", "j", "l", "k"}
+	data := []string{"delta", "bravo", "golang", "golf", "charlie", "echo", "alpha", "hotel", "foxtrot", "india", "juliett", "lima", "kilo"}
+
+	// Here, Tree gets instantiated with the `string` type for both Value and Data.
+	// This is basically the same tree as in the original article about balanced trees.
+	tree := &generictree.Tree[string, string]{}
+	for i := 0; i < len(values); i++ {
+		tree.Insert(values[i], data[i])
+	}
+
+	fmt.Print("\n*** Tree with string search values and string data ***\n\n")
+	fmt.Print("Sorted values: | ")
+	// As with `*Tree` above, `*Node` also needs to get instantiated with concrete types.
+	tree.Traverse(tree.Root, func(n *generictree.Node[string, string]) { fmt.Print(n.Value, ": ", n.Data, " | ") })
+	fmt.Println()
+
+	fmt.Println("Pretty print (turned 90° anti-clockwise):")
+	tree.PrettyPrint()
+
+	// Let's try the same with integers as search values.
+	keys := []int{4, 2, 7, 7, 3, 5, 1, 8, 6, 9, 10, 12, 11}
+	// No new `data` slice here. It remains the same slice of strings.
+
+	// This time, Tree gets instantiated with `int` and `string` for Value and Data, respectively.
+	intTree := &generictree.Tree[int, string]{}
+	for i := 0; i < len(keys); i++ {
+		intTree.Insert(keys[i], data[i])
+	}
+
+	fmt.Print("\n*** Tree with int search values and string data ***\n\n")
+	fmt.Print("Sorted values: | ")
+	intTree.Traverse(intTree.Root, func(n *generictree.Node[int, string]) { fmt.Print(n.Value, ": ", n.Data, " | ") })
+	fmt.Println()
+
+	fmt.Println("Pretty print")
+	intTree.PrettyPrint()
+
+	/*
+		### How about creating a search tree of search trees?
+
+		Let's feed a search tree with search trees as payload data. \
+		Because why not?\
+		And because doing this can answer an interesting question: Will the syntax of nested generic type instantiatons become unwieldy?
+	*/
+	// The search values shall be integers.
+	keys = []int{3, 1, 2}
+	// I am lazy here and use the existing "string, string" tree thrice.
+	trees := []*generictree.Tree[string, string]{tree, tree, tree}
+
+	// This is a nested instantiation of generic types. Nice detail: the syntax really remains readable.
+	treeTree := &generictree.Tree[int, *generictree.Tree[string, string]]{}
+	for i := 0; i < len(keys); i++ {
+		treeTree.Insert(keys[i], trees[i])
+	}
+
+	fmt.Print("\n*** Tree with int search values and Tree[string, string] data ***\n\n")
+	fmt.Print("Sorted values: | ")
+	// As with `*Tree` above, `*Node` also needs to get instantiated with concrete types.
+	treeTree.Traverse(treeTree.Root, func(n *generictree.Node[int, *generictree.Tree[string, string]]) {
+		fmt.Print(n.Value, ": ", n.Data, " | ")
+	})
+	fmt.Println()
+
+	fmt.Println("Pretty print:")
+	treeTree.PrettyPrint()
+
+	var val string
+	subtree, found := treeTree.Find(2)
+	if found {
+		val, found = subtree.Find("b")
+	}
+	fmt.Printf("Find \"s\" in subtree 2: %v (found: %t)\n", val, found)
+
+}
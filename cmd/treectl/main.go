@@ -0,0 +1,203 @@
+// Command treectl inspects trees that were saved as JSON with
+// Tree.MarshalJSON, without writing a Go program for each question.
+//
+// Usage:
+//
+//	treectl [-keys string|int|float] get FILE KEY
+//	treectl [-keys string|int|float] range FILE LO HI
+//	treectl [-keys string|int|float] stats FILE
+//	treectl [-keys string|int|float] validate FILE
+//	treectl [-keys string|int|float] dot FILE
+//	treectl [-keys string|int|float] dotpath FILE KEY
+//	treectl [-keys string|int|float] diff FILE1 FILE2
+//
+// FILE may be "-" to read from stdin. The -keys flag selects the type of
+// the values that the tree is ordered by; the default is string. Data is
+// passed through as raw JSON.
+//
+// range prints the entries with LO <= value < HI. dotpath writes the DOT
+// graph like dot and highlights the search path of KEY. diff prints added (+),
+// removed (-), and updated (~) entries and, like diff(1), exits with
+// status 1 if the trees differ.
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/appliedgo/generictree"
+)
+
+// errDiffer is returned by the diff command if the trees differ.
+var errDiffer = errors.New("trees differ")
+
+// commands maps each command name to its number of arguments.
+var commands = map[string]int{
+	"get":      2,
+	"range":    3,
+	"stats":    1,
+	"validate": 1,
+	"dot":      1,
+	"dotpath":  2,
+	"diff":     2,
+}
+
+func main() {
+	keys := flag.String("keys", "string", "type of the tree `values`: string, int, or float")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: treectl [-keys string|int|float] get|range|stats|validate|dot|dotpath|diff FILE [ARGS...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	err := run(*keys, flag.Args(), os.Stdin, os.Stdout)
+	switch {
+	case errors.Is(err, errDiffer):
+		os.Exit(1)
+	case errors.Is(err, flag.ErrHelp):
+		flag.Usage()
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "treectl:", err)
+		os.Exit(2)
+	}
+}
+
+// run executes the command in args with values of the given key type.
+func run(keys string, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || commands[args[0]] != len(args)-1 {
+		return flag.ErrHelp
+	}
+	switch keys {
+	case "string":
+		return command(args, stdin, stdout, func(s string) (string, error) { return s, nil })
+	case "int":
+		return command(args, stdin, stdout, func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+	case "float":
+		return command(args, stdin, stdout, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+	}
+	return fmt.Errorf("unknown key type %q", keys)
+}
+
+type tree[K cmp.Ordered] = generictree.Tree[K, json.RawMessage]
+
+func command[K cmp.Ordered](args []string, stdin io.Reader, stdout io.Writer, parse func(string) (K, error)) error {
+	t, err := load[K](args[1], stdin)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "get":
+		k, err := parse(args[2])
+		if err != nil {
+			return err
+		}
+		data, ok := t.Find(k)
+		if !ok {
+			return fmt.Errorf("%v: %w", k, generictree.ErrKeyNotFound)
+		}
+		_, err = fmt.Fprintf(stdout, "%s\n", data)
+		return err
+
+	case "range":
+		lo, err := parse(args[2])
+		if err != nil {
+			return err
+		}
+		hi, err := parse(args[3])
+		if err != nil {
+			return err
+		}
+		t.Range(lo, hi, func(k K, data json.RawMessage) bool {
+			_, err = fmt.Fprintf(stdout, "%v\t%s\n", k, data)
+			return err == nil
+		})
+		return err
+
+	case "stats":
+		s := t.Stats()
+		_, err := fmt.Fprintf(stdout, "len\t%d\nheight\t%d\noptimal height\t%d\nleaves\t%d\navg depth\t%.2f\nsackin\t%d\ncolless\t%d\n",
+			s.Len, s.Height, s.OptimalHeight, s.Leaves, s.AvgDepth, s.Sackin, s.Colless)
+		return err
+
+	case "validate":
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(stdout, "ok")
+		return err
+
+	case "dot":
+		return t.WriteDOT(stdout)
+
+	case "dotpath":
+		k, err := parse(args[2])
+		if err != nil {
+			return err
+		}
+		return t.WriteDOTPath(stdout, k)
+
+	case "diff":
+		other, err := load[K](args[2], stdin)
+		if err != nil {
+			return err
+		}
+		return diff(t, other, stdout)
+	}
+	return flag.ErrHelp
+}
+
+// load reads a tree from the JSON file at path, or from stdin if path is "-".
+func load[K cmp.Ordered](path string, stdin io.Reader) (*tree[K], error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = io.ReadAll(stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &tree[K]{}
+	if err := t.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+func diff[K cmp.Ordered](a, b *tree[K], stdout io.Writer) error {
+	ch := generictree.Diff(a, b, equalJSON)
+	for _, c := range []struct {
+		mark    string
+		entries []generictree.KV[K, json.RawMessage]
+	}{{"-", ch.Removed}, {"+", ch.Added}, {"~", ch.Updated}} {
+		for _, e := range c.entries {
+			if _, err := fmt.Fprintf(stdout, "%s %v\t%s\n", c.mark, e.Value, e.Data); err != nil {
+				return err
+			}
+		}
+	}
+	if !ch.Empty() {
+		return errDiffer
+	}
+	return nil
+}
+
+// equalJSON reports whether x and y are equal, ignoring insignificant
+// whitespace.
+func equalJSON(x, y json.RawMessage) bool {
+	var bx, by bytes.Buffer
+	if json.Compact(&bx, x) != nil || json.Compact(&by, y) != nil {
+		return bytes.Equal(x, y)
+	}
+	return bytes.Equal(bx.Bytes(), by.Bytes())
+}
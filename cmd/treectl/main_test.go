@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	os.WriteFile(a, []byte(`[{"value":1,"data":"one"},{"value":2,"data":{"n": 2}},{"value":10,"data":null}]`), 0o644)
+	os.WriteFile(b, []byte(`[{"value":2,"data":{"n":2}},{"value":10,"data":true}]`), 0o644)
+
+	tests := []struct {
+		keys string
+		args []string
+		want string
+		err  bool
+	}{
+		{"int", []string{"get", a, "2"}, "{\"n\": 2}\n", false},
+		{"int", []string{"range", a, "1", "10"}, "1\t\"one\"\n2\t{\"n\": 2}\n", false},
+		{"int", []string{"validate", a}, "ok\n", false},
+		{"int", []string{"diff", a, b}, "- 1\t\"one\"\n~ 10\ttrue\n", true},
+		{"int", []string{"diff", b, b}, "", false},
+		// Ordered as strings, "10" comes before "2".
+		{"string", []string{"validate", a}, "", true},
+		{"int", []string{"get", a}, "", true},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		err := run(tt.keys, tt.args, strings.NewReader(""), &out)
+		if (err != nil) != tt.err {
+			t.Errorf("%s %v: got error %v, want %v", tt.keys, tt.args[0], err, tt.err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s %v: got %q, want %q", tt.keys, tt.args[0], out.String(), tt.want)
+		}
+	}
+
+	if err := run("int", []string{"diff", a, b}, nil, &strings.Builder{}); !errors.Is(err, errDiffer) {
+		t.Errorf("diff of different trees: got error %v, want errDiffer", err)
+	}
+}
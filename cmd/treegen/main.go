@@ -0,0 +1,145 @@
+// Command treegen writes the code of a non-generic AVL tree for one pair of
+// value and data types. The generated tree behaves like
+// generictree.Tree[K, V] with the default options, but it compares values
+// with the operators of K and stores data of type V directly, without the
+// dictionaries and function values that the generic tree passes around.
+// Use it only where profiles show that this makes a difference; the
+// generated tree has none of the options, hooks, and methods beyond the
+// basic ones.
+//
+// Usage:
+//
+//	treegen -key K -value V -type NAME [-import PATHS] [-package NAME] [-o FILE]
+//
+// K must be one of Go's built-in ordered types: an integer, floating-point,
+// or string type. V may be any type; -import lists the comma-separated
+// import paths that it needs. The generated type NAME has the methods
+// Insert, Find, Contains, Delete, Min, Max, Floor, Ceiling, Len, and All.
+//
+// treegen is meant to be run by go generate, which sets the package name:
+//
+//	//go:generate go run github.com/appliedgo/generictree/cmd/treegen -key int64 -value uint64 -type Int64Tree
+//
+// The output file defaults to the lowercase NAME followed by "_gen.go";
+// "-" writes to stdout.
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed tree.go.tmpl
+var source string
+
+var tmpl = template.Must(template.New("tree").Parse(source))
+
+// ordered lists the key types that treegen supports.
+var ordered = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true,
+	"string": true, "byte": true, "rune": true,
+}
+
+// params are the parameters of the template.
+type params struct {
+	Package string
+	Imports []string
+	Type    string // the name of the tree type
+	Node    string // the name of the node type
+	Key     string
+	Value   string
+	Float   bool // whether Key is a floating-point type
+}
+
+func main() {
+	err := run(os.Args[1:], os.Getenv("GOPACKAGE"), os.Stdout)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "treegen:", err)
+		os.Exit(2)
+	}
+}
+
+// run generates the tree that args describe. pkg is the default package
+// name.
+func run(args []string, pkg string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("treegen", flag.ContinueOnError)
+	key := fs.String("key", "", "the `type` of the values; a built-in ordered type")
+	value := fs.String("value", "", "the `type` of the data")
+	name := fs.String("type", "", "the `name` of the generated tree type")
+	imports := fs.String("import", "", "comma-separated import `paths` that the data type needs")
+	fs.StringVar(&pkg, "package", pkg, "the `name` of the package of the generated code")
+	out := fs.String("o", "", "the output `file`; default: the lowercase type name + \"_gen.go\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case fs.NArg() > 0:
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	case !ordered[*key]:
+		return fmt.Errorf("-key %q is not a built-in ordered type", *key)
+	case *value == "":
+		return errors.New("-value is missing")
+	case !token.IsIdentifier(*name):
+		return fmt.Errorf("-type %q is not an identifier", *name)
+	case !token.IsIdentifier(pkg):
+		return errors.New("-package is missing; run treegen with go generate or set -package")
+	}
+	p := params{
+		Package: pkg,
+		Type:    *name,
+		Node:    lowerFirst(*name) + "Node",
+		Key:     *key,
+		Value:   *value,
+		Float:   strings.HasPrefix(*key, "float"),
+	}
+	if *imports != "" {
+		p.Imports = strings.Split(*imports, ",")
+	}
+
+	code, err := generate(p)
+	if err != nil {
+		return err
+	}
+	switch *out {
+	case "-":
+		_, err = stdout.Write(code)
+		return err
+	case "":
+		*out = strings.ToLower(*name) + "_gen.go"
+	}
+	return os.WriteFile(*out, code, 0o644)
+}
+
+// generate returns the formatted code of the tree.
+func generate(p params) ([]byte, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, p); err != nil {
+		return nil, err
+	}
+	code, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code (check -value and -import): %w", err)
+	}
+	return code, nil
+}
+
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/appliedgo/generictree"
+)
+
+//go:generate go run . -key int64 -value uint64 -type int64Tree -o int64tree_gen_test.go
+//go:generate go run . -key float64 -value time.Duration -import time -type float64Tree -o float64tree_gen_test.go
+
+// TestGenerated checks that the generated files are up to date.
+func TestGenerated(t *testing.T) {
+	for file, args := range map[string][]string{
+		"int64tree_gen_test.go":   {"-key", "int64", "-value", "uint64", "-type", "int64Tree"},
+		"float64tree_gen_test.go": {"-key", "float64", "-value", "time.Duration", "-import", "time", "-type", "float64Tree"},
+	} {
+		var out bytes.Buffer
+		if err := run(append(args, "-o", "-"), "main", &out); err != nil {
+			t.Fatal(err)
+		}
+		want, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Errorf("%s is out of date; run go generate", file)
+		}
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-key", "[]byte", "-value", "int", "-type", "T"},
+		{"-key", "int", "-type", "T"},
+		{"-key", "int", "-value", "int", "-type", "1T"},
+		{"-key", "int", "-value", "map[", "-type", "T", "-o", "-"},
+		{"-key", "int", "-value", "int", "-type", "T", "extra"},
+	} {
+		if err := run(args, "p", &bytes.Buffer{}); err == nil {
+			t.Errorf("run(%q) succeeded", args)
+		}
+	}
+	if err := run([]string{"-key", "int", "-value", "int", "-type", "T"}, "", &bytes.Buffer{}); err == nil {
+		t.Error("run without a package succeeded")
+	}
+}
+
+// monomorphic is the method set of the generated trees.
+type monomorphic[K, V any] interface {
+	Insert(K, V)
+	Find(K) (V, bool)
+	Contains(K) bool
+	Delete(K) (V, bool)
+	Min() (K, V, bool)
+	Max() (K, V, bool)
+	Floor(K) (K, V, bool)
+	Ceiling(K) (K, V, bool)
+	Len() int
+}
+
+// checkEquivalent applies random operations to m and to a generic tree and
+// fails if their results differ.
+func checkEquivalent[K cmp.Ordered, V comparable](t *testing.T, m monomorphic[K, V], all iter.Seq2[K, V], key func(*rand.Rand) K, data func(int) V) {
+	t.Helper()
+	r := rand.New(rand.NewPCG(1, 2))
+	g := generictree.New[K, V]()
+	type result struct {
+		k  K
+		v  V
+		ok bool
+	}
+	same := func(a, b result) bool {
+		return cmp.Compare(a.k, b.k) == 0 && a.v == b.v && a.ok == b.ok
+	}
+	for i := range 20000 {
+		k := key(r)
+		var got, want result
+		switch r.IntN(10) {
+		case 0, 1, 2, 3:
+			m.Insert(k, data(i))
+			g.Insert(k, data(i))
+		case 4, 5:
+			got.v, got.ok = m.Delete(k)
+			want.v, want.ok = g.Delete(k)
+		case 6:
+			got.v, got.ok = m.Find(k)
+			want.v, want.ok = g.Find(k)
+			if m.Contains(k) != want.ok {
+				t.Fatalf("step %d: Contains(%v) = %v", i, k, !want.ok)
+			}
+		case 7:
+			got.k, got.v, got.ok = m.Floor(k)
+			want.k, want.v, want.ok = g.Floor(k)
+		case 8:
+			got.k, got.v, got.ok = m.Ceiling(k)
+			want.k, want.v, want.ok = g.Ceiling(k)
+		case 9:
+			got.k, got.v, got.ok = m.Min()
+			want.k, want.v, want.ok = g.Min()
+			if same(got, want) {
+				got.k, got.v, got.ok = m.Max()
+				want.k, want.v, want.ok = g.Max()
+			}
+		}
+		if !same(got, want) {
+			t.Fatalf("step %d, operation on %v: got %+v, want %+v", i, k, got, want)
+		}
+		if m.Len() != g.Len() {
+			t.Fatalf("step %d: Len() = %d, want %d", i, m.Len(), g.Len())
+		}
+	}
+	if got, want := keys(all), keys(g.All()); !slices.EqualFunc(got, want, func(a, b K) bool { return cmp.Compare(a, b) == 0 }) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
+
+func keys[K, V any](seq iter.Seq2[K, V]) []K {
+	var ks []K
+	for k := range seq {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// checkHeight fails if a tree of n entries has height h, which is more
+// than an AVL tree can have.
+func checkHeight(t *testing.T, h, n int) {
+	t.Helper()
+	if max := 1.45 * math.Log2(float64(n+2)); float64(h) > max {
+		t.Errorf("height %d for %d entries, want at most %.1f", h, n, max)
+	}
+}
+
+func TestInt64Tree(t *testing.T) {
+	var m int64Tree
+	checkEquivalent(t, &m, m.All(),
+		func(r *rand.Rand) int64 { return r.Int64N(500) - 250 },
+		func(i int) uint64 { return uint64(i) })
+	checkHeight(t, m.root.h(), m.Len())
+}
+
+func TestFloat64Tree(t *testing.T) {
+	var m float64Tree
+	checkEquivalent(t, &m, m.All(),
+		func(r *rand.Rand) float64 {
+			if r.IntN(100) == 0 {
+				return math.NaN()
+			}
+			return float64(r.IntN(500)) / 4
+		},
+		func(i int) time.Duration { return time.Duration(i) })
+	checkHeight(t, m.root.h(), m.Len())
+}
+
+func BenchmarkFind(b *testing.B) {
+	var m int64Tree
+	g := generictree.New[int64, uint64]()
+	for i := range int64(1 << 16) {
+		m.Insert(i*7%(1<<16), uint64(i))
+		g.Insert(i*7%(1<<16), uint64(i))
+	}
+	b.Run("generated", func(b *testing.B) {
+		for i := range b.N {
+			m.Find(int64(i) & (1<<16 - 1))
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		for i := range b.N {
+			g.Find(int64(i) & (1<<16 - 1))
+		}
+	})
+}
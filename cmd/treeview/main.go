@@ -0,0 +1,183 @@
+// Command treeview explores a tree in the terminal. The tree can be a file
+// saved with Tree.MarshalJSON or a live tree served by package treehttp:
+//
+//	treeview [-keys string|int|float] FILE
+//	treeview http://localhost:8080/debug/tree
+//
+// The tree is shown as an outline with collapsible subtrees. Use the arrow
+// keys (or h, j, k, l) to move, open, and close nodes, e and c to open and
+// close whole subtrees, / to search for a value and open its search path,
+// r to reload the tree, and q to quit.
+//
+// The -keys flag selects the type of the values that a saved tree is
+// ordered by; the default is string. treeview needs a Unix terminal and
+// the stty command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+func main() {
+	keys := flag.String("keys", "string", "type of the tree `values` in FILE: string, int, or float")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: treeview [-keys string|int|float] FILE|URL")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	src, err := newSource(flag.Arg(0), *keys)
+	if err == nil {
+		err = run(src)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "treeview:", err)
+		os.Exit(1)
+	}
+}
+
+func newSource(arg, keys string) (source, error) {
+	if strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
+		return &live{base: arg}, nil
+	}
+	switch keys {
+	case "string":
+		return &snapshot[string]{path: arg, parse: func(s string) (string, error) { return s, nil }}, nil
+	case "int":
+		return &snapshot[int64]{path: arg, parse: func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }}, nil
+	case "float":
+		return &snapshot[float64]{path: arg, parse: func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }}, nil
+	}
+	return nil, fmt.Errorf("unknown key type %q", keys)
+}
+
+func run(src source) error {
+	root, err := src.load()
+	if err != nil {
+		return err
+	}
+	m := newModel(root, src.search)
+
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	defer fmt.Print("\x1b[H\x1b[2J")
+
+	buf := make([]byte, 64)
+	for {
+		width, height := termSize()
+		m.render(os.Stdout, width, height)
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			if k == "ctrl-c" {
+				return nil
+			}
+			if k == "r" && !m.searching {
+				root, err := src.load()
+				if err != nil {
+					m.status = err.Error()
+					continue
+				}
+				m = newModel(root, src.search)
+				m.status = "reloaded"
+				continue
+			}
+			if m.key(k, height-2) {
+				return nil
+			}
+		}
+	}
+}
+
+// escapes maps terminal escape sequences to key names.
+var escapes = map[string]string{
+	"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+	"\x1b[H": "home", "\x1b[F": "end", "\x1b[1~": "home", "\x1b[4~": "end",
+	"\x1b[5~": "pgup", "\x1b[6~": "pgdown",
+}
+
+// parseKeys splits the bytes read from the terminal into key names.
+func parseKeys(b []byte) []string {
+	var keys []string
+	s := string(b)
+	for s != "" {
+		if s[0] == 0x1b {
+			k, rest := "esc", s[1:]
+			for seq, name := range escapes {
+				if strings.HasPrefix(s, seq) {
+					k, rest = name, s[len(seq):]
+					break
+				}
+			}
+			keys = append(keys, k)
+			s = rest
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		default:
+			keys = append(keys, s[:size])
+		}
+		s = s[size:]
+	}
+	return keys
+}
+
+// rawMode switches the terminal to raw mode and returns a function that
+// restores the previous mode.
+func rawMode() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read terminal mode: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("cannot switch to raw mode: %v", err)
+	}
+	fmt.Print("\x1b[?25l") // hide the cursor
+	return func() {
+		fmt.Print("\x1b[?25h")
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+// termSize returns the size of the terminal, or 80×24 if it is unknown.
+func termSize() (width, height int) {
+	out, err := stty("size")
+	if err == nil {
+		if _, err := fmt.Sscan(out, &height, &width); err == nil {
+			return width, height
+		}
+	}
+	return 80, 24
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// node is a node of the tree being explored, as encoded by
+// generictree.Shape.
+type node struct {
+	Value   jsonValue `json:"value"`
+	Data    jsonValue `json:"data"`
+	Height  int       `json:"height"`
+	Size    int       `json:"size"`
+	Balance int       `json:"balance"`
+	Left    *node     `json:"left"`
+	Right   *node     `json:"right"`
+
+	open bool // children are shown
+}
+
+// jsonValue is a raw JSON value that prints without surrounding quotes
+// for strings.
+type jsonValue []byte
+
+func (v *jsonValue) UnmarshalJSON(b []byte) error {
+	*v = bytes.Clone(b)
+	return nil
+}
+
+func (v jsonValue) String() string {
+	if len(v) > 1 && v[0] == '"' {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			return s
+		}
+	}
+	return string(v)
+}
+
+// row is a visible line of the outline.
+type row struct {
+	n     *node
+	depth int
+	side  string // "L" or "R" for children, empty for the root
+}
+
+// model is the state of the explorer, independent of the terminal.
+type model struct {
+	root   *node
+	rows   []row
+	cursor int // index of the selected row
+	top    int // index of the first row on screen
+	status string
+
+	searching bool   // the search prompt is open
+	query     string // text typed into the search prompt
+
+	// search returns the values on the search path of query, encoded
+	// as JSON, from the root to the last visited node.
+	search func(query string) (path []string, found bool, err error)
+}
+
+func newModel(root *node, search func(string) ([]string, bool, error)) *model {
+	m := &model{root: root, search: search}
+	if root != nil {
+		root.open = true
+	}
+	m.refresh()
+	return m
+}
+
+// refresh recomputes the visible rows.
+func (m *model) refresh() {
+	m.rows = m.rows[:0]
+	var walk func(n *node, depth int, side string)
+	walk = func(n *node, depth int, side string) {
+		if n == nil {
+			return
+		}
+		m.rows = append(m.rows, row{n, depth, side})
+		if n.open {
+			walk(n.Left, depth+1, "L")
+			walk(n.Right, depth+1, "R")
+		}
+	}
+	walk(m.root, 0, "")
+	m.cursor = min(max(m.cursor, 0), max(len(m.rows)-1, 0))
+}
+
+// key handles a key press and reports whether the explorer should quit.
+// Keys are single characters or the names "up", "down", "left", "right",
+// "enter", "backspace", "esc", "home", "end", "pgup", and "pgdown".
+func (m *model) key(k string, pageSize int) (quit bool) {
+	if m.searching {
+		switch k {
+		case "enter":
+			m.searching = false
+			m.find(m.query)
+		case "esc":
+			m.searching = false
+		case "backspace":
+			if m.query != "" {
+				_, size := utf8.DecodeLastRuneInString(m.query)
+				m.query = m.query[:len(m.query)-size]
+			}
+		default:
+			if utf8.RuneCountInString(k) == 1 {
+				m.query += k
+			}
+		}
+		return false
+	}
+
+	m.status = ""
+	if len(m.rows) == 0 {
+		return k == "q"
+	}
+	cur := m.rows[m.cursor]
+	switch k {
+	case "q":
+		return true
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "pgup":
+		m.cursor -= pageSize
+	case "pgdown":
+		m.cursor += pageSize
+	case "home":
+		m.cursor = 0
+	case "end":
+		m.cursor = len(m.rows) - 1
+	case "right", "l":
+		if !cur.n.open && (cur.n.Left != nil || cur.n.Right != nil) {
+			cur.n.open = true
+		} else if cur.n.open && m.cursor+1 < len(m.rows) && m.rows[m.cursor+1].depth > cur.depth {
+			m.cursor++
+		}
+	case "left", "h":
+		if cur.n.open {
+			cur.n.open = false
+		} else {
+			// Go to the parent.
+			for i := m.cursor - 1; i >= 0; i-- {
+				if m.rows[i].depth < cur.depth {
+					m.cursor = i
+					break
+				}
+			}
+		}
+	case "enter", " ":
+		cur.n.open = !cur.n.open
+	case "e":
+		setOpen(cur.n, true)
+	case "c":
+		setOpen(cur.n, false)
+	case "/":
+		m.searching, m.query = true, ""
+	}
+	m.refresh()
+	return false
+}
+
+// setOpen opens or closes n and all of its descendants.
+func setOpen(n *node, open bool) {
+	if n == nil {
+		return
+	}
+	n.open = open
+	setOpen(n.Left, open)
+	setOpen(n.Right, open)
+}
+
+// find opens the search path of query and selects its last node.
+func (m *model) find(query string) {
+	path, found, err := m.search(query)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	var target *node
+	n := m.root
+	for i, v := range path {
+		if n == nil || !jsonEqual(n.Value, v) {
+			break
+		}
+		target = n
+		if i+1 == len(path) {
+			break
+		}
+		n.open = true
+		if n.Left != nil && jsonEqual(n.Left.Value, path[i+1]) {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	m.refresh()
+	for i, r := range m.rows {
+		if r.n == target {
+			m.cursor = i
+		}
+	}
+	if found {
+		m.status = fmt.Sprintf("%s: found after %d comparisons", query, len(path))
+	} else {
+		m.status = fmt.Sprintf("%s: not found after %d comparisons", query, len(path))
+	}
+}
+
+func jsonEqual(v jsonValue, s string) bool {
+	return string(v) == s
+}
+
+// render writes the screen: a header, the visible rows, and a status line.
+func (m *model) render(w io.Writer, width, height int) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	header := "treeview  ↑↓ move  → open  ← close  e/c open/close all  / search  r reload  q quit"
+	fmt.Fprintf(&b, "\x1b[7m%s\x1b[0m\r\n", clip(header, width))
+
+	lines := max(height-2, 1)
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+lines {
+		m.top = m.cursor - lines + 1
+	}
+	for i := m.top; i < min(m.top+lines, len(m.rows)); i++ {
+		line := clip(m.line(m.rows[i]), width)
+		if i == m.cursor {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		b.WriteString(line + "\r\n")
+	}
+	for i := len(m.rows) - m.top; i < lines; i++ {
+		b.WriteString("\r\n")
+	}
+	switch {
+	case m.searching:
+		b.WriteString("/" + m.query)
+	case m.status != "":
+		b.WriteString(clip(m.status, width))
+	case len(m.rows) == 0:
+		b.WriteString("empty tree")
+	}
+	io.WriteString(w, b.String())
+}
+
+func (m *model) line(r row) string {
+	marker := "·"
+	if r.n.Left != nil || r.n.Right != nil {
+		marker = "▸"
+		if r.n.open {
+			marker = "▾"
+		}
+	} else if r.n.Size > 1 {
+		marker = "…" // children were not loaded
+	}
+	side := ""
+	if r.side != "" {
+		side = r.side + " "
+	}
+	return fmt.Sprintf("%s%s %s%v  [h=%d n=%d b=%+d]  %v",
+		strings.Repeat("  ", r.depth), marker, side, r.n.Value, r.n.Height, r.n.Size, r.n.Balance, r.n.Data)
+}
+
+// clip shortens s to width runes.
+func clip(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:max(width-1, 0)]) + "…"
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+	"github.com/appliedgo/generictree/treehttp"
+)
+
+// testSource saves a tree of the values 1 to 15 and returns it as a
+// snapshot source.
+func testSource(t *testing.T) *snapshot[int64] {
+	t.Helper()
+	tree := generictree.New[int64, json.RawMessage]()
+	for i := int64(1); i <= 15; i++ {
+		tree.Insert(i, json.RawMessage(`"d"`))
+	}
+	b, err := tree.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tree.json")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := newSource(path, "int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src.(*snapshot[int64])
+}
+
+func testModel(t *testing.T) *model {
+	t.Helper()
+	src := testSource(t)
+	root, err := src.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newModel(root, src.search)
+}
+
+func (m *model) values() string {
+	var s []string
+	for _, r := range m.rows {
+		s = append(s, r.n.Value.String())
+	}
+	return strings.Join(s, " ")
+}
+
+func TestModelNavigation(t *testing.T) {
+	m := testModel(t)
+	if got, want := m.values(), "8 4 12"; got != want {
+		t.Fatalf("initial rows = %q, want %q", got, want)
+	}
+	m.key("down", 10)
+	m.key("right", 10) // open 4
+	if got, want := m.values(), "8 4 2 6 12"; got != want {
+		t.Fatalf("after opening 4: rows = %q, want %q", got, want)
+	}
+	m.key("right", 10) // move to 2
+	if got := m.rows[m.cursor].n.Value.String(); got != "2" {
+		t.Fatalf("selected %s, want 2", got)
+	}
+	m.key("left", 10) // go to parent 4
+	m.key("left", 10) // close 4
+	if got, want := m.values(), "8 4 12"; got != want {
+		t.Fatalf("after closing 4: rows = %q, want %q", got, want)
+	}
+	m.key("home", 10)
+	m.key("e", 10)
+	if len(m.rows) != 15 {
+		t.Fatalf("after opening all: %d rows, want 15", len(m.rows))
+	}
+	m.key("end", 10)
+	if got := m.rows[m.cursor].n.Value.String(); got != "15" {
+		t.Fatalf("end selected %s, want 15", got)
+	}
+	m.key("pgdown", 10)
+	if m.cursor != len(m.rows)-1 {
+		t.Fatalf("cursor %d moved past the last row", m.cursor)
+	}
+	m.key("home", 10)
+	m.key("c", 10)
+	if got, want := m.values(), "8"; got != want {
+		t.Fatalf("after closing all: rows = %q, want %q", got, want)
+	}
+	if !m.key("q", 10) {
+		t.Fatal("q does not quit")
+	}
+}
+
+func TestModelSearch(t *testing.T) {
+	for _, tt := range []struct {
+		query, selected, status string
+	}{
+		{"11", "11", "11: found after 4 comparisons"},
+		{"16", "15", "16: not found after 4 comparisons"},
+		{"x", "8", `strconv.ParseInt: parsing "x": invalid syntax`},
+	} {
+		m := testModel(t)
+		m.key("/", 10)
+		for _, r := range tt.query + "9" {
+			m.key(string(r), 10)
+		}
+		m.key("backspace", 10)
+		m.key("enter", 10)
+		if got := m.rows[m.cursor].n.Value.String(); got != tt.selected {
+			t.Errorf("search %s: selected %s, want %s", tt.query, got, tt.selected)
+		}
+		if m.status != tt.status {
+			t.Errorf("search %s: status %q, want %q", tt.query, m.status, tt.status)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := testModel(t)
+	var b strings.Builder
+	m.render(&b, 40, 10)
+	for _, want := range []string{"8", "4", "12"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("screen does not show %s:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := strings.Join(parseKeys([]byte("\x1b[Aj\x1b[6~\x1b\r\x7fä/")), ",")
+	if want := "up,j,pgdown,esc,enter,backspace,ä,/"; got != want {
+		t.Errorf("parseKeys = %s, want %s", got, want)
+	}
+}
+
+func TestLive(t *testing.T) {
+	tree := generictree.New[int, string]()
+	for i := 1; i <= 15; i++ {
+		tree.Insert(i, "d")
+	}
+	srv := httptest.NewServer(treehttp.Handler(tree, treehttp.WithDepth(0)))
+	defer srv.Close()
+
+	src, err := newSource(srv.URL+"/", "string")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := src.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(root, src.search)
+	m.key("/", 10)
+	m.key("1", 10)
+	m.key("4", 10)
+	m.key("enter", 10)
+	if got := m.rows[m.cursor].n.Value.String(); got != "14" {
+		t.Errorf("selected %s, want 14", got)
+	}
+	if want := "14: found after 3 comparisons"; m.status != want {
+		t.Errorf("status %q, want %q", m.status, want)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/appliedgo/generictree"
+)
+
+// source provides the tree to explore.
+type source interface {
+	// load returns the whole tree.
+	load() (*node, error)
+	// search returns the JSON-encoded values on the search path of query.
+	search(query string) (path []string, found bool, err error)
+}
+
+// snapshot is a tree saved with Tree.MarshalJSON.
+type snapshot[K cmp.Ordered] struct {
+	path  string
+	parse func(string) (K, error)
+	tree  *generictree.Tree[K, json.RawMessage]
+}
+
+func (s *snapshot[K]) load() (*node, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.tree = &generictree.Tree[K, json.RawMessage]{}
+	if err := s.tree.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return decodeShape(s.tree.Inspect(0))
+}
+
+func (s *snapshot[K]) search(query string) (path []string, found bool, err error) {
+	value, err := s.parse(query)
+	if err != nil {
+		return nil, false, err
+	}
+	for n := s.tree.Inspect(0); n != nil; {
+		b, err := json.Marshal(n.Value)
+		if err != nil {
+			return nil, false, err
+		}
+		path = append(path, string(b))
+		c := s.tree.Compare(value, n.Value)
+		if c == 0 {
+			return path, true, nil
+		}
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return path, false, nil
+}
+
+// decodeShape converts a typed shape into an untyped node tree.
+func decodeShape(shape any) (*node, error) {
+	b, err := json.Marshal(shape)
+	if err != nil {
+		return nil, err
+	}
+	var root *node
+	err = json.Unmarshal(b, &root)
+	return root, err
+}
+
+// live is a tree served by package treehttp.
+type live struct {
+	base string // URL of the handler, without a trailing slash
+}
+
+func (l *live) load() (*node, error) {
+	var root *node
+	err := l.get("/tree", &root)
+	return root, err
+}
+
+func (l *live) search(query string) (path []string, found bool, err error) {
+	var info struct {
+		Found bool              `json:"found"`
+		Path  []json.RawMessage `json:"path"`
+	}
+	if err := l.get("/node?q="+url.QueryEscape(query), &info); err != nil {
+		return nil, false, err
+	}
+	for _, v := range info.Path {
+		path = append(path, string(v))
+	}
+	return path, info.Found, nil
+}
+
+func (l *live) get(path string, v any) error {
+	resp, err := http.Get(strings.TrimSuffix(l.base, "/") + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
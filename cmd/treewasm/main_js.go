@@ -0,0 +1,32 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+func main() {
+	s := newSession()
+	parse := js.Global().Get("JSON").Get("parse")
+	// wrap turns a session method into a JavaScript function that
+	// returns a parsed result object.
+	wrap := func(f func(args []js.Value) string) js.Func {
+		return js.FuncOf(func(_ js.Value, args []js.Value) any {
+			return parse.Invoke(f(args))
+		})
+	}
+	arg := func(args []js.Value, i int) string {
+		if i >= len(args) {
+			return ""
+		}
+		return args[i].String()
+	}
+	js.Global().Set("generictree", js.ValueOf(map[string]any{
+		"insert": wrap(func(a []js.Value) string { return s.insert(arg(a, 0), arg(a, 1)) }),
+		"delete": wrap(func(a []js.Value) string { return s.delete(arg(a, 0)) }),
+		"find":   wrap(func(a []js.Value) string { return s.find(arg(a, 0)) }),
+		"trace":  wrap(func(a []js.Value) string { return s.trace(arg(a, 0)) }),
+		"dump":   wrap(func([]js.Value) string { return s.dump() }),
+		"reset":  wrap(func([]js.Value) string { return s.reset() }),
+	}))
+	select {} // keep the functions alive
+}
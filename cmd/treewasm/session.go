@@ -0,0 +1,118 @@
+// Command treewasm runs a tree in the browser, so that interactive
+// tutorials can animate the real implementation instead of a mockup. Build
+// it with
+//
+//	GOOS=js GOARCH=wasm go build -o tree.wasm ./cmd/treewasm
+//
+// and load tree.wasm with wasm_exec.js from $(go env GOROOT)/lib/wasm.
+// The program sets a global object generictree with these functions:
+//
+//	generictree.insert(value, data)
+//	generictree.delete(value)
+//	generictree.find(value)
+//	generictree.trace(value)
+//	generictree.dump()
+//	generictree.reset()
+//
+// Values and data are strings. Every function returns an object with the
+// search path of the value (the visited node values and the direction
+// taken at each), whether the value was found, its data, the shape of the
+// tree after the operation, and the tree's statistics. The path of insert
+// and delete is the one taken before the tree rebalances, so an animation
+// can first follow the path and then morph into the new shape.
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/appliedgo/generictree"
+)
+
+// session holds the tree that the JavaScript side works on. Values and
+// data are strings, as in the articles.
+type session struct {
+	tree *generictree.Tree[string, string]
+}
+
+func newSession() *session {
+	return &session{tree: generictree.New[string, string]()}
+}
+
+// step is one comparison on the search path of a value.
+type step struct {
+	Value string `json:"value"` // value of the visited node
+	Cmp   int    `json:"cmp"`   // -1: go left, 1: go right, 0: found
+}
+
+// result is the answer to every call, encoded as JSON.
+type result struct {
+	Path  []step                             `json:"path"`
+	Found bool                               `json:"found"`
+	Data  string                             `json:"data,omitempty"`
+	Tree  *generictree.Shape[string, string] `json:"tree"`
+	Stats generictree.Stats                  `json:"stats"`
+}
+
+// path returns the search path of value in the current tree.
+func (s *session) path(value string) (path []step, found bool) {
+	path = []step{}
+	for _, st := range s.tree.FindPath(value) {
+		path = append(path, step{st.Value, int(st.Turn)})
+		found = st.Turn == generictree.TurnFound
+	}
+	return path, found
+}
+
+// answer returns the JSON result for a search path and the current tree.
+func (s *session) answer(path []step, found bool, data string) string {
+	b, err := json.Marshal(result{
+		Path:  path,
+		Found: found,
+		Data:  data,
+		Tree:  s.tree.Inspect(0),
+		Stats: s.tree.Stats(),
+	})
+	if err != nil {
+		panic(err) // strings always encode
+	}
+	return string(b)
+}
+
+// insert inserts value and data. The path is the one the insertion took
+// before any rebalancing.
+func (s *session) insert(value, data string) string {
+	path, found := s.path(value)
+	s.tree.Insert(value, data)
+	return s.answer(path, found, data)
+}
+
+// delete deletes value.
+func (s *session) delete(value string) string {
+	path, _ := s.path(value)
+	data, found := s.tree.Delete(value)
+	return s.answer(path, found, data)
+}
+
+// find looks up value.
+func (s *session) find(value string) string {
+	path, _ := s.path(value)
+	data, found := s.tree.Find(value)
+	return s.answer(path, found, data)
+}
+
+// trace returns the search path of value without its data.
+func (s *session) trace(value string) string {
+	path, found := s.path(value)
+	return s.answer(path, found, "")
+}
+
+// dump returns the current tree.
+func (s *session) dump() string {
+	return s.answer([]step{}, false, "")
+}
+
+// reset empties the tree.
+func (s *session) reset() string {
+	s.tree = generictree.New[string, string]()
+	return s.dump()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decode(t *testing.T, s string) result {
+	t.Helper()
+	var r result
+	if err := json.Unmarshal([]byte(s), &r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestSession(t *testing.T) {
+	s := newSession()
+	for _, v := range []string{"d", "b", "g", "a"} {
+		s.insert(v, v+v)
+	}
+
+	r := decode(t, s.insert("c", "cc"))
+	want := []step{{"d", -1}, {"b", 1}}
+	if len(r.Path) != len(want) || r.Path[0] != want[0] || r.Path[1] != want[1] {
+		t.Errorf("insert path = %v, want %v", r.Path, want)
+	}
+	if r.Found || r.Tree == nil || r.Stats.Len != 5 {
+		t.Errorf("insert = %+v", r)
+	}
+
+	r = decode(t, s.find("c"))
+	if !r.Found || r.Data != "cc" || r.Path[len(r.Path)-1] != (step{"c", 0}) {
+		t.Errorf("find = %+v", r)
+	}
+	r = decode(t, s.trace("c"))
+	if !r.Found || r.Data != "" {
+		t.Errorf("trace = %+v", r)
+	}
+	r = decode(t, s.find("x"))
+	if r.Found || len(r.Path) == 0 {
+		t.Errorf("find missing = %+v", r)
+	}
+
+	r = decode(t, s.delete("d"))
+	if !r.Found || r.Data != "dd" || r.Stats.Len != 4 {
+		t.Errorf("delete = %+v", r)
+	}
+	r = decode(t, s.dump())
+	if r.Tree == nil || r.Tree.Value == "d" {
+		t.Errorf("dump = %+v", r.Tree)
+	}
+	r = decode(t, s.reset())
+	if r.Tree != nil || r.Stats.Len != 0 {
+		t.Errorf("reset = %+v", r)
+	}
+}
@@ -0,0 +1,298 @@
+package generictree
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// A Codec converts values of type T to bytes and back. The persistence
+// layers of the package take the two conversions as functions, so the
+// methods of a codec plug in directly:
+//
+//	c := CodecFor[Data]()
+//	f.Encode(w, c.Encode)
+//	v, err := OpenFrozen[Value](b, c.Decode)
+//	s := NewSpillTree[Value](dir, budget, c.Encode, c.Decode)
+//
+// Tokens (see Token) encode their values with CodecFor as well. The JSON
+// formats of MarshalJSON, WriteSnapshot, and Export do not use codecs:
+// they stay plain JSON that other tools can read, and types customize
+// them with json.Marshaler instead.
+//
+// Decode must not keep b, which may refer to a memory-mapped file.
+type Codec[T any] interface {
+	Encode(v T) ([]byte, error)
+	Decode(b []byte) (T, error)
+}
+
+var codecs sync.Map // reflect.Type -> Codec[T]
+
+// RegisterCodec makes c the codec that CodecFor returns for T, replacing
+// a built-in or previously registered codec. Register codecs during
+// initialization, before trees are encoded with them.
+func RegisterCodec[T any](c Codec[T]) {
+	codecs.Store(reflect.TypeFor[T](), c)
+}
+
+// CodecFor returns the codec for T: the codec registered with
+// RegisterCodec, if any, or else a built-in codec. The built-in codecs
+// handle strings and byte slices as their bytes, booleans as one byte,
+// integers as varints, and floating-point numbers in the 4 or 8 bytes of
+// their IEEE 754 representation. Types that implement
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler with a pointer
+// receiver use these methods. Other types defined on a string, boolean,
+// integer, or floating-point type are encoded like that type. All other
+// types are encoded as JSON.
+func CodecFor[T any]() Codec[T] {
+	if c, ok := codecs.Load(reflect.TypeFor[T]()); ok {
+		return c.(Codec[T])
+	}
+	var c any
+	switch any(*new(T)).(type) {
+	case string:
+		c = stringCodec{}
+	case []byte:
+		c = bytesCodec{}
+	case bool:
+		c = boolCodec{}
+	case int:
+		c = varintCodec[int]{}
+	case int8:
+		c = varintCodec[int8]{}
+	case int16:
+		c = varintCodec[int16]{}
+	case int32:
+		c = varintCodec[int32]{}
+	case int64:
+		c = varintCodec[int64]{}
+	case uint:
+		c = uvarintCodec[uint]{}
+	case uint8:
+		c = uvarintCodec[uint8]{}
+	case uint16:
+		c = uvarintCodec[uint16]{}
+	case uint32:
+		c = uvarintCodec[uint32]{}
+	case uint64:
+		c = uvarintCodec[uint64]{}
+	case uintptr:
+		c = uvarintCodec[uintptr]{}
+	case float32:
+		c = float32Codec{}
+	case float64:
+		c = float64Codec{}
+	default:
+		if _, ok := any(new(T)).(interface {
+			encoding.BinaryMarshaler
+			encoding.BinaryUnmarshaler
+		}); ok {
+			return binaryCodec[T]{}
+		}
+		if basicKind(reflect.TypeFor[T]().Kind()) {
+			return kindCodec[T]{}
+		}
+		return JSONCodec[T]{}
+	}
+	return c.(Codec[T])
+}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode returns the JSON encoding of v.
+func (JSONCodec[T]) Encode(v T) ([]byte, error) { return json.Marshal(v) }
+
+// Decode returns the value that b encodes in JSON.
+func (JSONCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// GobCodec encodes values with encoding/gob. Each value is encoded on its
+// own, including its type information, so GobCodec suits larger values
+// better than small ones.
+type GobCodec[T any] struct{}
+
+// Encode returns the gob encoding of v.
+func (GobCodec[T]) Encode(v T) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+// Decode returns the value that b encodes in gob.
+func (GobCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return v, err
+}
+
+// ErrCodec is wrapped by the errors that the built-in codecs return for
+// bytes that do not encode a value of their type.
+var ErrCodec = errors.New("generictree: invalid encoding of a value")
+
+type stringCodec struct{}
+
+func (stringCodec) Encode(v string) ([]byte, error) { return []byte(v), nil }
+func (stringCodec) Decode(b []byte) (string, error) { return string(b), nil }
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(v []byte) ([]byte, error) { return v, nil }
+func (bytesCodec) Decode(b []byte) ([]byte, error) { return bytes.Clone(b), nil }
+
+type boolCodec struct{}
+
+func (boolCodec) Encode(v bool) ([]byte, error) { return []byte{byte(b2i(v))}, nil }
+
+func (boolCodec) Decode(b []byte) (bool, error) {
+	if len(b) != 1 || b[0] > 1 {
+		return false, fmt.Errorf("%w of bool: % x", ErrCodec, b)
+	}
+	return b[0] == 1, nil
+}
+
+type varintCodec[T ~int | ~int8 | ~int16 | ~int32 | ~int64] struct{}
+
+func (varintCodec[T]) Encode(v T) ([]byte, error) { return binary.AppendVarint(nil, int64(v)), nil }
+
+func (varintCodec[T]) Decode(b []byte) (T, error) {
+	x, n := binary.Varint(b)
+	if n != len(b) || int64(T(x)) != x {
+		return 0, fmt.Errorf("%w of %T: % x", ErrCodec, T(0), b)
+	}
+	return T(x), nil
+}
+
+type uvarintCodec[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr] struct{}
+
+func (uvarintCodec[T]) Encode(v T) ([]byte, error) { return binary.AppendUvarint(nil, uint64(v)), nil }
+
+func (uvarintCodec[T]) Decode(b []byte) (T, error) {
+	x, n := binary.Uvarint(b)
+	if n != len(b) || uint64(T(x)) != x {
+		return 0, fmt.Errorf("%w of %T: % x", ErrCodec, T(0), b)
+	}
+	return T(x), nil
+}
+
+type float32Codec struct{}
+
+func (float32Codec) Encode(v float32) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)), nil
+}
+
+func (float32Codec) Decode(b []byte) (float32, error) {
+	if len(b) != 4 {
+		return 0, fmt.Errorf("%w of float32: % x", ErrCodec, b)
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+}
+
+type float64Codec struct{}
+
+func (float64Codec) Encode(v float64) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)), nil
+}
+
+func (float64Codec) Decode(b []byte) (float64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("%w of float64: % x", ErrCodec, b)
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+}
+
+// binaryCodec uses the encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler methods of *T.
+type binaryCodec[T any] struct{}
+
+func (binaryCodec[T]) Encode(v T) ([]byte, error) {
+	return any(&v).(encoding.BinaryMarshaler).MarshalBinary()
+}
+
+func (binaryCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := any(&v).(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+	return v, err
+}
+
+// basicKind reports whether kindCodec handles types of kind k.
+func basicKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// kindCodec encodes a type defined on a basic type like the basic type.
+type kindCodec[T any] struct{}
+
+func (kindCodec[T]) Encode(v T) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return stringCodec{}.Encode(rv.String())
+	case reflect.Bool:
+		return boolCodec{}.Encode(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return varintCodec[int64]{}.Encode(rv.Int())
+	case reflect.Float32:
+		return float32Codec{}.Encode(float32(rv.Float()))
+	case reflect.Float64:
+		return float64Codec{}.Encode(rv.Float())
+	default:
+		return uvarintCodec[uint64]{}.Encode(rv.Uint())
+	}
+}
+
+func (kindCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	var err error
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(string(b))
+	case reflect.Bool:
+		var x bool
+		x, err = boolCodec{}.Decode(b)
+		rv.SetBool(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var x int64
+		if x, err = (varintCodec[int64]{}).Decode(b); err == nil && rv.OverflowInt(x) {
+			err = fmt.Errorf("%w of %T: % x", ErrCodec, v, b)
+		}
+		rv.SetInt(x)
+	case reflect.Float32:
+		var x float32
+		x, err = float32Codec{}.Decode(b)
+		rv.SetFloat(float64(x))
+	case reflect.Float64:
+		var x float64
+		x, err = float64Codec{}.Decode(b)
+		rv.SetFloat(x)
+	default:
+		var x uint64
+		if x, err = (uvarintCodec[uint64]{}).Decode(b); err == nil && rv.OverflowUint(x) {
+			err = fmt.Errorf("%w of %T: % x", ErrCodec, v, b)
+		}
+		rv.SetUint(x)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
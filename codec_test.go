@@ -0,0 +1,105 @@
+package generictree
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func roundTrip[T any](t *testing.T, c Codec[T], v T, equal func(a, b T) bool) {
+	t.Helper()
+	b, err := c.Encode(v)
+	if err != nil {
+		t.Fatalf("%T: Encode(%v): %v", c, v, err)
+	}
+	got, err := c.Decode(b)
+	if err != nil || !equal(got, v) {
+		t.Errorf("%T: Decode(Encode(%v)) = %v, %v", c, v, got, err)
+	}
+}
+
+func eq[T comparable](a, b T) bool { return a == b }
+
+type point struct{ X, Y int }
+
+type level int8
+
+func TestCodecFor(t *testing.T) {
+	roundTrip(t, CodecFor[string](), "héllo", eq)
+	roundTrip(t, CodecFor[[]byte](), []byte{0, 1, 2}, bytes.Equal)
+	roundTrip(t, CodecFor[bool](), true, eq)
+	roundTrip(t, CodecFor[int](), -12345, eq)
+	roundTrip(t, CodecFor[int8](), math.MinInt8, eq)
+	roundTrip(t, CodecFor[uint64](), math.MaxUint64, eq)
+	roundTrip(t, CodecFor[float32](), 1.5, eq)
+	roundTrip(t, CodecFor[float64](), math.Inf(-1), eq)
+	roundTrip(t, CodecFor[netip.Addr](), netip.MustParseAddr("10.0.0.1"), eq)
+	roundTrip(t, CodecFor[point](), point{1, 2}, eq)
+	roundTrip(t, CodecFor[[]string](), []string{"a", "b"}, slices.Equal)
+	roundTrip(t, GobCodec[point]{}, point{3, 4}, eq)
+	roundTrip(t, CodecFor[celsius](), celsius(math.Inf(1)), eq)
+	roundTrip(t, CodecFor[level](), -3, eq)
+
+	if _, ok := CodecFor[netip.Addr]().(binaryCodec[netip.Addr]); !ok {
+		t.Errorf("netip.Addr uses %T, want its binary methods", CodecFor[netip.Addr]())
+	}
+	if b, _ := CodecFor[int64]().Encode(1); len(b) != 1 {
+		t.Errorf("int64 1 takes %d bytes, want 1", len(b))
+	}
+	for _, err := range []error{
+		func() error { _, err := CodecFor[int8]().Decode([]byte{0x80, 0x02}); return err }(), // 128
+		func() error { _, err := CodecFor[bool]().Decode([]byte{2}); return err }(),
+		func() error { _, err := CodecFor[float64]().Decode([]byte{1, 2}); return err }(),
+		func() error { _, err := CodecFor[level]().Decode([]byte{0x80, 0x02}); return err }(), // 128
+	} {
+		if !errors.Is(err, ErrCodec) {
+			t.Errorf("got error %v, want ErrCodec", err)
+		}
+	}
+
+	// A mapped file must not be referenced after decoding.
+	in := []byte("abc")
+	out, _ := CodecFor[[]byte]().Decode(in)
+	in[0] = 'x'
+	if string(out) != "abc" {
+		t.Error("the []byte codec keeps the input")
+	}
+}
+
+// upperCodec encodes strings in upper case, to tell it from the built-in
+// codec.
+type upperCodec struct{}
+
+func (upperCodec) Encode(v string) ([]byte, error) { return []byte(strings.ToUpper(v)), nil }
+func (upperCodec) Decode(b []byte) (string, error) { return strings.ToLower(string(b)), nil }
+
+type label string
+
+func TestRegisterCodec(t *testing.T) {
+	RegisterCodec[label](GobCodec[label]{})
+	if _, ok := CodecFor[label]().(GobCodec[label]); !ok {
+		t.Errorf("CodecFor[label]() = %T, want the registered codec", CodecFor[label]())
+	}
+
+	tree := New[int, string]()
+	tree.Insert(1, "one")
+	var buf bytes.Buffer
+	c := Codec[string](upperCodec{})
+	if err := tree.Freeze().Encode(&buf, c.Encode); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("ONE")) {
+		t.Error("Encode did not use the codec")
+	}
+	view, err := OpenFrozen[int](buf.Bytes(), c.Decode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok, err := view.Find(1); err != nil || !ok || d != "one" {
+		t.Errorf("Find(1) = %q, %t, %v", d, ok, err)
+	}
+}
@@ -0,0 +1,225 @@
+// Package compat adapts the AVL tree of package generictree to the method
+// set of the Map type of github.com/tidwall/btree, which other ordered map
+// libraries have largely copied. Code written against that API can switch
+// to generictree, for example to compare both in a benchmark, by changing
+// only the type of the map.
+//
+// Only the common methods are provided. Methods that depend on the
+// internals of a B-tree, such as copy-on-write copies, have no equivalent.
+package compat
+
+import (
+	"cmp"
+
+	"github.com/appliedgo/generictree"
+)
+
+// Map is an ordered map from keys of type K to values of type V. The zero
+// Map is empty and ready to use. A Map must not be copied after first use.
+type Map[K cmp.Ordered, V any] struct {
+	tree generictree.Tree[K, V]
+}
+
+// Set stores value for key. It returns the previous value and true if key
+// was already in the map, or the zero value of V and false otherwise.
+func (m *Map[K, V]) Set(key K, value V) (prev V, replaced bool) {
+	prev, replaced = m.tree.Find(key)
+	m.tree.Insert(key, value)
+	return prev, replaced
+}
+
+// Load is Set for keys that arrive in ascending order. The AVL tree has no
+// faster path for sorted input, so Load is the same as Set.
+func (m *Map[K, V]) Load(key K, value V) (prev V, replaced bool) {
+	return m.Set(key, value)
+}
+
+// Get returns the value stored for key and true, or the zero value of V
+// and false if key is not in the map.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	return m.tree.Find(key)
+}
+
+// Delete removes key and returns its value and true, or the zero value of
+// V and false if key is not in the map.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	return m.tree.Delete(key)
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return m.tree.Len()
+}
+
+// Min returns the smallest key and its value. ok is false if the map is
+// empty.
+func (m *Map[K, V]) Min() (key K, value V, ok bool) {
+	return m.tree.Min()
+}
+
+// Max returns the largest key and its value. ok is false if the map is
+// empty.
+func (m *Map[K, V]) Max() (key K, value V, ok bool) {
+	return m.tree.Max()
+}
+
+// PopMin removes the smallest key and returns it with its value. ok is
+// false if the map is empty.
+func (m *Map[K, V]) PopMin() (key K, value V, ok bool) {
+	return m.tree.DeleteMin()
+}
+
+// PopMax removes the largest key and returns it with its value. ok is
+// false if the map is empty.
+func (m *Map[K, V]) PopMax() (key K, value V, ok bool) {
+	return m.tree.DeleteMax()
+}
+
+// GetAt returns the key and value at index i in ascending order. ok is
+// false if i is out of range.
+func (m *Map[K, V]) GetAt(i int) (key K, value V, ok bool) {
+	return m.tree.Select(i)
+}
+
+// DeleteAt removes the key at index i in ascending order and returns it
+// with its value. ok is false if i is out of range.
+func (m *Map[K, V]) DeleteAt(i int) (key K, value V, ok bool) {
+	if key, value, ok = m.tree.Select(i); ok {
+		m.tree.Delete(key)
+	}
+	return key, value, ok
+}
+
+// Ascend calls iter for each key not less than pivot, in ascending order,
+// until iter returns false.
+func (m *Map[K, V]) Ascend(pivot K, iter func(key K, value V) bool) {
+	for k, v := range m.tree.From(pivot) {
+		if !iter(k, v) {
+			return
+		}
+	}
+}
+
+// Descend calls iter for each key not greater than pivot, in descending
+// order, until iter returns false.
+func (m *Map[K, V]) Descend(pivot K, iter func(key K, value V) bool) {
+	c := m.tree.Cursor()
+	ok := c.Seek(pivot)
+	switch {
+	case !ok:
+		ok = c.Last()
+	case c.Value() > pivot:
+		ok = c.Prev()
+	}
+	for ; ok; ok = c.Prev() {
+		if !iter(c.Value(), c.Data()) {
+			return
+		}
+	}
+}
+
+// Scan calls iter for each key in ascending order until iter returns
+// false.
+func (m *Map[K, V]) Scan(iter func(key K, value V) bool) {
+	for k, v := range m.tree.All() {
+		if !iter(k, v) {
+			return
+		}
+	}
+}
+
+// Reverse calls iter for each key in descending order until iter returns
+// false.
+func (m *Map[K, V]) Reverse(iter func(key K, value V) bool) {
+	for k, v := range m.tree.Backward() {
+		if !iter(k, v) {
+			return
+		}
+	}
+}
+
+// Keys returns all keys in ascending order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for k := range m.tree.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values in the ascending order of their keys.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	for _, v := range m.tree.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Clear removes all keys.
+func (m *Map[K, V]) Clear() {
+	m.tree = generictree.Tree[K, V]{}
+}
+
+// Iter returns a new iterator over the map. Like the iterators of package
+// generictree, it becomes invalid when the map is modified.
+func (m *Map[K, V]) Iter() MapIter[K, V] {
+	return MapIter[K, V]{cursor: m.tree.Cursor()}
+}
+
+// MapIter moves through a Map in either direction. An iterator that has
+// not been positioned with First, Last, or Seek starts at the first key on
+// Next and at the last key on Prev.
+type MapIter[K cmp.Ordered, V any] struct {
+	cursor *generictree.Cursor[K, V]
+	seeked bool
+}
+
+// Seek moves the iterator to the first key not less than key. It reports
+// whether there is such a key.
+func (it *MapIter[K, V]) Seek(key K) bool {
+	it.seeked = true
+	return it.cursor.Seek(key)
+}
+
+// First moves the iterator to the smallest key. It reports whether the map
+// has a key.
+func (it *MapIter[K, V]) First() bool {
+	it.seeked = true
+	return it.cursor.First()
+}
+
+// Last moves the iterator to the largest key. It reports whether the map
+// has a key.
+func (it *MapIter[K, V]) Last() bool {
+	it.seeked = true
+	return it.cursor.Last()
+}
+
+// Next moves the iterator to the next key. It reports whether there is
+// one.
+func (it *MapIter[K, V]) Next() bool {
+	if !it.seeked {
+		return it.First()
+	}
+	return it.cursor.Next()
+}
+
+// Prev moves the iterator to the previous key. It reports whether there is
+// one.
+func (it *MapIter[K, V]) Prev() bool {
+	if !it.seeked {
+		return it.Last()
+	}
+	return it.cursor.Prev()
+}
+
+// Key returns the key at the iterator's position.
+func (it *MapIter[K, V]) Key() K {
+	return it.cursor.Value()
+}
+
+// Value returns the value at the iterator's position.
+func (it *MapIter[K, V]) Value() V {
+	return it.cursor.Data()
+}
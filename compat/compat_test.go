@@ -0,0 +1,112 @@
+package compat_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/appliedgo/generictree/compat"
+)
+
+// collect returns the keys that a Scan-like method passes to iter.
+func collect(scan func(iter func(int, string) bool)) []int {
+	var keys []int
+	scan(func(k int, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestMap(t *testing.T) {
+	var m compat.Map[int, string]
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		if _, replaced := m.Set(k, "v"); replaced {
+			t.Fatalf("Set(%d) replaced", k)
+		}
+	}
+	if prev, replaced := m.Set(30, "w"); !replaced || prev != "v" {
+		t.Errorf("Set(30) = %q, %v, want v, true", prev, replaced)
+	}
+	if v, ok := m.Get(30); !ok || v != "w" {
+		t.Errorf("Get(30) = %q, %v", v, ok)
+	}
+	if m.Len() != 5 {
+		t.Errorf("Len = %d, want 5", m.Len())
+	}
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Scan", collect(m.Scan), []int{10, 20, 30, 40, 50}},
+		{"Reverse", collect(m.Reverse), []int{50, 40, 30, 20, 10}},
+		{"Ascend(25)", collect(func(f func(int, string) bool) { m.Ascend(25, f) }), []int{30, 40, 50}},
+		{"Descend(25)", collect(func(f func(int, string) bool) { m.Descend(25, f) }), []int{20, 10}},
+		{"Descend(30)", collect(func(f func(int, string) bool) { m.Descend(30, f) }), []int{30, 20, 10}},
+		{"Descend(99)", collect(func(f func(int, string) bool) { m.Descend(99, f) }), []int{50, 40, 30, 20, 10}},
+		{"Descend(5)", collect(func(f func(int, string) bool) { m.Descend(5, f) }), nil},
+		{"Keys", m.Keys(), []int{10, 20, 30, 40, 50}},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if k, _, ok := m.GetAt(1); !ok || k != 20 {
+		t.Errorf("GetAt(1) = %d, %v", k, ok)
+	}
+	if k, _, ok := m.DeleteAt(1); !ok || k != 20 {
+		t.Errorf("DeleteAt(1) = %d, %v", k, ok)
+	}
+	if k, _, ok := m.PopMin(); !ok || k != 10 {
+		t.Errorf("PopMin = %d, %v", k, ok)
+	}
+	if k, _, ok := m.PopMax(); !ok || k != 50 {
+		t.Errorf("PopMax = %d, %v", k, ok)
+	}
+	if got := m.Keys(); !slices.Equal(got, []int{30, 40}) {
+		t.Errorf("Keys after pops = %v", got)
+	}
+	m.Clear()
+	if _, _, ok := m.PopMin(); ok || m.Len() != 0 {
+		t.Errorf("map not empty after Clear")
+	}
+}
+
+func TestMapIter(t *testing.T) {
+	var m compat.Map[int, string]
+	for k := 1; k <= 5; k++ {
+		m.Set(k*10, "v")
+	}
+	it := m.Iter()
+	var got []int
+	for it.Next() {
+		got = append(got, it.Key())
+	}
+	if !slices.Equal(got, []int{10, 20, 30, 40, 50}) {
+		t.Errorf("Next from start = %v", got)
+	}
+
+	it = m.Iter()
+	got = nil
+	for ok := it.Seek(25); ok; ok = it.Prev() {
+		got = append(got, it.Key())
+	}
+	if !slices.Equal(got, []int{30, 20, 10}) {
+		t.Errorf("Prev from Seek(25) = %v", got)
+	}
+
+	it = m.Iter()
+	if !it.Prev() || it.Key() != 50 || it.Value() != "v" {
+		t.Errorf("Prev from start did not move to the last key")
+	}
+}
+
+func BenchmarkMap_Set(b *testing.B) {
+	var m compat.Map[int, int]
+	for i := range b.N {
+		m.Set(i*7919%b.N, i)
+	}
+}
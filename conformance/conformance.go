@@ -0,0 +1,394 @@
+// Package conformance provides the tests and benchmarks that every
+// generictree.SortedMap implementation must pass. The backends in this
+// module run it, and third-party implementations can run it as well:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func() conformance.Map { return mymap.New[int, string]() })
+//	}
+//
+// If the map has a method Validate() error, the suite calls it between
+// operations to check the map's internal invariants.
+//
+// Equivalent checks that several implementations behave identically: it
+// applies the same operations to all of them and compares their order and
+// their Range results, so that switching backends never changes what a
+// program observes.
+package conformance
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+)
+
+// Map is the type under test.
+type Map = generictree.SortedMap[int, string]
+
+// validator is implemented by maps that can check their internal invariants.
+type validator interface {
+	Validate() error
+}
+
+func validate(t *testing.T, m Map) {
+	t.Helper()
+	if v, ok := m.(validator); ok {
+		if err := v.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Run runs the conformance tests against maps created by newMap.
+// newMap must return a new, empty map on every call.
+func Run(t *testing.T, newMap func() Map) {
+	t.Run("Empty", func(t *testing.T) { testEmpty(t, newMap()) })
+	t.Run("SetGet", func(t *testing.T) { testSetGet(t, newMap()) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newMap()) })
+	t.Run("MinMax", func(t *testing.T) { testMinMax(t, newMap()) })
+	t.Run("Range", func(t *testing.T) { testRange(t, newMap()) })
+	t.Run("Order", func(t *testing.T) { testOrder(t, newMap()) })
+	t.Run("Random", func(t *testing.T) { testRandom(t, newMap()) })
+	t.Run("Allocs", func(t *testing.T) { testAllocs(t, newMap()) })
+}
+
+func testEmpty(t *testing.T, m Map) {
+	if n := m.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+	if v, ok := m.Get(1); ok || v != "" {
+		t.Errorf("Get(1) = %q, %t; want \"\", false", v, ok)
+	}
+	if v, ok := m.Delete(1); ok || v != "" {
+		t.Errorf("Delete(1) = %q, %t; want \"\", false", v, ok)
+	}
+	if _, _, ok := m.Min(); ok {
+		t.Error("Min() on an empty map returned ok = true")
+	}
+	if _, _, ok := m.Max(); ok {
+		t.Error("Max() on an empty map returned ok = true")
+	}
+	m.Range(-100, 100, func(k int, _ string) bool {
+		t.Errorf("Range on an empty map called f(%d)", k)
+		return true
+	})
+}
+
+func testSetGet(t *testing.T, m Map) {
+	keys := rand.New(rand.NewSource(1)).Perm(500)
+	for i, k := range keys {
+		m.Set(k, strconv.Itoa(k))
+		if n := m.Len(); n != i+1 {
+			t.Fatalf("Len() = %d after %d insertions", n, i+1)
+		}
+	}
+	validate(t, m)
+	for _, k := range keys {
+		if v, ok := m.Get(k); !ok || v != strconv.Itoa(k) {
+			t.Errorf("Get(%d) = %q, %t; want %q, true", k, v, ok, strconv.Itoa(k))
+		}
+	}
+	if _, ok := m.Get(500); ok {
+		t.Error("Get(500) found a key that was never set")
+	}
+
+	// Overwriting keeps the number of keys.
+	for _, k := range keys[:100] {
+		m.Set(k, "new")
+	}
+	validate(t, m)
+	if n := m.Len(); n != len(keys) {
+		t.Errorf("Len() = %d after overwriting, want %d", n, len(keys))
+	}
+	for _, k := range keys[:100] {
+		if v, _ := m.Get(k); v != "new" {
+			t.Errorf("Get(%d) = %q after overwriting, want \"new\"", k, v)
+		}
+	}
+}
+
+func testDelete(t *testing.T, m Map) {
+	rnd := rand.New(rand.NewSource(2))
+	keys := rnd.Perm(500)
+	for _, k := range keys {
+		m.Set(k, strconv.Itoa(k))
+	}
+	rnd.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	for i, k := range keys {
+		if v, ok := m.Delete(k); !ok || v != strconv.Itoa(k) {
+			t.Fatalf("Delete(%d) = %q, %t; want %q, true", k, v, ok, strconv.Itoa(k))
+		}
+		if _, ok := m.Delete(k); ok {
+			t.Fatalf("second Delete(%d) returned ok = true", k)
+		}
+		if _, ok := m.Get(k); ok {
+			t.Fatalf("Get(%d) found a deleted key", k)
+		}
+		if n := m.Len(); n != len(keys)-i-1 {
+			t.Fatalf("Len() = %d after %d deletions, want %d", n, i+1, len(keys)-i-1)
+		}
+		if i%50 == 0 {
+			validate(t, m)
+		}
+	}
+	validate(t, m)
+}
+
+func testMinMax(t *testing.T, m Map) {
+	for _, k := range []int{5, 3, 8, 1, 9, 7} {
+		m.Set(k, strconv.Itoa(k))
+	}
+	if k, v, ok := m.Min(); !ok || k != 1 || v != "1" {
+		t.Errorf("Min() = %d, %q, %t; want 1, \"1\", true", k, v, ok)
+	}
+	if k, v, ok := m.Max(); !ok || k != 9 || v != "9" {
+		t.Errorf("Max() = %d, %q, %t; want 9, \"9\", true", k, v, ok)
+	}
+	m.Delete(1)
+	m.Delete(9)
+	if k, _, _ := m.Min(); k != 3 {
+		t.Errorf("Min() = %d after deleting 1, want 3", k)
+	}
+	if k, _, _ := m.Max(); k != 8 {
+		t.Errorf("Max() = %d after deleting 9, want 8", k)
+	}
+}
+
+func testRange(t *testing.T, m Map) {
+	for k := 0; k < 100; k += 2 {
+		m.Set(k, strconv.Itoa(k))
+	}
+	collect := func(lo, hi int) []int {
+		var got []int
+		m.Range(lo, hi, func(k int, v string) bool {
+			if v != strconv.Itoa(k) {
+				t.Errorf("Range passed %d, %q", k, v)
+			}
+			got = append(got, k)
+			return true
+		})
+		return got
+	}
+	for _, c := range []struct {
+		lo, hi int
+		want   []int
+	}{
+		{10, 16, []int{10, 12, 14}},    // lo is inclusive, hi is exclusive
+		{9, 17, []int{10, 12, 14, 16}}, // bounds between keys
+		{-10, 3, []int{0, 2}},
+		{95, 200, []int{96, 98}},
+		{20, 20, nil},
+		{30, 10, nil},
+		{200, 300, nil},
+	} {
+		if got := collect(c.lo, c.hi); !slices.Equal(got, c.want) {
+			t.Errorf("Range(%d, %d) visited %v, want %v", c.lo, c.hi, got, c.want)
+		}
+	}
+
+	// Range stops when f returns false.
+	var got []int
+	m.Range(0, 100, func(k int, _ string) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	if !slices.Equal(got, []int{0, 2, 4}) {
+		t.Errorf("Range did not stop early: visited %v", got)
+	}
+}
+
+// keys returns the keys of m in the interval [lo, hi), in the order in
+// which Range visits them.
+func keys(m Map, lo, hi int) []int {
+	var got []int
+	m.Range(lo, hi, func(k int, _ string) bool {
+		got = append(got, k)
+		return true
+	})
+	return got
+}
+
+// testOrder checks the order and the Range bounds at the extremes of int.
+func testOrder(t *testing.T, m Map) {
+	for _, k := range []int{0, math.MaxInt, -1, math.MinInt, 1, math.MaxInt - 1, math.MinInt + 1} {
+		m.Set(k, strconv.Itoa(k))
+	}
+	all := []int{math.MinInt, math.MinInt + 1, -1, 0, 1, math.MaxInt - 1}
+	for _, c := range []struct {
+		lo, hi int
+		want   []int
+	}{
+		{math.MinInt, math.MaxInt, all}, // hi is exclusive even at the maximum
+		{math.MinInt + 1, 1, all[1:4]},
+		{-1, 0, []int{-1}},
+		{math.MaxInt, math.MaxInt, nil},
+		{math.MaxInt, math.MinInt, nil},
+	} {
+		if got := keys(m, c.lo, c.hi); !slices.Equal(got, c.want) {
+			t.Errorf("Range(%d, %d) visited %v, want %v", c.lo, c.hi, got, c.want)
+		}
+	}
+	if k, _, _ := m.Max(); k != math.MaxInt {
+		t.Errorf("Max() = %d, want %d", k, math.MaxInt)
+	}
+	if k, _, _ := m.Min(); k != math.MinInt {
+		t.Errorf("Min() = %d, want %d", k, math.MinInt)
+	}
+}
+
+// testRandom runs a random sequence of operations against the map and a
+// plain Go map as the model.
+func testRandom(t *testing.T, m Map) {
+	rnd := rand.New(rand.NewSource(3))
+	model := map[int]string{}
+	for i := 0; i < 5000; i++ {
+		k := rnd.Intn(300)
+		switch op := rnd.Intn(10); {
+		case op < 5:
+			v := strconv.Itoa(i)
+			m.Set(k, v)
+			model[k] = v
+		case op < 8:
+			v, ok := m.Delete(k)
+			mv, mok := model[k]
+			if v != mv || ok != mok {
+				t.Fatalf("step %d: Delete(%d) = %q, %t; want %q, %t", i, k, v, ok, mv, mok)
+			}
+			delete(model, k)
+		default:
+			v, ok := m.Get(k)
+			mv, mok := model[k]
+			if v != mv || ok != mok {
+				t.Fatalf("step %d: Get(%d) = %q, %t; want %q, %t", i, k, v, ok, mv, mok)
+			}
+		}
+		if m.Len() != len(model) {
+			t.Fatalf("step %d: Len() = %d, want %d", i, m.Len(), len(model))
+		}
+		if i%500 == 0 {
+			validate(t, m)
+		}
+	}
+	validate(t, m)
+
+	want := make([]int, 0, len(model))
+	for k := range model {
+		want = append(want, k)
+	}
+	slices.Sort(want)
+	var got []int
+	m.Range(0, 300, func(k int, _ string) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slices.Equal(got, want) {
+		t.Errorf("Range returned keys %v, want %v", got, want)
+	}
+}
+
+// testAllocs checks that lookups do not allocate.
+func testAllocs(t *testing.T, m Map) {
+	for k := range 100 {
+		m.Set(k, strconv.Itoa(k))
+	}
+	for name, f := range map[string]func(){
+		"Get": func() { m.Get(50); m.Get(-1) },
+		"Min": func() { m.Min() },
+		"Max": func() { m.Max() },
+	} {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%s: %v allocations, want 0", name, allocs)
+		}
+	}
+}
+
+// Equivalent applies the same random sequence of operations to a map of
+// each implementation in newMaps, keyed by name, and fails if any two maps
+// return different results or visit different keys in a Range.
+func Equivalent(t *testing.T, newMaps map[string]func() Map) {
+	names := slices.Sorted(maps.Keys(newMaps))
+	if len(names) < 2 {
+		t.Fatal("Equivalent needs at least two implementations")
+	}
+	ms := make([]Map, len(names))
+	for i, name := range names {
+		ms[i] = newMaps[name]()
+	}
+	rnd := rand.New(rand.NewSource(5))
+	for i := 0; i < 5000; i++ {
+		k := rnd.Intn(400) - 200
+		switch op := rnd.Intn(10); {
+		case op < 5:
+			for _, m := range ms {
+				m.Set(k, strconv.Itoa(i))
+			}
+		case op < 8:
+			v0, ok0 := ms[0].Delete(k)
+			for j, m := range ms[1:] {
+				if v, ok := m.Delete(k); v != v0 || ok != ok0 {
+					t.Fatalf("step %d: Delete(%d) = %q, %t for %s, but %q, %t for %s", i, k, v0, ok0, names[0], v, ok, names[j+1])
+				}
+			}
+		default:
+			lo, hi := k, k+rnd.Intn(100)-10
+			want := keys(ms[0], lo, hi)
+			for j, m := range ms[1:] {
+				if got := keys(m, lo, hi); !slices.Equal(got, want) {
+					t.Fatalf("step %d: Range(%d, %d) visited %v for %s, but %v for %s", i, lo, hi, want, names[0], got, names[j+1])
+				}
+			}
+		}
+	}
+	want := keys(ms[0], math.MinInt, math.MaxInt)
+	for j, m := range ms[1:] {
+		if got := keys(m, math.MinInt, math.MaxInt); !slices.Equal(got, want) {
+			t.Errorf("final keys differ: %v for %s, but %v for %s", want, names[0], got, names[j+1])
+		}
+		validate(t, m)
+	}
+}
+
+// Benchmark runs the conformance benchmarks against maps created by newMap.
+func Benchmark(b *testing.B, newMap func() Map) {
+	for _, n := range []int{1_000, 100_000} {
+		keys := rand.New(rand.NewSource(4)).Perm(n)
+		full := newMap()
+		for _, k := range keys {
+			full.Set(k, "")
+		}
+
+		b.Run(fmt.Sprintf("Set/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if i%n == 0 {
+					b.StopTimer()
+					full = newMap()
+					b.StartTimer()
+				}
+				full.Set(keys[i%n], "")
+			}
+		})
+		b.Run(fmt.Sprintf("Get/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				full.Get(keys[i%n])
+			}
+		})
+		b.Run(fmt.Sprintf("Range/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lo := keys[i%n]
+				full.Range(lo, lo+100, func(int, string) bool { return true })
+			}
+		})
+		b.Run(fmt.Sprintf("SetDelete/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				k := keys[i%n]
+				full.Delete(k)
+				full.Set(k, "")
+			}
+		})
+	}
+}
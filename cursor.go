@@ -0,0 +1,144 @@
+package generictree
+
+import "cmp"
+
+// Cursor points to an entry of a tree and moves through the tree in either
+// direction. A new cursor does not point to any entry; position it with
+// First, Last, or Seek.
+//
+// If the tree is modified, the cursor becomes invalid until it is
+// positioned again.
+type Cursor[Value cmp.Ordered, Data any] struct {
+	tree  *Tree[Value, Data]
+	stack []*Node[Value, Data] // path from the root to the current node
+}
+
+// Cursor returns a new, unpositioned cursor for t.
+func (t *Tree[Value, Data]) Cursor() *Cursor[Value, Data] {
+	return &Cursor[Value, Data]{tree: t}
+}
+
+// Valid reports whether the cursor points to an entry.
+func (c *Cursor[Value, Data]) Valid() bool {
+	return len(c.stack) > 0
+}
+
+func (c *Cursor[Value, Data]) node() *Node[Value, Data] {
+	return c.stack[len(c.stack)-1]
+}
+
+// Entry returns the entry the cursor points to.
+// Entry panics if the cursor is not valid.
+func (c *Cursor[Value, Data]) Entry() Entry[Value, Data] {
+	return c.tree.entry(c.node())
+}
+
+// Value returns the value of the entry the cursor points to.
+// Value panics if the cursor is not valid.
+func (c *Cursor[Value, Data]) Value() Value {
+	return c.node().Value
+}
+
+// Data returns the data of the entry the cursor points to.
+// Data panics if the cursor is not valid.
+func (c *Cursor[Value, Data]) Data() Data {
+	return c.node().Data
+}
+
+// reset empties the stack. The first time, it allocates room for the
+// longest path of the tree, so that the stack does not grow in steps.
+func (c *Cursor[Value, Data]) reset() {
+	if c.stack == nil && c.tree.Root != nil {
+		c.stack = make([]*Node[Value, Data], 0, c.tree.Root.Height())
+	}
+	c.stack = c.stack[:0]
+}
+
+// First moves the cursor to the first entry in the tree's order.
+// It reports whether the tree has an entry.
+func (c *Cursor[Value, Data]) First() bool {
+	c.reset()
+	c.pushLeft(c.tree.Root)
+	return c.Valid()
+}
+
+// Last moves the cursor to the last entry in the tree's order.
+// It reports whether the tree has an entry.
+func (c *Cursor[Value, Data]) Last() bool {
+	c.reset()
+	c.pushRight(c.tree.Root)
+	return c.Valid()
+}
+
+// Seek moves the cursor to the first entry whose value is not less than
+// value. It reports whether there is such an entry.
+func (c *Cursor[Value, Data]) Seek(value Value) bool {
+	c.reset()
+	// found is the length of the path to the best candidate so far.
+	found := 0
+	for n := c.tree.Root; n != nil; {
+		c.stack = append(c.stack, n)
+		if c.tree.compare(value, n.Value) <= 0 {
+			found = len(c.stack)
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	c.stack = c.stack[:found]
+	return c.Valid()
+}
+
+// Next moves the cursor to the next entry. It reports whether there is one;
+// if not, the cursor becomes invalid.
+func (c *Cursor[Value, Data]) Next() bool {
+	if !c.Valid() {
+		return false
+	}
+	if n := c.node(); n.Right != nil {
+		c.pushLeft(n.Right)
+		return true
+	}
+	// Go up until we come from a left child.
+	for {
+		child := c.node()
+		c.stack = c.stack[:len(c.stack)-1]
+		if !c.Valid() || c.node().Left == child {
+			return c.Valid()
+		}
+	}
+}
+
+// Prev moves the cursor to the previous entry. It reports whether there is
+// one; if not, the cursor becomes invalid.
+func (c *Cursor[Value, Data]) Prev() bool {
+	if !c.Valid() {
+		return false
+	}
+	if n := c.node(); n.Left != nil {
+		c.pushRight(n.Left)
+		return true
+	}
+	// Go up until we come from a right child.
+	for {
+		child := c.node()
+		c.stack = c.stack[:len(c.stack)-1]
+		if !c.Valid() || c.node().Right == child {
+			return c.Valid()
+		}
+	}
+}
+
+// pushLeft pushes n and its chain of left descendants.
+func (c *Cursor[Value, Data]) pushLeft(n *Node[Value, Data]) {
+	for ; n != nil; n = n.Left {
+		c.stack = append(c.stack, n)
+	}
+}
+
+// pushRight pushes n and its chain of right descendants.
+func (c *Cursor[Value, Data]) pushRight(n *Node[Value, Data]) {
+	for ; n != nil; n = n.Right {
+		c.stack = append(c.stack, n)
+	}
+}
@@ -0,0 +1,71 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	for _, tree := range trees {
+		t.Run(tree.name, func(t *testing.T) {
+			tt := newTree(tree)
+			want := tt.values()
+			c := tt.Cursor()
+
+			var got []string
+			for ok := c.First(); ok; ok = c.Next() {
+				got = append(got, c.Value())
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("First/Next: %v, want %v", got, want)
+			}
+			if c.Valid() {
+				t.Error("cursor is still valid after the last Next")
+			}
+
+			got = got[:0]
+			for ok := c.Last(); ok; ok = c.Prev() {
+				got = append(got, c.Value())
+			}
+			slices.Reverse(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("Last/Prev reversed: %v, want %v", got, want)
+			}
+
+			for _, v := range []string{"", "0", "05", "a", "bb", "h", "z"} {
+				i, _ := slices.BinarySearch(want, v)
+				ok := c.Seek(v)
+				if ok != (i < len(want)) {
+					t.Errorf("Seek(%q) = %t", v, ok)
+					continue
+				}
+				if ok && c.Value() != want[i] {
+					t.Errorf("Seek(%q) moved to %q, want %q", v, c.Value(), want[i])
+				}
+				if ok && c.Entry().Data() != c.Data() {
+					t.Errorf("Entry().Data() = %q, Data() = %q", c.Entry().Data(), c.Data())
+				}
+			}
+		})
+	}
+}
+
+func TestCursor_seekAndReverse(t *testing.T) {
+	tree := &Tree[int, int]{}
+	for i := 0; i < 50; i += 5 {
+		tree.Insert(i, i)
+	}
+	c := tree.Cursor()
+	if !c.Seek(22) || c.Value() != 25 {
+		t.Fatalf("Seek(22) did not move to 25")
+	}
+	if !c.Prev() || c.Value() != 20 {
+		t.Errorf("Prev after Seek(22) did not move to 20")
+	}
+	if !c.Next() || !c.Next() || c.Value() != 30 {
+		t.Errorf("Next, Next did not move to 30")
+	}
+	if c.Prev(); !c.Valid() || c.Value() != 25 {
+		t.Errorf("Prev did not move back to 25")
+	}
+}
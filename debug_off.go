@@ -0,0 +1,16 @@
+//go:build !treedebug
+
+package generictree
+
+import "cmp"
+
+// debugEnabled reports whether the package is built with the treedebug tag.
+const debugEnabled = false
+
+// debugLog is empty in normal builds. Build with `-tags treedebug`
+// to check the tree invariants after every mutating operation.
+type debugLog[Value cmp.Ordered, Data any] struct{}
+
+func (*debugLog[Value, Data]) record(*Tree[Value, Data], string, ...any) {}
+
+func (*debugLog[Value, Data]) setup(*options) {}
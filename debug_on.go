@@ -0,0 +1,104 @@
+//go:build treedebug
+
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// debugEnabled reports whether the package is built with the treedebug tag.
+const debugEnabled = true
+
+// debugLog records every mutating operation on a tree. After each operation,
+// the tree is validated. On the first violation, debugLog panics with a
+// trace of Go statements that replays all operations up to the failing one.
+// The comparisons of the validation are not traced or counted (see
+// WithTracer and WithCounters).
+type debugLog[Value cmp.Ordered, Data any] struct {
+	newCall string // the New call that created the tree; empty for &Tree{}
+	ops     []string
+}
+
+// setup records the options of New that shape the tree, so that the
+// replay builds the tree the same way. Options that only observe the
+// tree, such as hooks and tracers, are left out. Functions cannot be
+// printed; the replay names them and says so in a comment.
+func (l *debugLog[Value, Data]) setup(o *options) {
+	var opts, funcs []string
+	if o.compare != nil {
+		opts, funcs = append(opts, "WithComparator(compare)"), append(funcs, "compare")
+	}
+	if o.descending {
+		opts = append(opts, "WithDescending()")
+	}
+	if o.allowDuplicates {
+		opts = append(opts, "WithAllowDuplicates()")
+	}
+	switch o.duplicates {
+	case DuplicateKeep:
+		opts = append(opts, "WithOnDuplicate(DuplicateKeep)")
+	case DuplicateError:
+		opts = append(opts, "WithOnDuplicate(DuplicateError)")
+	case DuplicateFunc:
+		opts, funcs = append(opts, "WithDuplicateFunc(onDuplicate)"), append(funcs, "onDuplicate")
+	}
+	if o.noBalance {
+		opts = append(opts, "WithNoBalance()")
+	}
+	if o.slack > 1 {
+		opts = append(opts, fmt.Sprintf("WithRelaxedBalance(%d)", o.slack))
+	}
+	if o.maxSize != 0 {
+		policy := [...]string{EvictMin: "EvictMin", EvictMax: "EvictMax", EvictLRU: "EvictLRU"}[o.eviction]
+		opts = append(opts, fmt.Sprintf("WithMaxSize(%d, %s)", o.maxSize, policy))
+	}
+	if o.maxEntries != 0 {
+		opts = append(opts, fmt.Sprintf("WithMaxEntries(%d)", o.maxEntries))
+	}
+	if o.maxHeight != 0 {
+		opts = append(opts, fmt.Sprintf("WithMaxHeight(%d)", o.maxHeight))
+	}
+	l.newCall = fmt.Sprintf("New[%T, %T](%s)", *new(Value), *new(Data), strings.Join(opts, ", "))
+	if len(funcs) > 0 {
+		l.newCall += " // " + strings.Join(funcs, " and ") + ": the functions passed to New"
+	}
+}
+
+func (l *debugLog[Value, Data]) record(t *Tree[Value, Data], op string, args ...any) {
+	l.ops = append(l.ops, formatOp(op, args))
+	if tr := t.tracing(); tr != nil {
+		t.cfg.tracing = nil
+		defer func() { t.cfg.tracing = tr }()
+	}
+	if err := t.Validate(); err != nil {
+		panic(l.trace(t, err))
+	}
+}
+
+func (l *debugLog[Value, Data]) trace(t *Tree[Value, Data], err error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "generictree: invariant violation after %s: %v\n", l.ops[len(l.ops)-1], err)
+	switch {
+	case l.newCall != "":
+		fmt.Fprintf(&b, "replay:\n\tt := %s\n", l.newCall)
+	case t.cfg != nil:
+		// For example, a tree returned by Filter or MapValues.
+		fmt.Fprintf(&b, "replay:\n\tt := &Tree[%T, %T]{} // the tree was built with non-default options\n", *new(Value), *new(Data))
+	default:
+		fmt.Fprintf(&b, "replay:\n\tt := &Tree[%T, %T]{}\n", *new(Value), *new(Data))
+	}
+	for _, op := range l.ops {
+		fmt.Fprintf(&b, "\tt.%s\n", op)
+	}
+	return b.String()
+}
+
+func formatOp(op string, args []any) string {
+	s := make([]string, len(args))
+	for i, a := range args {
+		s[i] = fmt.Sprintf("%#v", a)
+	}
+	return op + "(" + strings.Join(s, ", ") + ")"
+}
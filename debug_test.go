@@ -0,0 +1,72 @@
+//go:build treedebug
+
+package generictree
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+)
+
+func TestDebugLog(t *testing.T) {
+	tt := &Tree[int, string]{}
+	tt.Insert(2, "two")
+	tt.Insert(1, "one")
+	// Break the search property behind the tree's back.
+	tt.Root.Left.Value = 3
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Insert did not panic on a corrupt tree")
+		}
+		trace, _ := r.(string)
+		for _, want := range []string{
+			"t := &Tree[int, string]{}",
+			`t.Insert(2, "two")`,
+			`t.Insert(1, "one")`,
+			`t.Insert(4, "four")`,
+		} {
+			if !strings.Contains(trace, want) {
+				t.Errorf("trace does not contain %q:\n%s", want, trace)
+			}
+		}
+	}()
+	tt.Insert(4, "four")
+}
+
+func TestDebugLog_counters(t *testing.T) {
+	// The validation after each operation compares values and visits
+	// every node; the counters must not see any of it.
+	tt := New[int, int](WithCounters())
+	for v := range 3 {
+		tt.Insert(v, v)
+	}
+	if got, want := tt.StatsSnapshot(), (Counters{Inserts: 3, Comparisons: 3, Rotations: 1}); got != want {
+		t.Errorf("StatsSnapshot() = %+v, want %+v", got, want)
+	}
+	if tt.cfg.tracing == nil {
+		t.Error("the validation did not restore the counters")
+	}
+}
+
+func TestDebugLog_options(t *testing.T) {
+	tt := New[float64, int](WithDescending(), WithOnDuplicate(DuplicateKeep), WithRelaxedBalance(2), WithComparator(cmp.Compare[float64]))
+	tt.Insert(2, 2)
+	tt.Insert(1, 1)
+	// In descending order, 1 is the right child of 2.
+	tt.Root.Right.Value = 3
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Insert did not panic on a corrupt tree")
+		}
+		trace, _ := r.(string)
+		want := "t := New[float64, int](WithComparator(compare), WithDescending(), WithOnDuplicate(DuplicateKeep), WithRelaxedBalance(2)) // compare: the functions passed to New"
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace does not contain %q:\n%s", want, trace)
+		}
+	}()
+	tt.Insert(0, 0)
+}
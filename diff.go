@@ -0,0 +1,108 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+)
+
+// KV is a single entry of a tree: a value and its data.
+type KV[Value, Data any] struct {
+	Value Value `json:"value"`
+	Data  Data  `json:"data"`
+}
+
+// Changes describes the differences between two trees, as returned by Diff.
+// All slices are in tree order.
+type Changes[Value, Data any] struct {
+	Added   []KV[Value, Data] // entries of b whose value is not in a
+	Removed []KV[Value, Data] // entries of a whose value is not in b
+	Updated []KV[Value, Data] // entries of b whose value is in a with different data
+}
+
+// Empty reports whether there are no changes.
+func (c Changes[Value, Data]) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0
+}
+
+// Diff returns the changes that turn tree a into tree b. equal reports
+// whether two data are equal; if equal is nil, Diff uses reflect.DeepEqual.
+// Both trees must use the same order. Diff walks both trees once and takes
+// O(n+m) time.
+func Diff[Value cmp.Ordered, Data any](a, b *Tree[Value, Data], equal func(x, y Data) bool) Changes[Value, Data] {
+	if equal == nil {
+		equal = func(x, y Data) bool { return reflect.DeepEqual(x, y) }
+	}
+	var ch Changes[Value, Data]
+	ca, cb := a.Cursor(), b.Cursor()
+	okA, okB := ca.First(), cb.First()
+	for okA || okB {
+		c := 0
+		switch {
+		case !okB:
+			c = -1
+		case !okA:
+			c = 1
+		default:
+			c = a.compare(ca.Value(), cb.Value())
+		}
+		switch {
+		case c < 0:
+			ch.Removed = append(ch.Removed, KV[Value, Data]{ca.Value(), ca.Data()})
+			okA = ca.Next()
+		case c > 0:
+			ch.Added = append(ch.Added, KV[Value, Data]{cb.Value(), cb.Data()})
+			okB = cb.Next()
+		default:
+			if !equal(ca.Data(), cb.Data()) {
+				ch.Updated = append(ch.Updated, KV[Value, Data]{cb.Value(), cb.Data()})
+			}
+			okA, okB = ca.Next(), cb.Next()
+		}
+	}
+	return ch
+}
+
+// Apply changes t by ch: it deletes the Removed entries, replaces the data
+// of the Updated entries, and inserts the Added entries, in this order.
+// Applying Diff(a, b) to a turns a into a copy of b.
+//
+// Apply first checks that every removed and updated value is in t and that
+// no added value is in t (unless t allows duplicates). If a check fails,
+// Apply returns an error wrapping ErrKeyNotFound or ErrDuplicateKey and
+// leaves t unchanged. Apply calls the hooks of t for each change.
+func (t *Tree[Value, Data]) Apply(ch Changes[Value, Data]) error {
+	for _, e := range ch.Removed {
+		if t.findNode(e.Value) == nil {
+			return fmt.Errorf("apply: remove %v: %w", e.Value, ErrKeyNotFound)
+		}
+	}
+	for _, e := range ch.Updated {
+		if t.findNode(e.Value) == nil {
+			return fmt.Errorf("apply: update %v: %w", e.Value, ErrKeyNotFound)
+		}
+	}
+	if !t.allowDuplicates() {
+		for _, e := range ch.Added {
+			if t.findNode(e.Value) != nil {
+				return fmt.Errorf("apply: add %v: %w", e.Value, ErrDuplicateKey)
+			}
+		}
+	}
+
+	for _, e := range ch.Removed {
+		t.Delete(e.Value)
+	}
+	for _, e := range ch.Updated {
+		// Find the node again; the deletions may have moved it.
+		n := t.findNode(e.Value)
+		old := n.Data
+		n.Data = t.internData(e.Data)
+		t.debug.record(t, "Insert", e.Value, e.Data)
+		t.afterInsert(e.Value, e.Data, old, true)
+	}
+	for _, e := range ch.Added {
+		t.Insert(e.Value, e.Data)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package generictree
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a, b := &Tree[int, string]{}, &Tree[int, string]{}
+	for _, v := range []int{1, 2, 3, 5} {
+		a.Insert(v, "a")
+	}
+	for _, v := range []int{2, 3, 4, 6} {
+		b.Insert(v, "a")
+	}
+	b.Insert(3, "b")
+
+	want := Changes[int, string]{
+		Added:   []KV[int, string]{{4, "a"}, {6, "a"}},
+		Removed: []KV[int, string]{{1, "a"}, {5, "a"}},
+		Updated: []KV[int, string]{{3, "b"}},
+	}
+	if got := Diff(a, b, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := Diff(a, a, func(x, y string) bool { return x == y }); !got.Empty() {
+		t.Errorf("Diff of a tree with itself: got %+v", got)
+	}
+}
+
+func TestTree_Apply(t *testing.T) {
+	a, b := &Tree[int, string]{}, &Tree[int, string]{}
+	for v := range 50 {
+		a.Insert(v, "a")
+		if v%3 != 0 {
+			b.Insert(v+10, "a")
+		}
+	}
+	b.Insert(20, "b")
+	if err := a.Apply(Diff(a, b, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if ch := Diff(a, b, nil); !ch.Empty() {
+		t.Errorf("trees differ after Apply: %+v", ch)
+	}
+}
+
+func TestTree_Apply_errors(t *testing.T) {
+	tree := &Tree[int, string]{}
+	tree.Insert(1, "a")
+	for _, c := range []struct {
+		name string
+		ch   Changes[int, string]
+		err  error
+	}{
+		{"remove missing", Changes[int, string]{Removed: []KV[int, string]{{1, ""}, {2, ""}}}, ErrKeyNotFound},
+		{"update missing", Changes[int, string]{Updated: []KV[int, string]{{2, ""}}}, ErrKeyNotFound},
+		{"add existing", Changes[int, string]{Added: []KV[int, string]{{0, ""}, {1, ""}}}, ErrDuplicateKey},
+	} {
+		if err := tree.Apply(c.ch); !errors.Is(err, c.err) {
+			t.Errorf("%s: got error %v, want %v", c.name, err, c.err)
+		}
+	}
+	if got := tree.values(); !slices.Equal(got, []int{1}) {
+		t.Errorf("tree changed after failed Apply: %v", got)
+	}
+}
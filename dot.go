@@ -0,0 +1,172 @@
+package generictree
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+)
+
+// WriteDOT writes the tree to w in the DOT language of Graphviz:
+//
+//	go run ./cmd/treectl dot tree.json | dot -Tsvg > tree.svg
+//
+// Each node is labeled with its value (see WithKeyFormatter). A missing
+// left or right child is drawn as an invisible node, so that the layout
+// keeps left and right apart.
+func (t *Tree[Value, Data]) WriteDOT(w io.Writer) error {
+	return t.writeDOT(w, nil)
+}
+
+// WriteDOTPath writes the tree like WriteDOT and highlights the search
+// path of value (see FindPath): the visited nodes and the edges between
+// them are drawn in red, and the node that holds value is filled.
+func (t *Tree[Value, Data]) WriteDOTPath(w io.Writer, value Value) error {
+	path := t.FindPath(value)
+	onPath := make(map[*Node[Value, Data]]Turn, len(path))
+	n := t.Root
+	for _, step := range path {
+		onPath[n] = step.Turn
+		if step.Turn == TurnLeft {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return t.writeDOT(w, onPath)
+}
+
+func (t *Tree[Value, Data]) writeDOT(w io.Writer, onPath map[*Node[Value, Data]]Turn) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph tree {")
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+	t.writeDOTNodes(bw, "\t", "n", func(n *Node[Value, Data]) string {
+		turn, ok := onPath[n]
+		switch {
+		case !ok:
+			return ""
+		case turn == TurnFound:
+			return ", color=red, style=filled, fillcolor=mistyrose"
+		}
+		return ", color=red"
+	}, func(n *Node[Value, Data], left bool) string {
+		if turn, ok := onPath[n]; ok && left == (turn == TurnLeft) && turn != TurnFound {
+			return " [color=red, penwidth=2]"
+		}
+		return ""
+	})
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeDOTNodes writes the nodes and edges of the tree, each line indented
+// by indent and each node ID starting with prefix. nodeAttrs and edgeAttrs
+// return the extra attributes of a node and of the edge to its left or
+// right child.
+func (t *Tree[Value, Data]) writeDOTNodes(bw *bufio.Writer, indent, prefix string, nodeAttrs func(n *Node[Value, Data]) string, edgeAttrs func(n *Node[Value, Data], left bool) string) {
+	id := 0
+	var walk func(n *Node[Value, Data]) int
+	walk = func(n *Node[Value, Data]) int {
+		self := id
+		id++
+		fmt.Fprintf(bw, "%s%s%d [label=%q%s];\n", indent, prefix, self, t.FormatValue(n.Value), nodeAttrs(n))
+		if n.Left == nil && n.Right == nil {
+			return self
+		}
+		for i, child := range []*Node[Value, Data]{n.Left, n.Right} {
+			if child == nil {
+				fmt.Fprintf(bw, "%[1]s%[2]s%[3]d [style=invis];\n%[1]s%[2]s%[4]d -> %[2]s%[3]d [style=invis];\n", indent, prefix, id, self)
+				id++
+				continue
+			}
+			fmt.Fprintf(bw, "%s%s%d -> %s%d%s;\n", indent, prefix, self, prefix, walk(child), edgeAttrs(n, i == 0))
+		}
+		return self
+	}
+	if t.Root != nil {
+		walk(t.Root)
+	}
+}
+
+// RenderDiff writes trees a and b side by side to w in the DOT language of
+// Graphviz, to review what a batch of operations did to a tree. The nodes
+// of removed entries are filled red in a, those of added entries green in
+// b, and those of updated entries (see Diff) yellow in both. Nodes of b
+// whose entry got a different parent, or moved to the other side of its
+// parent, are drawn with a bold blue outline. Data are compared with
+// reflect.DeepEqual. Both trees must use the same order. With duplicate
+// values, the markings are approximate.
+func RenderDiff[Value cmp.Ordered, Data any](a, b *Tree[Value, Data], w io.Writer) error {
+	ch := Diff(a, b, nil)
+	removed, added, updated := valueSet(ch.Removed), valueSet(ch.Added), valueSet(ch.Updated)
+	before := a.positions()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph diff {")
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+	noEdgeAttrs := func(*Node[Value, Data], bool) string { return "" }
+	fmt.Fprintln(bw, "\tsubgraph cluster_a {")
+	fmt.Fprintln(bw, "\t\tlabel=\"before\";")
+	a.writeDOTNodes(bw, "\t\t", "a", func(n *Node[Value, Data]) string {
+		switch {
+		case removed[n.Value]:
+			return ", style=filled, fillcolor=lightpink"
+		case updated[n.Value]:
+			return ", style=filled, fillcolor=lightyellow"
+		}
+		return ""
+	}, noEdgeAttrs)
+	fmt.Fprintln(bw, "\t}")
+	fmt.Fprintln(bw, "\tsubgraph cluster_b {")
+	fmt.Fprintln(bw, "\t\tlabel=\"after\";")
+	after := b.positions()
+	b.writeDOTNodes(bw, "\t\t", "b", func(n *Node[Value, Data]) string {
+		attrs := ""
+		switch {
+		case added[n.Value]:
+			return ", style=filled, fillcolor=palegreen"
+		case updated[n.Value]:
+			attrs = ", style=filled, fillcolor=lightyellow"
+		}
+		if before[n.Value] != after[n.Value] {
+			attrs += ", color=blue, penwidth=2"
+		}
+		return attrs
+	}, noEdgeAttrs)
+	fmt.Fprintln(bw, "\t}")
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// position is the place of an entry in a tree: the value of its parent and
+// the side of the parent it is on. The root has no parent.
+type position[Value any] struct {
+	parent    Value
+	hasParent bool
+	left      bool
+}
+
+// positions returns the position of each value of the tree.
+func (t *Tree[Value, Data]) positions() map[Value]position[Value] {
+	pos := make(map[Value]position[Value], t.Len())
+	var walk func(n *Node[Value, Data], p position[Value])
+	walk = func(n *Node[Value, Data], p position[Value]) {
+		if n == nil {
+			return
+		}
+		pos[n.Value] = p
+		walk(n.Left, position[Value]{n.Value, true, true})
+		walk(n.Right, position[Value]{n.Value, true, false})
+	}
+	walk(t.Root, position[Value]{})
+	return pos
+}
+
+// valueSet returns the set of the values of kvs.
+func valueSet[Value comparable, Data any](kvs []KV[Value, Data]) map[Value]bool {
+	set := make(map[Value]bool, len(kvs))
+	for _, kv := range kvs {
+		set[kv.Value] = true
+	}
+	return set
+}
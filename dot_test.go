@@ -0,0 +1,89 @@
+package generictree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTree_WriteDOT(t *testing.T) {
+	tree := &Tree[int, string]{}
+	tree.Insert(2, "")
+	tree.Insert(3, "")
+	var b strings.Builder
+	if err := tree.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph tree {
+	node [shape=circle];
+	n0 [label="2"];
+	n1 [style=invis];
+	n0 -> n1 [style=invis];
+	n2 [label="3"];
+	n0 -> n2;
+}
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestTree_WriteDOTPath(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for _, v := range []int{2, 1, 3} {
+		tree.Insert(v, "")
+	}
+	var b strings.Builder
+	if err := tree.WriteDOTPath(&b, 3); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph tree {
+	node [shape=circle];
+	n0 [label="2", color=red];
+	n1 [label="1"];
+	n0 -> n1;
+	n2 [label="3", color=red, style=filled, fillcolor=mistyrose];
+	n0 -> n2 [color=red, penwidth=2];
+}
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	a, b := &Tree[int, string]{}, &Tree[int, string]{}
+	for _, v := range []int{2, 1, 3} {
+		a.Insert(v, "")
+		b.Insert(v, "")
+	}
+	b.Delete(1)
+	b.Insert(3, "x")
+	b.Insert(4, "") // rotates 3 to the root
+	var buf strings.Builder
+	if err := RenderDiff(a, b, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph diff {
+	node [shape=circle];
+	subgraph cluster_a {
+		label="before";
+		a0 [label="2"];
+		a1 [label="1", style=filled, fillcolor=lightpink];
+		a0 -> a1;
+		a2 [label="3", style=filled, fillcolor=lightyellow];
+		a0 -> a2;
+	}
+	subgraph cluster_b {
+		label="after";
+		b0 [label="3", style=filled, fillcolor=lightyellow, color=blue, penwidth=2];
+		b1 [label="2", color=blue, penwidth=2];
+		b0 -> b1;
+		b2 [label="4", style=filled, fillcolor=palegreen];
+		b0 -> b2;
+	}
+}
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
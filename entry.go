@@ -0,0 +1,193 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Entry is a read-only view of an entry in a tree. Unlike a *Node, an
+// Entry does not give access to the tree structure, so it cannot break the
+// search order or the balance of the tree.
+//
+// An Entry stays valid as long as its entry is in the tree, even while
+// other entries are inserted or deleted. Once the entry is deleted, Valid
+// reports false. An Entry is a handle for updating an entry repeatedly
+// without searching the tree each time (see InsertEntry and FindEntry).
+type Entry[Value cmp.Ordered, Data any] struct {
+	tree  *Tree[Value, Data]
+	node  *Node[Value, Data]
+	value Value // the value of node when the Entry was created
+}
+
+// entry returns an Entry for n.
+func (t *Tree[Value, Data]) entry(n *Node[Value, Data]) Entry[Value, Data] {
+	return Entry[Value, Data]{t, n, n.Value}
+}
+
+// Value returns the search value of the entry.
+func (e Entry[Value, Data]) Value() Value {
+	return e.node.Value
+}
+
+// Data returns the data of the entry.
+func (e Entry[Value, Data]) Data() Data {
+	return e.node.Data
+}
+
+// Valid reports whether the entry is still in the tree. It is false for
+// the zero Entry and after the entry was deleted or evicted.
+func (e Entry[Value, Data]) Valid() bool {
+	// Deleted nodes have size 0. A free list can reuse a deleted node
+	// for another entry, which then has a different value, unless it
+	// is an entry for the same value.
+	return e.node != nil && e.node.size > 0 && e.tree.compare(e.node.Value, e.value) == 0
+}
+
+// SetData replaces the data of the entry in place in O(1) time, without
+// searching the tree again. Only if the tree has an augmentation, such as
+// the sums of a NumericTree, SetData searches the path to the entry to
+// update it, in O(log n) time. Like EditData, SetData calls the tree's
+// OnUpdate hooks. It panics if the entry is no longer valid (see Valid).
+func (e Entry[Value, Data]) SetData(data Data) {
+	if !e.Valid() {
+		panic("generictree: Entry.SetData: the entry is not in the tree")
+	}
+	old := e.node.Data
+	e.node.Data = e.tree.internData(data)
+	if e.tree.cfg != nil && e.tree.cfg.augment != nil {
+		path := e.tree.pathTo(e.node)
+		for i := len(path) - 1; i >= 0; i-- {
+			path[i].update()
+		}
+	}
+	e.tree.afterInsert(e.node.Value, e.node.Data, old, true)
+}
+
+// pathTo returns the nodes from the root down to n, or nil if n is not in
+// the tree. With duplicates, it searches all entries equal to n.
+func (t *Tree[Value, Data]) pathTo(n *Node[Value, Data]) []*Node[Value, Data] {
+	var path []*Node[Value, Data]
+	var find func(m *Node[Value, Data]) bool
+	find = func(m *Node[Value, Data]) bool {
+		if m == nil {
+			return false
+		}
+		path = append(path, m)
+		if m == n {
+			return true
+		}
+		c := t.compare(n.Value, m.Value)
+		if c <= 0 && find(m.Left) || c >= 0 && find(m.Right) {
+			return true
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	find(t.Root)
+	return path
+}
+
+// InsertEntry inserts value and data like Insert and returns the entry
+// that holds them. If a limit rejects the entry (see WithMaxEntries), or
+// the tree evicts it right away (see WithMaxSize), the returned Entry is
+// not valid.
+func (t *Tree[Value, Data]) InsertEntry(value Value, data Data) Entry[Value, Data] {
+	n, _ := t.put(value, data)
+	if n == nil {
+		return Entry[Value, Data]{}
+	}
+	return t.entry(n)
+}
+
+// FindEntry returns the entry for value and true, or an invalid Entry and
+// false if value is not in the tree.
+func (t *Tree[Value, Data]) FindEntry(value Value) (Entry[Value, Data], bool) {
+	if t == nil || t.Root == nil {
+		return Entry[Value, Data]{}, false
+	}
+	n := t.findNode(value)
+	if n == nil {
+		return Entry[Value, Data]{}, false
+	}
+	t.touch(n)
+	return t.entry(n), true
+}
+
+// All returns an iterator over the values and data of the tree, in the
+// tree's order.
+func (t *Tree[Value, Data]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		t.Root.ascend(func(n *Node[Value, Data]) bool { return yield(n.Value, n.Data) })
+	}
+}
+
+// Backward returns an iterator over the values and data of the tree, in
+// reverse order.
+func (t *Tree[Value, Data]) Backward() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		t.Root.descend(func(n *Node[Value, Data]) bool { return yield(n.Value, n.Data) })
+	}
+}
+
+// From returns an iterator over the values and data of the tree, starting
+// at the first value that is not less than value, in the tree's order.
+func (t *Tree[Value, Data]) From(value Value) iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		// Trees of up to one entry need no cursor.
+		if r := t.Root; r == nil || r.Left == nil && r.Right == nil {
+			if r != nil && t.compare(value, r.Value) <= 0 {
+				yield(r.Value, r.Data)
+			}
+			return
+		}
+		c := t.Cursor()
+		for ok := c.Seek(value); ok; ok = c.Next() {
+			if !yield(c.Value(), c.Data()) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns an iterator over the entries of the tree, in the tree's
+// order. The tree must not be modified during the iteration, except through
+// Entry.SetData.
+func (t *Tree[Value, Data]) Entries() iter.Seq[Entry[Value, Data]] {
+	return func(yield func(Entry[Value, Data]) bool) {
+		t.Root.ascend(func(n *Node[Value, Data]) bool { return yield(t.entry(n)) })
+	}
+}
+
+// AppendKeys appends the values of the tree to dst in the tree's order
+// and returns the extended slice. It does not allocate if dst has room
+// for all values, so that repeated exports can reuse one slice.
+func (t *Tree[Value, Data]) AppendKeys(dst []Value) []Value {
+	dst = slices.Grow(dst, t.Len())
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		dst = append(dst, n.Value)
+		return true
+	})
+	return dst
+}
+
+// AppendEntries appends the values and data of the tree to dst in the
+// tree's order and returns the extended slice. Like AppendKeys, it does
+// not allocate if dst has room for all entries.
+func (t *Tree[Value, Data]) AppendEntries(dst []KV[Value, Data]) []KV[Value, Data] {
+	dst = slices.Grow(dst, t.Len())
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		dst = append(dst, KV[Value, Data]{n.Value, n.Data})
+		return true
+	})
+	return dst
+}
+
+// descend calls f for each node of the subtree rooted at n in descending
+// order. It stops and returns false as soon as f returns false.
+func (n *Node[Value, Data]) descend(f func(*Node[Value, Data]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.Right.descend(f) && f(n) && n.Left.descend(f)
+}
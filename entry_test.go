@@ -0,0 +1,188 @@
+package generictree
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestTree_iterators(t *testing.T) {
+	for _, tree := range trees {
+		t.Run(tree.name, func(t *testing.T) {
+			tt := newTree(tree)
+			want := tt.values()
+
+			var got []string
+			for v, d := range tt.All() {
+				if fd, _ := tt.Find(v); fd != d {
+					t.Errorf("All yielded %q, %q; Find returns %q", v, d, fd)
+				}
+				got = append(got, v)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("All() = %v, want %v", got, want)
+			}
+
+			got = got[:0]
+			for v := range tt.Backward() {
+				got = append(got, v)
+			}
+			slices.Reverse(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("Backward() reversed = %v, want %v", got, want)
+			}
+
+			got = got[:0]
+			for e := range tt.Entries() {
+				got = append(got, e.Value())
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("Entries() = %v, want %v", got, want)
+			}
+
+			got = got[:0]
+			for v := range tt.From("c") {
+				got = append(got, v)
+			}
+			i, _ := slices.BinarySearch(want, "c")
+			if !slices.Equal(got, want[i:]) {
+				t.Errorf("From(c) = %v, want %v", got, want[i:])
+			}
+		})
+	}
+}
+
+func TestEntry(t *testing.T) {
+	var updates []int
+	tree := New[int, int](WithHooks(Hooks[int, int]{
+		OnUpdate: func(_ int, old, new int) { updates = append(updates, old, new) },
+	}))
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+
+	var e Entry[int, int]
+	for entry := range tree.Entries() {
+		if entry.Value() == 50 {
+			e = entry
+		}
+	}
+	e.SetData(-50)
+	if d, _ := tree.Find(50); d != -50 {
+		t.Errorf("Find(50) = %d after SetData(-50)", d)
+	}
+	if !slices.Equal(updates, []int{50, -50}) {
+		t.Errorf("SetData called OnUpdate with %v, want [50 -50]", updates)
+	}
+
+	// The entry survives deletions and insertions of other entries, including
+	// deletions where the node of 50 takes the place of its predecessor.
+	for i := 0; i < 100; i += 3 {
+		if i != 50 {
+			tree.Delete(i)
+		}
+	}
+	for i := 100; i < 200; i++ {
+		tree.Insert(i, i)
+	}
+	if e.Value() != 50 || e.Data() != -50 {
+		t.Errorf("entry changed to %d, %d", e.Value(), e.Data())
+	}
+	e.SetData(0)
+	if d, _ := tree.Find(50); d != 0 {
+		t.Errorf("Find(50) = %d after SetData(0)", d)
+	}
+}
+
+func TestTree_InsertFindEntry(t *testing.T) {
+	tree := New[string, int](WithFreeList(NewFreeList[string, int](10)))
+	counter := tree.InsertEntry("a", 0)
+	for range 5 {
+		counter.SetData(counter.Data() + 1)
+	}
+	if d, _ := tree.Find("a"); d != 5 || !counter.Valid() {
+		t.Errorf("Find(a) = %d, valid %v", d, counter.Valid())
+	}
+	if e, ok := tree.FindEntry("a"); !ok || e != counter {
+		t.Errorf("FindEntry(a) = %+v, %v", e, ok)
+	}
+	if e, ok := tree.FindEntry("b"); ok || e.Valid() {
+		t.Errorf("FindEntry(b) = %+v, %v", e, ok)
+	}
+
+	tree.Delete("a")
+	if counter.Valid() {
+		t.Error("entry valid after Delete")
+	}
+	// The free list hands the node of "a" to "b".
+	b := tree.InsertEntry("b", 1)
+	if counter.Valid() || !b.Valid() {
+		t.Errorf("after reusing the node: valid %v and %v", counter.Valid(), b.Valid())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("SetData on a deleted entry did not panic")
+		}
+	}()
+	counter.SetData(6)
+}
+
+func TestTree_Append(t *testing.T) {
+	tree := New[int, string](WithDescending())
+	for v := range 100 {
+		tree.Insert(v, "")
+	}
+	keys := tree.AppendKeys([]int{-1})
+	if len(keys) != 101 || keys[0] != -1 || !slices.Equal(keys[1:], tree.values()) {
+		t.Errorf("AppendKeys = %v", keys)
+	}
+	entries := tree.AppendEntries(nil)
+	if len(entries) != 100 || entries[0].Value != 99 || entries[99].Value != 0 {
+		t.Errorf("AppendEntries returned %d entries from %v", len(entries), entries[:1])
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		keys = tree.AppendKeys(keys[:0])
+		entries = tree.AppendEntries(entries[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("reusing the slices allocates %v times", allocs)
+	}
+}
+
+func TestEntry_SetData_augmented(t *testing.T) {
+	tree := NewNumericTree[int, int](WithAllowDuplicates())
+	for v := range 50 {
+		tree.Insert(v%10, 1)
+	}
+	var entries []Entry[int, int]
+	for e := range tree.tree.Entries() {
+		entries = append(entries, e)
+	}
+	for _, e := range entries[20:30] { // the entries of 4 and 5
+		e.SetData(3)
+	}
+	if err := tree.tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.SumRange(0, 10); got != 70 {
+		t.Errorf("SumRange(0, 10) = %d after SetData, want 70", got)
+	}
+	if got, _ := tree.MaxRange(5, 6); got != 3 {
+		t.Errorf("MaxRange(5, 6) = %d after SetData, want 3", got)
+	}
+}
+
+func TestEntry_SetData_interned(t *testing.T) {
+	var hooked string
+	tree := New[int, string](WithInterning(), WithHooks(Hooks[int, string]{
+		OnUpdate: func(_ int, _, new string) { hooked = new },
+	}))
+	tree.Insert(1, "shared")
+	tree.Insert(2, "other")
+	e, _ := tree.FindEntry(2)
+	e.SetData(strings.Clone("shared"))
+	if unsafe.StringData(hooked) != unsafe.StringData(e.Data()) {
+		t.Error("OnUpdate got the data before interning")
+	}
+}
@@ -0,0 +1,91 @@
+package generictree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the error-returning variants of the tree operations.
+// The returned errors may wrap these errors with more detail; use errors.Is
+// to test for them.
+var (
+	ErrKeyNotFound  = errors.New("generictree: key not found")
+	ErrEmptyTree    = errors.New("generictree: tree is empty")
+	ErrDuplicateKey = errors.New("generictree: duplicate key")
+	ErrKeyOrder     = errors.New("generictree: keys out of order")
+	ErrRotation     = errors.New("generictree: rotation not possible")
+	ErrKeyOverflow  = errors.New("generictree: key overflow")
+	ErrToken        = errors.New("generictree: invalid token")
+)
+
+// LimitError is returned by InsertStrict when an insertion would exceed a
+// limit set with WithMaxEntries or WithMaxHeight.
+type LimitError struct {
+	Limit string // "entries" or "height"
+	Max   int    // the configured limit
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("generictree: insertion would exceed the maximum %s of %d", e.Limit, e.Max)
+}
+
+// InsertStrict inserts value and data like Insert, but returns a
+// *LimitError and leaves the tree unchanged if the insertion would exceed
+// a limit set with WithMaxEntries or WithMaxHeight. With the
+// DuplicateError policy (see WithOnDuplicate), it returns an error
+// wrapping ErrDuplicateKey if value is already in the tree.
+func (t *Tree[Value, Data]) InsertStrict(value Value, data Data) error {
+	// The limits never reject a value that is already in the tree, so
+	// checking for duplicates first does not change the error.
+	if t.cfg != nil && t.cfg.duplicates == DuplicateError && t.findNode(value) != nil {
+		return fmt.Errorf("insert %v: %w", value, ErrDuplicateKey)
+	}
+	if _, err := t.put(value, data); err != nil {
+		return fmt.Errorf("insert %v: %w", value, err)
+	}
+	return nil
+}
+
+// DeleteStrict removes value from the tree and returns its data. Unlike
+// Delete, it returns an error if there is nothing to delete: ErrEmptyTree
+// if the tree is empty, or ErrKeyNotFound if value is not in the tree.
+func (t *Tree[Value, Data]) DeleteStrict(value Value) (Data, error) {
+	if t == nil || t.Root == nil {
+		var zero Data
+		return zero, fmt.Errorf("delete %v: %w", value, ErrEmptyTree)
+	}
+	data, ok := t.Delete(value)
+	if !ok {
+		return data, fmt.Errorf("delete %v: %w", value, ErrKeyNotFound)
+	}
+	return data, nil
+}
+
+// ReKey moves the data stored for old to the new value. It returns
+// ErrKeyNotFound if old is not in the tree, ErrDuplicateKey if new is
+// already in the tree and the tree does not allow duplicates, and a
+// *LimitError if WithMaxHeight rejects new. In all these cases, the tree
+// keeps the entry of old.
+func (t *Tree[Value, Data]) ReKey(old, new Value) error {
+	if t.findNode(old) == nil {
+		return fmt.Errorf("rekey %v: %w", old, ErrKeyNotFound)
+	}
+	if t.compare(old, new) == 0 {
+		return nil
+	}
+	if !t.allowDuplicates() && t.findNode(new) != nil {
+		return fmt.Errorf("rekey %v to %v: %w", old, new, ErrDuplicateKey)
+	}
+	data, _ := t.Delete(old)
+	if err := t.checkLimits(new); err != nil {
+		// Put the entry back. The deletion may have changed the shape of
+		// the tree, so the limits must not reject old in turn.
+		maxEntries, maxHeight := t.cfg.maxEntries, t.cfg.maxHeight
+		t.cfg.maxEntries, t.cfg.maxHeight = 0, 0
+		t.Insert(old, data)
+		t.cfg.maxEntries, t.cfg.maxHeight = maxEntries, maxHeight
+		return fmt.Errorf("rekey %v to %v: %w", old, new, err)
+	}
+	t.Insert(new, data)
+	return nil
+}
@@ -0,0 +1,99 @@
+package generictree
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTree_DeleteStrict(t *testing.T) {
+	tree := &Tree[int, string]{}
+	if _, err := tree.DeleteStrict(1); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("empty tree: got error %v, want ErrEmptyTree", err)
+	}
+	tree.Insert(1, "one")
+	tree.Insert(2, "two")
+	if _, err := tree.DeleteStrict(3); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("missing value: got error %v, want ErrKeyNotFound", err)
+	}
+	data, err := tree.DeleteStrict(1)
+	if err != nil || data != "one" {
+		t.Errorf("DeleteStrict(1) = %q, %v, want \"one\", nil", data, err)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{2}) {
+		t.Errorf("values after delete: got %v", got)
+	}
+
+	var nilTree *Tree[int, string]
+	if _, err := nilTree.DeleteStrict(1); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("nil tree: got error %v, want ErrEmptyTree", err)
+	}
+}
+
+// TestTree_InsertStrict_comparisons checks that InsertStrict checks the
+// limits once, so that it compares no more than Insert.
+func TestTree_InsertStrict_comparisons(t *testing.T) {
+	insert := New[int, string](WithCounters(), WithMaxEntries(10))
+	strict := New[int, string](WithCounters(), WithMaxEntries(10))
+	for _, v := range []int{4, 2, 6, 1, 3, 5} {
+		insert.Insert(v, "")
+		if err := strict.InsertStrict(v, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := strict.StatsSnapshot(), insert.StatsSnapshot(); got != want {
+		t.Errorf("InsertStrict: %+v, Insert: %+v", got, want)
+	}
+}
+
+func TestTree_ReKey(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for _, v := range []int{1, 2, 3} {
+		tree.Insert(v, string(rune('a'+v-1)))
+	}
+	if err := tree.ReKey(4, 5); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("missing value: got error %v, want ErrKeyNotFound", err)
+	}
+	if err := tree.ReKey(1, 3); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("existing target: got error %v, want ErrDuplicateKey", err)
+	}
+	if err := tree.ReKey(2, 2); err != nil {
+		t.Errorf("ReKey(2, 2): %v", err)
+	}
+	if err := tree.ReKey(1, 10); err != nil {
+		t.Fatalf("ReKey(1, 10): %v", err)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{2, 3, 10}) {
+		t.Errorf("values after ReKey: got %v", got)
+	}
+	if data, _ := tree.Find(10); data != "a" {
+		t.Errorf("data of 10: got %q, want \"a\"", data)
+	}
+
+	dup := New[int, string](WithAllowDuplicates())
+	dup.Insert(1, "a")
+	dup.Insert(2, "b")
+	if err := dup.ReKey(1, 2); err != nil {
+		t.Errorf("ReKey with duplicates allowed: %v", err)
+	}
+	if got := dup.values(); !slices.Equal(got, []int{2, 2}) {
+		t.Errorf("values after ReKey with duplicates: got %v", got)
+	}
+
+	// Without balancing, 1 and 3 below 2 and 4 below 3 make a tree of height
+	// 3. Moving 1 to 5 would make it higher.
+	limited := New[int, string](WithNoBalance(), WithMaxHeight(3))
+	for _, v := range []int{2, 1, 3, 4} {
+		limited.Insert(v, string(rune('a'+v-1)))
+	}
+	var limit *LimitError
+	if err := limited.ReKey(1, 5); !errors.As(err, &limit) {
+		t.Errorf("ReKey beyond the height limit: got error %v, want *LimitError", err)
+	}
+	if data, ok := limited.Find(1); !ok || data != "a" {
+		t.Errorf("after a rejected ReKey, Find(1) = %q, %v", data, ok)
+	}
+	if err := limited.Validate(); err != nil || limited.Len() != 4 {
+		t.Errorf("after a rejected ReKey: %d entries, %v", limited.Len(), err)
+	}
+}
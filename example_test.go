@@ -0,0 +1,93 @@
+package generictree_test
+
+import (
+	"fmt"
+
+	"github.com/appliedgo/generictree"
+)
+
+func Example() {
+	tree := &generictree.Tree[int, string]{}
+	tree.Insert(2, "two")
+	tree.Insert(1, "one")
+	tree.Insert(3, "three")
+
+	data, found := tree.Find(2)
+	fmt.Println(data, found)
+
+	_, found = tree.Find(4)
+	fmt.Println(found)
+	// Output:
+	// two true
+	// false
+}
+
+func ExampleTree_All() {
+	tree := &generictree.Tree[string, int]{}
+	for i, s := range []string{"c", "a", "d", "b"} {
+		tree.Insert(s, i)
+	}
+	for value, data := range tree.All() {
+		fmt.Print(value, ":", data, " ")
+	}
+	fmt.Println()
+	// Output:
+	// a:1 b:3 c:0 d:2
+}
+
+func ExampleCursor() {
+	tree := &generictree.Tree[int, string]{}
+	for _, v := range []int{10, 20, 30, 40} {
+		tree.Insert(v, fmt.Sprint("#", v))
+	}
+	c := tree.Cursor()
+	for ok := c.Seek(25); ok; ok = c.Prev() {
+		fmt.Print(c.Value(), " ")
+	}
+	fmt.Println()
+	// Output:
+	// 30 20 10
+}
+
+func ExampleTree_PrettyPrint() {
+	tree := &generictree.Tree[int, struct{}]{}
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		tree.Insert(v, struct{}{})
+	}
+	tree.PrettyPrint()
+	// Output:
+	//     5
+	//   4
+	//     3
+	// 2
+	//   1
+}
+
+func ExampleTree_Dump() {
+	tree := &generictree.Tree[int, struct{}]{}
+	for _, v := range []int{1, 2, 3, 4} {
+		tree.Insert(v, struct{}{})
+	}
+	tree.Dump()
+	// Output:
+	// 2[1,3,4]
+	// +L--1[0,1,1]
+	// +R--3[1,2,2]
+	//     +R--4[0,1,1]
+}
+
+func ExampleWithKeyFormatter() {
+	tree := generictree.New[uint32, string](
+		generictree.WithKeyFormatter(func(ip uint32) string {
+			return fmt.Sprintf("%d.%d.%d.%d", ip>>24, ip>>16&0xff, ip>>8&0xff, ip&0xff)
+		}),
+	)
+	for _, ip := range []uint32{0x0a000001, 0xc0a80001, 0x7f000001} {
+		tree.Insert(ip, "")
+	}
+	tree.Dump()
+	// Output:
+	// 127.0.0.1[0,2,3]
+	// +L--10.0.0.1[0,1,1]
+	// +R--192.168.0.1[0,1,1]
+}
@@ -0,0 +1,40 @@
+// Wordfreq reads text from stdin and prints each word with its number of
+// occurrences, in alphabetical order.
+//
+//	go run ./examples/wordfreq < LICENSE.txt
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/appliedgo/generictree"
+)
+
+func main() {
+	words := &generictree.Tree[string, int]{}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimFunc(scanner.Text(), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}))
+		if word == "" {
+			continue
+		}
+		count, _ := words.Find(word)
+		words.Insert(word, count+1)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "wordfreq:", err)
+		os.Exit(1)
+	}
+
+	for word, count := range words.All() {
+		fmt.Printf("%6d %s\n", count, word)
+	}
+}
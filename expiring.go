@@ -0,0 +1,154 @@
+package generictree
+
+import (
+	"cmp"
+	"sync"
+	"time"
+)
+
+// ExpiringTree is a tree whose entries expire after a time to live. An
+// expired entry is evicted lazily, when Get runs into it, or by Sweep, which
+// a background sweeper can call periodically (see StartSweeper). Each
+// eviction calls the OnEvict callback.
+//
+// Internally, a second index orders the entries by expiry time, so that
+// Sweep only visits expired entries. ExpiringTree is safe for concurrent
+// use by multiple goroutines.
+type ExpiringTree[K cmp.Ordered, V any] struct {
+	mu      sync.Mutex
+	tree    Tree[K, expiring[V]]
+	expiry  MultiMap[int64, K] // expiry time in Unix nanoseconds -> keys
+	onEvict func(K, V)
+	now     func() time.Time
+}
+
+type expiring[V any] struct {
+	data    V
+	expires int64 // Unix nanoseconds
+}
+
+// NewExpiringTree returns an empty expiring tree. onEvict, if not nil, is
+// called for every entry that is evicted because it expired, but not for
+// entries removed by Delete or replaced by Set. onEvict is called without
+// holding the tree's lock, so it may use the tree.
+func NewExpiringTree[K cmp.Ordered, V any](onEvict func(K, V)) *ExpiringTree[K, V] {
+	return &ExpiringTree[K, V]{onEvict: onEvict, now: time.Now}
+}
+
+// Set stores data for key, replacing any previous entry. The entry expires
+// after ttl.
+func (t *ExpiringTree[K, V]) Set(key K, data V, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.remove(key)
+	expires := t.now().Add(ttl).UnixNano()
+	t.tree.Insert(key, expiring[V]{data, expires})
+	t.expiry.Add(expires, key)
+}
+
+// Get returns the data stored for key and true, or the zero value of V and
+// false if key is not in the tree or has expired. An expired entry is
+// evicted.
+func (t *ExpiringTree[K, V]) Get(key K) (V, bool) {
+	t.mu.Lock()
+	e, ok := t.tree.Find(key)
+	if ok && e.expires <= t.now().UnixNano() {
+		t.remove(key)
+		t.mu.Unlock()
+		t.evicted(key, e.data)
+		var zero V
+		return zero, false
+	}
+	t.mu.Unlock()
+	return e.data, ok
+}
+
+// Delete removes key from the tree without calling OnEvict. It returns the
+// data that was stored for key and true, or the zero value of V and false
+// if key is not in the tree. Delete also removes expired entries that have
+// not been evicted yet.
+func (t *ExpiringTree[K, V]) Delete(key K) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.remove(key)
+	return e.data, ok
+}
+
+// Len returns the number of entries, including expired entries that have
+// not been evicted yet.
+func (t *ExpiringTree[K, V]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Len()
+}
+
+// Sweep evicts all expired entries and returns their number.
+func (t *ExpiringTree[K, V]) Sweep() int {
+	t.mu.Lock()
+	now := t.now().UnixNano()
+	var due []int64
+	for expires := range t.expiry.Keys() {
+		if expires > now {
+			break
+		}
+		due = append(due, expires)
+	}
+	var evicted []KV[K, V]
+	for _, expires := range due {
+		for _, key := range t.expiry.RemoveKey(expires) {
+			if e, ok := t.tree.Delete(key); ok {
+				evicted = append(evicted, KV[K, V]{key, e.data})
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	for _, e := range evicted {
+		t.evicted(e.Value, e.Data)
+	}
+	return len(evicted)
+}
+
+// StartSweeper starts a goroutine that calls Sweep every interval, until
+// the returned stop function is called. stop waits for a running sweep to
+// finish.
+func (t *ExpiringTree[K, V]) StartSweeper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				t.Sweep()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// remove deletes key from both indexes. t.mu must be held.
+func (t *ExpiringTree[K, V]) remove(key K) (expiring[V], bool) {
+	e, ok := t.tree.Delete(key)
+	if ok {
+		// cmp.Compare, unlike ==, finds NaN keys.
+		t.expiry.RemoveValue(e.expires, func(k K) bool { return cmp.Compare(k, key) == 0 })
+	}
+	return e, ok
+}
+
+func (t *ExpiringTree[K, V]) evicted(key K, data V) {
+	if t.onEvict != nil {
+		t.onEvict(key, data)
+	}
+}
@@ -0,0 +1,99 @@
+package generictree
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExpiringTree(t *testing.T) {
+	var evicted []string
+	tree := NewExpiringTree[string, int](func(k string, _ int) { evicted = append(evicted, k) })
+	now := time.Unix(1000, 0)
+	tree.now = func() time.Time { return now }
+
+	tree.Set("a", 1, time.Second)
+	tree.Set("b", 2, 3*time.Second)
+	tree.Set("c", 3, 2*time.Second)
+	tree.Set("d", 4, time.Second)
+	tree.Set("d", 5, 5*time.Second) // replaces the entry and its expiry
+
+	now = now.Add(time.Second)
+	if _, ok := tree.Get("a"); ok {
+		t.Error("Get(a) found an expired entry")
+	}
+	if !slices.Equal(evicted, []string{"a"}) {
+		t.Errorf("lazy eviction: evicted %v", evicted)
+	}
+	if v, ok := tree.Get("d"); !ok || v != 5 {
+		t.Errorf("Get(d) = %d, %t; want 5, true", v, ok)
+	}
+
+	now = now.Add(2 * time.Second)
+	if n := tree.Sweep(); n != 2 {
+		t.Errorf("Sweep evicted %d entries, want 2", n)
+	}
+	if !slices.Equal(evicted, []string{"a", "c", "b"}) {
+		t.Errorf("Sweep: evicted %v", evicted)
+	}
+	if n := tree.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+	if v, ok := tree.Delete("d"); !ok || v != 5 {
+		t.Errorf("Delete(d) = %d, %t; want 5, true", v, ok)
+	}
+	now = now.Add(time.Hour)
+	if n := tree.Sweep(); n != 0 || len(evicted) != 3 {
+		t.Errorf("Sweep after Delete evicted %d entries", n)
+	}
+	if tree.expiry.Len() != 0 {
+		t.Errorf("expiry index has %d stale entries", tree.expiry.Len())
+	}
+}
+
+func TestExpiringTree_NaN(t *testing.T) {
+	var evicted []int
+	tree := NewExpiringTree[float64, int](func(_ float64, v int) { evicted = append(evicted, v) })
+	now := time.Unix(1000, 0)
+	tree.now = func() time.Time { return now }
+
+	nan := math.NaN()
+	tree.Set(nan, 1, time.Second)
+	tree.Set(nan, 2, 5*time.Second) // must also replace the expiry of NaN
+	now = now.Add(time.Second)
+	if n := tree.Sweep(); n != 0 || len(evicted) != 0 {
+		t.Errorf("Sweep evicted %v, want nothing", evicted)
+	}
+	if v, ok := tree.Get(nan); !ok || v != 2 {
+		t.Errorf("Get(NaN) = %d, %t; want 2, true", v, ok)
+	}
+	if _, ok := tree.Delete(nan); !ok {
+		t.Error("Delete(NaN) found nothing")
+	}
+	if tree.expiry.Len() != 0 {
+		t.Errorf("expiry index has %d stale entries", tree.expiry.Len())
+	}
+}
+
+func TestExpiringTree_StartSweeper(t *testing.T) {
+	evicted := make(chan string, 1)
+	var tree *ExpiringTree[string, int]
+	tree = NewExpiringTree[string, int](func(k string, _ int) {
+		tree.Len() // callbacks may use the tree
+		evicted <- k
+	})
+	stop := tree.StartSweeper(time.Millisecond)
+	defer stop()
+	tree.Set("x", 1, 0)
+	select {
+	case k := <-evicted:
+		if k != "x" {
+			t.Errorf("evicted %q, want x", k)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("sweeper did not evict the expired entry")
+	}
+	stop()
+	stop() // stopping twice is fine
+}
@@ -0,0 +1,73 @@
+package generictree
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ExportOption configures Tree.Export.
+type ExportOption func(*exportConfig)
+
+type exportConfig struct {
+	limit    int // 0 means no limit
+	deadline time.Time
+}
+
+// ExportLimit makes Export stop after n entries.
+func ExportLimit(n int) ExportOption {
+	return func(c *exportConfig) { c.limit = n }
+}
+
+// ExportDeadline makes Export stop once the time d has passed.
+func ExportDeadline(d time.Time) ExportOption {
+	return func(c *exportConfig) { c.deadline = d }
+}
+
+// Export writes the entries of t to w as JSON lines of the form
+// {"value":...,"data":...}, in tree order, and can be stopped and resumed,
+// so that a large tree can be exported in several steps while it is in
+// use. token tells where to start: the empty token starts with the first
+// entry, and any other token must come from an earlier call of Export or
+// Page on the same tree (see Token). If an option stops the export before
+// the last entry, Export returns the token to continue with; otherwise, it
+// returns the empty token.
+//
+// If w returns an error, Export returns it with the token that resumes
+// after the last complete line. Entries that are inserted behind the
+// token before the export is resumed are included, and entries before it
+// are not. Export returns an error wrapping ErrToken if token is invalid.
+//
+// The lines encode values and data with encoding/json, like MarshalJSON;
+// only the tokens use the codecs of CodecFor.
+func (t *Tree[Value, Data]) Export(w io.Writer, token Token, opts ...ExportOption) (next Token, err error) {
+	var cfg exportConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	b, err := decodeBookmark[Value](token)
+	if err != nil {
+		return "", err
+	}
+
+	c := t.Cursor()
+	ok := t.seekAfter(c, b.After, b.Dups)
+	enc := json.NewEncoder(w)
+	for n := 0; ok; n++ {
+		if cfg.limit > 0 && n >= cfg.limit || !cfg.deadline.IsZero() && time.Now().After(cfg.deadline) {
+			return b.token()
+		}
+		v := c.Value()
+		if err := enc.Encode(KV[Value, Data]{v, c.Data()}); err != nil {
+			next, _ = b.token()
+			return next, err
+		}
+		if b.After != nil && t.compare(*b.After, v) == 0 && b.Dups >= 0 {
+			b.Dups++
+		} else {
+			b.After, b.Dups = &v, 1
+		}
+		ok = c.Next()
+	}
+	return "", nil
+}
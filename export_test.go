@@ -0,0 +1,121 @@
+package generictree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// exported decodes the JSON lines written by Export.
+func exported(t *testing.T, b []byte) []KV[int, string] {
+	t.Helper()
+	var kvs []KV[int, string]
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		var kv KV[int, string]
+		if err := json.Unmarshal(s.Bytes(), &kv); err != nil {
+			t.Fatal(err)
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
+
+func TestTree_Export(t *testing.T) {
+	tree := New[int, string]()
+	for i := range 10 {
+		tree.Insert(i, string(rune('a'+i)))
+	}
+
+	var buf bytes.Buffer
+	var token Token
+	steps := 0
+	for {
+		next, err := tree.Export(&buf, token, ExportLimit(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps++
+		if next == "" {
+			break
+		}
+		token = next
+		if steps == 2 {
+			// Changes behind the token are exported, those before it not.
+			tree.Insert(-1, "new")
+			tree.Insert(100, "z")
+		}
+	}
+	if steps != 4 {
+		t.Errorf("steps = %d, want 4", steps)
+	}
+	var values []int
+	for _, kv := range exported(t, buf.Bytes()) {
+		values = append(values, kv.Value)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 100}; !slices.Equal(values, want) {
+		t.Errorf("exported %v, want %v", values, want)
+	}
+}
+
+func TestTree_Export_duplicates(t *testing.T) {
+	tree := New[int, string](WithAllowDuplicates())
+	for _, d := range []string{"a", "b", "c", "d"} {
+		tree.Insert(1, d)
+	}
+	tree.Insert(2, "e")
+
+	var buf bytes.Buffer
+	token, err := tree.Export(&buf, "", ExportLimit(2))
+	if err != nil || token == "" {
+		t.Fatalf("Export = %s, %v", token, err)
+	}
+	if token, err = tree.Export(&buf, token); err != nil || token != "" {
+		t.Fatalf("resumed Export = %s, %v", token, err)
+	}
+	var data []string
+	for _, kv := range exported(t, buf.Bytes()) {
+		data = append(data, kv.Data)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(data, want) {
+		t.Errorf("exported %v, want %v", data, want)
+	}
+}
+
+func TestTree_Export_deadline(t *testing.T) {
+	tree := New[int, string]()
+	tree.Insert(1, "a")
+	var buf bytes.Buffer
+	token, err := tree.Export(&buf, "", ExportDeadline(time.Now().Add(-time.Second)))
+	if err != nil || token == "" || buf.Len() != 0 {
+		t.Fatalf("Export after the deadline = %s, %v, %d bytes", token, err, buf.Len())
+	}
+	if token, err = tree.Export(&buf, token); err != nil || token != "" || buf.Len() == 0 {
+		t.Fatalf("resumed Export = %s, %v, %d bytes", token, err, buf.Len())
+	}
+	if _, err := tree.Export(&buf, "not a token"); !errors.Is(err, ErrToken) {
+		t.Errorf("Export(invalid) error = %v, want ErrToken", err)
+	}
+}
+
+func TestTree_Export_bookmark(t *testing.T) {
+	tree := New[int, string]()
+	for i := range 5 {
+		tree.Insert(i, string(rune('a'+i)))
+	}
+	var buf bytes.Buffer
+	if _, err := tree.Export(&buf, tree.Bookmark(2)); err != nil {
+		t.Fatal(err)
+	}
+	var values []int
+	for _, kv := range exported(t, buf.Bytes()) {
+		values = append(values, kv.Value)
+	}
+	if want := []int{3, 4}; !slices.Equal(values, want) {
+		t.Errorf("exported %v after Bookmark(2), want %v", values, want)
+	}
+}
@@ -0,0 +1,19 @@
+package generictree
+
+// Hash returns a fingerprint of the contents of the tree: a hash of the
+// sequence of entries in tree order, where h hashes a single entry. Trees
+// with the same entries in the same order have the same fingerprint,
+// whatever their shapes, so replicas can compare fingerprints before they
+// resort to Diff. Different contents collide with a probability of about
+// 2^-64 if h spreads its results well, for example with hash/maphash and a
+// shared seed.
+func (t *Tree[Value, Data]) Hash(h func(Value, Data) uint64) uint64 {
+	// Each step mixes the previous state with the next entry through a
+	// bijection, so that the result depends on the order of the entries.
+	acc := uint64(0x9e3779b97f4a7c15)
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		acc = mix(acc ^ h(n.Value, n.Data))
+		return true
+	})
+	return mix(acc ^ uint64(t.Len()))
+}
@@ -0,0 +1,52 @@
+package generictree
+
+import (
+	"hash/maphash"
+	"strconv"
+	"testing"
+)
+
+func TestTree_Hash(t *testing.T) {
+	seed := maphash.MakeSeed()
+	h := func(v int, d string) uint64 {
+		var m maphash.Hash
+		m.SetSeed(seed)
+		m.WriteString(strconv.Itoa(v))
+		m.WriteByte(0)
+		m.WriteString(d)
+		return m.Sum64()
+	}
+
+	a, b := &Tree[int, string]{}, &Tree[int, string]{}
+	if a.Hash(h) != b.Hash(h) {
+		t.Error("empty trees have different fingerprints")
+	}
+	for i := range 100 {
+		a.Insert(i, "x")
+		b.Insert(99-i, "x") // same contents, different shape
+	}
+	if a.Hash(h) != b.Hash(h) {
+		t.Error("equal trees have different fingerprints")
+	}
+	b.Insert(50, "y")
+	if a.Hash(h) == b.Hash(h) {
+		t.Error("changed data does not change the fingerprint")
+	}
+	b.Insert(50, "x")
+	b.Delete(99)
+	if a.Hash(h) == b.Hash(h) {
+		t.Error("a deletion does not change the fingerprint")
+	}
+
+	// Swapping the data of two entries changes the order of the entry
+	// hashes, which must change the fingerprint.
+	c, d := &Tree[int, string]{}, &Tree[int, string]{}
+	c.Insert(1, "a")
+	c.Insert(2, "b")
+	d.Insert(1, "b")
+	d.Insert(2, "a")
+	entry := func(_ int, s string) uint64 { return uint64(s[0]) }
+	if c.Hash(entry) == d.Hash(entry) {
+		t.Error("the fingerprint does not depend on the order of the entries")
+	}
+}
@@ -0,0 +1,87 @@
+package generictree
+
+import (
+	"cmp"
+	"container/list"
+	"fmt"
+)
+
+// WithFrontCache keeps the nodes of the size most recently looked up values
+// in a hash map that lookups consult before they descend into the tree.
+// For skewed workloads, in which a few values receive most lookups, this
+// replaces most descents by a single map lookup; HottestKeys tells whether
+// a workload is skewed enough (see WithAccessCounts). Stats reports the
+// hits and misses of the cache.
+//
+// The cache serves Find, Get, Contains, FindEntry, and all other operations
+// that look up a single value. It drops the values that are deleted in any
+// way, including by ReKey, eviction, or Subtree; rotations do not move
+// entries to other nodes, so they do not affect it.
+//
+// The cache finds values with ==, so it must agree with the tree's order.
+// New panics if WithFrontCache is combined with WithAllowDuplicates, which
+// makes values ambiguous, or with WithComparator, under which values that
+// differ can compare equal. Floating-point NaNs are never cached.
+// WithFrontCache panics if size is less than 1.
+func WithFrontCache(size int) Option {
+	if size < 1 {
+		panic(fmt.Sprintf("generictree: WithFrontCache: size %d is less than 1", size))
+	}
+	return func(o *options) { o.frontCache = size }
+}
+
+// frontCache maps recently looked up values to their nodes, in the order
+// of their last lookup, the most recent first.
+type frontCache[Value cmp.Ordered, Data any] struct {
+	order        list.List // of *cached[Value, Data]
+	elems        map[Value]*list.Element
+	size         int
+	hits, misses int
+}
+
+type cached[Value cmp.Ordered, Data any] struct {
+	value Value
+	node  *Node[Value, Data]
+}
+
+func newFrontCache[Value cmp.Ordered, Data any](size int) *frontCache[Value, Data] {
+	return &frontCache[Value, Data]{elems: make(map[Value]*list.Element, size), size: size}
+}
+
+// get returns the cached node of value, or nil.
+func (c *frontCache[Value, Data]) get(value Value) *Node[Value, Data] {
+	e, ok := c.elems[value]
+	if !ok {
+		c.misses++
+		return nil
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*cached[Value, Data]).node
+}
+
+// put caches n as the node of value. When the cache is full, it reuses the
+// element of the least recently looked up value.
+func (c *frontCache[Value, Data]) put(value Value, n *Node[Value, Data]) {
+	if value != value {
+		return // NaN: a map can neither find nor delete it
+	}
+	if c.order.Len() < c.size {
+		c.elems[value] = c.order.PushFront(&cached[Value, Data]{value, n})
+		return
+	}
+	e := c.order.Back()
+	entry := e.Value.(*cached[Value, Data])
+	delete(c.elems, entry.value)
+	entry.value, entry.node = value, n
+	c.elems[value] = e
+	c.order.MoveToFront(e)
+}
+
+// remove drops value from the cache.
+func (c *frontCache[Value, Data]) remove(value Value) {
+	if e, ok := c.elems[value]; ok {
+		c.order.Remove(e)
+		delete(c.elems, value)
+	}
+}
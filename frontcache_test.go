@@ -0,0 +1,106 @@
+package generictree
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestWithFrontCache(t *testing.T) {
+	tree := New[int, string](WithFrontCache(2))
+	for v := range 10 {
+		tree.Insert(v, "old")
+	}
+	for range 3 {
+		tree.Find(5)
+	}
+	if s := tree.Stats(); s.CacheHits != 2 || s.CacheMisses != 1 {
+		t.Errorf("after 3 lookups of one value: %d hits, %d misses; want 2, 1", s.CacheHits, s.CacheMisses)
+	}
+
+	// Cached nodes see updates of their data.
+	tree.Insert(5, "new")
+	if d, _ := tree.Find(5); d != "new" {
+		t.Errorf("Find(5) = %q after an update, want new", d)
+	}
+
+	// Deleting a value in any way drops it from the cache.
+	tree.Find(3)
+	tree.Delete(5)
+	if tree.Contains(5) {
+		t.Error("Contains(5) after Delete")
+	}
+	if err := tree.ReKey(3, 30); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Contains(3) || !tree.Contains(30) {
+		t.Error("ReKey(3, 30) left the cache stale")
+	}
+	tree.Find(1)
+	tree.Subtree(0, 2)
+	if tree.Contains(1) {
+		t.Error("Contains(1) after Subtree(0, 2)")
+	}
+	if n := tree.cfg.front.order.Len(); n > 2 {
+		t.Errorf("cache holds %d nodes, want at most 2", n)
+	}
+}
+
+func TestWithFrontCache_random(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	tree := New[int, int](WithFrontCache(8), WithMaxSize(40, EvictLRU))
+	plain := New[int, int](WithMaxSize(40, EvictLRU))
+	for i := range 20000 {
+		v := int(r.ExpFloat64() * 4) // skewed towards small values
+		switch r.IntN(20) {
+		case 0, 1, 2:
+			tree.Insert(v, i)
+			plain.Insert(v, i)
+		case 3:
+			tree.Delete(v)
+			plain.Delete(v)
+		default:
+			d1, ok1 := tree.Find(v)
+			d2, ok2 := plain.Find(v)
+			if d1 != d2 || ok1 != ok2 {
+				t.Fatalf("step %d: Find(%d) = %d, %v; want %d, %v", i, v, d1, ok1, d2, ok2)
+			}
+		}
+	}
+	if s := tree.Stats(); s.CacheHits < s.CacheMisses {
+		t.Errorf("%d hits and %d misses on a skewed workload", s.CacheHits, s.CacheMisses)
+	}
+}
+
+func TestWithFrontCache_NaN(t *testing.T) {
+	tree := New[float64, int](WithFrontCache(2))
+	tree.Insert(math.NaN(), 1)
+	for range 10 {
+		if d, ok := tree.Find(math.NaN()); !ok || d != 1 {
+			t.Fatalf("Find(NaN) = %d, %v", d, ok)
+		}
+	}
+	if n := len(tree.cfg.front.elems); n != 0 {
+		t.Errorf("cache holds %d NaN keys", n)
+	}
+}
+
+func TestWithFrontCache_panics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"size":       func() { WithFrontCache(0) },
+		"duplicates": func() { New[int, int](WithFrontCache(4), WithAllowDuplicates()) },
+		"comparator": func() {
+			New[string, int](WithFrontCache(4), WithComparator(strings.Compare))
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,153 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+)
+
+// FrozenTree is an immutable copy of a tree, optimized for lookups. It
+// stores the entries in two arrays in Eytzinger layout: the root at index 1
+// and the children of index k at 2k and 2k+1, the layout of a binary heap.
+// A search then walks through memory in a predictable pattern that the
+// CPU can prefetch, without pointers to chase.
+//
+// A FrozenTree has no methods that modify it, so it is safe for concurrent
+// use.
+type FrozenTree[Value cmp.Ordered, Data any] struct {
+	values  []Value // index 0 is unused
+	data    []Data
+	compare func(a, b Value) int // nil means cmp.Compare
+}
+
+// Freeze returns a FrozenTree with the entries of t in O(n) time. Later
+// changes to t do not affect the FrozenTree.
+func (t *Tree[Value, Data]) Freeze() *FrozenTree[Value, Data] {
+	n := t.Len()
+	f := &FrozenTree[Value, Data]{
+		values: make([]Value, n+1),
+		data:   make([]Data, n+1),
+	}
+	if t.cfg != nil {
+		f.compare = t.cfg.compare
+	}
+	// An in-order walk of the implicit tree visits the entries in order.
+	next := t.Cursor()
+	next.First()
+	var fill func(k int)
+	fill = func(k int) {
+		if k > n {
+			return
+		}
+		fill(2 * k)
+		f.values[k], f.data[k] = next.Value(), next.Data()
+		next.Next()
+		fill(2*k + 1)
+	}
+	fill(1)
+	return f
+}
+
+// Len returns the number of entries.
+func (f *FrozenTree[Value, Data]) Len() int {
+	return len(f.values) - 1
+}
+
+// lowerBound returns the index of the first entry whose value is not less
+// than value, or 0 if there is none.
+func (f *FrozenTree[Value, Data]) lowerBound(value Value) int {
+	n, k := len(f.values)-1, 1
+	if f.compare == nil {
+		for k <= n {
+			k = 2*k + b2i(cmp.Less(f.values[k], value))
+		}
+	} else {
+		for k <= n {
+			k = 2*k + b2i(f.compare(f.values[k], value) < 0)
+		}
+	}
+	// The search went right after the last node where it went left; that
+	// node is the result. Undo the right turns and the last left turn.
+	return k >> (bits.TrailingZeros(^uint(k)) + 1)
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (f *FrozenTree[Value, Data]) cmp(a, b Value) int {
+	if f.compare == nil {
+		return cmp.Compare(a, b)
+	}
+	return f.compare(a, b)
+}
+
+// Find returns the data stored for value and true, or the zero value of
+// Data and false if value is not in the tree.
+func (f *FrozenTree[Value, Data]) Find(value Value) (Data, bool) {
+	k := f.lowerBound(value)
+	if k == 0 || f.cmp(f.values[k], value) != 0 {
+		var zero Data
+		return zero, false
+	}
+	return f.data[k], true
+}
+
+// Contains reports whether value is in the tree.
+func (f *FrozenTree[Value, Data]) Contains(value Value) bool {
+	_, ok := f.Find(value)
+	return ok
+}
+
+// Ceiling returns the first entry whose value is not less than value in
+// the tree's order. If there is no such entry, ok is false.
+func (f *FrozenTree[Value, Data]) Ceiling(value Value) (v Value, data Data, ok bool) {
+	k := f.lowerBound(value)
+	if k == 0 {
+		return v, data, false
+	}
+	return f.values[k], f.data[k], true
+}
+
+// Min returns the first entry in the tree's order.
+// If the tree is empty, ok is false.
+func (f *FrozenTree[Value, Data]) Min() (value Value, data Data, ok bool) {
+	k := 1
+	for 2*k < len(f.values) {
+		k *= 2
+	}
+	if k >= len(f.values) {
+		return value, data, false
+	}
+	return f.values[k], f.data[k], true
+}
+
+// Max returns the last entry in the tree's order.
+// If the tree is empty, ok is false.
+func (f *FrozenTree[Value, Data]) Max() (value Value, data Data, ok bool) {
+	k := 1
+	for 2*k+1 < len(f.values) {
+		k = 2*k + 1
+	}
+	if k >= len(f.values) {
+		return value, data, false
+	}
+	return f.values[k], f.data[k], true
+}
+
+// All returns an iterator over the entries in the tree's order.
+func (f *FrozenTree[Value, Data]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		var walk func(k int) bool
+		walk = func(k int) bool {
+			if k >= len(f.values) {
+				return true
+			}
+			return walk(2*k) && yield(f.values[k], f.data[k]) && walk(2*k+1)
+		}
+		walk(1)
+	}
+}
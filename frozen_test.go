@@ -0,0 +1,77 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTree_Freeze(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(7, 8))
+	for _, opts := range [][]Option{nil, {WithDescending()}} {
+		for n := range 70 {
+			tree := New[int, int](opts...)
+			for tree.Len() < n {
+				v := rnd.IntN(3 * n)
+				tree.Insert(v, -v)
+			}
+			f := tree.Freeze()
+			if f.Len() != n {
+				t.Fatalf("Len() = %d, want %d", f.Len(), n)
+			}
+			var got []int
+			for v, d := range f.All() {
+				if d != -v {
+					t.Fatalf("All: data of %d is %d", v, d)
+				}
+				got = append(got, v)
+			}
+			if !slices.Equal(got, tree.values()) {
+				t.Fatalf("All: got %v, want %v", got, tree.values())
+			}
+			for v := -1; v <= 3*n; v++ {
+				d, ok := f.Find(v)
+				wd, wok := tree.Find(v)
+				if d != wd || ok != wok {
+					t.Fatalf("Find(%d) = %d, %t; want %d, %t", v, d, ok, wd, wok)
+				}
+				cv, _, cok := f.Ceiling(v)
+				wv, _, wok := tree.Ceiling(v)
+				if cv != wv || cok != wok {
+					t.Fatalf("Ceiling(%d) = %d, %t; want %d, %t", v, cv, cok, wv, wok)
+				}
+			}
+			minV, _, minOK := f.Min()
+			wantMin, _, _ := tree.Min()
+			maxV, _, maxOK := f.Max()
+			wantMax, _, _ := tree.Max()
+			if minOK != (n > 0) || maxOK != (n > 0) || n > 0 && (minV != wantMin || maxV != wantMax) {
+				t.Fatalf("n = %d: Min() = %d, %t, Max() = %d, %t", n, minV, minOK, maxV, maxOK)
+			}
+		}
+	}
+}
+
+func BenchmarkFrozenTree(b *testing.B) {
+	const n = 1 << 20
+	tree := New[int, int]()
+	for i := range n {
+		tree.Insert(i, i)
+	}
+	frozen := tree.Freeze()
+	keys := make([]int, 4096)
+	rnd := rand.New(rand.NewPCG(1, 1))
+	for i := range keys {
+		keys[i] = rnd.IntN(n)
+	}
+	b.Run("Tree", func(b *testing.B) {
+		for i := range b.N {
+			tree.Find(keys[i%len(keys)])
+		}
+	})
+	b.Run("FrozenTree", func(b *testing.B) {
+		for i := range b.N {
+			frozen.Find(keys[i%len(keys)])
+		}
+	})
+}
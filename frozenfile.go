@@ -0,0 +1,425 @@
+package generictree
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// The compact encoding of a FrozenTree, written by FrozenTree.Encode and
+// read by OpenFrozen. All integers are little-endian. The shape of the
+// tree is not stored: the keys are stored in tree order, and a search is a
+// binary search over them.
+//
+//	magic    "GTF\x01"
+//	kind     1 byte: 1 signed integers, 2 unsigned integers, 3 floats,
+//	         4 strings
+//	width    1 byte: bytes per numeric key, 0 for strings
+//	n        uvarint: number of entries
+//	keys     numeric keys: n keys of width bytes each
+//	         string keys: see below
+//	offsets  n+1 uint32: offsets of the data of each entry in the data
+//	         block, and the length of the data block
+//	data     the encoded data of all entries
+//
+// String keys are front-coded: each key is stored as the length of the
+// prefix it shares with the previous key, the length of the rest, and the
+// rest. Every frozenRestart-th key is stored in full, and a table of the
+// offsets of these keys lets a search jump into the middle:
+//
+//	size     uvarint: size of the encoded keys in bytes
+//	keys     the encoded keys
+//	restarts ceil(n/frozenRestart) uint32: offsets of the full keys
+const (
+	frozenMagic   = "GTF\x01"
+	frozenRestart = 16
+)
+
+const (
+	kindInt byte = iota + 1
+	kindUint
+	kindFloat
+	kindString
+)
+
+// ErrFormat is returned by OpenFrozen for data that is not a valid
+// encoding of a FrozenTree.
+var ErrFormat = errors.New("generictree: invalid frozen tree encoding")
+
+// keyKind returns the encoding kind and width of Value.
+func keyKind[Value cmp.Ordered]() (kind, width byte) {
+	t := reflect.TypeFor[Value]()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return kindInt, byte(t.Size())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return kindUint, byte(t.Size())
+	case reflect.Float32, reflect.Float64:
+		return kindFloat, byte(t.Size())
+	}
+	return kindString, 0
+}
+
+// Encode writes f to w in a compact binary format that OpenFrozen can
+// query without decoding it first. encode turns the data of each entry
+// into bytes, for example the Encode method of a Codec. Numeric keys take
+// as many bytes as their type, and string keys are front-coded, which
+// shrinks keys with common prefixes.
+func (f *FrozenTree[Value, Data]) Encode(w io.Writer, encode func(Data) ([]byte, error)) error {
+	fw := newFrozenWriter[Value](new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer))
+	for v, d := range f.All() {
+		enc, err := encode(d)
+		if err != nil {
+			return err
+		}
+		if err := fw.add(v, enc); err != nil {
+			return err
+		}
+	}
+	return fw.finish(w)
+}
+
+// frozenWriter writes the encoding of a frozen tree one entry at a time,
+// in tree order. The sections of the encoding go to separate buffers,
+// which finish joins. Buffers can be temporary files, which lets encodings
+// larger than memory be written; files must be rewound before finish.
+type frozenWriter[Value cmp.Ordered] struct {
+	kind, width byte
+	n           int
+	prev        string
+	keysLen     uint64
+	dataLen     uint64
+	keys        io.ReadWriter
+	restarts    io.ReadWriter
+	offsets     io.ReadWriter
+	data        io.ReadWriter
+	buf         []byte
+}
+
+func newFrozenWriter[Value cmp.Ordered](keys, restarts, offsets, data io.ReadWriter) *frozenWriter[Value] {
+	kind, width := keyKind[Value]()
+	return &frozenWriter[Value]{kind: kind, width: width, keys: keys, restarts: restarts, offsets: offsets, data: data}
+}
+
+// add appends an entry with the encoded data enc.
+func (fw *frozenWriter[Value]) add(v Value, enc []byte) error {
+	b := fw.buf[:0]
+	if fw.kind != kindString {
+		b = appendNumber(b, reflect.ValueOf(v), fw.width)
+	} else {
+		s := reflect.ValueOf(v).String()
+		shared := 0
+		if fw.n%frozenRestart == 0 {
+			var r [4]byte
+			binary.LittleEndian.PutUint32(r[:], uint32(fw.keysLen))
+			if _, err := fw.restarts.Write(r[:]); err != nil {
+				return err
+			}
+		} else {
+			for shared < min(len(s), len(fw.prev)) && s[shared] == fw.prev[shared] {
+				shared++
+			}
+		}
+		b = binary.AppendUvarint(b, uint64(shared))
+		b = binary.AppendUvarint(b, uint64(len(s)-shared))
+		b = append(b, s[shared:]...)
+		fw.prev = s
+	}
+	if _, err := fw.keys.Write(b); err != nil {
+		return err
+	}
+	fw.keysLen += uint64(len(b))
+
+	b = binary.LittleEndian.AppendUint32(b[:0], uint32(fw.dataLen))
+	if _, err := fw.offsets.Write(b); err != nil {
+		return err
+	}
+	if _, err := fw.data.Write(enc); err != nil {
+		return err
+	}
+	fw.dataLen += uint64(len(enc))
+	fw.buf = b
+	fw.n++
+	if fw.dataLen > math.MaxUint32 || fw.keysLen > math.MaxUint32 {
+		return errors.New("generictree: frozen tree too large to encode")
+	}
+	return nil
+}
+
+// finish writes the header and the sections to w.
+func (fw *frozenWriter[Value]) finish(w io.Writer) error {
+	b := []byte(frozenMagic)
+	b = append(b, fw.kind, fw.width)
+	b = binary.AppendUvarint(b, uint64(fw.n))
+	if fw.kind == kindString {
+		b = binary.AppendUvarint(b, fw.keysLen)
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	for _, section := range []io.Reader{fw.keys, fw.restarts, fw.offsets} {
+		if _, err := io.Copy(w, section); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(binary.LittleEndian.AppendUint32(nil, uint32(fw.dataLen))); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, fw.data)
+	return err
+}
+
+func appendNumber(b []byte, v reflect.Value, width byte) []byte {
+	var x uint64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x = uint64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x = v.Uint()
+	case reflect.Float32:
+		x = uint64(math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		x = math.Float64bits(v.Float())
+	}
+	return binary.LittleEndian.AppendUint64(b, x)[:len(b)+int(width)]
+}
+
+// FrozenView is a read-only tree that works directly on the encoding
+// written by FrozenTree.Encode. It decodes only the keys that a search
+// visits and the data of the entries it returns.
+type FrozenView[Value cmp.Ordered, Data any] struct {
+	n        int
+	kind     byte
+	width    int
+	keys     []byte
+	restarts []byte
+	offsets  []byte
+	data     []byte
+	decode   func([]byte) (Data, error)
+	compare  func(a, b Value) int
+}
+
+// OpenFrozen returns a view of b, which must hold a FrozenTree encoded with
+// FrozenTree.Encode for the same Value type. decode turns the bytes
+// written by the encode function back into data, for example the Decode
+// method of the Codec that encoded them. opts must specify the
+// same order as the frozen tree (see WithComparator and WithDescending);
+// other options are ignored. The view refers to b, which must not be
+// modified.
+func OpenFrozen[Value cmp.Ordered, Data any](b []byte, decode func([]byte) (Data, error), opts ...Option) (*FrozenView[Value, Data], error) {
+	kind, width := keyKind[Value]()
+	if len(b) < len(frozenMagic)+2 || string(b[:len(frozenMagic)]) != frozenMagic {
+		return nil, ErrFormat
+	}
+	b = b[len(frozenMagic):]
+	if b[0] != kind || b[1] != width {
+		return nil, fmt.Errorf("%w: key kind %d of width %d, want %d of width %d", ErrFormat, b[0], b[1], kind, width)
+	}
+	b = b[2:]
+	v := &FrozenView[Value, Data]{kind: kind, width: int(width), decode: decode, compare: New[Value, Data](opts...).compare}
+
+	n, size := binary.Uvarint(b)
+	if size <= 0 || n > uint64(len(b)) {
+		return nil, ErrFormat
+	}
+	v.n, b = int(n), b[size:]
+	take := func(size uint64) ([]byte, bool) {
+		if size > uint64(len(b)) {
+			return nil, false
+		}
+		part := b[:size]
+		b = b[size:]
+		return part, true
+	}
+	var ok bool
+	if kind == kindString {
+		keysLen, size := binary.Uvarint(b)
+		if size <= 0 {
+			return nil, ErrFormat
+		}
+		b = b[size:]
+		restarts := (uint64(v.n) + frozenRestart - 1) / frozenRestart
+		if v.keys, ok = take(keysLen); !ok {
+			return nil, ErrFormat
+		}
+		if v.restarts, ok = take(4 * restarts); !ok {
+			return nil, ErrFormat
+		}
+	} else if v.keys, ok = take(uint64(v.n) * uint64(width)); !ok {
+		return nil, ErrFormat
+	}
+	if v.offsets, ok = take(4 * uint64(v.n+1)); !ok {
+		return nil, ErrFormat
+	}
+	v.data = b
+	if int(v.offset(v.n)) != len(v.data) {
+		return nil, ErrFormat
+	}
+	return v, nil
+}
+
+func (v *FrozenView[Value, Data]) offset(i int) uint32 {
+	return binary.LittleEndian.Uint32(v.offsets[4*i:])
+}
+
+// Len returns the number of entries.
+func (v *FrozenView[Value, Data]) Len() int {
+	return v.n
+}
+
+func (v *FrozenView[Value, Data]) cmp(a, b Value) int {
+	if v.compare == nil {
+		return cmp.Compare(a, b)
+	}
+	return v.compare(a, b)
+}
+
+// number returns the numeric key at index i.
+func (v *FrozenView[Value, Data]) number(i int) Value {
+	var buf [8]byte
+	copy(buf[:], v.keys[i*v.width:(i+1)*v.width])
+	x := binary.LittleEndian.Uint64(buf[:])
+	var value Value
+	r := reflect.ValueOf(&value).Elem()
+	switch v.kind {
+	case kindInt:
+		shift := 64 - 8*v.width
+		r.SetInt(int64(x<<shift) >> shift) // sign-extend
+	case kindUint:
+		r.SetUint(x)
+	case kindFloat:
+		if v.width == 4 {
+			r.SetFloat(float64(math.Float32frombits(uint32(x))))
+		} else {
+			r.SetFloat(math.Float64frombits(x))
+		}
+	}
+	return value
+}
+
+// stringKeys calls f with the index and the value of each string key from
+// the restart point r onwards, until f returns false.
+func (v *FrozenView[Value, Data]) stringKeys(r int, f func(i int, value Value) bool) error {
+	pos := int(binary.LittleEndian.Uint32(v.restarts[4*r:]))
+	var key []byte
+	for i := r * frozenRestart; i < v.n; i++ {
+		shared, n1 := binary.Uvarint(v.keys[min(pos, len(v.keys)):])
+		if n1 <= 0 {
+			return ErrFormat
+		}
+		rest, n2 := binary.Uvarint(v.keys[pos+n1:])
+		start := pos + n1 + n2
+		if n2 <= 0 || shared > uint64(len(key)) || rest > uint64(len(v.keys)-start) {
+			return ErrFormat
+		}
+		key = append(key[:shared], v.keys[start:start+int(rest)]...)
+		pos = start + int(rest)
+		var value Value
+		reflect.ValueOf(&value).Elem().SetString(string(key))
+		if !f(i, value) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// search returns the index of the first key that is not less than value.
+func (v *FrozenView[Value, Data]) search(value Value) (int, error) {
+	if v.kind != kindString {
+		return sort.Search(v.n, func(i int) bool { return v.cmp(v.number(i), value) >= 0 }), nil
+	}
+	// Find the last restart point whose key is less than value, then scan
+	// its block.
+	restarts := len(v.restarts) / 4
+	var err error
+	r := sort.Search(restarts, func(r int) bool {
+		less := false
+		err = errors.Join(err, v.stringKeys(r, func(_ int, key Value) bool {
+			less = v.cmp(key, value) < 0
+			return false
+		}))
+		return !less
+	})
+	if r == 0 || err != nil {
+		return 0, err
+	}
+	result := min(r*frozenRestart, v.n)
+	err = v.stringKeys(r-1, func(i int, key Value) bool {
+		if i >= r*frozenRestart || v.cmp(key, value) >= 0 {
+			result = i
+			return false
+		}
+		return true
+	})
+	return result, err
+}
+
+// key returns the key at index i.
+func (v *FrozenView[Value, Data]) key(i int) (Value, error) {
+	if v.kind != kindString {
+		return v.number(i), nil
+	}
+	var key Value
+	err := v.stringKeys(i/frozenRestart, func(j int, value Value) bool {
+		key = value
+		return j < i
+	})
+	return key, err
+}
+
+// dataAt decodes the data at index i.
+func (v *FrozenView[Value, Data]) dataAt(i int) (Data, error) {
+	lo, hi := v.offset(i), v.offset(i+1)
+	if lo > hi || int(hi) > len(v.data) {
+		var zero Data
+		return zero, ErrFormat
+	}
+	return v.decode(v.data[lo:hi])
+}
+
+// Find returns the data stored for value and true, or the zero value of
+// Data and false if value is not in the tree. It returns an error if the
+// encoding is corrupt or the data cannot be decoded.
+func (v *FrozenView[Value, Data]) Find(value Value) (Data, bool, error) {
+	var zero Data
+	i, err := v.search(value)
+	if err != nil || i == v.n {
+		return zero, false, err
+	}
+	key, err := v.key(i)
+	if err != nil || v.cmp(key, value) != 0 {
+		return zero, false, err
+	}
+	d, err := v.dataAt(i)
+	return d, err == nil, err
+}
+
+// All returns an iterator over the entries in the tree's order. All stops
+// early if the encoding is corrupt or the data of an entry cannot be
+// decoded; Find reports such errors.
+func (v *FrozenView[Value, Data]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		each := func(i int, key Value) bool {
+			d, err := v.dataAt(i)
+			return err == nil && yield(key, d)
+		}
+		if v.kind == kindString {
+			if v.n > 0 {
+				v.stringKeys(0, each)
+			}
+			return
+		}
+		for i := range v.n {
+			if !each(i, v.number(i)) {
+				return
+			}
+		}
+	}
+}
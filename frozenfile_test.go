@@ -0,0 +1,118 @@
+package generictree
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func encodeString(s string) ([]byte, error) { return []byte(s), nil }
+func decodeString(b []byte) (string, error) { return string(b), nil }
+
+// checkView compares a view with the tree it was encoded from.
+func checkView[Value cmp.Ordered](t *testing.T, tree *Tree[Value, string], probes []Value, opts ...Option) {
+	t.Helper()
+	var b bytes.Buffer
+	if err := tree.Freeze().Encode(&b, encodeString); err != nil {
+		t.Fatal(err)
+	}
+	view, err := OpenFrozen[Value](b.Bytes(), decodeString, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Len() != tree.Len() {
+		t.Errorf("Len() = %d, want %d", view.Len(), tree.Len())
+	}
+	var got, want []string
+	for v, d := range view.All() {
+		got = append(got, fmt.Sprint(v, "=", d))
+	}
+	for v, d := range tree.All() {
+		want = append(want, fmt.Sprint(v, "=", d))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("All:\ngot  %v\nwant %v", got, want)
+	}
+	for _, p := range probes {
+		d, ok, err := view.Find(p)
+		wd, wok := tree.Find(p)
+		if err != nil || d != wd || ok != wok {
+			t.Errorf("Find(%v) = %q, %t, %v; want %q, %t", p, d, ok, err, wd, wok)
+		}
+	}
+}
+
+func TestFrozenView(t *testing.T) {
+	ints := New[int32, string]()
+	var intProbes []int32
+	for i := int32(-100); i < 100; i++ {
+		if i%3 == 0 {
+			ints.Insert(i, strconv.Itoa(int(i)))
+		}
+		intProbes = append(intProbes, i)
+	}
+	checkView(t, ints, intProbes)
+
+	floats := New[float64, string](WithDescending())
+	for i := range 50 {
+		floats.Insert(float64(i)/4, "")
+	}
+	checkView(t, floats, []float64{-1, 0, 0.25, 0.3, 12.25, 100}, WithDescending())
+
+	words := New[string, string]()
+	var wordProbes []string
+	for i := range 100 {
+		w := fmt.Sprintf("user/%03d/profile", i*2)
+		words.Insert(w, strconv.Itoa(i))
+		wordProbes = append(wordProbes, w, fmt.Sprintf("user/%03d/profile", i*2+1))
+	}
+	wordProbes = append(wordProbes, "", "a", "user/", "z")
+	checkView(t, words, wordProbes)
+
+	checkView(t, New[string, string](), []string{"x"})
+}
+
+func TestFrozenView_size(t *testing.T) {
+	tree := New[string, string]()
+	for i := range 1000 {
+		tree.Insert(fmt.Sprintf("sensor/building-a/floor-%d/room-%d", i/100, i%100), "ok")
+	}
+	var b bytes.Buffer
+	tree.Freeze().Encode(&b, encodeString)
+	j, _ := json.Marshal(tree)
+	if b.Len()*3 > len(j) {
+		t.Errorf("encoding takes %d bytes, JSON %d", b.Len(), len(j))
+	}
+}
+
+func TestOpenFrozen_errors(t *testing.T) {
+	tree := New[int, string]()
+	for i := range 20 {
+		tree.Insert(i, "x")
+	}
+	var b bytes.Buffer
+	tree.Freeze().Encode(&b, encodeString)
+	enc := b.Bytes()
+
+	if _, err := OpenFrozen[string](enc, decodeString); !errors.Is(err, ErrFormat) {
+		t.Errorf("wrong key type: got %v, want ErrFormat", err)
+	}
+	for _, n := range []int{0, 3, 10, len(enc) - 1} {
+		if _, err := OpenFrozen[int](enc[:n], decodeString); !errors.Is(err, ErrFormat) {
+			t.Errorf("truncated to %d bytes: got %v, want ErrFormat", n, err)
+		}
+	}
+	errBad := errors.New("bad data")
+	view, err := OpenFrozen[int](enc, func([]byte) (string, error) { return "", errBad })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := view.Find(5); !errors.Is(err, errBad) {
+		t.Errorf("Find with a failing decoder: got %v", err)
+	}
+}
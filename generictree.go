@@ -72,11 +72,36 @@ Let's walk through the code and adjust it as required.
 
 */
 
-// As always, the code starts with package and import statements, as the whole blog article is generated from a single, compilable Go source file.
+// Package generictree implements a generic, AVL-balanced binary search tree.
 //
-// Note the import of the 'cmp' package (added in Go 1.21). This package provides types and functions for comparing ordered values, including the `Ordered` constraint that I need for being able to compare and sort the nodes.
-package main
+// The search values of a [Tree] can be of any ordered type (see [cmp.Ordered]),
+// and the payload data can be of any type:
+//
+//	tree := &generictree.Tree[int, string]{}
+//	tree.Insert(42, "answer")
+//	data, found := tree.Find(42)
+//
+// The package started out as the code of a blog article about turning a
+// binary search tree into a generic data structure. The article text lives
+// in the comments of generictree.go. The demo from the article is in cmd/generictree.
+//
+// [Tree] implements the [SortedMap] interface, as do the alternative backends
+// in the subpackages rbtree (a red-black tree) and btree (a B-tree). Package
+// interval implements an interval tree, and package hashring a
+// consistent-hash ring. Package conformance tests any SortedMap
+// implementation, including third-party ones. Package compat offers the
+// method set of the Map type of github.com/tidwall/btree, so that code
+// written for it can switch to this tree.
+//
+// Trees can be saved and loaded as JSON (see [Tree.MarshalJSON]). The
+// command cmd/treectl inspects, validates, and compares saved trees, and
+// cmd/treeview explores saved trees and live trees served by package
+// treehttp in the terminal. Package treerpc shares a tree with other
+// processes through net/rpc. The command cmd/treewasm runs a tree in the
+// browser for interactive tutorials.
+package generictree
 
+// Note the import of the 'cmp' package (added in Go 1.21). This package provides types and functions for comparing ordered values, including the `Ordered` constraint that I need for being able to compare and sort the nodes.
 import (
 	"cmp"
 	"fmt"
@@ -108,6 +133,8 @@ type Node[Value cmp.Ordered, Data any] struct {
 	Left   *Node[Value, Data]
 	Right  *Node[Value, Data]
 	height int
+	size   int                     // number of nodes in the subtree rooted at this node
+	aug    *augmented[Value, Data] // extra subtree information; nil for plain trees
 }
 
 /*
@@ -156,6 +183,24 @@ func (n *Node[Value, Data]) Bal() int {
 	return n.Right.Height() - n.Left.Height()
 }
 
+// count returns the number of nodes in the subtree rooted at n.
+func (n *Node[Value, Data]) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recalculates the height, size, and augmentation of n from its
+// children.
+func (n *Node[Value, Data]) update() {
+	n.height = max(n.Left.Height(), n.Right.Height()) + 1
+	n.size = n.Left.count() + n.Right.count() + 1
+	if n.aug != nil {
+		n.aug.update(n)
+	}
+}
+
 // Here is the first occurrence of generic parameters and return types.\
 // `value, data string` is now \
 // `value Value, data Data`.\
@@ -168,6 +213,7 @@ func (n *Node[Value, Data]) Insert(value Value, data Data) *Node[Value, Data] {
 			Value:  value,
 			Data:   data,
 			height: 1,
+			size:   1,
 		}
 	}
 	if n.Value == value {
@@ -181,7 +227,7 @@ func (n *Node[Value, Data]) Insert(value Value, data Data) *Node[Value, Data] {
 		n.Right = n.Right.Insert(value, data)
 	}
 
-	n.height = max(n.Left.Height(), n.Right.Height()) + 1
+	n.update()
 
 	return n.rebalance()
 }
@@ -190,45 +236,68 @@ func (n *Node[Value, Data]) Insert(value Value, data Data) *Node[Value, Data] {
 // `#boring`
 func (n *Node[Value, Data]) rotateLeft() *Node[Value, Data] {
 	r := n.Right
+	n.push()
+	r.push()
 	n.Right = r.Left
 	r.Left = n
-	n.height = max(n.Left.Height(), n.Right.Height()) + 1
-	r.height = max(r.Left.Height(), r.Right.Height()) + 1
+	n.update()
+	r.update()
+	n.rotated()
+	r.rotated()
 	return r
 }
 
 func (n *Node[Value, Data]) rotateRight() *Node[Value, Data] {
 	l := n.Left
+	n.push()
+	l.push()
 	n.Left = l.Right
 	l.Right = n
-	n.height = max(n.Left.Height(), n.Right.Height()) + 1
-	l.height = max(l.Left.Height(), l.Right.Height()) + 1
+	n.update()
+	l.update()
+	n.rotated()
+	l.rotated()
 	return l
 }
 
 func (n *Node[Value, Data]) rotateRightLeft() *Node[Value, Data] {
 	n.Right = n.Right.rotateRight()
 	n = n.rotateLeft()
-	n.height = max(n.Left.Height(), n.Right.Height()) + 1
+	n.update()
 	return n
 }
 
 func (n *Node[Value, Data]) rotateLeftRight() *Node[Value, Data] {
 	n.Left = n.Left.rotateLeft()
 	n = n.rotateRight()
-	n.height = max(n.Left.Height(), n.Right.Height()) + 1
+	n.update()
 	return n
 }
 
+// After a deletion, the child on the heavy side can be perfectly balanced.
+// A single rotation fixes this case, hence the `<= 0` and `>= 0` comparisons.
 func (n *Node[Value, Data]) rebalance() *Node[Value, Data] {
-	switch {
-	case n.Bal() < -1 && n.Left.Bal() == -1:
+	return n.rebalanceWithin(balance{limit: 1})
+}
+
+// rebalanceWithin rotates n if its balance factor exceeds ±b.limit (see
+// WithRelaxedBalance), and counts the rotations in b. The subtrees of n
+// must be balanced within b.limit, and the balance factor of n must be at
+// most ±(b.limit+1), as after a single insertion or deletion; one single
+// or double rotation then balances n within b.limit.
+func (n *Node[Value, Data]) rebalanceWithin(b balance) *Node[Value, Data] {
+	switch bal := n.Bal(); {
+	case bal < -b.limit && n.Left.Bal() <= 0:
+		b.count(1)
 		return n.rotateRight()
-	case n.Bal() > 1 && n.Right.Bal() == 1:
+	case bal > b.limit && n.Right.Bal() >= 0:
+		b.count(1)
 		return n.rotateLeft()
-	case n.Bal() < -1 && n.Left.Bal() == 1:
+	case bal < -b.limit:
+		b.count(2)
 		return n.rotateLeftRight()
-	case n.Bal() > 1 && n.Right.Bal() == -1:
+	case bal > b.limit:
+		b.count(2)
 		return n.rotateRightLeft()
 	}
 	return n
@@ -255,6 +324,8 @@ func (n *Node[Value, Data]) Find(s Value) (Data, bool) {
 	}
 }
 
+// Dump prints each node as value[balance,height,size], where size is the
+// number of nodes in the node's subtree.
 func (n *Node[Value, Data]) Dump(i int, lr string) {
 	if n == nil {
 		return
@@ -263,27 +334,59 @@ func (n *Node[Value, Data]) Dump(i int, lr string) {
 	if i > 0 {
 		indent = strings.Repeat(" ", (i-1)*4) + "+" + lr + "--"
 	}
-	fmt.Printf("%s%v[%d,%d]\n", indent, n.Value, n.Bal(), n.Height())
+	fmt.Printf("%s%v[%d,%d,%d]\n", indent, n.Value, n.Bal(), n.Height(), n.count())
 	n.Left.Dump(i+1, "L")
 	n.Right.Dump(i+1, "R")
 }
 
 type Tree[Value cmp.Ordered, Data any] struct {
+	debug debugLog[Value, Data]
+	// Root is the root node of the tree.
+	//
+	// Deprecated: Modifying the nodes directly can break the search order
+	// and the balance of the tree. Use the iterators, Entry, and Cursor
+	// instead. Direct access to the nodes will be removed in v2.
 	Root *Node[Value, Data]
+	cfg  *config[Value, Data] // set by New; nil for a zero Tree
 }
 
+// The tree-level methods use the tree's configuration, such as a custom
+// comparator (see `New`). The internal `insert` method works like `Node.Insert`
+// above, but uses that configuration.
 func (t *Tree[Value, Data]) Insert(value Value, data Data) {
-	t.Root = t.Root.Insert(value, data)
-	if t.Root.Bal() < -1 || t.Root.Bal() > 1 {
-		t.rebalance()
-	}
+	t.put(value, data)
 }
 
-func (t *Tree[Value, Data]) rebalance() {
-	if t == nil || t.Root == nil {
-		return
+// put inserts value and data like Insert and returns the node that holds
+// them, or nil and a *LimitError if a limit rejected the entry (see
+// WithMaxEntries).
+func (t *Tree[Value, Data]) put(value Value, data Data) (*Node[Value, Data], error) {
+	if tr := t.tracing(); tr != nil {
+		defer traceEnd(tr, "Insert", value, tr.begin())
+	}
+	if err := t.checkLimits(value); err != nil {
+		return nil, err
+	}
+	var (
+		old      Data
+		n        *Node[Value, Data]
+		replaced bool
+	)
+	if hooks := t.hooks(); len(hooks) == 0 {
+		t.Root, n, replaced = t.insert(t.Root, value, data, nil)
+	} else {
+		t.Root, n, replaced = t.insert(t.Root, value, data, &old)
 	}
-	t.Root = t.Root.rebalance()
+	if replaced && t.keepsExisting() {
+		return n, nil
+	}
+	t.debug.record(t, "Insert", value, data)
+	t.afterInsert(value, n.Data, old, replaced)
+	if !replaced {
+		t.enforceMaxSize()
+		t.checkHeight()
+	}
+	return n, nil
 }
 
 func (t *Tree[Value, Data]) Find(s Value) (Data, bool) {
@@ -293,9 +396,21 @@ func (t *Tree[Value, Data]) Find(s Value) (Data, bool) {
 		// `new` returns a pointer, and hence we need to add the dereferencing operator.
 		return *new(Data), false
 	}
-	return t.Root.Find(s)
+	if tr := t.tracing(); tr != nil {
+		defer traceEnd(tr, "Find", s, tr.begin())
+	}
+	n := t.findNode(s)
+	if n == nil {
+		return *new(Data), false
+	}
+	t.touch(n)
+	return n.Data, true
 }
 
+// Traverse calls f for each node of the subtree rooted at n, in ascending order.
+//
+// Deprecated: Traverse passes the tree's nodes to f, which lets f break the
+// tree. Use All or Entries instead. Traverse will be removed in v2.
 func (t *Tree[Value, Data]) Traverse(n *Node[Value, Data], f func(*Node[Value, Data])) {
 	if n == nil {
 		return
@@ -308,7 +423,7 @@ func (t *Tree[Value, Data]) Traverse(n *Node[Value, Data], f func(*Node[Value, D
 func (t *Tree[Value, Data]) PrettyPrint() {
 
 	printNode := func(n *Node[Value, Data], depth int) {
-		fmt.Printf("%s%v\n", strings.Repeat("  ", depth), n.Value)
+		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), t.FormatValue(n.Value))
 	}
 	var walk func(*Node[Value, Data], int)
 	walk = func(n *Node[Value, Data], depth int) {
@@ -323,89 +438,23 @@ func (t *Tree[Value, Data]) PrettyPrint() {
 	walk(t.Root, 0)
 }
 
+// Dump prints the tree like Node.Dump, with the values formatted by
+// FormatValue.
 func (t *Tree[Value, Data]) Dump() {
-	t.Root.Dump(0, "")
-}
-
-/*
-## How to use the new generic tree type
-
-Now is the moment where I can instantiate the generic `Tree[Value, Data]` type into something tangible like `Tree[int,string]`.
-
-*/
-//
-func main() {
-	values := []string{"d", "b", "g", "g", "c", "e", "a", "h", "f", "i", "j", "l", "k"}
-	data := []string{"delta", "bravo", "golang", "golf", "charlie", "echo", "alpha", "hotel", "foxtrot", "india", "juliett", "lima", "kilo"}
-
-	// Here, Tree gets instantiated with the `string` type for both Value and Data.
-	// This is basically the same tree as in the original article about balanced trees.
-	tree := &Tree[string, string]{}
-	for i := 0; i < len(values); i++ {
-		tree.Insert(values[i], data[i])
-	}
-
-	fmt.Print("\n*** Tree with string search values and string data ***\n\n")
-	fmt.Print("Sorted values: | ")
-	// As with `*Tree` above, `*Node` also needs to get instantiated with concrete types.
-	tree.Traverse(tree.Root, func(n *Node[string, string]) { fmt.Print(n.Value, ": ", n.Data, " | ") })
-	fmt.Println()
-
-	fmt.Println("Pretty print (turned 90° anti-clockwise):")
-	tree.PrettyPrint()
-
-	// Let's try the same with integers as search values.
-	keys := []int{4, 2, 7, 7, 3, 5, 1, 8, 6, 9, 10, 12, 11}
-	// No new `data` slice here. It remains the same slice of strings.
-
-	// This time, Tree gets instantiated with `int` and `string` for Value and Data, respectively.
-	intTree := &Tree[int, string]{}
-	for i := 0; i < len(keys); i++ {
-		intTree.Insert(keys[i], data[i])
-	}
-
-	fmt.Print("\n*** Tree with int search values and string data ***\n\n")
-	fmt.Print("Sorted values: | ")
-	intTree.Traverse(intTree.Root, func(n *Node[int, string]) { fmt.Print(n.Value, ": ", n.Data, " | ") })
-	fmt.Println()
-
-	fmt.Println("Pretty print")
-	intTree.PrettyPrint()
-
-	/*
-		### How about creating a search tree of search trees?
-
-		Let's feed a search tree with search trees as payload data. \
-		Because why not?\
-		And because doing this can answer an interesting question: Will the syntax of nested generic type instantiatons become unwieldy?
-	*/
-	// The search values shall be integers.
-	keys = []int{3, 1, 2}
-	// I am lazy here and use the existing "string, string" tree thrice.
-	trees := []*Tree[string, string]{tree, tree, tree}
-
-	// This is a nested instantiation of generic types. Nice detail: the syntax really remains readable.
-	treeTree := &Tree[int, *Tree[string, string]]{}
-	for i := 0; i < len(keys); i++ {
-		treeTree.Insert(keys[i], trees[i])
-	}
-
-	fmt.Print("\n*** Tree with int search values and Tree[string, string] data ***\n\n")
-	fmt.Print("Sorted values: | ")
-	// As with `*Tree` above, `*Node` also needs to get instantiated with concrete types.
-	treeTree.Traverse(treeTree.Root, func(n *Node[int, *Tree[string, string]]) { fmt.Print(n.Value, ": ", n.Data, " | ") })
-	fmt.Println()
-
-	fmt.Println("Pretty print:")
-	treeTree.PrettyPrint()
-
-	var val string
-	subtree, found := treeTree.Find(2)
-	if found {
-		val, found = subtree.Find("b")
+	var dump func(n *Node[Value, Data], i int, lr string)
+	dump = func(n *Node[Value, Data], i int, lr string) {
+		if n == nil {
+			return
+		}
+		indent := ""
+		if i > 0 {
+			indent = strings.Repeat(" ", (i-1)*4) + "+" + lr + "--"
+		}
+		fmt.Printf("%s%s[%d,%d,%d]\n", indent, t.FormatValue(n.Value), n.Bal(), n.Height(), n.count())
+		dump(n.Left, i+1, "L")
+		dump(n.Right, i+1, "R")
 	}
-	fmt.Printf("Find \"s\" in subtree 2: %v (found: %t)\n", val, found)
-
+	dump(t.Root, 0, "")
 }
 
 /*
@@ -414,6 +463,13 @@ func main() {
 
 This [code](https://github.com/appliedgo/generictree) runs with Go 1.21 or later. It also runs fine in the [Go Playground](https://go.dev/play/p/Jw9f9zM_bUi).
 
+The tree is now an importable package, `github.com/appliedgo/generictree`, which requires Go 1.23 or later. To run the demo from this article, call
+
+```
+go run github.com/appliedgo/generictree/cmd/generictree@latest
+```
+
+
 
 ## Conclusion
 
@@ -441,6 +497,10 @@ ___
 
 Changelog
 
+2026-10-16
+
+- Turned the code into an importable package. The demo code from `main()` moved to `cmd/generictree`.
+
 2023-08-22
 
 - Updated the code to work with Go 1.21. New: The `cmp` package. Obsolete: the `constraints` package. Link to the playground updated accordingly.
@@ -1,4 +1,4 @@
-package main
+package generictree
 
 import (
 	"cmp"
@@ -178,3 +178,66 @@ func TestTree_rebalance(t *testing.T) {
 		})
 	}
 }
+
+func TestTree_Find(t *testing.T) {
+	for _, tree := range trees {
+		t.Run(tree.name, func(t *testing.T) {
+			tt := newTree(tree)
+			// Later duplicates overwrite the data of earlier ones.
+			want := map[string]string{}
+			for i, v := range tree.value {
+				want[v] = tree.data[i]
+			}
+			for v, d := range want {
+				got, found := tt.Find(v)
+				if !found || got != d {
+					t.Errorf("Find(%q) = %q, %t; want %q, true", v, got, found, d)
+				}
+			}
+			if got, found := tt.Find("not there"); found || got != "" {
+				t.Errorf("Find(%q) = %q, %t; want \"\", false", "not there", got, found)
+			}
+		})
+	}
+
+	var nilTree *Tree[int, int]
+	if _, found := nilTree.Find(1); found {
+		t.Error("Find on a nil tree returned found = true")
+	}
+	if _, found := nilTree.Delete(1); found {
+		t.Error("Delete on a nil tree returned found = true")
+	}
+}
+
+func TestTree_Traverse(t *testing.T) {
+	for _, tree := range trees {
+		t.Run(tree.name, func(t *testing.T) {
+			tt := newTree(tree)
+			var got []string
+			tt.Traverse(tt.Root, func(n *Node[string, string]) { got = append(got, n.Value) })
+			for i := 1; i < len(got); i++ {
+				if got[i-1] >= got[i] {
+					t.Fatalf("Traverse visited %q before %q", got[i-1], got[i])
+				}
+			}
+			unique := map[string]bool{}
+			for _, v := range tree.value {
+				unique[v] = true
+			}
+			if len(got) != len(unique) {
+				t.Errorf("Traverse visited %d nodes, want %d", len(got), len(unique))
+			}
+		})
+	}
+}
+
+func TestNode_Height(t *testing.T) {
+	var n *Node[int, int]
+	if h := n.Height(); h != 0 {
+		t.Errorf("nil node has height %d, want 0", h)
+	}
+	n = n.Insert(1, 1)
+	if h := n.Height(); h != 1 {
+		t.Errorf("single node has height %d, want 1", h)
+	}
+}
@@ -0,0 +1,205 @@
+// Package hashring implements consistent hashing on top of a
+// generictree.Tree.
+//
+// A Ring places each node at several points of a 64-bit hash ring, its
+// virtual nodes. A key belongs to the first virtual node at or after the
+// hash of the key, wrapping around to the smallest hash at the end of the
+// ring. When a node joins or leaves, only the keys next to its virtual
+// nodes change owners; AddNode and RemoveNode report these key ranges so
+// that the data can be moved.
+package hashring
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/appliedgo/generictree"
+)
+
+// DefaultReplicas is the number of virtual nodes per node used by New if
+// replicas is not positive.
+const DefaultReplicas = 100
+
+// Move describes the keys that change owners when a node joins or leaves:
+// the keys whose hashes lie in the arc (Start, End] of the ring move from
+// the node From to the node To. If Start >= End, the arc wraps around past
+// the largest hash.
+type Move struct {
+	Start, End uint64
+	From, To   string
+}
+
+// Contains reports whether a key with hash h lies in the arc of m.
+func (m Move) Contains(h uint64) bool {
+	if m.Start < m.End {
+		return m.Start < h && h <= m.End
+	}
+	return h > m.Start || h <= m.End
+}
+
+// Ring is a consistent-hash ring. The zero value is not usable; create
+// rings with New. A Ring is not safe for concurrent use.
+type Ring struct {
+	tree     *generictree.Tree[uint64, string] // virtual node hash -> node
+	replicas int
+	hash     func([]byte) uint64
+	nodes    map[string][]uint64 // node -> hashes of its virtual nodes
+}
+
+// Option configures a Ring.
+type Option func(*Ring)
+
+// WithHash sets the hash function for keys and virtual nodes. The default
+// is 64-bit FNV-1a.
+func WithHash(hash func([]byte) uint64) Option {
+	return func(r *Ring) { r.hash = hash }
+}
+
+// New returns an empty ring with replicas virtual nodes per node.
+func New(replicas int, opts ...Option) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	r := &Ring{
+		tree:     generictree.New[uint64, string](),
+		replicas: replicas,
+		hash:     fnv64a,
+		nodes:    map[string][]uint64{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func fnv64a(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// Hash returns the position of key on the ring.
+func (r *Ring) Hash(key string) uint64 {
+	return r.hash([]byte(key))
+}
+
+// Get returns the node that owns key, or false if the ring is empty.
+func (r *Ring) Get(key string) (node string, ok bool) {
+	_, node, ok = r.successor(r.Hash(key))
+	return node, ok
+}
+
+// successor returns the first virtual node at or after h, wrapping around.
+func (r *Ring) successor(h uint64) (uint64, string, bool) {
+	if v, node, ok := r.tree.Ceiling(h); ok {
+		return v, node, true
+	}
+	return r.tree.Min()
+}
+
+// predecessor returns the last virtual node before h, wrapping around.
+func (r *Ring) predecessor(h uint64) (uint64, string, bool) {
+	if h > 0 {
+		if v, node, ok := r.tree.Floor(h - 1); ok {
+			return v, node, true
+		}
+	}
+	return r.tree.Max()
+}
+
+// Nodes returns the nodes of the ring in sorted order.
+func (r *Ring) Nodes() []string {
+	nodes := make([]string, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// Len returns the number of nodes in the ring.
+func (r *Ring) Len() int {
+	return len(r.nodes)
+}
+
+// AddNode adds node to the ring and returns the key ranges that move to
+// it, ordered by the end of their arcs. If node is already in the ring or
+// the ring was empty, AddNode returns nil.
+//
+// A virtual node whose hash collides with a virtual node that is already
+// in the ring is skipped.
+func (r *Ring) AddNode(node string) []Move {
+	if _, ok := r.nodes[node]; ok {
+		return nil
+	}
+	empty := r.tree.Len() == 0
+	var hashes []uint64
+	for i := range r.replicas {
+		h := r.hash([]byte(node + "#" + strconv.Itoa(i)))
+		if _, ok := r.tree.Find(h); ok {
+			continue
+		}
+		r.tree.Insert(h, node)
+		hashes = append(hashes, h)
+	}
+	slices.Sort(hashes)
+	r.nodes[node] = hashes
+	if empty {
+		return nil
+	}
+	return r.moves(node, hashes, false)
+}
+
+// RemoveNode removes node from the ring and returns the key ranges that
+// move away from it, ordered by the end of their arcs. If node is not in
+// the ring or was its last node, RemoveNode returns nil.
+func (r *Ring) RemoveNode(node string) []Move {
+	hashes, ok := r.nodes[node]
+	if !ok {
+		return nil
+	}
+	var moves []Move
+	if len(r.nodes) > 1 {
+		moves = r.moves(node, hashes, true)
+	}
+	for _, h := range hashes {
+		r.tree.Delete(h)
+	}
+	delete(r.nodes, node)
+	return moves
+}
+
+// moves returns the arcs owned by the virtual nodes of node at hashes,
+// which are in the ring. Each arc ends at one of the hashes and starts at
+// the previous virtual node; consecutive virtual nodes of node form a
+// single arc. The other end of each move is the owner of the first
+// virtual node after the arc that does not belong to node.
+func (r *Ring) moves(node string, hashes []uint64, leaving bool) []Move {
+	owned := map[uint64]bool{}
+	for _, h := range hashes {
+		owned[h] = true
+	}
+	var moves []Move
+	for _, h := range hashes {
+		next, other, _ := r.successor(h + 1)
+		if owned[next] && next != h {
+			continue // the arc goes on with the next virtual node
+		}
+		// Extend the arc backwards over node's own virtual nodes.
+		start := h
+		for {
+			p, _, _ := r.predecessor(start)
+			start = p
+			if !owned[p] || p == h {
+				break
+			}
+		}
+		m := Move{Start: start, End: h, From: other, To: node}
+		if leaving {
+			m.From, m.To = node, other
+		}
+		moves = append(moves, m)
+	}
+	return moves
+}
@@ -0,0 +1,111 @@
+package hashring
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func keys(n int) []string {
+	k := make([]string, n)
+	for i := range k {
+		k[i] = fmt.Sprintf("key-%d", i)
+	}
+	return k
+}
+
+func owners(r *Ring, keys []string) map[string]string {
+	m := map[string]string{}
+	for _, k := range keys {
+		m[k], _ = r.Get(k)
+	}
+	return m
+}
+
+// checkMoves verifies that exactly the keys in moves changed owners.
+func checkMoves(t *testing.T, r *Ring, keys []string, before, after map[string]string, moves []Move) {
+	t.Helper()
+	for _, k := range keys {
+		h := r.Hash(k)
+		var in []Move
+		for _, m := range moves {
+			if m.Contains(h) {
+				in = append(in, m)
+			}
+		}
+		switch {
+		case before[k] == after[k] && len(in) != 0:
+			t.Errorf("%s stays with %s but is in %v", k, before[k], in)
+		case before[k] != after[k] && len(in) != 1:
+			t.Errorf("%s moves from %s to %s but is in %v", k, before[k], after[k], in)
+		case before[k] != after[k] && (in[0].From != before[k] || in[0].To != after[k]):
+			t.Errorf("%s moves from %s to %s but is in %v", k, before[k], after[k], in[0])
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := New(20)
+	if _, ok := r.Get("x"); ok {
+		t.Error("Get on an empty ring succeeded")
+	}
+	if moves := r.AddNode("a"); moves != nil {
+		t.Errorf("first AddNode returned %v", moves)
+	}
+	if n, _ := r.Get("x"); n != "a" {
+		t.Errorf("Get with one node = %q, want a", n)
+	}
+
+	ks := keys(2000)
+	for _, node := range []string{"b", "c", "d"} {
+		before := owners(r, ks)
+		moves := r.AddNode(node)
+		after := owners(r, ks)
+		if len(moves) == 0 {
+			t.Fatalf("AddNode(%s) moved nothing", node)
+		}
+		checkMoves(t, r, ks, before, after, moves)
+	}
+	if got := r.Nodes(); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Nodes() = %v", got)
+	}
+	if r.AddNode("b") != nil {
+		t.Error("adding an existing node moved keys")
+	}
+
+	for _, node := range []string{"c", "a", "d"} {
+		before := owners(r, ks)
+		moves := r.RemoveNode(node)
+		after := owners(r, ks)
+		checkMoves(t, r, ks, before, after, moves)
+	}
+	if r.RemoveNode("b") != nil || r.Len() != 0 {
+		t.Errorf("removing the last node: Len() = %d", r.Len())
+	}
+	if r.RemoveNode("x") != nil {
+		t.Error("removing an unknown node moved keys")
+	}
+}
+
+func TestRing_wraparound(t *testing.T) {
+	// Place the virtual nodes by hand: a at 100 and b at 200.
+	pos := map[string]uint64{"a#0": 100, "b#0": 200, "k1": 50, "k2": 150, "k3": 250}
+	r := New(1, WithHash(func(b []byte) uint64 { return pos[string(b)] }))
+	r.AddNode("a")
+	moves := r.AddNode("b")
+	if want := []Move{{100, 200, "a", "b"}}; !slices.Equal(moves, want) {
+		t.Errorf("AddNode(b) = %v, want %v", moves, want)
+	}
+	for k, want := range map[string]string{"k1": "a", "k2": "b", "k3": "a"} {
+		if got, _ := r.Get(k); got != want {
+			t.Errorf("Get(%s) = %s, want %s", k, got, want)
+		}
+	}
+	moves = r.RemoveNode("a")
+	if want := []Move{{200, 100, "a", "b"}}; !slices.Equal(moves, want) {
+		t.Errorf("RemoveNode(a) = %v, want %v", moves, want)
+	}
+	if !moves[0].Contains(250) || !moves[0].Contains(50) || moves[0].Contains(150) {
+		t.Errorf("%v does not wrap around", moves[0])
+	}
+}
@@ -0,0 +1,136 @@
+package generictree
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportOption configures Tree.ImportLines.
+type ImportOption func(*importConfig)
+
+type importConfig struct {
+	progress      func(lines int)
+	progressEvery int
+	maxErrors     int
+}
+
+// ImportProgress makes ImportLines call f with the number of lines read so
+// far after every n lines and once at the end of the input.
+func ImportProgress(n int, f func(lines int)) ImportOption {
+	return func(c *importConfig) {
+		c.progress, c.progressEvery = f, max(n, 1)
+	}
+}
+
+// ImportMaxErrors makes ImportLines stop after n lines that it could not
+// import. By default, ImportLines reads the whole input.
+func ImportMaxErrors(n int) ImportOption {
+	return func(c *importConfig) {
+		c.maxErrors = n
+	}
+}
+
+// LineError describes a line that ImportLines could not import.
+type LineError struct {
+	Line int // line number, starting at 1
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ImportLines reads r line by line and inserts the value and data that
+// split returns for each line. Empty lines are skipped; the line passed to
+// split has no line terminator.
+//
+// If split returns an error, ImportLines skips the line and goes on. It
+// returns the number of entries inserted and all errors joined together:
+// a *LineError for each skipped line, and the read error that ended the
+// input, if any.
+//
+// If the tree is empty and the lines are in tree order, ImportLines builds
+// a perfectly balanced tree in one go instead of inserting the entries one
+// by one. It falls back to insertion at the first line out of order.
+func (t *Tree[Value, Data]) ImportLines(r io.Reader, split func(line string) (Value, Data, error), opts ...ImportOption) (n int, err error) {
+	var cfg importConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var errs []error
+	var sorted []KV[Value, Data] // entries in tree order, while the tree is empty
+	bulk := t.Root == nil
+	br := bufio.NewReader(r)
+	lines, bad := 0, 0
+	for cfg.maxErrors == 0 || bad < cfg.maxErrors {
+		line, err := br.ReadString('\n')
+		if err != nil && line == "" {
+			if err != io.EOF {
+				errs = append(errs, err)
+			}
+			break
+		}
+		lines++
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			v, d, splitErr := split(line)
+			switch {
+			case splitErr != nil:
+				errs = append(errs, &LineError{lines, splitErr})
+				bad++
+			case bulk && t.inOrder(sorted, v):
+				sorted = append(sorted, KV[Value, Data]{v, d})
+				n++
+			default:
+				if bulk {
+					t.importSorted(sorted)
+					bulk, sorted = false, nil
+				}
+				t.Insert(v, d)
+				n++
+			}
+		}
+		if cfg.progress != nil && lines%cfg.progressEvery == 0 {
+			cfg.progress(lines)
+		}
+	}
+	if bulk {
+		t.importSorted(sorted)
+	}
+	if cfg.progress != nil && (lines == 0 || lines%cfg.progressEvery != 0) {
+		cfg.progress(lines)
+	}
+	return n, errors.Join(errs...)
+}
+
+// inOrder reports whether v can be appended to the sorted entries.
+func (t *Tree[Value, Data]) inOrder(sorted []KV[Value, Data], v Value) bool {
+	if len(sorted) == 0 {
+		return true
+	}
+	c := t.compare(sorted[len(sorted)-1].Value, v)
+	return c < 0 || c == 0 && t.allowDuplicates()
+}
+
+// importSorted fills an empty tree with entries in tree order.
+func (t *Tree[Value, Data]) importSorted(entries []KV[Value, Data]) {
+	if len(entries) == 0 {
+		return
+	}
+	t.Root = build(len(entries), t.allocator(), func(i int) (Value, Data) {
+		return entries[i].Value, entries[i].Data
+	})
+	t.debug.record(t, "ImportLines")
+	for _, e := range entries {
+		var zero Data
+		t.afterInsert(e.Value, e.Data, zero, false)
+	}
+	t.adopt()
+}
@@ -0,0 +1,106 @@
+package generictree
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// splitKV splits a line of the form "value data".
+func splitKV(line string) (int, string, error) {
+	v, d, _ := strings.Cut(line, " ")
+	n, err := strconv.Atoi(v)
+	return n, d, err
+}
+
+func TestTree_ImportLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		values []int
+		bulk   bool // the tree is perfectly balanced
+	}{
+		{"sorted", "1 a\n2 b\n3 c\n4 d\n5 e\n6 f\n7 g\n", []int{1, 2, 3, 4, 5, 6, 7}, true},
+		{"unsorted", "4 d\r\n2 b\r\n\r\n6 f\r\n1 a", []int{1, 2, 4, 6}, false},
+		{"sorted prefix", "1 a\n2 b\n3 c\n0 z\n", []int{0, 1, 2, 3}, false},
+		{"empty", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := &Tree[int, string]{}
+			inserts := 0
+			tree.addHooks(Hooks[int, string]{OnInsert: func(int, string) { inserts++ }})
+			n, err := tree.ImportLines(strings.NewReader(tt.input), splitKV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.values(); !slices.Equal(got, tt.values) {
+				t.Errorf("values: got %v, want %v", got, tt.values)
+			}
+			if n != len(tt.values) || inserts != n {
+				t.Errorf("got n = %d and %d OnInsert calls, want %d", n, inserts, len(tt.values))
+			}
+			if tt.bulk && tree.Stats().Height != tree.Stats().OptimalHeight {
+				t.Errorf("height %d, want %d", tree.Stats().Height, tree.Stats().OptimalHeight)
+			}
+		})
+	}
+}
+
+func TestTree_ImportLines_errors(t *testing.T) {
+	input := "1 a\nx b\n2 c\ny d\nz e\n3 f\n"
+
+	tree := &Tree[int, string]{}
+	n, err := tree.ImportLines(strings.NewReader(input), splitKV)
+	if n != 3 || tree.Len() != 3 {
+		t.Errorf("imported %d lines, tree has %d entries, want 3", n, tree.Len())
+	}
+	var lines []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var le *LineError
+		if !errors.As(e, &le) {
+			t.Fatalf("error %v is not a *LineError", e)
+		}
+		lines = append(lines, le.Line)
+	}
+	if !slices.Equal(lines, []int{2, 4, 5}) {
+		t.Errorf("bad lines: got %v, want [2 4 5]", lines)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("error %v does not wrap strconv.ErrSyntax", err)
+	}
+
+	tree = &Tree[int, string]{}
+	n, err = tree.ImportLines(strings.NewReader(input), splitKV, ImportMaxErrors(2))
+	if n != 2 || err == nil {
+		t.Errorf("with ImportMaxErrors(2): got %d, %v, want 2 and an error", n, err)
+	}
+
+	tree = &Tree[int, string]{}
+	n, err = tree.ImportLines(iotest.TimeoutReader(strings.NewReader(strings.Repeat("1 a\n", 5000))), splitKV)
+	if n == 0 || !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("with a failing reader: got %d, %v", n, err)
+	}
+}
+
+func TestTree_ImportLines_progress(t *testing.T) {
+	var input strings.Builder
+	for i := range 25 {
+		fmt.Fprintf(&input, "%d x\n", i)
+	}
+	var calls []int
+	tree := &Tree[int, string]{}
+	tree.ImportLines(strings.NewReader(input.String()), splitKV, ImportProgress(10, func(lines int) {
+		calls = append(calls, lines)
+	}))
+	if want := []int{10, 20, 25}; !slices.Equal(calls, want) {
+		t.Errorf("progress calls: got %v, want %v", calls, want)
+	}
+}
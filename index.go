@@ -0,0 +1,158 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Index is a secondary index of a tree. It orders the entries of the tree
+// by a key that is extracted from each entry, and maps each key to the
+// values of the entries with that key. The tree keeps its indexes up to
+// date on every change.
+type Index[Key, Value cmp.Ordered, Data any] struct {
+	entries MultiMap[Key, Value]
+	extract func(Value, Data) Key
+}
+
+// AddIndex adds a secondary index named name to t and returns it. extract
+// returns the index key of an entry; several entries may have the same key.
+// AddIndex indexes the current entries of t, which takes O(n log n) time,
+// and then maintains the index through hooks (see WithHooks), so every
+// change of t also takes O(log n) time per index.
+//
+// AddIndex panics if t already has an index named name.
+func AddIndex[Key, Value cmp.Ordered, Data any](t *Tree[Value, Data], name string, extract func(Value, Data) Key) *Index[Key, Value, Data] {
+	if t.cfg != nil {
+		if _, ok := t.cfg.indexes[name]; ok {
+			panic(fmt.Sprintf("generictree: AddIndex: duplicate index name %q", name))
+		}
+	}
+	idx := &Index[Key, Value, Data]{extract: extract}
+	for v, d := range t.All() {
+		idx.add(v, d)
+	}
+	t.addHooks(Hooks[Value, Data]{
+		OnInsert: idx.add,
+		OnUpdate: func(v Value, old, new Data) {
+			idx.remove(v, old)
+			idx.add(v, new)
+		},
+		OnDelete: idx.remove,
+	})
+	if t.cfg.indexes == nil {
+		t.cfg.indexes = map[string]any{}
+	}
+	t.cfg.indexes[name] = idx
+	return idx
+}
+
+// FindByIndex returns the values of the entries of t whose key in the index
+// named name is key, in the order in which they were indexed. It panics if
+// t has no such index or if the type of key does not match the index.
+func FindByIndex[Key, Value cmp.Ordered, Data any](t *Tree[Value, Data], name string, key Key) []Value {
+	var idx any
+	if t.cfg != nil {
+		idx = t.cfg.indexes[name]
+	}
+	if idx == nil {
+		panic(fmt.Sprintf("generictree: FindByIndex: no index named %q", name))
+	}
+	return typed[*Index[Key, Value, Data]](idx, "FindByIndex").Find(key)
+}
+
+// Find returns the values of the entries whose key is key, or nil if there
+// are none.
+func (idx *Index[Key, Value, Data]) Find(key Key) []Value {
+	return idx.entries.Get(key)
+}
+
+// Range calls f for each key in the half-open interval [lo, hi) and each
+// value with that key, in ascending order of keys, until f returns false.
+func (idx *Index[Key, Value, Data]) Range(lo, hi Key, f func(Key, Value) bool) {
+	for k, values := range idx.entries.tree.From(lo) {
+		if k >= hi {
+			return
+		}
+		for _, v := range values {
+			if !f(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of indexed entries.
+func (idx *Index[Key, Value, Data]) Len() int {
+	return idx.entries.Len()
+}
+
+func (idx *Index[Key, Value, Data]) add(v Value, d Data) {
+	idx.entries.Add(idx.extract(v, d), v)
+}
+
+func (idx *Index[Key, Value, Data]) remove(v Value, d Data) {
+	// Remove a single occurrence; a tree with duplicates may hold v more
+	// than once under the same key.
+	done := false
+	idx.entries.RemoveValue(idx.extract(v, d), func(w Value) bool {
+		if done || w != v {
+			return false
+		}
+		done = true
+		return true
+	})
+}
+
+// ScoreIndex ranks the entries of a tree by a score that is computed from
+// their data, highest score first. It is a Leaderboard of the values of
+// the tree that the tree keeps up to date on every change.
+type ScoreIndex[Value cmp.Ordered] struct {
+	board *Leaderboard[Value]
+}
+
+// AddScoreIndex adds an index to t that ranks its entries by score, which
+// returns the score of an entry's data. tie decides how entries with equal
+// scores rank. Like AddIndex, it indexes the current entries of t and then
+// maintains the index through hooks, so every change of t takes another
+// O(log n) time.
+//
+// AddScoreIndex panics if t allows duplicates, because the index ranks each
+// value once.
+func AddScoreIndex[Value cmp.Ordered, Data any](t *Tree[Value, Data], tie TieBreak, score func(Data) float64) *ScoreIndex[Value] {
+	if t.allowDuplicates() {
+		panic("generictree: AddScoreIndex: tree allows duplicates")
+	}
+	idx := &ScoreIndex[Value]{board: NewLeaderboard[Value](tie)}
+	for v, d := range t.All() {
+		idx.board.SetScore(v, score(d))
+	}
+	t.addHooks(Hooks[Value, Data]{
+		OnInsert: func(v Value, d Data) { idx.board.SetScore(v, score(d)) },
+		OnUpdate: func(v Value, _, new Data) { idx.board.SetScore(v, score(new)) },
+		OnDelete: func(v Value, _ Data) { idx.board.Remove(v) },
+	})
+	return idx
+}
+
+// RankByScore returns the rank of the entry with value, 1 being the entry
+// with the highest score. ok is false if value is not in the tree.
+func (idx *ScoreIndex[Value]) RankByScore(value Value) (rank int, ok bool) {
+	return idx.board.RankOf(value)
+}
+
+// TopByScore returns the standings of the n entries with the highest
+// scores, or of all entries if there are fewer than n.
+func (idx *ScoreIndex[Value]) TopByScore(n int) []Standing[Value] {
+	return idx.board.Top(n)
+}
+
+// Score returns the score of the entry with value. ok is false if value is
+// not in the tree.
+func (idx *ScoreIndex[Value]) Score(value Value) (score float64, ok bool) {
+	return idx.board.Score(value)
+}
+
+// Len returns the number of indexed entries.
+func (idx *ScoreIndex[Value]) Len() int {
+	return idx.board.Len()
+}
@@ -0,0 +1,105 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+type person struct {
+	city string
+	age  int
+}
+
+func TestAddIndex(t *testing.T) {
+	people := &Tree[string, person]{}
+	people.Insert("ann", person{"Oslo", 31})
+	people.Insert("bob", person{"Rome", 45})
+
+	byCity := AddIndex(people, "city", func(_ string, p person) string { return p.city })
+	byAge := AddIndex(people, "age", func(_ string, p person) int { return p.age })
+
+	people.Insert("cid", person{"Oslo", 28})
+	people.Insert("bob", person{"Oslo", 46}) // update moves bob to Oslo
+	people.Insert("dan", person{"Kyiv", 31})
+	people.Delete("ann")
+
+	if got := FindByIndex(people, "city", "Oslo"); !slices.Equal(got, []string{"cid", "bob"}) {
+		t.Errorf("Oslo: got %v", got)
+	}
+	if got := byCity.Find("Rome"); got != nil {
+		t.Errorf("Rome: got %v, want none", got)
+	}
+	if got := FindByIndex(people, "age", 31); !slices.Equal(got, []string{"dan"}) {
+		t.Errorf("age 31: got %v", got)
+	}
+	var ages []int
+	byAge.Range(30, 50, func(age int, name string) bool {
+		ages = append(ages, age)
+		return true
+	})
+	if !slices.Equal(ages, []int{31, 46}) {
+		t.Errorf("Range(30, 50): got ages %v", ages)
+	}
+	if byCity.Len() != people.Len() || byAge.Len() != people.Len() {
+		t.Errorf("index sizes %d and %d, tree size %d", byCity.Len(), byAge.Len(), people.Len())
+	}
+}
+
+func TestAddIndex_duplicates(t *testing.T) {
+	tree := New[int, string](WithAllowDuplicates())
+	idx := AddIndex(tree, "data", func(_ int, d string) string { return d })
+	tree.Insert(1, "x")
+	tree.Insert(1, "x")
+	tree.Delete(1)
+	if got := idx.Find("x"); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want one remaining entry", got)
+	}
+}
+
+func TestFindByIndex_panics(t *testing.T) {
+	tree := &Tree[int, string]{}
+	AddIndex(tree, "len", func(_ int, d string) int { return len(d) })
+	for name, f := range map[string]func(){
+		"missing index": func() { FindByIndex(tree, "nope", 1) },
+		"wrong key":     func() { FindByIndex(tree, "len", "1") },
+		"duplicate":     func() { AddIndex(tree, "len", func(int, string) int { return 0 }) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestAddScoreIndex(t *testing.T) {
+	people := &Tree[string, person]{}
+	people.Insert("ann", person{"Oslo", 31})
+	people.Insert("bob", person{"Rome", 45})
+
+	byAge := AddScoreIndex(people, FirstCome, func(p person) float64 { return float64(p.age) })
+	people.Insert("cid", person{"Oslo", 28})
+	people.Insert("ann", person{"Oslo", 50}) // update moves ann to the top
+	people.Insert("dan", person{"Kyiv", 45})
+	people.Delete("cid")
+
+	var top []string
+	for _, s := range byAge.TopByScore(2) {
+		top = append(top, s.ID)
+	}
+	if !slices.Equal(top, []string{"ann", "bob"}) {
+		t.Errorf("TopByScore(2): got %v", top)
+	}
+	if rank, ok := byAge.RankByScore("dan"); !ok || rank != 3 {
+		t.Errorf("RankByScore(dan) = %d, %t, want 3, true", rank, ok)
+	}
+	if _, ok := byAge.RankByScore("cid"); ok {
+		t.Error("RankByScore(cid): deleted entry is still ranked")
+	}
+	if byAge.Len() != people.Len() {
+		t.Errorf("index size %d, tree size %d", byAge.Len(), people.Len())
+	}
+}
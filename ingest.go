@@ -0,0 +1,89 @@
+package generictree
+
+import "cmp"
+
+// Ingester adds entries to a tree whose values arrive mostly in ascending
+// order, such as timestamps or sequence numbers. Inserting such values one
+// by one always descends the right spine of the tree and rotates it again
+// and again. An Ingester instead appends in-order values to a buffer in
+// amortized O(1) time. Flush builds a balanced tree from the buffer and
+// joins it to the tree in O(m + log n) time for m buffered entries.
+//
+// Unlike an append to the right spine with delayed rebalancing, which would
+// leave the tree unbalanced for readers in between, the buffer keeps the
+// tree valid at all times, but buffered entries are not visible in the
+// tree until Flush is called: Find, Len, and iterations of the tree do not
+// see them. Flush before reading the tree. A value that is not after all
+// values seen so far flushes the buffer and is inserted normally. Do not
+// modify the tree while an Ingester has buffered entries.
+//
+// Like Insert, an Ingester drops new entries beyond the limit of
+// WithMaxEntries, counting the buffered ones. WithMaxHeight cannot be
+// checked for buffered entries, so with it, Add inserts every entry
+// normally.
+type Ingester[Value cmp.Ordered, Data any] struct {
+	tree    *Tree[Value, Data]
+	pending []KV[Value, Data]
+}
+
+// Ingester returns a new Ingester for t.
+func (t *Tree[Value, Data]) Ingester() *Ingester[Value, Data] {
+	return &Ingester[Value, Data]{tree: t}
+}
+
+// Add adds value and data to the tree, buffering them if value comes after
+// all values in the tree and in the buffer. A value that is already there
+// is handled as by Insert, including the duplicate policy (see
+// WithOnDuplicate and WithDuplicateFunc) and the hooks; if it is the last
+// buffered value, Add flushes the buffer first.
+func (in *Ingester[Value, Data]) Add(value Value, data Data) {
+	t := in.tree
+	if t.cfg != nil && t.cfg.maxHeight > 0 {
+		in.Flush()
+		t.Insert(value, data)
+		return
+	}
+	if n := len(in.pending); n > 0 {
+		if c := t.compare(in.pending[n-1].Value, value); c < 0 || c == 0 && t.allowDuplicates() {
+			in.buffer(value, data)
+			return
+		}
+	} else if last, _, ok := t.Max(); !ok || t.compare(last, value) < 0 {
+		in.buffer(value, data)
+		return
+	}
+	in.Flush()
+	t.Insert(value, data)
+}
+
+// buffer appends value and data to the buffer, unless the tree and the
+// buffer are full (see WithMaxEntries).
+func (in *Ingester[Value, Data]) buffer(value Value, data Data) {
+	t := in.tree
+	if t.cfg != nil && t.cfg.maxEntries > 0 && t.Len()+len(in.pending) >= t.cfg.maxEntries {
+		return
+	}
+	in.pending = append(in.pending, KV[Value, Data]{value, data})
+}
+
+// Len returns the number of buffered entries.
+func (in *Ingester[Value, Data]) Len() int {
+	return len(in.pending)
+}
+
+// Flush adds the buffered entries to the tree.
+func (in *Ingester[Value, Data]) Flush() {
+	if len(in.pending) == 0 {
+		return
+	}
+	batch := sameOrder[Value, Data, Data](in.tree)
+	batch.Root = build(len(in.pending), in.tree.allocator(), func(i int) (Value, Data) {
+		return in.pending[i].Value, in.pending[i].Data
+	})
+	// The buffer only holds values after the tree's last value.
+	if err := in.tree.Concat(batch); err != nil {
+		panic("generictree: tree was modified while the Ingester had buffered entries: " + err.Error())
+	}
+	clear(in.pending)
+	in.pending = in.pending[:0]
+}
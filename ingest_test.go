@@ -0,0 +1,135 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIngester(t *testing.T) {
+	tree := New[int, int]()
+	inserts, updates := 0, 0
+	tree.addHooks(Hooks[int, int]{
+		OnInsert: func(int, int) { inserts++ },
+		OnUpdate: func(int, int, int) { updates++ },
+	})
+	tree.Insert(0, 0)
+	in := tree.Ingester()
+	for i := 1; i <= 100; i++ {
+		in.Add(i, i)
+	}
+	if in.Len() != 100 || tree.Len() != 1 {
+		t.Fatalf("buffered %d, tree has %d entries; want 100 and 1", in.Len(), tree.Len())
+	}
+	in.Add(100, -100) // equal to the last buffered value: flushes and replaces
+	if in.Len() != 0 || tree.Len() != 101 {
+		t.Fatalf("after an equal value: buffered %d, tree has %d entries", in.Len(), tree.Len())
+	}
+	in.Add(50, -50) // out of order
+	if in.Len() != 0 || tree.Len() != 101 {
+		t.Fatalf("after an out-of-order value: buffered %d, tree has %d entries", in.Len(), tree.Len())
+	}
+	in.Add(50, 50) // equal to a value in the tree
+	in.Add(101, 101)
+	in.Flush()
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	want := make([]int, 102)
+	for i := range want {
+		want[i] = i
+	}
+	if !slices.Equal(tree.values(), want) {
+		t.Errorf("values: got %v", tree.values())
+	}
+	if d, _ := tree.Find(100); d != -100 {
+		t.Errorf("data of 100 = %d, want -100", d)
+	}
+	if d, _ := tree.Find(50); d != 50 {
+		t.Errorf("data of 50 = %d, want 50", d)
+	}
+	if inserts != 102 || updates != 3 {
+		t.Errorf("%d OnInsert and %d OnUpdate calls, want 102 and 3", inserts, updates)
+	}
+}
+
+func TestIngester_onDuplicate(t *testing.T) {
+	tree := New[int, int](WithDuplicateFunc(func(_ int, old, new int) int { return old + new }))
+	in := tree.Ingester()
+	for _, v := range []int{1, 2, 2, 3, 3, 3} {
+		in.Add(v, v)
+	}
+	in.Flush()
+	for v, want := range map[int]int{1: 1, 2: 4, 3: 9} {
+		if d, _ := tree.Find(v); d != want {
+			t.Errorf("data of %d = %d, want %d", v, d, want)
+		}
+	}
+}
+
+func TestIngester_duplicates(t *testing.T) {
+	tree := New[int, int](WithAllowDuplicates())
+	in := tree.Ingester()
+	for _, v := range []int{1, 1, 2, 2, 2, 3} {
+		in.Add(v, v)
+	}
+	in.Flush()
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{1, 1, 2, 2, 2, 3}) {
+		t.Errorf("values: got %v", got)
+	}
+}
+
+func BenchmarkIngester(b *testing.B) {
+	b.Run("Insert", func(b *testing.B) {
+		tree := New[int, int]()
+		for i := range b.N {
+			tree.Insert(i, i)
+		}
+	})
+	b.Run("Ingester", func(b *testing.B) {
+		tree := New[int, int]()
+		in := tree.Ingester()
+		for i := range b.N {
+			in.Add(i, i)
+		}
+		in.Flush()
+	})
+}
+
+func TestIngester_maxEntries(t *testing.T) {
+	tree := New[int, int](WithMaxEntries(5))
+	tree.Insert(0, 0)
+	in := tree.Ingester()
+	for v := 1; v < 10; v++ {
+		in.Add(v, v)
+	}
+	in.Flush()
+	if got := tree.values(); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("got %v, want [0 1 2 3 4]", got)
+	}
+
+	tree = New[int, int](WithNoBalance(), WithMaxHeight(3))
+	in = tree.Ingester()
+	for v := range 10 {
+		in.Add(v, v)
+	}
+	in.Flush()
+	if h := tree.Root.Height(); h > 3 || tree.Len() != 3 {
+		t.Errorf("height %d with %d entries, want at most 3 with 3", h, tree.Len())
+	}
+}
+
+func TestIngester_allocator(t *testing.T) {
+	c := &countingAllocator{}
+	tree := New[int, string](WithAllocator[int, string](c))
+	in := tree.Ingester()
+	for v := range 10 {
+		in.Add(v, "x")
+	}
+	in.Flush()
+	if c.allocs != 10 {
+		t.Errorf("%d allocs, want 10", c.allocs)
+	}
+}
@@ -0,0 +1,97 @@
+package generictree
+
+import "cmp"
+
+// Shape is a copy of a node and its subtree, for tools that inspect or draw
+// trees without access to the nodes themselves.
+type Shape[Value cmp.Ordered, Data any] struct {
+	Value   Value               `json:"value"`
+	Data    Data                `json:"data"`
+	Height  int                 `json:"height"`
+	Size    int                 `json:"size"`    // number of entries in the subtree
+	Balance int                 `json:"balance"` // height of the right minus height of the left subtree
+	Left    *Shape[Value, Data] `json:"left,omitempty"`
+	Right   *Shape[Value, Data] `json:"right,omitempty"`
+}
+
+// Inspect returns a copy of the top depth levels of the tree, or of the
+// whole tree if depth is zero or negative. It returns nil for an empty
+// tree. A Shape whose Size is larger than the sizes of its children plus
+// one has children that were cut off.
+func (t *Tree[Value, Data]) Inspect(depth int) *Shape[Value, Data] {
+	var copyShape func(n *Node[Value, Data], depth int) *Shape[Value, Data]
+	copyShape = func(n *Node[Value, Data], depth int) *Shape[Value, Data] {
+		if n == nil || depth == 0 {
+			return nil
+		}
+		return &Shape[Value, Data]{
+			Value:   n.Value,
+			Data:    n.Data,
+			Height:  n.height,
+			Size:    n.size,
+			Balance: n.Bal(),
+			Left:    copyShape(n.Left, depth-1),
+			Right:   copyShape(n.Right, depth-1),
+		}
+	}
+	if depth <= 0 {
+		depth = -1
+	}
+	return copyShape(t.Root, depth)
+}
+
+// Turn is the outcome of one comparison on a search path.
+type Turn int
+
+const (
+	TurnLeft  Turn = -1 // the search value comes before the node's value
+	TurnFound Turn = 0  // the node holds the search value
+	TurnRight Turn = 1  // the search value comes after the node's value
+)
+
+func (t Turn) String() string {
+	switch t {
+	case TurnLeft:
+		return "left"
+	case TurnRight:
+		return "right"
+	}
+	return "found"
+}
+
+// Step is one node visited by a search (see FindPath).
+type Step[Value cmp.Ordered] struct {
+	Value Value `json:"value"` // value of the visited node
+	Turn  Turn  `json:"turn"`
+}
+
+// FindPath returns the nodes that a search for value visits, from the
+// root down, and where the search turns at each of them. If value is in
+// the tree, the last step has TurnFound; otherwise, the search ends at a
+// missing child of the last node, which is where Insert would add value.
+// FindPath is what drawings of the tree use to highlight a search, such
+// as WriteDOTPath and package treehttp.
+func (t *Tree[Value, Data]) FindPath(value Value) []Step[Value] {
+	var path []Step[Value]
+	for n := t.Root; n != nil; {
+		c := t.compare(value, n.Value)
+		switch {
+		case c == 0:
+			return append(path, Step[Value]{n.Value, TurnFound})
+		case c < 0:
+			path = append(path, Step[Value]{n.Value, TurnLeft})
+			n = n.Left
+		default:
+			path = append(path, Step[Value]{n.Value, TurnRight})
+			n = n.Right
+		}
+	}
+	return path
+}
+
+// Compare compares a and b in the tree's order. It returns a negative
+// number if a comes before b, zero if they are equal, and a positive number
+// if a comes after b.
+func (t *Tree[Value, Data]) Compare(a, b Value) int {
+	return t.compare(a, b)
+}
@@ -0,0 +1,60 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTree_Inspect(t *testing.T) {
+	tree := &Tree[int, string]{}
+	if tree.Inspect(0) != nil {
+		t.Error("empty tree: want nil")
+	}
+	for v := range 7 {
+		tree.Insert(v, "")
+	}
+	s := tree.Inspect(0)
+	if s.Value != 3 || s.Height != 3 || s.Size != 7 || s.Left.Value != 1 || s.Right.Right.Value != 6 {
+		t.Errorf("unexpected shape: %+v", s)
+	}
+	s = tree.Inspect(2)
+	if s.Left.Left != nil || s.Left.Size != 3 {
+		t.Errorf("depth 2: got %+v", s.Left)
+	}
+	s.Left.Value = 100
+	if v, _, _ := tree.Select(1); v != 1 {
+		t.Error("changing the shape changed the tree")
+	}
+}
+
+func TestTree_Compare(t *testing.T) {
+	desc := New[int, string](WithDescending())
+	if desc.Compare(1, 2) <= 0 || (&Tree[int, string]{}).Compare(1, 2) >= 0 {
+		t.Error("Compare does not follow the tree's order")
+	}
+}
+
+func TestTree_FindPath(t *testing.T) {
+	tree := New[int, string](WithDescending())
+	for v := range 7 {
+		tree.Insert(v, "")
+	}
+	// In descending order, the root is 3, with 5 on the left and 1 on the
+	// right.
+	got := tree.FindPath(0)
+	want := []Step[int]{{3, TurnRight}, {1, TurnRight}, {0, TurnFound}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindPath(0) = %v, want %v", got, want)
+	}
+	got = tree.FindPath(7)
+	want = []Step[int]{{3, TurnLeft}, {5, TurnLeft}, {6, TurnLeft}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindPath(7) = %v, want %v", got, want)
+	}
+	if got := New[int, string]().FindPath(1); len(got) != 0 {
+		t.Errorf("empty tree: %v", got)
+	}
+	if s := TurnLeft.String() + TurnFound.String() + TurnRight.String(); s != "leftfoundright" {
+		t.Errorf("Turn strings: %s", s)
+	}
+}
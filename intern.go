@@ -0,0 +1,149 @@
+package generictree
+
+import (
+	"cmp"
+	"reflect"
+)
+
+// interner makes equal strings among the values and data of a tree share
+// one backing array. It counts the entries that use each string, so that
+// strings of deleted entries are released.
+type interner[Value cmp.Ordered, Data any] struct {
+	strs   map[string]internRef
+	saved  int  // bytes saved by sharing
+	values bool // Value is a string type
+	data   bool // Data is a string type
+}
+
+// internRef is a canonical string and the number of its uses.
+type internRef struct {
+	s    string
+	refs int
+}
+
+// newInterner returns an interner for the string types among Value and
+// Data, or nil if neither is a string type.
+func newInterner[Value cmp.Ordered, Data any]() *interner[Value, Data] {
+	in := &interner[Value, Data]{
+		strs:   map[string]internRef{},
+		values: reflect.TypeFor[Value]().Kind() == reflect.String,
+		data:   reflect.TypeFor[Data]().Kind() == reflect.String,
+	}
+	if !in.values && !in.data {
+		return nil
+	}
+	return in
+}
+
+// canonical returns the shared copy of s, or s if there is none yet.
+func (in *interner[Value, Data]) canonical(s string) string {
+	if r, ok := in.strs[s]; ok {
+		return r.s
+	}
+	return s
+}
+
+func (in *interner[Value, Data]) add(s string) {
+	r, ok := in.strs[s]
+	if ok {
+		in.saved += len(s)
+	} else {
+		r.s = s
+	}
+	r.refs++
+	in.strs[s] = r
+}
+
+func (in *interner[Value, Data]) remove(s string) {
+	r := in.strs[s]
+	r.refs--
+	if r.refs <= 0 {
+		delete(in.strs, s)
+		return
+	}
+	in.saved -= len(s)
+	in.strs[s] = r
+}
+
+// hooks returns the hooks that count the uses of strings.
+func (in *interner[Value, Data]) hooks() Hooks[Value, Data] {
+	return Hooks[Value, Data]{
+		OnInsert: func(v Value, d Data) {
+			if in.values {
+				in.add(toString(v))
+			}
+			if in.data {
+				in.add(toString(d))
+			}
+		},
+		OnUpdate: func(_ Value, old, new Data) {
+			if in.data {
+				in.add(toString(new))
+				in.remove(toString(old))
+			}
+		},
+		OnDelete: func(v Value, d Data) {
+			if in.values {
+				in.remove(toString(v))
+			}
+			if in.data {
+				in.remove(toString(d))
+			}
+		},
+	}
+}
+
+// value returns the shared copy of v.
+func (in *interner[Value, Data]) value(v Value) Value {
+	if !in.values {
+		return v
+	}
+	return fromString[Value](in.canonical(toString(v)))
+}
+
+// datum returns the shared copy of d.
+func (in *interner[Value, Data]) datum(d Data) Data {
+	if !in.data {
+		return d
+	}
+	return fromString[Data](in.canonical(toString(d)))
+}
+
+// toString returns the string that v holds; v must be of a string type.
+func toString[T any](v T) string {
+	if s, ok := any(v).(string); ok {
+		return s
+	}
+	return reflect.ValueOf(v).String()
+}
+
+// fromString converts s to the string type T.
+func fromString[T any](s string) T {
+	if v, ok := any(s).(T); ok {
+		return v
+	}
+	var v T
+	reflect.ValueOf(&v).Elem().SetString(s)
+	return v
+}
+
+// internData returns the shared copy of data if the tree interns strings.
+func (t *Tree[Value, Data]) internData(data Data) Data {
+	if t.cfg == nil || t.cfg.interner == nil {
+		return data
+	}
+	return t.cfg.interner.datum(data)
+}
+
+// internAll replaces the strings of all nodes by their shared copies. It is
+// used after entries were added in bulk.
+func (t *Tree[Value, Data]) internAll() {
+	if t.cfg == nil || t.cfg.interner == nil {
+		return
+	}
+	in := t.cfg.interner
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		n.Value, n.Data = in.value(n.Value), in.datum(n.Data)
+		return true
+	})
+}
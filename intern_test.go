@@ -0,0 +1,72 @@
+package generictree
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestWithInterning(t *testing.T) {
+	tree := New[int, string](WithInterning())
+	for i := range 100 {
+		// Build each string anew, so that the copies do not share memory.
+		tree.Insert(i, strings.Repeat("x", 3)+"tag")
+	}
+	s := tree.Stats()
+	if s.Interned != 1 || s.InternSaved != 99*6 {
+		t.Errorf("Interned, InternSaved = %d, %d, want 1, %d", s.Interned, s.InternSaved, 99*6)
+	}
+	first, _ := tree.Find(0)
+	last, _ := tree.Find(99)
+	if unsafe.StringData(first) != unsafe.StringData(last) {
+		t.Error("equal data do not share memory")
+	}
+
+	tree.Insert(5, "other")
+	for i := 10; i < 100; i++ {
+		tree.Delete(i)
+	}
+	if s := tree.Stats(); s.Interned != 2 || s.InternSaved != 8*6 {
+		t.Errorf("after changes: Interned, InternSaved = %d, %d, want 2, %d", s.Interned, s.InternSaved, 8*6)
+	}
+	for i := range 10 {
+		tree.Delete(i)
+	}
+	if s := tree.Stats(); s.Interned != 0 || s.InternSaved != 0 {
+		t.Errorf("empty tree: Interned, InternSaved = %d, %d", s.Interned, s.InternSaved)
+	}
+}
+
+func TestWithInterning_bulk(t *testing.T) {
+	type tag string
+	src := New[tag, tag]()
+	for _, v := range []string{"a", "b", "c"} {
+		src.Insert(tag(v), tag(strings.Repeat("v", 2)))
+	}
+	b, err := src.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := New[tag, tag](WithInterning())
+	tree.Insert("a", "vv")
+	if err := tree.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if s := tree.Stats(); s.Interned != 4 || s.InternSaved != 2*2 {
+		t.Errorf("Interned, InternSaved = %d, %d, want 4, 4", s.Interned, s.InternSaved)
+	}
+	a, _ := tree.Find("a")
+	c, _ := tree.Find("c")
+	if unsafe.StringData(string(a)) != unsafe.StringData(string(c)) {
+		t.Error("equal data do not share memory after UnmarshalJSON")
+	}
+}
+
+func TestWithInterning_panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for a tree without string types")
+		}
+	}()
+	New[int, float64](WithInterning())
+}
@@ -0,0 +1,312 @@
+// Package interval implements an interval tree: an AVL tree of intervals
+// that finds all intervals overlapping a given interval or containing a
+// given point in O(log n + k) time, where k is the number of results. It
+// also has helpers for scheduling: Conflicts, MergeOverlapping, and
+// FirstFreeSlot.
+package interval
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Interval is the half-open interval [Low, High).
+// An interval with High <= Low is empty.
+type Interval[T cmp.Ordered] struct {
+	Low, High T
+}
+
+// Empty reports whether iv contains no points.
+func (iv Interval[T]) Empty() bool {
+	return iv.High <= iv.Low
+}
+
+// Contains reports whether p lies within iv.
+func (iv Interval[T]) Contains(p T) bool {
+	return iv.Low <= p && p < iv.High
+}
+
+// Overlaps reports whether iv and other have at least one point in common.
+func (iv Interval[T]) Overlaps(other Interval[T]) bool {
+	return iv.Low < other.High && other.Low < iv.High && !iv.Empty() && !other.Empty()
+}
+
+// compare orders intervals by their low end, then by their high end.
+func (iv Interval[T]) compare(other Interval[T]) int {
+	if c := cmp.Compare(iv.Low, other.Low); c != 0 {
+		return c
+	}
+	return cmp.Compare(iv.High, other.High)
+}
+
+func (iv Interval[T]) String() string {
+	return fmt.Sprintf("[%v, %v)", iv.Low, iv.High)
+}
+
+type node[T cmp.Ordered, V any] struct {
+	iv          Interval[T]
+	value       V
+	left, right *node[T, V]
+	height      int
+	maxHigh     T // largest High in the subtree
+}
+
+// Tree is an interval tree. Each distinct interval is stored once, with a
+// value of type V. The zero value is an empty tree ready to use.
+type Tree[T cmp.Ordered, V any] struct {
+	root *node[T, V]
+	len  int
+}
+
+func (n *node[T, V]) h() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[T, V]) bal() int {
+	return n.right.h() - n.left.h()
+}
+
+// update recalculates the height and the largest high end of n.
+func (n *node[T, V]) update() {
+	n.height = max(n.left.h(), n.right.h()) + 1
+	n.maxHigh = n.iv.High
+	if n.left != nil && n.left.maxHigh > n.maxHigh {
+		n.maxHigh = n.left.maxHigh
+	}
+	if n.right != nil && n.right.maxHigh > n.maxHigh {
+		n.maxHigh = n.right.maxHigh
+	}
+}
+
+func (n *node[T, V]) rotateLeft() *node[T, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *node[T, V]) rotateRight() *node[T, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *node[T, V]) rebalance() *node[T, V] {
+	n.update()
+	switch {
+	case n.bal() < -1:
+		if n.left.bal() > 0 {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case n.bal() > 1:
+		if n.right.bal() < 0 {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// Insert adds iv with value to the tree. If iv is already in the tree,
+// Insert replaces its value.
+func (t *Tree[T, V]) Insert(iv Interval[T], value V) {
+	t.root = t.insert(t.root, iv, value)
+}
+
+func (t *Tree[T, V]) insert(n *node[T, V], iv Interval[T], value V) *node[T, V] {
+	if n == nil {
+		t.len++
+		return &node[T, V]{iv: iv, value: value, height: 1, maxHigh: iv.High}
+	}
+	switch c := iv.compare(n.iv); {
+	case c < 0:
+		n.left = t.insert(n.left, iv, value)
+	case c > 0:
+		n.right = t.insert(n.right, iv, value)
+	default:
+		n.value = value
+		return n
+	}
+	return n.rebalance()
+}
+
+// Get returns the value stored for iv and true,
+// or the zero value of V and false if iv is not in the tree.
+func (t *Tree[T, V]) Get(iv Interval[T]) (V, bool) {
+	for n := t.root; n != nil; {
+		switch c := iv.compare(n.iv); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes iv from the tree and returns its value and true,
+// or the zero value of V and false if iv is not in the tree.
+func (t *Tree[T, V]) Delete(iv Interval[T]) (V, bool) {
+	var (
+		value V
+		ok    bool
+	)
+	t.root, value, ok = deleteInterval(t.root, iv)
+	if ok {
+		t.len--
+	}
+	return value, ok
+}
+
+func deleteInterval[T cmp.Ordered, V any](n *node[T, V], iv Interval[T]) (*node[T, V], V, bool) {
+	var (
+		value V
+		ok    bool
+	)
+	if n == nil {
+		return nil, value, false
+	}
+	switch c := iv.compare(n.iv); {
+	case c < 0:
+		n.left, value, ok = deleteInterval(n.left, iv)
+	case c > 0:
+		n.right, value, ok = deleteInterval(n.right, iv)
+	default:
+		value, ok = n.value, true
+		if n.left == nil {
+			return n.right, value, true
+		}
+		if n.right == nil {
+			return n.left, value, true
+		}
+		succ := n.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		n.iv, n.value = succ.iv, succ.value
+		n.right, _, _ = deleteInterval(n.right, succ.iv)
+	}
+	if !ok {
+		return n, value, false
+	}
+	return n.rebalance(), value, true
+}
+
+// Len returns the number of intervals in the tree.
+func (t *Tree[T, V]) Len() int {
+	return t.len
+}
+
+// All calls f for each interval in the tree, ordered by low end and then by
+// high end, until f returns false.
+func (t *Tree[T, V]) All(f func(Interval[T], V) bool) {
+	t.root.walk(f)
+}
+
+func (n *node[T, V]) walk(f func(Interval[T], V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(f) && f(n.iv, n.value) && n.right.walk(f)
+}
+
+// Overlapping calls f for each interval that overlaps q, ordered by low end
+// and then by high end, until f returns false.
+func (t *Tree[T, V]) Overlapping(q Interval[T], f func(Interval[T], V) bool) {
+	if q.Empty() {
+		return
+	}
+	t.root.overlapping(q, f)
+}
+
+func (n *node[T, V]) overlapping(q Interval[T], f func(Interval[T], V) bool) bool {
+	// No interval in this subtree ends after q starts.
+	if n == nil || n.maxHigh <= q.Low {
+		return true
+	}
+	if !n.left.overlapping(q, f) {
+		return false
+	}
+	// This node and all nodes to its right start at or after q ends.
+	if n.iv.Low >= q.High {
+		return true
+	}
+	if n.iv.Overlaps(q) && !f(n.iv, n.value) {
+		return false
+	}
+	return n.right.overlapping(q, f)
+}
+
+// Stab calls f for each interval that contains p, ordered by low end and
+// then by high end, until f returns false.
+func (t *Tree[T, V]) Stab(p T, f func(Interval[T], V) bool) {
+	t.root.stab(p, f)
+}
+
+func (n *node[T, V]) stab(p T, f func(Interval[T], V) bool) bool {
+	if n == nil || n.maxHigh <= p {
+		return true
+	}
+	if !n.left.stab(p, f) {
+		return false
+	}
+	if n.iv.Low > p {
+		return true
+	}
+	if n.iv.Contains(p) && !f(n.iv, n.value) {
+		return false
+	}
+	return n.right.stab(p, f)
+}
+
+// Validate checks the invariants of the tree: the order of the intervals,
+// the AVL balance, and the recorded heights and high ends. It returns an
+// error describing the first violation found, or nil.
+func (t *Tree[T, V]) Validate() error {
+	size, err := t.root.validate(nil, nil)
+	if err != nil {
+		return err
+	}
+	if size != t.len {
+		return fmt.Errorf("tree has %d nodes but Len() is %d", size, t.len)
+	}
+	return nil
+}
+
+func (n *node[T, V]) validate(lo, hi *Interval[T]) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	if (lo != nil && n.iv.compare(*lo) <= 0) || (hi != nil && n.iv.compare(*hi) >= 0) {
+		return 0, fmt.Errorf("interval %v violates the search order", n.iv)
+	}
+	ls, err := n.left.validate(lo, &n.iv)
+	if err != nil {
+		return 0, err
+	}
+	rs, err := n.right.validate(&n.iv, hi)
+	if err != nil {
+		return 0, err
+	}
+	want := *n
+	want.update()
+	if n.height != want.height || n.maxHigh != want.maxHigh {
+		return 0, fmt.Errorf("interval %v: recorded height %d and high end %v, want %d and %v", n.iv, n.height, n.maxHigh, want.height, want.maxHigh)
+	}
+	if b := n.bal(); b < -1 || b > 1 {
+		return 0, fmt.Errorf("interval %v: balance factor %d is out of range", n.iv, b)
+	}
+	return ls + rs + 1, nil
+}
@@ -0,0 +1,101 @@
+package interval
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomInterval(rnd *rand.Rand) Interval[int] {
+	lo := rnd.Intn(1000)
+	return Interval[int]{lo, lo + rnd.Intn(50)}
+}
+
+func TestTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var tree Tree[int, int]
+	model := map[Interval[int]]int{}
+
+	for i := 0; i < 3000; i++ {
+		iv := randomInterval(rnd)
+		if rnd.Intn(3) == 0 {
+			v, ok := tree.Delete(iv)
+			mv, mok := model[iv]
+			if v != mv || ok != mok {
+				t.Fatalf("Delete(%v) = %d, %t; want %d, %t", iv, v, ok, mv, mok)
+			}
+			delete(model, iv)
+		} else {
+			tree.Insert(iv, i)
+			model[iv] = i
+		}
+		if tree.Len() != len(model) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(model))
+		}
+		if i%100 == 0 {
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		q := randomInterval(rnd)
+		var want []Interval[int]
+		for iv := range model {
+			if iv.Overlaps(q) {
+				want = append(want, iv)
+			}
+		}
+		slices.SortFunc(want, Interval[int].compare)
+		var got []Interval[int]
+		tree.Overlapping(q, func(iv Interval[int], v int) bool {
+			if v != model[iv] {
+				t.Errorf("Overlapping(%v) passed %v with value %d, want %d", q, iv, v, model[iv])
+			}
+			got = append(got, iv)
+			return true
+		})
+		if !slices.Equal(got, want) {
+			t.Errorf("Overlapping(%v) = %v, want %v", q, got, want)
+		}
+
+		p := q.Low
+		want = want[:0]
+		for iv := range model {
+			if iv.Contains(p) {
+				want = append(want, iv)
+			}
+		}
+		slices.SortFunc(want, Interval[int].compare)
+		got = got[:0]
+		tree.Stab(p, func(iv Interval[int], _ int) bool {
+			got = append(got, iv)
+			return true
+		})
+		if !slices.Equal(got, want) {
+			t.Errorf("Stab(%d) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	a := Interval[int]{1, 5}
+	for _, c := range []struct {
+		b    Interval[int]
+		want bool
+	}{
+		{Interval[int]{5, 7}, false}, // touching half-open intervals do not overlap
+		{Interval[int]{4, 7}, true},
+		{Interval[int]{0, 1}, false},
+		{Interval[int]{2, 3}, true},
+		{Interval[int]{3, 3}, false}, // empty
+	} {
+		if got := a.Overlaps(c.b); got != c.want {
+			t.Errorf("%v.Overlaps(%v) = %t, want %t", a, c.b, got, c.want)
+		}
+	}
+	if !a.Contains(1) || a.Contains(5) {
+		t.Errorf("%v: Contains(1) = %t, Contains(5) = %t", a, a.Contains(1), a.Contains(5))
+	}
+}
@@ -0,0 +1,62 @@
+package interval
+
+// Number is the set of types that FirstFreeSlot can compute with.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Conflicts returns the intervals in the tree that overlap iv, ordered by
+// low end and then by high end.
+func (t *Tree[T, V]) Conflicts(iv Interval[T]) []Interval[T] {
+	var result []Interval[T]
+	t.Overlapping(iv, func(other Interval[T], _ V) bool {
+		result = append(result, other)
+		return true
+	})
+	return result
+}
+
+// MergeOverlapping returns the union of the intervals in the tree as a
+// sorted list of disjoint intervals. Intervals that overlap or touch, such
+// as [1, 3) and [3, 5), are merged into one; empty intervals are ignored.
+// The tree itself is not changed.
+func (t *Tree[T, V]) MergeOverlapping() []Interval[T] {
+	var result []Interval[T]
+	t.All(func(iv Interval[T], _ V) bool {
+		switch {
+		case iv.Empty():
+		case len(result) > 0 && iv.Low <= result[len(result)-1].High:
+			last := &result[len(result)-1]
+			last.High = max(last.High, iv.High)
+		default:
+			result = append(result, iv)
+		}
+		return true
+	})
+	return result
+}
+
+// FirstFreeSlot returns the earliest interval of length duration within
+// window that overlaps no interval in t. It returns false if there is no
+// such interval or if duration is not positive.
+func FirstFreeSlot[T Number, V any](t *Tree[T, V], duration T, window Interval[T]) (Interval[T], bool) {
+	if duration <= 0 {
+		return Interval[T]{}, false
+	}
+	free := window.Low // start of the free time found so far
+	found := false
+	t.Overlapping(window, func(iv Interval[T], _ V) bool {
+		if iv.Low >= free && iv.Low-free >= duration {
+			found = true
+			return false
+		}
+		free = max(free, iv.High)
+		return true
+	})
+	if !found && (window.High < free || window.High-free < duration) {
+		return Interval[T]{}, false
+	}
+	return Interval[T]{free, free + duration}, true
+}
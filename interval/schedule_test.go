@@ -0,0 +1,69 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+// calendar returns a tree with busy times from 9 to 10, 11 to 13, 12 to
+// 14, and 16 to 17.
+func calendar() *Tree[uint, string] {
+	var tree Tree[uint, string]
+	tree.Insert(Interval[uint]{9, 10}, "standup")
+	tree.Insert(Interval[uint]{11, 13}, "review")
+	tree.Insert(Interval[uint]{12, 14}, "lunch")
+	tree.Insert(Interval[uint]{16, 17}, "retro")
+	return &tree
+}
+
+func TestTree_Conflicts(t *testing.T) {
+	tree := calendar()
+	got := tree.Conflicts(Interval[uint]{12, 16})
+	want := []Interval[uint]{{11, 13}, {12, 14}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Conflicts([12, 16)) = %v, want %v", got, want)
+	}
+	if got := tree.Conflicts(Interval[uint]{14, 16}); got != nil {
+		t.Errorf("Conflicts([14, 16)) = %v, want none", got)
+	}
+}
+
+func TestTree_MergeOverlapping(t *testing.T) {
+	tree := calendar()
+	tree.Insert(Interval[uint]{10, 11}, "coffee")
+	tree.Insert(Interval[uint]{15, 15}, "empty")
+	got := tree.MergeOverlapping()
+	want := []Interval[uint]{{9, 14}, {16, 17}}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeOverlapping() = %v, want %v", got, want)
+	}
+	if tree.Len() != 6 {
+		t.Errorf("MergeOverlapping changed the tree: Len() = %d", tree.Len())
+	}
+}
+
+func TestFirstFreeSlot(t *testing.T) {
+	tree := calendar()
+	tests := []struct {
+		duration uint
+		window   Interval[uint]
+		want     Interval[uint]
+		ok       bool
+	}{
+		{1, Interval[uint]{8, 18}, Interval[uint]{8, 9}, true},
+		{1, Interval[uint]{9, 18}, Interval[uint]{10, 11}, true},
+		{2, Interval[uint]{9, 18}, Interval[uint]{14, 16}, true},
+		{1, Interval[uint]{12, 18}, Interval[uint]{14, 15}, true},
+		{2, Interval[uint]{15, 18}, Interval[uint]{}, false},
+		{1, Interval[uint]{16, 18}, Interval[uint]{17, 18}, true},
+		{3, Interval[uint]{9, 18}, Interval[uint]{}, false},
+		{0, Interval[uint]{8, 18}, Interval[uint]{}, false},
+		{1, Interval[uint]{12, 13}, Interval[uint]{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := FirstFreeSlot(tree, tt.duration, tt.window)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FirstFreeSlot(%d, %v) = %v, %t; want %v, %t", tt.duration, tt.window, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Concat moves all entries of other to the end of t in O(log n) time and
+// leaves other empty. Every value in other must come after every value in t
+// in the tree's order; otherwise, Concat returns an error wrapping
+// ErrKeyOrder (or ErrDuplicateKey if the largest value of t equals the
+// smallest value of other) and leaves both trees unchanged.
+//
+// Both trees must use the same order and balance slack (see
+// WithRelaxedBalance). If either tree has hooks, Concat
+// calls the OnDelete hooks of other and the OnInsert hooks of t for each
+// moved entry, which takes O(m) time for m moved entries. If t has a
+// maximum size (see WithMaxSize), Concat evicts entries to stay within it.
+func (t *Tree[Value, Data]) Concat(other *Tree[Value, Data]) error {
+	if other.Root == nil {
+		return nil
+	}
+	if t.Root != nil {
+		hi, _, _ := t.Max()
+		lo, _, _ := other.Min()
+		switch c := t.compare(hi, lo); {
+		case c == 0 && !t.allowDuplicates():
+			return fmt.Errorf("concat: %v: %w", lo, ErrDuplicateKey)
+		case c > 0:
+			return fmt.Errorf("concat: %v is not less than %v: %w", hi, lo, ErrKeyOrder)
+		}
+	}
+
+	var moved []*Node[Value, Data]
+	if len(t.hooks()) > 0 || len(other.hooks()) > 0 || other.cfg != nil && other.cfg.nodeHooks != nil {
+		other.Root.ascend(func(n *Node[Value, Data]) bool {
+			moved = append(moved, n)
+			return true
+		})
+	}
+
+	if t.Root == nil {
+		t.Root = other.Root
+	} else {
+		right, k := other.Root.deleteMin(t.joining())
+		t.Root = joinNodes(t.Root, k, right, t.joining())
+	}
+	other.Root = nil
+	if other.cfg != nil && other.cfg.lru != nil {
+		other.cfg.lru.clear()
+	}
+	t.debug.record(t, "Concat")
+
+	for _, n := range moved {
+		other.detach(n)
+		for _, h := range other.hooks() {
+			if h.OnDelete != nil {
+				h.OnDelete(n.Value, n.Data)
+			}
+		}
+		for _, h := range t.hooks() {
+			if h.OnInsert != nil {
+				h.OnInsert(n.Value, n.Data)
+			}
+		}
+	}
+	t.adopt()
+	return nil
+}
+
+// joinNodes returns a tree of all nodes of l, the single node k, and all
+// nodes of r, balanced within ±b.limit like l and r. All values in l must
+// come before k.Value, and all values in r after it.
+func joinNodes[Value cmp.Ordered, Data any](l, k, r *Node[Value, Data], b balance) *Node[Value, Data] {
+	switch {
+	case l.Height() > r.Height()+1:
+		return joinRight(l, k, r, b)
+	case r.Height() > l.Height()+1:
+		return joinLeft(l, k, r, b)
+	}
+	k.Left, k.Right = l, r
+	k.update()
+	return k
+}
+
+// joinRight joins k and r into the right spine of the taller tree l.
+func joinRight[Value cmp.Ordered, Data any](l, k, r *Node[Value, Data], b balance) *Node[Value, Data] {
+	if l.Right.Height() <= r.Height()+1 {
+		k.Left, k.Right = l.Right, r
+		k.update()
+		l.Right = k
+	} else {
+		l.Right = joinRight(l.Right, k, r, b)
+	}
+	l.update()
+	return l.rebalanceWithin(b)
+}
+
+// joinLeft joins l and k into the left spine of the taller tree r.
+func joinLeft[Value cmp.Ordered, Data any](l, k, r *Node[Value, Data], b balance) *Node[Value, Data] {
+	if r.Left.Height() <= l.Height()+1 {
+		k.Left, k.Right = l, r.Left
+		k.update()
+		r.Left = k
+	} else {
+		r.Left = joinLeft(l, k, r.Left, b)
+	}
+	r.update()
+	return r.rebalanceWithin(b)
+}
+
+// split splits the subtree rooted at n into the nodes whose values come
+// before value in the tree's order and the remaining nodes, in O(log n)
+// time. It returns the roots of both parts.
+func (t *Tree[Value, Data]) split(n *Node[Value, Data], value Value) (before, rest *Node[Value, Data]) {
+	if n == nil {
+		return nil, nil
+	}
+	left, right := n.Left, n.Right
+	if t.compare(n.Value, value) < 0 {
+		rl, rr := t.split(right, value)
+		return joinNodes(left, n, rl, t.joining()), rr
+	}
+	ll, lr := t.split(left, value)
+	return ll, joinNodes(lr, n, right, t.joining())
+}
+
+// Subtree removes the entries whose values lie in the half-open interval
+// [lo, hi) of the tree's order and returns them as a new tree, in
+// O(log n) time. The nodes move to the new tree instead of being copied,
+// and both trees are balanced afterwards. Together with Concat, Subtree
+// moves ranges of entries between trees efficiently.
+//
+// The new tree uses the order and balancing of t but none of its other
+// options. If t has hooks or evicts by access order, Subtree calls the
+// OnDelete hooks for each moved entry, which takes O(m) time for m moved
+// entries.
+func (t *Tree[Value, Data]) Subtree(lo, hi Value) *Tree[Value, Data] {
+	result := sameOrder[Value, Data, Data](t)
+	if t.compare(lo, hi) >= 0 {
+		return result
+	}
+	before, rest := t.split(t.Root, lo)
+	moved, after := t.split(rest, hi)
+	t.Root = joinTrees(before, after, t.joining())
+	result.Root = moved
+	if moved == nil {
+		return result
+	}
+	t.debug.record(t, "Subtree", lo, hi)
+
+	if len(t.hooks()) == 0 && (t.cfg == nil || t.cfg.lru == nil && t.cfg.nodeHooks == nil) {
+		return result
+	}
+	moved.ascend(func(n *Node[Value, Data]) bool {
+		for _, h := range t.hooks() {
+			if h.OnDelete != nil {
+				h.OnDelete(n.Value, n.Data)
+			}
+		}
+		if t.cfg.lru != nil {
+			t.cfg.lru.remove(n)
+		}
+		t.detach(n)
+		return true
+	})
+	return result
+}
+
+// joinTrees returns a tree of all nodes of l and r, balanced within ±b.limit.
+// All values in l must come before all values in r.
+func joinTrees[Value cmp.Ordered, Data any](l, r *Node[Value, Data], b balance) *Node[Value, Data] {
+	if r == nil {
+		return l
+	}
+	r, k := r.deleteMin(b)
+	return joinNodes(l, k, r, b)
+}
@@ -0,0 +1,168 @@
+package generictree
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTree_Concat(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 200; i++ {
+		left, right := &Tree[int, int]{}, &Tree[int, int]{}
+		// Trees of very different sizes exercise both join directions.
+		nl, nr := r.IntN(1<<r.IntN(10)), r.IntN(1<<r.IntN(10))
+		var want []int
+		for v := range nl {
+			left.Insert(v, v)
+			want = append(want, v)
+		}
+		for v := nl; v < nl+nr; v++ {
+			right.Insert(v, v)
+			want = append(want, v)
+		}
+		if err := left.Concat(right); err != nil {
+			t.Fatalf("Concat(%d, %d): %v", nl, nr, err)
+		}
+		if err := left.Validate(); err != nil {
+			t.Fatalf("Concat(%d, %d): %v", nl, nr, err)
+		}
+		if got := left.values(); !slices.Equal(got, want) {
+			t.Fatalf("Concat(%d, %d): got %v", nl, nr, got)
+		}
+		if right.Root != nil {
+			t.Fatalf("Concat(%d, %d): other is not empty", nl, nr)
+		}
+	}
+}
+
+func TestTree_Concat_errors(t *testing.T) {
+	left, right := &Tree[int, string]{}, &Tree[int, string]{}
+	left.Insert(1, "a")
+	left.Insert(5, "b")
+	right.Insert(3, "c")
+	if err := left.Concat(right); !errors.Is(err, ErrKeyOrder) {
+		t.Errorf("overlapping trees: got error %v, want ErrKeyOrder", err)
+	}
+	right = &Tree[int, string]{}
+	right.Insert(5, "c")
+	if err := left.Concat(right); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("duplicate value: got error %v, want ErrDuplicateKey", err)
+	}
+	if got := left.values(); !slices.Equal(got, []int{1, 5}) {
+		t.Errorf("tree changed after failed Concat: got %v", got)
+	}
+	if right.Len() != 1 {
+		t.Errorf("other changed after failed Concat: got len %d", right.Len())
+	}
+}
+
+func TestTree_Concat_hooks(t *testing.T) {
+	var log []string
+	left := New[int, string](WithHooks(Hooks[int, string]{
+		OnInsert: func(v int, d string) { log = append(log, fmt.Sprintf("insert %d %s", v, d)) },
+	}))
+	right := New[int, string](WithHooks(Hooks[int, string]{
+		OnDelete: func(v int, d string) { log = append(log, fmt.Sprintf("delete %d %s", v, d)) },
+	}))
+	right.Insert(1, "a")
+	right.Insert(2, "b")
+	if err := left.Concat(right); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"delete 1 a", "insert 1 a", "delete 2 b", "insert 2 b"}
+	if !slices.Equal(log, want) {
+		t.Errorf("hooks: got %q, want %q", log, want)
+	}
+}
+
+func TestTree_split(t *testing.T) {
+	for _, dup := range []bool{false, true} {
+		for bound := -1; bound <= 51; bound++ {
+			tree := New[int, int](WithAllowDuplicates())
+			if !dup {
+				tree = New[int, int]()
+			}
+			for i := range 50 {
+				tree.Insert(i, 0)
+				if dup && i%3 == 0 {
+					tree.Insert(i, 1)
+				}
+			}
+			want := tree.values()
+			before, rest := tree.split(tree.Root, bound)
+			for _, part := range []*Node[int, int]{before, rest} {
+				if err := (&Tree[int, int]{Root: part, cfg: tree.cfg}).Validate(); err != nil {
+					t.Fatalf("split at %d: %v", bound, err)
+				}
+			}
+			got := slices.Concat((&Tree[int, int]{Root: before}).values(), (&Tree[int, int]{Root: rest}).values())
+			if !slices.Equal(got, want) {
+				t.Fatalf("split at %d: got %v, want %v", bound, got, want)
+			}
+			if m, _, ok := (&Tree[int, int]{Root: before}).Max(); ok && m >= bound {
+				t.Fatalf("split at %d: %d is in the first part", bound, m)
+			}
+		}
+	}
+}
+
+func TestTree_Subtree_noBalance(t *testing.T) {
+	tree := New[int, int](WithNoBalance())
+	for v := range 50 {
+		tree.Insert(v, v)
+	}
+	sub := tree.Subtree(10, 40)
+	sub.Insert(40, 40)
+	for _, tr := range []*Tree[int, int]{tree, sub} {
+		if err := tr.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sub.Len() != 31 || tree.Len() != 20 {
+		t.Errorf("Len() = %d and %d, want 31 and 20", sub.Len(), tree.Len())
+	}
+}
+
+func TestTree_Subtree(t *testing.T) {
+	var deleted []int
+	tree := New[int, string](WithHooks(Hooks[int, string]{
+		OnDelete: func(v int, _ string) { deleted = append(deleted, v) },
+	}))
+	for i := range 100 {
+		tree.Insert(i, "")
+	}
+	sub := tree.Subtree(20, 60)
+	if got := sub.values(); len(got) != 40 || got[0] != 20 || got[39] != 59 {
+		t.Errorf("subtree has %d values from %v", len(got), got[:1])
+	}
+	if tree.Len() != 60 || tree.Contains(20) || !tree.Contains(19) || !tree.Contains(60) {
+		t.Errorf("remaining tree has %d values", tree.Len())
+	}
+	if len(deleted) != 40 {
+		t.Errorf("%d OnDelete calls, want 40", len(deleted))
+	}
+	for _, tr := range []*Tree[int, string]{tree, sub} {
+		if err := tr.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Move the range back.
+	rest := tree.Subtree(60, 100)
+	if err := tree.Concat(sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Concat(rest); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Len() != 100 || tree.Validate() != nil {
+		t.Errorf("after moving back: Len = %d, Validate = %v", tree.Len(), tree.Validate())
+	}
+
+	if empty := tree.Subtree(50, 50); empty.Len() != 0 || tree.Len() != 100 {
+		t.Error("empty interval moved entries")
+	}
+}
@@ -0,0 +1,192 @@
+package generictree
+
+import "cmp"
+
+// Journal records the changes of a tree so that they can be undone and
+// redone. It keeps the inverse of each insertion, update, and deletion, up
+// to a limit; when the limit is reached, the oldest change is dropped.
+//
+// Every change counts separately, including evictions (see WithMaxSize) and
+// each entry of a bulk operation such as Concat. The changes that Undo or
+// Redo cause besides the one they revert or reapply, such as evictions,
+// belong to that change: Redo or Undo reverts them along with it, so that
+// the history stays in step with the tree. Replaying a change may still
+// evict other entries than the original change did, for example under
+// EvictLRU, so a series of undos and redos on such a tree does not always
+// restore it exactly. Undoing a change of an entry whose value occurs more
+// than once in a tree with duplicates may affect another entry with the
+// same value.
+type Journal[Value cmp.Ordered, Data any] struct {
+	tree      *Tree[Value, Data]
+	limit     int
+	undo      []change[Value, Data] // ring buffer of up to limit changes
+	first     int                   // index of the oldest change in undo
+	nundo     int                   // number of changes in undo
+	redo      []change[Value, Data]
+	replaying bool                  // set while Undo or Redo changes the tree
+	replayed  *change[Value, Data]  // the change that Undo or Redo makes, until its hook runs
+	side      []change[Value, Data] // other changes made while replaying
+}
+
+// change describes one change of a single entry: from the state before to
+// the state after.
+type change[Value cmp.Ordered, Data any] struct {
+	value             Value
+	before, after     Data
+	existed, survives bool                  // whether the entry exists before and after the change
+	side              []change[Value, Data] // changes caused when Undo or Redo last replayed the change, in order
+}
+
+// inverse returns the change that reverts c, without its side changes.
+func (c change[Value, Data]) inverse() change[Value, Data] {
+	return change[Value, Data]{value: c.value, before: c.after, after: c.before, existed: c.survives, survives: c.existed}
+}
+
+// NewJournal starts recording the changes of t, keeping at most limit
+// changes for Undo. The journal registers hooks on t (see WithHooks).
+func NewJournal[Value cmp.Ordered, Data any](t *Tree[Value, Data], limit int) *Journal[Value, Data] {
+	j := &Journal[Value, Data]{tree: t, limit: limit}
+	t.addHooks(Hooks[Value, Data]{
+		OnInsert: func(v Value, d Data) {
+			j.record(change[Value, Data]{value: v, after: d, survives: true})
+		},
+		OnUpdate: func(v Value, old, new Data) {
+			j.record(change[Value, Data]{value: v, before: old, after: new, existed: true, survives: true})
+		},
+		OnDelete: func(v Value, d Data) {
+			j.record(change[Value, Data]{value: v, before: d, existed: true})
+		},
+	})
+	return j
+}
+
+func (j *Journal[Value, Data]) record(c change[Value, Data]) {
+	if j.limit <= 0 {
+		return
+	}
+	if j.replaying {
+		if r := j.replayed; r != nil && c.existed == r.existed && c.survives == r.survives && j.tree.compare(c.value, r.value) == 0 {
+			j.replayed = nil
+			return
+		}
+		j.side = append(j.side, c)
+		return
+	}
+	j.add(c)
+	clear(j.redo)
+	j.redo = j.redo[:0]
+}
+
+// add adds c as the newest change to undo, dropping the oldest change if
+// undo is full.
+func (j *Journal[Value, Data]) add(c change[Value, Data]) {
+	if j.nundo == j.limit {
+		// Overwrite the oldest change.
+		j.undo[j.first] = c
+		j.first = (j.first + 1) % j.limit
+	} else {
+		j.push(c)
+	}
+}
+
+// push adds c as the newest change to undo, which must hold fewer than
+// limit changes.
+func (j *Journal[Value, Data]) push(c change[Value, Data]) {
+	if j.nundo < len(j.undo) {
+		j.undo[(j.first+j.nundo)%len(j.undo)] = c
+	} else {
+		// The buffer has not wrapped yet, so first is 0.
+		j.undo = append(j.undo, c)
+	}
+	j.nundo++
+}
+
+// pop removes the newest change from undo and returns it.
+func (j *Journal[Value, Data]) pop() change[Value, Data] {
+	j.nundo--
+	i := (j.first + j.nundo) % len(j.undo)
+	c := j.undo[i]
+	j.undo[i] = change[Value, Data]{}
+	return c
+}
+
+// Undo reverts the last n changes that have not been undone yet and returns
+// the number of changes it reverted, which is less than n if the journal
+// holds fewer changes.
+func (j *Journal[Value, Data]) Undo(n int) int {
+	done := 0
+	for ; done < n && j.nundo > 0; done++ {
+		j.redo = append(j.redo, j.replay(j.pop(), false))
+	}
+	return done
+}
+
+// Redo reapplies the last n changes that were undone and returns the number
+// of changes it reapplied. Any new change of the tree discards the changes
+// that could be redone.
+func (j *Journal[Value, Data]) Redo(n int) int {
+	done := 0
+	for ; done < n && len(j.redo) > 0; done++ {
+		c := j.redo[len(j.redo)-1]
+		j.redo = j.redo[:len(j.redo)-1]
+		j.add(j.replay(c, true))
+	}
+	return done
+}
+
+// replay reapplies c if forward is true, or reverts it otherwise. It then
+// reverts the side changes of c, newest first, and returns c with the
+// changes that the replay caused as its new side changes. c goes first
+// because in a tree with WithMaxSize, reverting an eviction before c
+// would exceed the size limit.
+func (j *Journal[Value, Data]) replay(c change[Value, Data], forward bool) change[Value, Data] {
+	var side []change[Value, Data]
+	if forward {
+		side = j.set(c)
+	} else {
+		side = j.set(c.inverse())
+	}
+	for i := len(c.side) - 1; i >= 0; i-- {
+		side = append(side, j.set(c.side[i].inverse())...)
+	}
+	c.side = side
+	return c
+}
+
+// UndoLen returns the number of changes that Undo can revert.
+func (j *Journal[Value, Data]) UndoLen() int {
+	return j.nundo
+}
+
+// RedoLen returns the number of changes that Redo can reapply.
+func (j *Journal[Value, Data]) RedoLen() int {
+	return len(j.redo)
+}
+
+// Clear discards the recorded changes.
+func (j *Journal[Value, Data]) Clear() {
+	clear(j.undo)
+	clear(j.redo)
+	j.undo, j.first, j.nundo = j.undo[:0], 0, 0
+	j.redo = j.redo[:0]
+}
+
+// set makes the change c to the tree and returns the other changes that it
+// caused, in the order in which they happened.
+func (j *Journal[Value, Data]) set(c change[Value, Data]) []change[Value, Data] {
+	j.replaying, j.replayed, j.side = true, &c, nil
+	defer func() { j.replaying, j.replayed, j.side = false, nil, nil }()
+	switch {
+	case !c.survives:
+		j.tree.Delete(c.value)
+	case c.existed:
+		// Insert would add another entry in a tree with duplicates, or
+		// follow the tree's duplicate policy; replace the data instead.
+		if n := j.tree.findNode(c.value); n != nil {
+			j.tree.entry(n).SetData(c.after)
+		}
+	default:
+		j.tree.Insert(c.value, c.after)
+	}
+	return j.side
+}
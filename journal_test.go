@@ -0,0 +1,143 @@
+package generictree
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	tree := &Tree[int, string]{}
+	tree.Insert(1, "a")
+	j := NewJournal(tree, 10)
+
+	current := func() string {
+		return Fold(tree, "", func(acc string, v int, d string) string { return acc + fmt.Sprintf(" %d:%s", v, d) })
+	}
+	snapshots := []string{current()}
+	step := func(f func()) {
+		f()
+		snapshots = append(snapshots, current())
+	}
+	step(func() { tree.Insert(2, "b") })
+	step(func() { tree.Insert(1, "A") })
+	step(func() { tree.Delete(2) })
+	step(func() { tree.Insert(3, "c") })
+
+	for i := len(snapshots) - 2; i >= 0; i-- {
+		if j.Undo(1) != 1 {
+			t.Fatalf("Undo failed at step %d", i)
+		}
+		if got := current(); got != snapshots[i] {
+			t.Fatalf("after undo to step %d: got %s, want %s", i, got, snapshots[i])
+		}
+	}
+	if j.Undo(1) != 0 {
+		t.Error("Undo beyond the first change succeeded")
+	}
+	if n := j.Redo(10); n != 4 {
+		t.Errorf("Redo(10) = %d, want 4", n)
+	}
+	if got := current(); got != snapshots[len(snapshots)-1] {
+		t.Errorf("after redo: got %s, want %s", got, snapshots[len(snapshots)-1])
+	}
+
+	// A new change discards the redo history.
+	j.Undo(2)
+	tree.Insert(9, "z")
+	if j.RedoLen() != 0 || j.UndoLen() != 3 {
+		t.Errorf("after a new change: UndoLen %d, RedoLen %d; want 3, 0", j.UndoLen(), j.RedoLen())
+	}
+}
+
+func TestJournal_limit(t *testing.T) {
+	tree := &Tree[int, int]{}
+	j := NewJournal(tree, 3)
+	for v := range 5 {
+		tree.Insert(v, v)
+	}
+	if n := j.Undo(5); n != 3 {
+		t.Errorf("Undo(5) = %d, want 3", n)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("got %v, want [0 1]", got)
+	}
+}
+
+// TestJournal_wrap checks that the journal keeps the newest changes in order
+// after its buffer wraps around, also when undos and redos interleave with
+// new changes.
+func TestJournal_wrap(t *testing.T) {
+	tree := &Tree[int, int]{}
+	j := NewJournal(tree, 4)
+	for v := range 10 {
+		tree.Insert(v, v)
+	}
+	j.Undo(2) // removes 9 and 8
+	tree.Insert(20, 20)
+	tree.Insert(21, 21)
+	j.Undo(1) // removes 21
+	j.Redo(1) // inserts 21
+	tree.Insert(22, 22)
+	if n := j.UndoLen(); n != 4 {
+		t.Errorf("UndoLen() = %d, want 4", n)
+	}
+	// The journal holds the insertions of 7, 20, 21, and 22.
+	if n := j.Undo(10); n != 4 {
+		t.Errorf("Undo(10) = %d, want 4", n)
+	}
+	if got, want := tree.values(), []int{0, 1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if n := j.Redo(10); n != 4 {
+		t.Errorf("Redo(10) = %d, want 4", n)
+	}
+	if got, want := tree.values(), []int{0, 1, 2, 3, 4, 5, 6, 7, 20, 21, 22}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	j.Clear()
+	tree.Insert(30, 30)
+	if n := j.Undo(10); n != 1 || tree.Len() != 11 {
+		t.Errorf("Undo(10) after Clear = %d with %d entries, want 1 with 11", n, tree.Len())
+	}
+}
+
+func TestJournal_duplicates(t *testing.T) {
+	tree := New[int, string](WithAllowDuplicates())
+	tree.Insert(1, "a")
+	j := NewJournal(tree, 10)
+	tree.Insert(1, "b")
+	tree.Delete(1)
+	for e := range tree.Entries() {
+		e.SetData("c")
+	}
+	j.Undo(3)
+	if tree.Len() != 1 {
+		t.Errorf("Len() = %d after undoing everything, want 1", tree.Len())
+	}
+}
+
+// TestJournal_evictions checks that Undo and Redo revert the evictions
+// that they cause along with the change that caused them, so that each
+// step restores the tree and counts as one change.
+func TestJournal_evictions(t *testing.T) {
+	tree := New[int, int](WithMaxSize(2, EvictLRU))
+	tree.Insert(1, 1)
+	tree.Insert(2, 2)
+	j := NewJournal(tree, 10)
+	tree.Insert(3, 3) // evicts 1
+	want := tree.values()
+
+	if n := j.Undo(1); n != 1 || j.RedoLen() != 1 {
+		t.Fatalf("Undo(1) = %d with RedoLen %d, want 1 and 1", n, j.RedoLen())
+	}
+	if got := tree.values(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("after undoing the eviction of 1: got %v, want [1 3]", got)
+	}
+	if n := j.Redo(1); n != 1 || j.RedoLen() != 0 {
+		t.Fatalf("Redo(1) = %d with RedoLen %d, want 1 and 0", n, j.RedoLen())
+	}
+	if got := tree.values(); !slices.Equal(got, want) {
+		t.Errorf("after Undo(1) and Redo(1): got %v, want %v", got, want)
+	}
+}
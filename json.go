@@ -0,0 +1,128 @@
+package generictree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// MarshalJSON implements json.Marshaler. It encodes the tree as an array of
+// entries in tree order:
+//
+//	[{"value":1,"data":"one"},{"value":2,"data":"two"}]
+//
+// The encoding depends only on the contents of the tree, not on its shape:
+// the entries are in tree order, each with the fields value and data in
+// this order, and encoding/json sorts the keys of maps. Rotations and the
+// order of insertions therefore do not change the encoding, except for the
+// order of duplicates; see CanonicalBytes.
+func (t *Tree[Value, Data]) MarshalJSON() ([]byte, error) {
+	entries := make([]KV[Value, Data], 0, t.Len())
+	for v, d := range t.All() {
+		entries = append(entries, KV[Value, Data]{v, d})
+	}
+	return json.Marshal(entries)
+}
+
+// CanonicalBytes returns the JSON encoding of the tree in a canonical form,
+// for content-addressed storage or golden files: trees with the same
+// entries have the same encoding. It is the encoding of MarshalJSON, except
+// that in a tree with duplicates, the entries of equal values are sorted by
+// their encoding rather than kept in insertion order. UnmarshalJSON accepts
+// it.
+func (t *Tree[Value, Data]) CanonicalBytes() ([]byte, error) {
+	if !t.allowDuplicates() {
+		return t.MarshalJSON()
+	}
+	var (
+		buf  = []byte{'['}
+		run  [][]byte // encoded entries of equal values
+		last Value
+	)
+	flush := func() {
+		slices.SortFunc(run, bytes.Compare)
+		for _, e := range run {
+			if len(buf) > 1 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, e...)
+		}
+		run = run[:0]
+	}
+	for v, d := range t.All() {
+		e, err := json.Marshal(KV[Value, Data]{v, d})
+		if err != nil {
+			return nil, err
+		}
+		if len(run) > 0 && t.compare(last, v) != 0 {
+			flush()
+		}
+		run, last = append(run, e), v
+	}
+	flush()
+	return append(buf, ']'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the contents of the
+// tree with the entries encoded by MarshalJSON. The entries must be in tree
+// order; otherwise, UnmarshalJSON returns an error wrapping ErrKeyOrder or
+// ErrDuplicateKey and leaves the tree unchanged. The new tree is perfectly
+// balanced.
+//
+// If the tree has hooks, UnmarshalJSON calls OnDelete for each previous
+// entry and OnInsert for each new entry. If the tree has a maximum size
+// (see WithMaxSize), UnmarshalJSON evicts entries to stay within it.
+func (t *Tree[Value, Data]) UnmarshalJSON(b []byte) error {
+	var entries []KV[Value, Data]
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return err
+	}
+	if err := t.replaceSorted(len(entries), func(i int) (Value, Data) {
+		return entries[i].Value, entries[i].Data
+	}); err != nil {
+		return err
+	}
+	t.debug.record(t, "UnmarshalJSON", b)
+	return nil
+}
+
+// replaceSorted replaces the contents of the tree with the n entries that
+// at returns, which must be in tree order, like UnmarshalJSON.
+func (t *Tree[Value, Data]) replaceSorted(n int, at func(i int) (Value, Data)) error {
+	for i := 1; i < n; i++ {
+		prev, _ := at(i - 1)
+		v, _ := at(i)
+		switch c := t.compare(prev, v); {
+		case c == 0 && !t.allowDuplicates():
+			return fmt.Errorf("entry %d: %v: %w", i, v, ErrDuplicateKey)
+		case c > 0:
+			return fmt.Errorf("entry %d: %v after %v: %w", i, v, prev, ErrKeyOrder)
+		}
+	}
+
+	old := t.Root
+	t.Root = build(n, t.allocator(), at)
+	if t.cfg != nil && t.cfg.lru != nil {
+		t.cfg.lru.clear()
+	}
+
+	if len(t.hooks()) > 0 || t.cfg != nil && t.cfg.nodeHooks != nil {
+		// Collect the old nodes first; afterDelete may recycle them.
+		var removed []*Node[Value, Data]
+		old.ascend(func(n *Node[Value, Data]) bool {
+			removed = append(removed, n)
+			return true
+		})
+		for _, n := range removed {
+			t.afterDelete(n)
+		}
+		for i := range n {
+			var zero Data
+			v, d := at(i)
+			t.afterInsert(v, d, zero, false)
+		}
+	}
+	t.adopt()
+	return nil
+}
@@ -0,0 +1,103 @@
+package generictree
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTree_JSON(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for _, v := range []int{5, 3, 8, 1, 4} {
+		tree.Insert(v, string(rune('a'+v)))
+	}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"value":1,"data":"b"},{"value":3,"data":"d"},{"value":4,"data":"e"},{"value":5,"data":"f"},{"value":8,"data":"i"}]`
+	if string(b) != want {
+		t.Errorf("MarshalJSON:\ngot  %s\nwant %s", b, want)
+	}
+
+	var loaded Tree[int, string]
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.values(); !slices.Equal(got, tree.values()) {
+		t.Errorf("UnmarshalJSON: got %v, want %v", got, tree.values())
+	}
+	if d, _ := loaded.Find(4); d != "e" {
+		t.Errorf("data of 4: got %q, want \"e\"", d)
+	}
+}
+
+func TestTree_UnmarshalJSON_errors(t *testing.T) {
+	tree := &Tree[int, string]{}
+	tree.Insert(1, "keep")
+	if err := tree.UnmarshalJSON([]byte(`[{"value":2},{"value":1}]`)); !errors.Is(err, ErrKeyOrder) {
+		t.Errorf("unsorted input: got error %v, want ErrKeyOrder", err)
+	}
+	if err := tree.UnmarshalJSON([]byte(`[{"value":1},{"value":1}]`)); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("duplicate input: got error %v, want ErrDuplicateKey", err)
+	}
+	if err := tree.UnmarshalJSON([]byte(`{`)); err == nil {
+		t.Error("invalid JSON: got no error")
+	}
+	if d, _ := tree.Find(1); d != "keep" || tree.Len() != 1 {
+		t.Errorf("tree changed after failed UnmarshalJSON: %v", tree)
+	}
+
+	dup := New[int, string](WithAllowDuplicates())
+	if err := dup.UnmarshalJSON([]byte(`[{"value":1},{"value":1}]`)); err != nil {
+		t.Errorf("duplicates allowed: %v", err)
+	}
+}
+
+func TestTree_CanonicalBytes(t *testing.T) {
+	// The same entries, inserted in different orders, into trees of
+	// different shapes.
+	a := New[int, map[string]int]()
+	b := New[int, map[string]int](WithNoBalance())
+	for i := range 20 {
+		a.Insert(i, map[string]int{"x": i, "a": -i})
+		b.Insert(19-i, map[string]int{"a": i - 19, "x": 19 - i})
+	}
+	a.Insert(50, nil)
+	a.Delete(50)
+	if a.Root.Height() == b.Root.Height() {
+		t.Fatal("trees have the same shape")
+	}
+	ja, _ := a.CanonicalBytes()
+	jb, _ := b.CanonicalBytes()
+	if string(ja) != string(jb) {
+		t.Errorf("different encodings:\n%s\n%s", ja, jb)
+	}
+	if m, _ := a.MarshalJSON(); string(m) != string(ja) {
+		t.Errorf("CanonicalBytes differs from MarshalJSON without duplicates")
+	}
+
+	d1 := New[int, string](WithAllowDuplicates())
+	d2 := New[int, string](WithAllowDuplicates())
+	for _, s := range []string{"x", "y", "z"} {
+		d1.Insert(1, s)
+		d2.Insert(1, string('x'+'z'-s[0]))
+	}
+	d1.Insert(0, "a")
+	d2.Insert(2, "b")
+	d1.Insert(2, "b")
+	d2.Insert(0, "a")
+	j1, _ := d1.CanonicalBytes()
+	j2, _ := d2.CanonicalBytes()
+	want := `[{"value":0,"data":"a"},{"value":1,"data":"x"},{"value":1,"data":"y"},{"value":1,"data":"z"},{"value":2,"data":"b"}]`
+	if string(j1) != want || string(j2) != want {
+		t.Errorf("duplicates:\ngot  %s\nand  %s\nwant %s", j1, j2, want)
+	}
+	if err := d2.UnmarshalJSON(j1); err != nil {
+		t.Errorf("UnmarshalJSON(CanonicalBytes()): %v", err)
+	}
+}
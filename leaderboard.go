@@ -0,0 +1,187 @@
+package generictree
+
+// TieBreak decides how a Leaderboard ranks players with equal scores.
+type TieBreak int
+
+const (
+	// FirstCome ranks players with equal scores by the time they reached
+	// the score: the earlier player ranks higher. Every player has a
+	// distinct rank (1, 2, 3, 4).
+	FirstCome TieBreak = iota
+	// SharedRank gives players with equal scores the same rank and skips
+	// the ranks that follow (1, 2, 2, 4). Players with equal scores are
+	// still listed in the order in which they reached the score.
+	SharedRank
+)
+
+// Standing is the position of a player on a Leaderboard.
+type Standing[ID comparable] struct {
+	Rank  int // 1 for the top player
+	ID    ID
+	Score float64
+}
+
+// Leaderboard ranks players by score, highest score first. It is built on
+// the order-statistic tree (see Tree.Rank and Tree.Select), so all of its
+// operations take O(log n) time, plus the number of standings returned.
+//
+// The zero value is not usable; create leaderboards with NewLeaderboard.
+type Leaderboard[ID comparable] struct {
+	tie     TieBreak
+	tree    *Tree[float64, player[ID]]
+	players map[ID]player[ID]
+	seq     uint64 // increases with every SetScore
+}
+
+// player is the data stored for each score. seq orders players with
+// equal scores: players with equal scores are in insertion order, which is
+// the order of seq.
+type player[ID comparable] struct {
+	id    ID
+	score float64
+	seq   uint64
+}
+
+// NewLeaderboard returns an empty leaderboard that breaks ties by tie.
+func NewLeaderboard[ID comparable](tie TieBreak) *Leaderboard[ID] {
+	return &Leaderboard[ID]{
+		tie:     tie,
+		tree:    New[float64, player[ID]](WithDescending(), WithAllowDuplicates()),
+		players: map[ID]player[ID]{},
+	}
+}
+
+// Len returns the number of players.
+func (l *Leaderboard[ID]) Len() int {
+	return len(l.players)
+}
+
+// SetScore sets the score of id, adding the player if needed. If the score
+// changes, the player counts as having reached the new score now.
+func (l *Leaderboard[ID]) SetScore(id ID, score float64) {
+	if p, ok := l.players[id]; ok {
+		if p.score == score {
+			return
+		}
+		l.remove(p)
+	}
+	l.seq++
+	p := player[ID]{id: id, score: score, seq: l.seq}
+	l.players[id] = p
+	l.tree.Insert(score, p)
+}
+
+// Score returns the score of id. ok is false if id is not on the
+// leaderboard.
+func (l *Leaderboard[ID]) Score(id ID) (score float64, ok bool) {
+	p, ok := l.players[id]
+	return p.score, ok
+}
+
+// Remove removes id from the leaderboard. It reports whether id was on it.
+func (l *Leaderboard[ID]) Remove(id ID) bool {
+	p, ok := l.players[id]
+	if ok {
+		l.remove(p)
+		delete(l.players, id)
+	}
+	return ok
+}
+
+func (l *Leaderboard[ID]) remove(p player[ID]) {
+	var removed *Node[float64, player[ID]]
+	l.tree.Root, removed = l.tree.Root.deleteFunc(l.locate(p), l.tree.balancing())
+	l.tree.debug.record(l.tree, "Delete", p.score)
+	l.tree.afterDelete(removed)
+}
+
+// locate returns a function that finds the node of p.
+func (l *Leaderboard[ID]) locate(p player[ID]) func(*Node[float64, player[ID]]) int {
+	return func(n *Node[float64, player[ID]]) int {
+		if c := l.tree.compare(p.score, n.Value); c != 0 {
+			return c
+		}
+		switch {
+		case p.seq < n.Data.seq:
+			return -1
+		case p.seq > n.Data.seq:
+			return 1
+		}
+		return 0
+	}
+}
+
+// index returns the position of p in the tree, counting from zero.
+func (l *Leaderboard[ID]) index(p player[ID]) int {
+	locate := l.locate(p)
+	i := 0
+	for n := l.tree.Root; n != nil; {
+		switch c := locate(n); {
+		case c < 0:
+			n = n.Left
+		case c > 0:
+			i += n.Left.count() + 1
+			n = n.Right
+		default:
+			return i + n.Left.count()
+		}
+	}
+	panic("generictree: leaderboard player is missing from its tree")
+}
+
+// rank returns the rank of the player at index i.
+func (l *Leaderboard[ID]) rank(i int, score float64) int {
+	if l.tie == SharedRank {
+		return l.tree.Rank(score) + 1
+	}
+	return i + 1
+}
+
+// RankOf returns the rank of id, 1 being the top rank. ok is false if id is
+// not on the leaderboard.
+func (l *Leaderboard[ID]) RankOf(id ID) (rank int, ok bool) {
+	p, ok := l.players[id]
+	if !ok {
+		return 0, false
+	}
+	return l.rank(l.index(p), p.score), true
+}
+
+// Top returns the standings of the top n players, or of all players if
+// there are fewer than n.
+func (l *Leaderboard[ID]) Top(n int) []Standing[ID] {
+	return l.standings(0, n)
+}
+
+// Around returns the standings of id and of up to k players directly above
+// and below it. It returns nil if id is not on the leaderboard.
+func (l *Leaderboard[ID]) Around(id ID, k int) []Standing[ID] {
+	p, ok := l.players[id]
+	if !ok {
+		return nil
+	}
+	i := l.index(p)
+	from := max(i-k, 0)
+	return l.standings(from, i+k+1-from)
+}
+
+// standings returns up to n standings, starting at index from.
+func (l *Leaderboard[ID]) standings(from, n int) []Standing[ID] {
+	var result []Standing[ID]
+	c := l.tree.Cursor()
+	for ok := c.SeekIndex(from); ok && len(result) < n; ok = c.Next() {
+		i := from + len(result)
+		rank := i + 1
+		if l.tie == SharedRank {
+			// Players with equal scores are adjacent; only the first one
+			// of a group needs a Rank query.
+			if len(result) > 0 && result[len(result)-1].Score == c.Value() {
+				rank = result[len(result)-1].Rank
+			} else {
+				rank = l.rank(i, c.Value())
+			}
+		}
+		result = append(result, Standing[ID]{Rank: rank, ID: c.Data().id, Score: c.Value()})
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package generictree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeaderboard(t *testing.T) {
+	l := NewLeaderboard[string](FirstCome)
+	l.SetScore("ann", 50)
+	l.SetScore("bob", 70)
+	l.SetScore("cid", 50)
+	l.SetScore("dan", 90)
+	l.SetScore("eve", 50)
+
+	want := []Standing[string]{
+		{1, "dan", 90}, {2, "bob", 70}, {3, "ann", 50}, {4, "cid", 50}, {5, "eve", 50},
+	}
+	if got := l.Top(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Top(10):\ngot  %v\nwant %v", got, want)
+	}
+	if got := l.Top(2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("Top(2): got %v", got)
+	}
+	for _, s := range want {
+		if rank, ok := l.RankOf(s.ID); !ok || rank != s.Rank {
+			t.Errorf("RankOf(%s) = %d, %t; want %d", s.ID, rank, ok, s.Rank)
+		}
+	}
+	if got := l.Around("ann", 1); !reflect.DeepEqual(got, want[1:4]) {
+		t.Errorf("Around(ann, 1): got %v", got)
+	}
+	if got := l.Around("dan", 2); !reflect.DeepEqual(got, want[:3]) {
+		t.Errorf("Around(dan, 2): got %v", got)
+	}
+
+	// Reaching a score again moves a player behind the others with that score.
+	l.SetScore("ann", 60)
+	l.SetScore("ann", 50)
+	if rank, _ := l.RankOf("ann"); rank != 5 {
+		t.Errorf("RankOf(ann) after rescoring = %d, want 5", rank)
+	}
+	if !l.Remove("bob") || l.Remove("bob") {
+		t.Error("Remove(bob) should succeed exactly once")
+	}
+	if rank, _ := l.RankOf("cid"); rank != 2 || l.Len() != 4 {
+		t.Errorf("after Remove: RankOf(cid) = %d, Len() = %d; want 2, 4", rank, l.Len())
+	}
+	if _, ok := l.RankOf("bob"); ok {
+		t.Error("RankOf(bob) after Remove returned ok = true")
+	}
+	if err := l.tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLeaderboard_SharedRank(t *testing.T) {
+	l := NewLeaderboard[int](SharedRank)
+	for id, score := range []float64{10, 30, 20, 30, 10, 30} {
+		l.SetScore(id, score)
+	}
+	want := []Standing[int]{
+		{1, 1, 30}, {1, 3, 30}, {1, 5, 30}, {4, 2, 20}, {5, 0, 10}, {5, 4, 10},
+	}
+	if got := l.Top(6); !reflect.DeepEqual(got, want) {
+		t.Errorf("Top(6):\ngot  %v\nwant %v", got, want)
+	}
+	if got := l.Around(0, 1); !reflect.DeepEqual(got, want[3:]) {
+		t.Errorf("Around(0, 1): got %v", got)
+	}
+	for _, s := range want {
+		if rank, _ := l.RankOf(s.ID); rank != s.Rank {
+			t.Errorf("RankOf(%d) = %d, want %d", s.ID, rank, s.Rank)
+		}
+	}
+	if score, ok := l.Score(2); !ok || score != 20 {
+		t.Errorf("Score(2) = %v, %t", score, ok)
+	}
+}
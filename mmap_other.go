@@ -0,0 +1,20 @@
+//go:build !unix
+
+package generictree
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the file f into memory. Systems without mmap pay for the
+// whole file in memory.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	b := make([]byte, size)
+	_, err := io.ReadFull(io.NewSectionReader(f, 0, int64(size)), b)
+	return b, err
+}
+
+func unmapFile([]byte) error {
+	return nil
+}
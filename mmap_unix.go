@@ -0,0 +1,18 @@
+//go:build unix
+
+package generictree
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the file f into memory, read-only. The mapping stays valid
+// after f is closed.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,96 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// MultiMap maps each key to an ordered collection of values. The keys are
+// kept in ascending order; the values of a key are kept in the order they
+// were added. The zero value is an empty multimap ready to use.
+type MultiMap[K cmp.Ordered, V any] struct {
+	tree Tree[K, []V]
+	len  int
+}
+
+// Add appends value to the values of key.
+func (m *MultiMap[K, V]) Add(key K, value V) {
+	m.len++
+	if n := m.tree.findNode(key); n != nil {
+		n.Data = append(n.Data, value)
+		return
+	}
+	m.tree.Insert(key, []V{value})
+}
+
+// Get returns a copy of the values of key, in the order they were added,
+// or nil if key has no values.
+func (m *MultiMap[K, V]) Get(key K) []V {
+	values, _ := m.tree.Find(key)
+	return slices.Clone(values)
+}
+
+// Has reports whether key has at least one value.
+func (m *MultiMap[K, V]) Has(key K) bool {
+	_, ok := m.tree.Find(key)
+	return ok
+}
+
+// RemoveValue removes all values of key for which pred returns true, and
+// returns the number of values removed. A key without values is removed
+// from the multimap.
+func (m *MultiMap[K, V]) RemoveValue(key K, pred func(V) bool) int {
+	n := m.tree.findNode(key)
+	if n == nil {
+		return 0
+	}
+	before := len(n.Data)
+	n.Data = slices.DeleteFunc(n.Data, pred)
+	removed := before - len(n.Data)
+	m.len -= removed
+	if len(n.Data) == 0 {
+		m.tree.Delete(key)
+	}
+	return removed
+}
+
+// RemoveKey removes key with all its values and returns the values,
+// or nil if key has no values.
+func (m *MultiMap[K, V]) RemoveKey(key K) []V {
+	values, _ := m.tree.Delete(key)
+	m.len -= len(values)
+	return values
+}
+
+// Len returns the number of key-value pairs in the multimap.
+func (m *MultiMap[K, V]) Len() int {
+	return m.len
+}
+
+// KeyLen returns the number of distinct keys in the multimap.
+func (m *MultiMap[K, V]) KeyLen() int {
+	return m.tree.Len()
+}
+
+// All returns an iterator over all key-value pairs, ordered by key and,
+// within a key, in the order the values were added.
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.tree.Root.ascend(func(n *Node[K, []V]) bool {
+			for _, v := range n.Data {
+				if !yield(n.Value, v) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Keys returns an iterator over the distinct keys in ascending order.
+func (m *MultiMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.tree.Root.ascend(func(n *Node[K, []V]) bool { return yield(n.Value) })
+	}
+}
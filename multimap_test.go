@@ -0,0 +1,54 @@
+package generictree
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	var m MultiMap[string, int]
+	m.Add("b", 1)
+	m.Add("a", 2)
+	m.Add("b", 3)
+	m.Add("b", 4)
+
+	if m.Len() != 4 || m.KeyLen() != 2 {
+		t.Errorf("Len() = %d, KeyLen() = %d; want 4, 2", m.Len(), m.KeyLen())
+	}
+	if got := m.Get("b"); !slices.Equal(got, []int{1, 3, 4}) {
+		t.Errorf("Get(b) = %v, want [1 3 4]", got)
+	}
+	if got := m.Get("c"); got != nil {
+		t.Errorf("Get(c) = %v, want nil", got)
+	}
+
+	var pairs []string
+	for k, v := range m.All() {
+		pairs = append(pairs, fmt.Sprint(k, v))
+	}
+	if want := []string{"a2", "b1", "b3", "b4"}; !slices.Equal(pairs, want) {
+		t.Errorf("All() = %v, want %v", pairs, want)
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Keys() = %v, want [a b]", got)
+	}
+
+	odd := func(v int) bool { return v%2 == 1 }
+	if n := m.RemoveValue("b", odd); n != 2 {
+		t.Errorf("RemoveValue(b, odd) removed %d values, want 2", n)
+	}
+	if got := m.Get("b"); !slices.Equal(got, []int{4}) {
+		t.Errorf("Get(b) = %v after RemoveValue, want [4]", got)
+	}
+
+	// Removing the last value removes the key.
+	m.RemoveValue("a", func(int) bool { return true })
+	if m.Has("a") || m.Len() != 1 || m.KeyLen() != 1 {
+		t.Errorf("after removing all values of a: Has(a) = %t, Len() = %d, KeyLen() = %d", m.Has("a"), m.Len(), m.KeyLen())
+	}
+
+	if got := m.RemoveKey("b"); !slices.Equal(got, []int{4}) || m.Len() != 0 {
+		t.Errorf("RemoveKey(b) = %v, Len() = %d; want [4], 0", got, m.Len())
+	}
+}
@@ -0,0 +1,71 @@
+package generictree
+
+import "cmp"
+
+// hooked is the augmentation of a node in a tree with node hooks. It calls
+// the hooks and passes updates on to the augmentation that it wraps, if
+// any.
+type hooked[Value cmp.Ordered, Data any] struct {
+	hooks *NodeHooks[Value, Data]
+	next  augmentation[Value, Data] // nil for plain trees
+}
+
+func (h *hooked[Value, Data]) update(n *Node[Value, Data]) {
+	if h.next != nil {
+		h.next.update(n)
+	}
+}
+
+// unwrap returns the augmentation that a hooked augmentation wraps, or a
+// itself.
+func unwrap[Value cmp.Ordered, Data any](a augmentation[Value, Data]) augmentation[Value, Data] {
+	if h, ok := a.(*hooked[Value, Data]); ok {
+		return h.next
+	}
+	return a
+}
+
+// attach makes n carry the node hooks of t and calls OnAttach. A node that
+// comes from another tree drops the hooks of that tree.
+func (t *Tree[Value, Data]) attach(n *Node[Value, Data]) {
+	h := t.cfg.nodeHooks
+	n.aug = box[Value, Data](&hooked[Value, Data]{hooks: h, next: unwrap(n.aug.get())})
+	if h.OnAttach != nil {
+		h.OnAttach(n)
+	}
+}
+
+// attachAll attaches the nodes of t that do not carry its node hooks yet.
+func (t *Tree[Value, Data]) attachAll() {
+	if t.cfg == nil || t.cfg.nodeHooks == nil {
+		return
+	}
+	var added []*Node[Value, Data]
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		if h, ok := n.aug.get().(*hooked[Value, Data]); !ok || h.hooks != t.cfg.nodeHooks {
+			added = append(added, n)
+		}
+		return true
+	})
+	for _, n := range added {
+		t.attach(n)
+	}
+}
+
+// detach removes the node hooks of t from n and calls OnDetach.
+func (t *Tree[Value, Data]) detach(n *Node[Value, Data]) {
+	if t.cfg == nil || t.cfg.nodeHooks == nil {
+		return
+	}
+	n.aug = box(unwrap(n.aug.get()))
+	if t.cfg.nodeHooks.OnDetach != nil {
+		t.cfg.nodeHooks.OnDetach(n)
+	}
+}
+
+// rotated calls the OnRotate hook for n, if n carries one.
+func (n *Node[Value, Data]) rotated() {
+	if h, ok := n.aug.get().(*hooked[Value, Data]); ok && h.hooks.OnRotate != nil {
+		h.hooks.OnRotate(n)
+	}
+}
@@ -0,0 +1,131 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// nodeTracker records the nodes that node hooks report.
+type nodeTracker struct {
+	attached map[*Node[int, int]]bool
+	rotated  int
+	stray    int // hooks called for nodes that are not attached
+}
+
+func newNodeTracker() *nodeTracker {
+	return &nodeTracker{attached: map[*Node[int, int]]bool{}}
+}
+
+func (r *nodeTracker) hooks() NodeHooks[int, int] {
+	return NodeHooks[int, int]{
+		OnAttach: func(n *Node[int, int]) {
+			if r.attached[n] {
+				r.stray++
+			}
+			r.attached[n] = true
+		},
+		OnDetach: func(n *Node[int, int]) {
+			if !r.attached[n] {
+				r.stray++
+			}
+			delete(r.attached, n)
+		},
+		OnRotate: func(n *Node[int, int]) {
+			if !r.attached[n] {
+				r.stray++
+			}
+			r.rotated++
+		},
+	}
+}
+
+// check verifies that exactly the nodes of tree are attached.
+func (r *nodeTracker) check(t *testing.T, tree *Tree[int, int]) {
+	t.Helper()
+	if r.stray > 0 {
+		t.Errorf("%d hook calls for unattached nodes", r.stray)
+	}
+	count := 0
+	tree.Root.ascend(func(n *Node[int, int]) bool {
+		if !r.attached[n] {
+			t.Errorf("node %d is not attached", n.Value)
+		}
+		count++
+		return true
+	})
+	if count != len(r.attached) {
+		t.Errorf("%d nodes attached, tree has %d", len(r.attached), count)
+	}
+}
+
+func TestWithNodeHooks(t *testing.T) {
+	r := newNodeTracker()
+	tree := New[int, int](WithNodeHooks(r.hooks()), WithFreeList(NewFreeList[int, int](8)))
+	for i := range 7 {
+		tree.Insert(i, i)
+	}
+	// Ascending insertions rotate at every second step.
+	if r.rotated == 0 {
+		t.Error("no rotations reported")
+	}
+	r.check(t, tree)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 1000 {
+		v := rng.IntN(50)
+		if rng.IntN(2) == 0 {
+			tree.Insert(v, v)
+		} else {
+			tree.Delete(v)
+		}
+	}
+	r.check(t, tree)
+
+	b, err := tree.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	r.check(t, tree)
+}
+
+func TestWithNodeHooks_Concat(t *testing.T) {
+	ra, rb := newNodeTracker(), newNodeTracker()
+	a := New[int, int](WithNodeHooks(ra.hooks()))
+	b := New[int, int](WithNodeHooks(rb.hooks()))
+	for i := range 10 {
+		a.Insert(i, i)
+		b.Insert(i+10, i)
+	}
+	if err := a.Concat(b); err != nil {
+		t.Fatal(err)
+	}
+	ra.check(t, a)
+	rb.check(t, b)
+
+	// Moved nodes must not report to the hooks of b anymore.
+	rotated := rb.rotated
+	for i := range 100 {
+		a.Insert(i+20, i)
+	}
+	if rb.rotated != rotated {
+		t.Error("hooks of the source tree still called after Concat")
+	}
+}
+
+func TestWithNodeHooks_NumericTree(t *testing.T) {
+	r := newNodeTracker()
+	tree := NewNumericTree[int, int](WithNodeHooks(r.hooks()))
+	for i := 1; i <= 100; i++ {
+		tree.Insert(i, i)
+	}
+	for i := 1; i <= 100; i += 3 {
+		tree.Delete(i)
+	}
+	r.check(t, tree.tree)
+	if got, want := tree.SumRange(1, 101), 5050-1717; got != want {
+		t.Errorf("SumRange = %d, want %d", got, want)
+	}
+}
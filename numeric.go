@@ -0,0 +1,192 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Number is the set of types that NumericTree can aggregate.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// augmentation maintains extra information about the subtree of a node,
+// such as the sum of its data. update is called whenever the children or
+// the data of n change, after the height and size of n are up to date.
+type augmentation[Value cmp.Ordered, Data any] interface {
+	update(n *Node[Value, Data])
+}
+
+// augmented holds the augmentation of a node. Nodes point to it instead of
+// storing the interface value, which keeps the nodes of plain trees, and
+// particularly of sets, one word smaller.
+type augmented[Value cmp.Ordered, Data any] struct {
+	augmentation[Value, Data]
+}
+
+// box returns a pointer to a holding a, or nil if a is nil.
+func box[Value cmp.Ordered, Data any](a augmentation[Value, Data]) *augmented[Value, Data] {
+	if a == nil {
+		return nil
+	}
+	return &augmented[Value, Data]{a}
+}
+
+// get returns the augmentation that a holds, or nil if a is nil.
+func (a *augmented[Value, Data]) get() augmentation[Value, Data] {
+	if a == nil {
+		return nil
+	}
+	return a.augmentation
+}
+
+// aggregate holds the sum, minimum, and maximum of the data of a subtree.
+type aggregate[Value cmp.Ordered, N Number] struct {
+	sum, min, max N
+}
+
+func (a *aggregate[Value, N]) update(n *Node[Value, N]) {
+	a.sum, a.min, a.max = n.Data, n.Data, n.Data
+	for _, child := range []*Node[Value, N]{n.Left, n.Right} {
+		if child == nil {
+			continue
+		}
+		c := aggregateOf(child)
+		a.sum += c.sum
+		a.min = min(a.min, c.min)
+		a.max = max(a.max, c.max)
+	}
+}
+
+// aggregateOf returns the aggregate of the subtree rooted at n.
+func aggregateOf[Value cmp.Ordered, N Number](n *Node[Value, N]) *aggregate[Value, N] {
+	return unwrap(n.aug.get()).(*aggregate[Value, N])
+}
+
+// add merges b into a. ok tells whether a holds a value yet.
+func (a *aggregate[Value, N]) add(b aggregate[Value, N], ok bool) {
+	if !ok {
+		*a = b
+		return
+	}
+	a.sum += b.sum
+	a.min = min(a.min, b.min)
+	a.max = max(a.max, b.max)
+}
+
+// NumericTree is a tree with numeric data that answers sum, minimum, and
+// maximum queries over a range of values in O(log n) time. Every node
+// stores the aggregates of its subtree, and the tree keeps them up to date
+// through insertions, deletions, and rotations.
+//
+// The data of a NumericTree can only be changed through its methods, so
+// that the aggregates cannot become stale.
+type NumericTree[Value cmp.Ordered, N Number] struct {
+	tree *Tree[Value, N]
+}
+
+// NewNumericTree returns an empty numeric tree configured by opts.
+// See New for the available options.
+func NewNumericTree[Value cmp.Ordered, N Number](opts ...Option) *NumericTree[Value, N] {
+	t := New[Value, N](opts...)
+	t.cfg.augment = func() augmentation[Value, N] { return &aggregate[Value, N]{} }
+	return &NumericTree[Value, N]{tree: t}
+}
+
+// Insert stores n for value, replacing any previous data unless the tree
+// allows duplicates.
+func (t *NumericTree[Value, N]) Insert(value Value, n N) {
+	t.tree.Insert(value, n)
+}
+
+// Find returns the data stored for value and true, or zero and false if
+// value is not in the tree.
+func (t *NumericTree[Value, N]) Find(value Value) (N, bool) {
+	return t.tree.Find(value)
+}
+
+// Delete removes value from the tree and returns its data and true, or
+// zero and false if value is not in the tree.
+func (t *NumericTree[Value, N]) Delete(value Value) (N, bool) {
+	return t.tree.Delete(value)
+}
+
+// Len returns the number of entries in the tree.
+func (t *NumericTree[Value, N]) Len() int {
+	return t.tree.Len()
+}
+
+// All returns an iterator over the values and data of the tree, in the
+// tree's order.
+func (t *NumericTree[Value, N]) All() iter.Seq2[Value, N] {
+	return t.tree.All()
+}
+
+// SumRange returns the sum of the data of all values in the half-open
+// interval [lo, hi), or zero if there are none.
+func (t *NumericTree[Value, N]) SumRange(lo, hi Value) N {
+	a, _ := t.query(t.tree.Root, Incl(lo), Excl(hi), false, false)
+	return a.sum
+}
+
+// MinRange returns the smallest data of all values in the half-open
+// interval [lo, hi). ok is false if there are none.
+func (t *NumericTree[Value, N]) MinRange(lo, hi Value) (n N, ok bool) {
+	a, ok := t.query(t.tree.Root, Incl(lo), Excl(hi), false, false)
+	return a.min, ok
+}
+
+// MaxRange returns the largest data of all values in the half-open
+// interval [lo, hi). ok is false if there are none.
+func (t *NumericTree[Value, N]) MaxRange(lo, hi Value) (n N, ok bool) {
+	a, ok := t.query(t.tree.Root, Incl(lo), Excl(hi), false, false)
+	return a.max, ok
+}
+
+// SumBetween returns the sum of the data of all values between the bounds
+// lo and hi, or zero if there are none.
+func (t *NumericTree[Value, N]) SumBetween(lo, hi Bound[Value]) N {
+	a, _ := t.query(t.tree.Root, lo, hi, false, false)
+	return a.sum
+}
+
+// MinBetween returns the smallest data of all values between the bounds lo
+// and hi. ok is false if there are none.
+func (t *NumericTree[Value, N]) MinBetween(lo, hi Bound[Value]) (n N, ok bool) {
+	a, ok := t.query(t.tree.Root, lo, hi, false, false)
+	return a.min, ok
+}
+
+// MaxBetween returns the largest data of all values between the bounds lo
+// and hi. ok is false if there are none.
+func (t *NumericTree[Value, N]) MaxBetween(lo, hi Bound[Value]) (n N, ok bool) {
+	a, ok := t.query(t.tree.Root, lo, hi, false, false)
+	return a.max, ok
+}
+
+// query returns the aggregate of the values between lo and hi in the subtree
+// rooted at n. noLo and noHi tell that all values of the subtree are known
+// to respect lo or hi, respectively. Once the search paths for lo and hi
+// split, one of the two bounds is known on each side, so query visits
+// O(log n) nodes.
+func (t *NumericTree[Value, N]) query(n *Node[Value, N], lo, hi Bound[Value], noLo, noHi bool) (a aggregate[Value, N], ok bool) {
+	switch {
+	case n == nil:
+		return a, false
+	case noLo && noHi:
+		return *aggregateOf(n), true
+	case !noLo && !t.tree.afterLo(lo, n.Value):
+		return t.query(n.Right, lo, hi, noLo, noHi)
+	case !noHi && !t.tree.beforeHi(hi, n.Value):
+		return t.query(n.Left, lo, hi, noLo, noHi)
+	}
+	a, ok = t.query(n.Left, lo, hi, noLo, true)
+	a.add(aggregate[Value, N]{n.Data, n.Data, n.Data}, ok)
+	right, rok := t.query(n.Right, lo, hi, true, noHi)
+	if rok {
+		a.add(right, true)
+	}
+	return a, true
+}
@@ -0,0 +1,75 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestNumericTree(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	tree := NewNumericTree[int, int]()
+	model := map[int]int{}
+	for i := range 3000 {
+		v := r.IntN(200)
+		if r.IntN(3) == 0 {
+			tree.Delete(v)
+			delete(model, v)
+		} else {
+			n := r.IntN(1000) - 500
+			tree.Insert(v, n)
+			model[v] = n
+		}
+		if i%100 != 0 {
+			continue
+		}
+		if err := tree.tree.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		for range 20 {
+			lo, hi := r.IntN(220)-10, r.IntN(220)-10
+			var sum, lowest, highest int
+			found := false
+			for v, n := range model {
+				if v < lo || v >= hi {
+					continue
+				}
+				sum += n
+				if !found || n < lowest {
+					lowest = n
+				}
+				if !found || n > highest {
+					highest = n
+				}
+				found = true
+			}
+			if got := tree.SumRange(lo, hi); got != sum {
+				t.Fatalf("step %d: SumRange(%d, %d) = %d, want %d", i, lo, hi, got, sum)
+			}
+			if got, ok := tree.MinRange(lo, hi); ok != found || got != lowest {
+				t.Fatalf("step %d: MinRange(%d, %d) = %d, %t; want %d, %t", i, lo, hi, got, ok, lowest, found)
+			}
+			if got, ok := tree.MaxRange(lo, hi); ok != found || got != highest {
+				t.Fatalf("step %d: MaxRange(%d, %d) = %d, %t; want %d, %t", i, lo, hi, got, ok, highest, found)
+			}
+		}
+	}
+	if tree.Len() != len(model) {
+		t.Errorf("Len() = %d, want %d", tree.Len(), len(model))
+	}
+}
+
+func TestNumericTree_options(t *testing.T) {
+	tree := NewNumericTree[string, float64](WithDescending(), WithFreeList(NewFreeList[string, float64](4)))
+	for _, v := range []string{"a", "b", "c", "d"} {
+		tree.Insert(v, float64(v[0]-'a'))
+	}
+	tree.Delete("b")
+	tree.Insert("e", 10)
+	// In descending order, ["d", "a") holds d and c.
+	if got := tree.SumRange("d", "a"); got != 5 {
+		t.Errorf("SumRange(d, a) = %v, want 5", got)
+	}
+	if got, _ := tree.MaxRange("z", ""); got != 10 {
+		t.Errorf("MaxRange over all = %v, want 10", got)
+	}
+}
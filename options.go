@@ -0,0 +1,483 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Option configures a Tree created by New.
+type Option func(*options)
+
+// options collects the settings of all options before New turns them into
+// a typed config. Settings that depend on the tree's type parameters are
+// stored as any and type-checked by New.
+type options struct {
+	compare         any // func(a, b Value) int
+	descending      bool
+	allowDuplicates bool
+	allocator       any   // Allocator[Value, Data]
+	hooks           []any // Hooks[Value, Data]
+	nodeHooks       any   // *NodeHooks[Value, Data]
+	maxSize         int
+	eviction        EvictionPolicy
+	bloomSize       int // expected number of entries; 0 means no filter
+	bloomRate       float64
+	intern          bool
+	accessSample    int // 0 means no access counts
+	frontCache      int // size of the front cache; 0 means no cache
+	noBalance       bool
+	slack           int // 0 means AVL balance
+	heightFactor    float64
+	heightWarn      func(Stats)
+	maxEntries      int
+	maxHeight       int
+	tracer          Tracer
+	counters        bool
+	duplicates      DuplicatePolicy
+	onDuplicate     any // func(value Value, old, new Data) Data
+	formatValue     any // func(Value) string
+	formatData      any // func(Data) string
+	readStripes     int // for NewSyncTree
+}
+
+// config is the typed configuration of a Tree.
+type config[Value cmp.Ordered, Data any] struct {
+	compare         func(a, b Value) int // nil means cmp.Compare
+	allowDuplicates bool
+	alloc           Allocator[Value, Data] // nil means the Go heap
+	hooks           []Hooks[Value, Data]
+	nodeHooks       *NodeHooks[Value, Data]          // nil means no node hooks
+	augment         func() augmentation[Value, Data] // nil means no augmentation
+	maxSize         int                              // 0 means unbounded
+	eviction        EvictionPolicy
+	lru             *lru[Value, Data]        // access order for EvictLRU
+	access          *accessCounts[Value]     // nil means no access counts
+	front           *frontCache[Value, Data] // nil means no front cache
+	indexes         map[string]any           // *Index[Key, Value, Data] by name
+	filter          *bloom[Value]            // nil means no Bloom filter
+	interner        *interner[Value, Data]   // nil means no interning
+	noBalance       bool
+	slack           int         // see WithRelaxedBalance; 0 means 1
+	heightFactor    float64     // see WithHeightWarning
+	heightWarn      func(Stats) // nil means no height warning
+	heightWarned    bool        // the tree is too high, and heightWarn was called
+	maxEntries      int         // 0 means no limit
+	maxHeight       int         // 0 means no limit
+	tracing         *tracing    // nil means neither tracer nor counters
+	duplicates      DuplicatePolicy
+	onDuplicate     func(value Value, old, new Data) Data // set for DuplicateFunc
+	formatValue     func(Value) string                    // nil means fmt.Sprint
+	formatData      func(Data) string                     // nil means fmt.Sprint
+}
+
+// New returns an empty tree configured by opts.
+// New panics if an option does not match the tree's type parameters.
+//
+// A Tree created with new(Tree) or &Tree{} uses the default configuration:
+// values in ascending order of cmp.Compare, no duplicates, no free list,
+// and no hooks.
+func New[Value cmp.Ordered, Data any](opts ...Option) *Tree[Value, Data] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg := &config[Value, Data]{
+		allowDuplicates: o.allowDuplicates,
+		noBalance:       o.noBalance,
+		slack:           o.slack,
+		heightFactor:    o.heightFactor,
+		heightWarn:      o.heightWarn,
+		maxEntries:      o.maxEntries,
+		maxHeight:       o.maxHeight,
+		duplicates:      o.duplicates,
+	}
+	if o.compare != nil {
+		cfg.compare = typed[func(a, b Value) int](o.compare, "WithComparator")
+	}
+	if o.descending {
+		compare := cfg.compare
+		if compare == nil {
+			compare = cmp.Compare[Value]
+		}
+		cfg.compare = func(a, b Value) int { return compare(b, a) }
+	}
+	if o.allocator != nil {
+		cfg.alloc = typed[Allocator[Value, Data]](o.allocator, "WithAllocator")
+	}
+	for _, h := range o.hooks {
+		cfg.hooks = append(cfg.hooks, typed[Hooks[Value, Data]](h, "WithHooks"))
+	}
+	if o.duplicates != DuplicateReplace && o.allowDuplicates {
+		panic("generictree: WithOnDuplicate and WithDuplicateFunc cannot be combined with WithAllowDuplicates")
+	}
+	if o.duplicates == DuplicateFunc && o.onDuplicate == nil {
+		panic("generictree: WithOnDuplicate(DuplicateFunc): use WithDuplicateFunc")
+	}
+	if o.onDuplicate != nil {
+		cfg.onDuplicate = typed[func(value Value, old, new Data) Data](o.onDuplicate, "WithDuplicateFunc")
+	}
+	if o.formatValue != nil {
+		cfg.formatValue = typed[func(Value) string](o.formatValue, "WithKeyFormatter")
+	}
+	if o.formatData != nil {
+		cfg.formatData = typed[func(Data) string](o.formatData, "WithDataFormatter")
+	}
+	if o.maxEntries < 0 || o.maxHeight < 0 {
+		panic(fmt.Sprintf("generictree: negative limit: WithMaxEntries(%d), WithMaxHeight(%d)", o.maxEntries, o.maxHeight))
+	}
+	if o.nodeHooks != nil {
+		cfg.nodeHooks = typed[*NodeHooks[Value, Data]](o.nodeHooks, "WithNodeHooks")
+	}
+	if o.tracer != nil || o.counters {
+		cfg.tracing = &tracing{tracer: o.tracer}
+	}
+	if o.maxSize != 0 {
+		if o.maxSize < 0 {
+			panic(fmt.Sprintf("generictree: WithMaxSize: negative size %d", o.maxSize))
+		}
+		cfg.maxSize, cfg.eviction = o.maxSize, o.eviction
+		if o.eviction == EvictLRU {
+			cfg.lru = newLRU[Value, Data]()
+		}
+	}
+	if o.bloomSize != 0 {
+		if o.compare != nil {
+			panic("generictree: WithBloomFilter cannot be combined with WithComparator")
+		}
+		if o.bloomSize < 0 || !(o.bloomRate > 0 && o.bloomRate < 1) {
+			panic(fmt.Sprintf("generictree: WithBloomFilter(%d, %g): invalid arguments", o.bloomSize, o.bloomRate))
+		}
+		f := newBloom[Value](o.bloomSize, o.bloomRate)
+		cfg.filter = f
+		// Run first, so that other hooks can already find new entries.
+		cfg.hooks = append([]Hooks[Value, Data]{{
+			OnInsert: func(v Value, _ Data) { f.add(v) },
+			OnDelete: func(v Value, _ Data) { f.remove(v) },
+		}}, cfg.hooks...)
+	}
+	if o.accessSample != 0 {
+		a := newAccessCounts[Value](o.accessSample)
+		cfg.access = a
+		cfg.hooks = append(cfg.hooks, Hooks[Value, Data]{
+			OnDelete: func(v Value, _ Data) { delete(a.counts, v) },
+		})
+	}
+	if o.frontCache != 0 {
+		if o.allowDuplicates {
+			panic("generictree: WithFrontCache cannot be combined with WithAllowDuplicates")
+		}
+		if o.compare != nil {
+			panic("generictree: WithFrontCache cannot be combined with WithComparator")
+		}
+		c := newFrontCache[Value, Data](o.frontCache)
+		cfg.front = c
+		cfg.hooks = append(cfg.hooks, Hooks[Value, Data]{
+			OnDelete: func(v Value, _ Data) { c.remove(v) },
+		})
+	}
+	if o.intern {
+		in := newInterner[Value, Data]()
+		if in == nil {
+			panic("generictree: WithInterning: neither Value nor Data is a string type")
+		}
+		cfg.interner = in
+		cfg.hooks = append([]Hooks[Value, Data]{in.hooks()}, cfg.hooks...)
+	}
+	t := &Tree[Value, Data]{cfg: cfg}
+	t.debug.setup(&o)
+	return t
+}
+
+func typed[T any](v any, option string) T {
+	t, ok := v.(T)
+	if !ok {
+		panic(fmt.Sprintf("generictree: %s: got %T, want %T", option, v, t))
+	}
+	return t
+}
+
+// WithComparator orders the tree by compare instead of cmp.Compare.
+// compare must return a negative number if a < b, zero if a == b, and a
+// positive number if a > b, and it must define a strict weak ordering.
+func WithComparator[Value cmp.Ordered](compare func(a, b Value) int) Option {
+	return func(o *options) { o.compare = compare }
+}
+
+// WithDescending reverses the order of the tree. Min then returns the
+// largest value, Max the smallest one, and iteration and Range run from
+// larger to smaller values.
+func WithDescending() Option {
+	return func(o *options) { o.descending = true }
+}
+
+// WithAllowDuplicates lets the tree hold several entries with equal values.
+// Insert then always adds a new entry; entries with equal values are kept
+// in insertion order. Find returns the data of one of the entries with
+// the given value, and Delete removes one of them.
+func WithAllowDuplicates() Option {
+	return func(o *options) { o.allowDuplicates = true }
+}
+
+// DuplicatePolicy selects what Insert does when the value it inserts is
+// already in the tree (see WithOnDuplicate).
+type DuplicatePolicy int
+
+const (
+	// DuplicateReplace replaces the data of the existing entry. This is
+	// the default.
+	DuplicateReplace DuplicatePolicy = iota
+	// DuplicateKeep keeps the data of the existing entry and discards the
+	// new data.
+	DuplicateKeep
+	// DuplicateError keeps the data of the existing entry like
+	// DuplicateKeep, and InsertStrict returns an error wrapping
+	// ErrDuplicateKey.
+	DuplicateError
+	// DuplicateFunc stores the data that the function passed to
+	// WithDuplicateFunc returns. Set it with WithDuplicateFunc.
+	DuplicateFunc
+)
+
+// WithOnDuplicate sets what Insert does when the value it inserts is
+// already in the tree. The policy applies to Insert and the operations
+// built on it, such as Set and InsertEntry; Entry.SetData always replaces
+// the data. Trees that allow duplicates (see WithAllowDuplicates) add
+// another entry instead, so New panics if both options are given.
+func WithOnDuplicate(policy DuplicatePolicy) Option {
+	return func(o *options) { o.duplicates = policy }
+}
+
+// WithDuplicateFunc makes Insert store the data that f returns when the
+// value it inserts is already in the tree. f receives the value, the
+// existing data, and the new data; it must not modify the tree. See
+// WithOnDuplicate.
+func WithDuplicateFunc[Value cmp.Ordered, Data any](f func(value Value, old, new Data) Data) Option {
+	return func(o *options) { o.duplicates, o.onDuplicate = DuplicateFunc, f }
+}
+
+// WithKeyFormatter sets how the tree renders its values in Dump,
+// PrettyPrint, WriteDOT, String, and in tools such as package treehttp
+// (see FormatValue). By default, values are formatted with fmt.Sprint,
+// which renders binary or structured values poorly.
+func WithKeyFormatter[Value cmp.Ordered](f func(Value) string) Option {
+	return func(o *options) { o.formatValue = f }
+}
+
+// WithDataFormatter sets how the tree renders its data in tools such as
+// package treehttp (see FormatData). By default, data is formatted with
+// fmt.Sprint.
+func WithDataFormatter[Data any](f func(Data) string) Option {
+	return func(o *options) { o.formatData = f }
+}
+
+// WithAllocator makes the tree take its nodes from a and return the nodes
+// of deleted entries to it.
+func WithAllocator[Value cmp.Ordered, Data any](a Allocator[Value, Data]) Option {
+	return func(o *options) { o.allocator = a }
+}
+
+// WithFreeList makes the tree recycle deleted nodes through f. It is
+// short for WithAllocator(f).
+func WithFreeList[Value cmp.Ordered, Data any](f *FreeList[Value, Data]) Option {
+	return func(o *options) { o.allocator = f }
+}
+
+// WithReadStripes makes a SyncTree created by NewSyncTree split its lock
+// into n read-write mutexes. A reader locks one of them at random, and a
+// writer locks all of them. With many cores, this spreads readers over n
+// cache lines instead of one, at the cost of writes that take n locks.
+// New ignores this option; n of 1 or less keeps the single mutex.
+func WithReadStripes(n int) Option {
+	return func(o *options) { o.readStripes = n }
+}
+
+// WithHooks registers callbacks that the tree calls after each change.
+// WithHooks can be used more than once; the hooks are called in the order
+// they were registered.
+func WithHooks[Value cmp.Ordered, Data any](h Hooks[Value, Data]) Option {
+	return func(o *options) { o.hooks = append(o.hooks, h) }
+}
+
+// WithNoBalance turns the tree into a plain binary search tree that never
+// rotates. Insertions and deletions then skip the rebalancing work, which
+// pays off only if the values arrive in random order: sorted input makes
+// the tree degenerate into a list with O(n) searches. Use
+// WithHeightWarning to detect this. Bulk operations such as Concat and
+// UnmarshalJSON still produce balanced trees.
+func WithNoBalance() Option {
+	return func(o *options) { o.noBalance = true }
+}
+
+// WithRelaxedBalance lets the heights of the two subtrees of a node differ
+// by up to k before the tree rotates, instead of by one as in an AVL tree.
+// A larger k saves rotations on write-heavy workloads, at the price of a
+// higher tree and longer searches: the height stays below about
+// 1.44·log2(n) for k = 1, 1.81·log2(n) for k = 2, and 2.15·log2(n) for
+// k = 3. Compare Stats.Height with Stats.AVLMaxHeight to tune k.
+// WithRelaxedBalance(1) is the default. WithRelaxedBalance panics if k is
+// less than 1.
+func WithRelaxedBalance(k int) Option {
+	if k < 1 {
+		panic(fmt.Sprintf("generictree: WithRelaxedBalance: k %d is less than 1", k))
+	}
+	return func(o *options) { o.slack = k }
+}
+
+// WithHeightWarning makes the tree call f with its statistics when an
+// insertion makes it higher than factor times log2(n+1), the optimal
+// height for n entries. f is called again only after the tree has
+// become low enough in between. The check takes O(1) time per insertion
+// and deletion; f is passed the result of Stats, which takes O(n).
+// Balanced trees stay below 1.45 times the optimal height.
+func WithHeightWarning(factor float64, f func(Stats)) Option {
+	return func(o *options) { o.heightFactor, o.heightWarn = factor, f }
+}
+
+// WithTracer makes the tree report its insertions, deletions, and
+// lookups to tracer, with the number of comparisons and rotations and the
+// time each one took. Tracer can, for example, record spans or log slow
+// operations. Tracing costs two clock readings per operation.
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) { o.tracer = tracer }
+}
+
+// WithCounters makes the tree count its insertions, deletions, lookups,
+// comparisons, and rotations (see StatsSnapshot and ResetStats). Counting
+// adds no per-node cost and no clock readings.
+func WithCounters() Option {
+	return func(o *options) { o.counters = true }
+}
+
+// WithNodeHooks registers callbacks for the nodes of the tree, so that
+// code can keep its own per-node metadata in step with the tree's
+// structure. A later WithNodeHooks replaces an earlier one.
+func WithNodeHooks[Value cmp.Ordered, Data any](h NodeHooks[Value, Data]) Option {
+	return func(o *options) { o.nodeHooks = &h }
+}
+
+// WithMaxSize limits the tree to n entries. If an insertion adds an entry to
+// a full tree, the tree evicts an entry according to policy, which may be
+// the new entry itself. Evictions call the OnDelete hooks.
+//
+// With EvictLRU, Find and Get record the access and hence modify the tree;
+// concurrent calls need exclusive locking.
+func WithMaxSize(n int, policy EvictionPolicy) Option {
+	return func(o *options) { o.maxSize, o.eviction = n, policy }
+}
+
+// WithMaxEntries limits the tree to n entries. Unlike WithMaxSize, which
+// evicts entries to make room, WithMaxEntries rejects new entries: Insert
+// and Set leave a full tree unchanged, and InsertStrict returns a
+// *LimitError. Replacing the data of an existing entry is always possible.
+//
+// The limit applies to Insert and to the operations built on it, such as
+// Set and Apply. Bulk operations such as Concat, UnmarshalJSON, and the
+// sorted fast path of ImportLines do not check it.
+func WithMaxEntries(n int) Option {
+	return func(o *options) { o.maxEntries = n }
+}
+
+// WithMaxHeight limits the height of the tree to h. Insertions that would
+// make the tree higher are rejected like those beyond WithMaxEntries. A
+// balanced tree reaches height h with no fewer than about 1.6^h entries
+// (fewer with WithRelaxedBalance), so the limit matters most for trees
+// created with WithNoBalance. The
+// check predicts the height before the insertion in O(log n) time.
+func WithMaxHeight(h int) Option {
+	return func(o *options) { o.maxHeight = h }
+}
+
+// WithBloomFilter keeps a counting Bloom filter of the tree's values, so
+// that Find, Get, and Contains can answer most queries for absent values
+// without searching the tree. The filter is sized for the expected number
+// of entries and the given rate of false positives, between 0 and 1; with
+// more entries, the rate of false positives rises. For a rate of 1%, the
+// filter takes about 10 bytes per expected entry.
+//
+// The filter hashes values, so it relies on the default order, in which
+// only identical values are equal; New panics if it is combined with
+// WithComparator.
+func WithBloomFilter(expected int, falsePositiveRate float64) Option {
+	return func(o *options) { o.bloomSize, o.bloomRate = expected, falsePositiveRate }
+}
+
+// Hooks are callbacks that a tree calls after an entry was inserted,
+// updated, or deleted. Any of the callbacks may be nil. The callbacks
+// must not modify the tree.
+type Hooks[Value cmp.Ordered, Data any] struct {
+	// OnInsert is called after a new entry was added.
+	OnInsert func(value Value, data Data)
+	// OnUpdate is called after the data of an existing entry was replaced.
+	OnUpdate func(value Value, old, new Data)
+	// OnDelete is called after an entry was removed.
+	OnDelete func(value Value, data Data)
+}
+
+// WithInterning makes equal strings among the tree's values and data share
+// their memory, which saves space in trees that hold many copies of the
+// same strings, such as log fields or tags. Interning applies to Value and
+// Data if they are string types; New panics if neither is. Stats reports
+// the number of distinct strings and the bytes saved.
+//
+// The tree keeps one reference count per distinct string, which costs
+// memory of its own; interning pays off only when strings repeat.
+func WithInterning() Option {
+	return func(o *options) { o.intern = true }
+}
+
+// NodeHooks are callbacks that a tree calls when a node joins the tree,
+// leaves it, or moves in a rotation. Unlike Hooks, they receive the nodes
+// themselves, which lets code attach metadata to a node, for example in a
+// map keyed by the node pointer, and learn which nodes a rebalance moved,
+// for example to mark cached layout for rendering as dirty. Any of the
+// callbacks may be nil. The callbacks must not modify the tree or its
+// nodes.
+type NodeHooks[Value cmp.Ordered, Data any] struct {
+	// OnAttach is called when n becomes part of the tree: when a new
+	// entry gets a node, before the tree rebalances, or after nodes were
+	// added in bulk, for example by UnmarshalJSON or Concat.
+	OnAttach func(n *Node[Value, Data])
+	// OnDetach is called after n was removed from the tree. A free list
+	// may reuse n for another entry later.
+	OnDetach func(n *Node[Value, Data])
+	// OnRotate is called after a rotation gave n new children, for both
+	// nodes of the rotation, the lower one first. Insertions and
+	// deletions also change the children of nodes on their path without
+	// a rotation; those changes are not reported.
+	OnRotate func(n *Node[Value, Data])
+}
+
+// FreeList keeps the nodes of deleted entries for reuse by later
+// insertions, which reduces allocations in trees with many insertions and
+// deletions. It is an Allocator that takes new nodes from the Go heap. A
+// FreeList can be shared by several trees of the same type, but it is not
+// safe for concurrent use.
+type FreeList[Value cmp.Ordered, Data any] struct {
+	nodes []*Node[Value, Data]
+}
+
+// NewFreeList returns a free list that holds up to size nodes.
+func NewFreeList[Value cmp.Ordered, Data any](size int) *FreeList[Value, Data] {
+	return &FreeList[Value, Data]{nodes: make([]*Node[Value, Data], 0, size)}
+}
+
+// Alloc returns a node from the free list, or a new one if the list is
+// empty.
+func (f *FreeList[Value, Data]) Alloc() *Node[Value, Data] {
+	if len(f.nodes) == 0 {
+		return new(Node[Value, Data])
+	}
+	n := f.nodes[len(f.nodes)-1]
+	f.nodes[len(f.nodes)-1] = nil
+	f.nodes = f.nodes[:len(f.nodes)-1]
+	return n
+}
+
+// Free clears n and keeps it for reuse, unless the free list is full.
+func (f *FreeList[Value, Data]) Free(n *Node[Value, Data]) {
+	if len(f.nodes) == cap(f.nodes) {
+		return
+	}
+	*n = Node[Value, Data]{}
+	f.nodes = append(f.nodes, n)
+}
@@ -0,0 +1,328 @@
+package generictree
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// values returns the values of the tree in tree order.
+func (t *Tree[Value, Data]) values() []Value {
+	var values []Value
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		values = append(values, n.Value)
+		return true
+	})
+	return values
+}
+
+func TestNew(t *testing.T) {
+	tree := New[int, string]()
+	for _, v := range []int{3, 1, 2} {
+		tree.Insert(v, fmt.Sprint(v))
+	}
+	if got := tree.values(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("default order: got %v", got)
+	}
+}
+
+func TestWithComparator(t *testing.T) {
+	// Order by length first, then alphabetically.
+	byLength := func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	}
+	tree := New[string, int](WithComparator(byLength))
+	for i, v := range []string{"ccc", "a", "bb", "aa", "b"} {
+		tree.Insert(v, i)
+	}
+	if got, want := tree.values(), []string{"a", "b", "aa", "bb", "ccc"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if d, ok := tree.Find("aa"); !ok || d != 3 {
+		t.Errorf("Find(aa) = %d, %t; want 3, true", d, ok)
+	}
+	var ranged []string
+	tree.Range("b", "bb", func(v string, _ int) bool {
+		ranged = append(ranged, v)
+		return true
+	})
+	if want := []string{"b", "aa"}; !slices.Equal(ranged, want) {
+		t.Errorf("Range(b, bb) = %v, want %v", ranged, want)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithDescending(t *testing.T) {
+	tree := New[int, int](WithDescending())
+	for i := 0; i < 20; i++ {
+		tree.Insert(i, i)
+	}
+	want := make([]int, 20)
+	for i := range want {
+		want[i] = 19 - i
+	}
+	if got := tree.values(); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if v, _, _ := tree.Min(); v != 19 {
+		t.Errorf("Min() = %d, want 19", v)
+	}
+	if v, _, _ := tree.Max(); v != 0 {
+		t.Errorf("Max() = %d, want 0", v)
+	}
+	var ranged []int
+	tree.Range(10, 7, func(v, _ int) bool {
+		ranged = append(ranged, v)
+		return true
+	})
+	if want := []int{10, 9, 8}; !slices.Equal(ranged, want) {
+		t.Errorf("Range(10, 7) = %v, want %v", ranged, want)
+	}
+	if _, ok := tree.Delete(5); !ok {
+		t.Error("Delete(5) failed")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithAllowDuplicates(t *testing.T) {
+	tree := New[int, string](WithAllowDuplicates())
+	for i, v := range []int{2, 1, 2, 3, 2, 1} {
+		tree.Insert(v, fmt.Sprint(v, "#", i))
+	}
+	if tree.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", tree.Len())
+	}
+	var got []string
+	tree.Traverse(tree.Root, func(n *Node[int, string]) { got = append(got, n.Data) })
+	// Equal values keep their insertion order.
+	if want := []string{"1#1", "1#5", "2#0", "2#2", "2#4", "3#3"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+	for i := 3; i > 0; i-- {
+		if _, ok := tree.Delete(2); !ok {
+			t.Fatalf("Delete(2) failed with %d entries left", i)
+		}
+	}
+	if _, ok := tree.Find(2); ok {
+		t.Error("Find(2) succeeded after deleting all three entries")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithFreeList(t *testing.T) {
+	fl := NewFreeList[int, int](2)
+	tree := New[int, int](WithFreeList(fl))
+	for i := 0; i < 5; i++ {
+		tree.Insert(i, i)
+	}
+	n := tree.findNode(3)
+	tree.Delete(3)
+	if len(fl.nodes) != 1 || fl.nodes[0] != n {
+		t.Fatal("the deleted node did not go to the free list")
+	}
+	tree.Delete(1)
+	tree.Delete(0) // exceeds the capacity of the free list
+	if len(fl.nodes) != 2 {
+		t.Errorf("free list holds %d nodes, want 2", len(fl.nodes))
+	}
+	tree.Insert(10, 10)
+	tree.Insert(11, 11)
+	if tree.findNode(11) != n {
+		t.Error("Insert did not reuse the node from the free list")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithHooks(t *testing.T) {
+	var events []string
+	tree := New[string, int](
+		WithHooks(Hooks[string, int]{
+			OnInsert: func(v string, d int) { events = append(events, fmt.Sprintf("insert %s %d", v, d)) },
+			OnUpdate: func(v string, old, new int) { events = append(events, fmt.Sprintf("update %s %d %d", v, old, new)) },
+			OnDelete: func(v string, d int) { events = append(events, fmt.Sprintf("delete %s %d", v, d)) },
+		}),
+		WithHooks(Hooks[string, int]{
+			OnDelete: func(v string, d int) { events = append(events, "second hook") },
+		}),
+	)
+	tree.Insert("a", 1)
+	tree.Insert("a", 2)
+	tree.Delete("a")
+	tree.Delete("b")
+	want := []string{"insert a 1", "update a 1 2", "delete a 2", "second hook"}
+	if !slices.Equal(events, want) {
+		t.Errorf("got events %q, want %q", events, want)
+	}
+}
+
+func TestNew_typeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New did not panic on a comparator of the wrong type")
+		}
+	}()
+	New[string, int](WithComparator(func(a, b int) int { return a - b }))
+}
+
+func TestWithNoBalance(t *testing.T) {
+	var warnings []Stats
+	tree := New[int, int](WithNoBalance(), WithHeightWarning(2, func(s Stats) {
+		warnings = append(warnings, s)
+	}))
+	for i := range 20 {
+		tree.Insert(i, i)
+	}
+	// Sorted input degenerates the tree into a list.
+	if h := tree.Root.Height(); h != 20 {
+		t.Errorf("height = %d, want 20", h)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+	if len(warnings) != 1 || warnings[0].Len != 6 {
+		t.Fatalf("warnings = %+v, want one at 6 entries", warnings)
+	}
+	for i := 19; i >= 3; i-- {
+		if _, ok := tree.Delete(i); !ok {
+			t.Fatalf("Delete(%d) failed", i)
+		}
+	}
+	if got := tree.values(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("values = %v", got)
+	}
+	// The warning rearms once the tree is low enough.
+	for i := 3; i < 20; i++ {
+		tree.Insert(i, i)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("%d warnings, want 2", len(warnings))
+	}
+
+	balanced := New[int, int](WithHeightWarning(1.5, func(Stats) { t.Error("balanced tree triggered the warning") }))
+	for i := range 1000 {
+		balanced.Insert(i, i)
+	}
+}
+
+func TestWithRelaxedBalance(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 8))
+	rotations := map[int]int{}
+	for _, k := range []int{1, 2, 3} {
+		tree := New[int, int](WithRelaxedBalance(k), WithCounters())
+		for i := range 5000 {
+			if v := rng.IntN(1000); i%3 == 2 {
+				tree.Delete(v)
+			} else {
+				tree.Insert(v, v)
+			}
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("k = %d: %v", k, err)
+		}
+		rotations[k] = tree.StatsSnapshot().Rotations
+		if s := tree.Stats(); k == 1 && s.Height > s.AVLMaxHeight {
+			t.Errorf("k = 1: height %d exceeds the AVL bound %d", s.Height, s.AVLMaxHeight)
+		}
+
+		// Joins and splits keep the relaxed balance.
+		want := tree.values()
+		mid := tree.Subtree(300, 700)
+		if err := mid.Validate(); err != nil {
+			t.Fatalf("k = %d: Subtree: %v", k, err)
+		}
+		rest := tree.Subtree(700, 1000)
+		if err := tree.Concat(mid); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Concat(rest); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("k = %d: Concat: %v", k, err)
+		}
+		if got := tree.values(); !slices.Equal(got, want) {
+			t.Fatalf("k = %d: values changed by Subtree and Concat", k)
+		}
+		if err := tree.RebuildSubtree(tree.Root.Left.Value); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("k = %d: RebuildSubtree: %v", k, err)
+		}
+	}
+	if !(rotations[3] < rotations[2] && rotations[2] < rotations[1]) {
+		t.Errorf("rotations for k = 1, 2, 3: %d, %d, %d; want fewer for larger k", rotations[1], rotations[2], rotations[3])
+	}
+
+	// With sorted input, the tree grows higher for larger k.
+	for k, want := range map[int]int{1: 10, 2: 11, 3: 12} {
+		tree := New[int, int](WithRelaxedBalance(k))
+		for i := range 1000 {
+			tree.Insert(i, i)
+		}
+		if s := tree.Stats(); s.Height != want {
+			t.Errorf("k = %d: height %d, want %d", k, s.Height, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithRelaxedBalance(0) did not panic")
+		}
+	}()
+	WithRelaxedBalance(0)
+}
+
+func TestWithOnDuplicate(t *testing.T) {
+	var events []string
+	hooks := WithHooks(Hooks[string, int]{
+		OnUpdate: func(v string, old, new int) { events = append(events, fmt.Sprintf("update %s %d %d", v, old, new)) },
+	})
+	for _, c := range []struct {
+		name   string
+		opt    Option
+		want   int
+		events int
+		err    error
+	}{
+		{"replace", WithOnDuplicate(DuplicateReplace), 2, 1, nil},
+		{"keep", WithOnDuplicate(DuplicateKeep), 1, 0, nil},
+		{"error", WithOnDuplicate(DuplicateError), 1, 0, ErrDuplicateKey},
+		{"func", WithDuplicateFunc(func(_ string, old, new int) int { return old + new }), 3, 1, nil},
+	} {
+		events = nil
+		tree := New[string, int](c.opt, hooks)
+		tree.Insert("a", 1)
+		tree.Insert("a", 2)
+		if d, _ := tree.Find("a"); d != c.want || len(events) != c.events || tree.Len() != 1 {
+			t.Errorf("%s: Find(a) = %d with events %v", c.name, d, events)
+		}
+		if err := tree.InsertStrict("a", 0); !errors.Is(err, c.err) || (c.err == nil) != (err == nil) {
+			t.Errorf("%s: InsertStrict = %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New did not panic on WithOnDuplicate combined with WithAllowDuplicates")
+		}
+	}()
+	New[string, int](WithOnDuplicate(DuplicateKeep), WithAllowDuplicates())
+}
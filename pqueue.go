@@ -0,0 +1,133 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// PriorityQueue is a priority queue built on a tree. Each item has an ID
+// and a priority; Pop removes the item that comes first in the order of
+// the priorities, by default the one with the smallest priority. Items
+// with equal priorities come out in the order in which they got their
+// priority. A map from IDs to items serves as a secondary index, so that
+// UpdatePriority and Remove find an item by its ID. All operations take
+// O(log n) time.
+//
+// The zero value is not usable; create queues with NewPriorityQueue.
+type PriorityQueue[ID comparable, P cmp.Ordered] struct {
+	tree  *Tree[P, pqItem[ID, P]]
+	items map[ID]pqItem[ID, P]
+	seq   uint64 // increases with every Push and UpdatePriority
+}
+
+// pqItem is the data stored for each priority. seq orders items with
+// equal priorities, like player does for a Leaderboard.
+type pqItem[ID comparable, P cmp.Ordered] struct {
+	id       ID
+	priority P
+	seq      uint64
+}
+
+// NewPriorityQueue returns an empty priority queue. opts can set the order
+// of the priorities, for example WithDescending for a queue that pops the
+// largest priority first. The queue adds WithAllowDuplicates itself.
+// NewPriorityQueue panics if opts include WithMaxSize, WithMaxEntries, or
+// WithMaxHeight, because a rejected or evicted item would stay in the ID
+// index.
+func NewPriorityQueue[ID comparable, P cmp.Ordered](opts ...Option) *PriorityQueue[ID, P] {
+	t := New[P, pqItem[ID, P]](append(opts, WithAllowDuplicates())...)
+	if t.cfg.maxSize > 0 || t.cfg.maxEntries > 0 || t.cfg.maxHeight > 0 {
+		panic(fmt.Sprintf("generictree: NewPriorityQueue: limits are not supported: WithMaxSize(%d), WithMaxEntries(%d), WithMaxHeight(%d)",
+			t.cfg.maxSize, t.cfg.maxEntries, t.cfg.maxHeight))
+	}
+	return &PriorityQueue[ID, P]{
+		tree:  t,
+		items: map[ID]pqItem[ID, P]{},
+	}
+}
+
+// Len returns the number of items in the queue.
+func (q *PriorityQueue[ID, P]) Len() int {
+	return len(q.items)
+}
+
+// Push adds id with the given priority. If id is already in the queue,
+// Push changes its priority like UpdatePriority.
+func (q *PriorityQueue[ID, P]) Push(id ID, priority P) {
+	if _, ok := q.items[id]; ok {
+		q.UpdatePriority(id, priority)
+		return
+	}
+	q.add(id, priority)
+}
+
+func (q *PriorityQueue[ID, P]) add(id ID, priority P) {
+	q.seq++
+	it := pqItem[ID, P]{id: id, priority: priority, seq: q.seq}
+	q.items[id] = it
+	q.tree.Insert(priority, it)
+}
+
+// Peek returns the item that Pop would remove, without removing it. ok is
+// false if the queue is empty.
+func (q *PriorityQueue[ID, P]) Peek() (id ID, priority P, ok bool) {
+	_, it, ok := q.tree.PeekMin()
+	return it.id, it.priority, ok
+}
+
+// Pop removes the first item in the order of the priorities and returns
+// it. ok is false if the queue is empty.
+func (q *PriorityQueue[ID, P]) Pop() (id ID, priority P, ok bool) {
+	_, it, ok := q.tree.DeleteMin()
+	if ok {
+		delete(q.items, it.id)
+	}
+	return it.id, it.priority, ok
+}
+
+// Priority returns the priority of id. ok is false if id is not in the
+// queue.
+func (q *PriorityQueue[ID, P]) Priority(id ID) (priority P, ok bool) {
+	it, ok := q.items[id]
+	return it.priority, ok
+}
+
+// UpdatePriority moves id to a new priority, as if it had been pushed
+// with that priority now. It reports whether id is in the queue. An item
+// whose priority does not change keeps its place.
+func (q *PriorityQueue[ID, P]) UpdatePriority(id ID, priority P) bool {
+	it, ok := q.items[id]
+	if !ok {
+		return false
+	}
+	if q.tree.compare(it.priority, priority) == 0 {
+		return true
+	}
+	// Like ReKey, but the ID index leads to the exact node, also among
+	// items with equal priorities.
+	q.remove(it)
+	q.add(id, priority)
+	return true
+}
+
+// Remove removes id from the queue. It reports whether id was in it.
+func (q *PriorityQueue[ID, P]) Remove(id ID) bool {
+	it, ok := q.items[id]
+	if ok {
+		q.remove(it)
+		delete(q.items, id)
+	}
+	return ok
+}
+
+func (q *PriorityQueue[ID, P]) remove(it pqItem[ID, P]) {
+	var removed *Node[P, pqItem[ID, P]]
+	q.tree.Root, removed = q.tree.Root.deleteFunc(func(n *Node[P, pqItem[ID, P]]) int {
+		if c := q.tree.compare(it.priority, n.Value); c != 0 {
+			return c
+		}
+		return cmp.Compare(it.seq, n.Data.seq)
+	}, q.tree.balancing())
+	q.tree.debug.record(q.tree, "Delete", it.priority)
+	q.tree.afterDelete(removed)
+}
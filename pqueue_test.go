@@ -0,0 +1,83 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue[string, int]()
+	for _, it := range []struct {
+		id       string
+		priority int
+	}{{"a", 3}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 5}} {
+		q.Push(it.id, it.priority)
+	}
+	if id, p, ok := q.Peek(); !ok || id != "b" || p != 1 {
+		t.Errorf("Peek() = %q, %d, %v", id, p, ok)
+	}
+	if !q.UpdatePriority("e", 0) || !q.UpdatePriority("b", 2) || q.UpdatePriority("x", 1) {
+		t.Error("UpdatePriority reported the wrong items")
+	}
+	q.Push("a", 1) // updates a
+	if p, ok := q.Priority("a"); !ok || p != 1 || q.Len() != 5 {
+		t.Errorf("Priority(a) = %d, %v with %d items", p, ok, q.Len())
+	}
+	if !q.Remove("c") || q.Remove("c") {
+		t.Error("Remove reported the wrong items")
+	}
+	var got []string
+	for {
+		id, _, ok := q.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, id)
+		if err := q.tree.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Equal priorities come out in the order in which they were set.
+	want := []string{"e", "d", "a", "b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %d after popping all items", q.Len())
+	}
+
+	max := NewPriorityQueue[int, float64](WithDescending())
+	max.Push(1, 0.5)
+	max.Push(2, 1.5)
+	if id, _, _ := max.Pop(); id != 2 {
+		t.Errorf("descending queue popped %d, want 2", id)
+	}
+}
+
+func TestPriorityQueue_noBalance(t *testing.T) {
+	q := NewPriorityQueue[int, int](WithNoBalance())
+	for i := range 10 {
+		q.Push(i, i)
+	}
+	q.Remove(5)
+	q.UpdatePriority(6, 20)
+	if h := q.tree.Root.Height(); h != q.Len() {
+		t.Errorf("height %d with %d items: the queue rotated", h, q.Len())
+	}
+	if id, _, _ := q.Pop(); id != 0 {
+		t.Errorf("Pop() = %d, want 0", id)
+	}
+}
+
+func TestNewPriorityQueue_limits(t *testing.T) {
+	for _, opt := range []Option{WithMaxSize(10, EvictMin), WithMaxEntries(10), WithMaxHeight(10)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewPriorityQueue with a limit did not panic")
+				}
+			}()
+			NewPriorityQueue[string, int](opt)
+		}()
+	}
+}
@@ -0,0 +1,156 @@
+package generictree
+
+import "fmt"
+
+// The size of each subtree turns the tree into an order-statistic tree:
+// Rank and Select find the position of a value and the entry at a position
+// in O(log n) time.
+
+// Rank returns the number of entries whose values come before value in the
+// tree's order. If value is in the tree, Rank is the index of its first
+// entry; otherwise, it is the index at which value would be inserted.
+func (t *Tree[Value, Data]) Rank(value Value) int {
+	rank := 0
+	for n := t.Root; n != nil; {
+		if t.compare(value, n.Value) <= 0 {
+			n = n.Left
+		} else {
+			rank += n.Left.count() + 1
+			n = n.Right
+		}
+	}
+	return rank
+}
+
+// Select returns the entry at index i in the tree's order, counting from
+// zero. ok is false if i is out of range.
+func (t *Tree[Value, Data]) Select(i int) (value Value, data Data, ok bool) {
+	c := t.Cursor()
+	if !c.SeekIndex(i) {
+		return value, data, false
+	}
+	return c.Value(), c.Data(), true
+}
+
+// SeekIndex moves the cursor to the entry at index i in the tree's order,
+// counting from zero. It reports whether there is such an entry.
+func (c *Cursor[Value, Data]) SeekIndex(i int) bool {
+	c.reset()
+	if i < 0 || i >= c.tree.Len() {
+		return false
+	}
+	for n := c.tree.Root; n != nil; {
+		c.stack = append(c.stack, n)
+		left := n.Left.count()
+		switch {
+		case i < left:
+			n = n.Left
+		case i == left:
+			return true
+		default:
+			i -= left + 1
+			n = n.Right
+		}
+	}
+	return false // not reached
+}
+
+// Distance returns the number of entries whose values lie strictly
+// between a and b, in O(log n) time. The order of a and b does not matter,
+// and they need not be in the tree. Distance is zero if a and b are equal
+// or adjacent.
+func (t *Tree[Value, Data]) Distance(a, b Value) int {
+	if t.compare(a, b) > 0 {
+		a, b = b, a
+	}
+	return t.CountBetween(Excl(a), Excl(b))
+}
+
+// SizeAt returns the number of entries in the subtree rooted at the node
+// with value, in O(log n) time. ok is false if value is not in the tree.
+// The sizes show how the entries are distributed over the tree, which the
+// heights do not.
+func (t *Tree[Value, Data]) SizeAt(value Value) (size int, ok bool) {
+	n := t.findNode(value)
+	return n.count(), n != nil
+}
+
+// SearchInfo describes how a search found an entry (see FindInfo).
+type SearchInfo struct {
+	Depth       int // depth of the entry; the root has depth 1
+	Rank        int // index of the entry in the tree's order
+	Comparisons int // number of value comparisons
+}
+
+// FindInfo is like Find but also reports where the entry is and what it
+// took to find it. If value is not in the tree, ok is false, Depth is the
+// length of the search path, and Rank is the index at which value would be
+// inserted. FindInfo always searches the tree, also with WithBloomFilter.
+func (t *Tree[Value, Data]) FindInfo(value Value) (data Data, info SearchInfo, ok bool) {
+	for n := t.Root; n != nil; {
+		info.Depth++
+		info.Comparisons++
+		c := t.compare(value, n.Value)
+		switch {
+		case c == 0:
+			info.Rank += n.Left.count()
+			t.touch(n)
+			return n.Data, info, true
+		case c < 0:
+			n = n.Left
+		default:
+			info.Rank += n.Left.count() + 1
+			n = n.Right
+		}
+	}
+	return data, info, false
+}
+
+// Boundaries returns up to n-1 values that split the tree into n ranges of
+// about equal size, for example to assign the ranges to n shards. The
+// values are in the tree's order, and range i holds the entries from
+// boundary i-1 up to, but not including, boundary i; the first range
+// starts at the beginning of the tree, and the last range ends at its end.
+// RangeSizes counts the entries of the ranges.
+//
+// Boundaries selects the entries at the indexes i·Len/n in O(n log n)
+// time. The values are distinct, so Boundaries returns fewer of them if
+// the tree has fewer than n entries or if duplicates span an index.
+// Boundaries panics if n is less than 1.
+func (t *Tree[Value, Data]) Boundaries(n int) []Value {
+	if n < 1 {
+		panic(fmt.Sprintf("generictree: Boundaries: n %d is less than 1", n))
+	}
+	var bounds []Value
+	for i := 1; i < n; i++ {
+		j := i * t.Len() / n
+		if j == 0 {
+			continue // the first range would be empty
+		}
+		v, _, _ := t.Select(j)
+		if len(bounds) == 0 || t.compare(bounds[len(bounds)-1], v) < 0 {
+			bounds = append(bounds, v)
+		}
+	}
+	return bounds
+}
+
+// RangeSizes returns the number of entries in each of the len(bounds)+1
+// ranges that bounds split the tree into, as described for Boundaries, in
+// O(k log n) time for k bounds. The bounds need not be in the tree, which
+// lets RangeSizes check how evenly the boundaries of shards still split
+// the tree after it has changed. RangeSizes panics if bounds are not in
+// the tree's order.
+func (t *Tree[Value, Data]) RangeSizes(bounds []Value) []int {
+	sizes := make([]int, len(bounds)+1)
+	prev := 0
+	for i, b := range bounds {
+		if i > 0 && t.compare(bounds[i-1], b) > 0 {
+			panic(fmt.Sprintf("generictree: RangeSizes: bound %v comes after %v", bounds[i-1], b))
+		}
+		rank := t.Rank(b)
+		sizes[i], prev = rank-prev, rank
+	}
+	sizes[len(bounds)] = t.Len() - prev
+	return sizes
+}
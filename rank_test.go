@@ -0,0 +1,168 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTree_RankSelect(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := 0; v < 100; v += 2 {
+		tree.Insert(v, "")
+	}
+	for i := range 50 {
+		if got := tree.Rank(2 * i); got != i {
+			t.Errorf("Rank(%d) = %d, want %d", 2*i, got, i)
+		}
+		if got := tree.Rank(2*i + 1); got != i+1 {
+			t.Errorf("Rank(%d) = %d, want %d", 2*i+1, got, i+1)
+		}
+		if v, _, ok := tree.Select(i); !ok || v != 2*i {
+			t.Errorf("Select(%d) = %d, %t; want %d, true", i, v, ok, 2*i)
+		}
+	}
+	for _, i := range []int{-1, 50} {
+		if _, _, ok := tree.Select(i); ok {
+			t.Errorf("Select(%d) returned ok = true", i)
+		}
+	}
+
+	// Rank counts duplicates before the first equal entry.
+	dup := New[int, int](WithAllowDuplicates())
+	for i, v := range []int{1, 2, 2, 2, 3} {
+		dup.Insert(v, i)
+	}
+	if got := dup.Rank(2); got != 1 {
+		t.Errorf("Rank(2) with duplicates = %d, want 1", got)
+	}
+	if got := dup.Rank(3); got != 4 {
+		t.Errorf("Rank(3) with duplicates = %d, want 4", got)
+	}
+}
+
+func TestCursor_SeekIndex(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := range 20 {
+		tree.Insert(v, "")
+	}
+	c := tree.Cursor()
+	if !c.SeekIndex(7) || c.Value() != 7 {
+		t.Fatal("SeekIndex(7) did not find 7")
+	}
+	for want := 8; want < 20; want++ {
+		if !c.Next() || c.Value() != want {
+			t.Fatalf("Next after SeekIndex: want %d", want)
+		}
+	}
+	if c.SeekIndex(20) || c.Valid() {
+		t.Error("SeekIndex(20) returned a valid cursor")
+	}
+}
+
+func TestTree_FindInfo(t *testing.T) {
+	tree := New[int, string]()
+	for v := range 7 {
+		tree.Insert(v*10, "")
+	}
+	// The perfect tree has 30 at the root, 10 and 50 below it, and the
+	// other values as leaves.
+	for _, c := range []struct {
+		value int
+		ok    bool
+		info  SearchInfo
+	}{
+		{30, true, SearchInfo{Depth: 1, Rank: 3, Comparisons: 1}},
+		{50, true, SearchInfo{Depth: 2, Rank: 5, Comparisons: 2}},
+		{0, true, SearchInfo{Depth: 3, Rank: 0, Comparisons: 3}},
+		{60, true, SearchInfo{Depth: 3, Rank: 6, Comparisons: 3}},
+		{35, false, SearchInfo{Depth: 3, Rank: 4, Comparisons: 3}},
+	} {
+		_, info, ok := tree.FindInfo(c.value)
+		if ok != c.ok || info != c.info {
+			t.Errorf("FindInfo(%d) = %+v, %v, want %+v, %v", c.value, info, ok, c.info, c.ok)
+		}
+	}
+}
+
+func TestTree_Distance(t *testing.T) {
+	tree := New[int, int]()
+	for i := range 10 {
+		tree.Insert(2*i, i) // 0, 2, ..., 18
+	}
+	tests := []struct{ a, b, want int }{
+		{0, 18, 8},
+		{18, 0, 8},
+		{1, 17, 8},
+		{4, 6, 0},
+		{4, 4, 0},
+		{-5, 100, 10},
+		{19, 30, 0},
+	}
+	for _, tt := range tests {
+		if got := tree.Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTree_SizeAt(t *testing.T) {
+	tree := New[int, string]()
+	for v := range 7 {
+		tree.Insert(v, "")
+	}
+	for _, c := range []struct{ value, size int }{{3, 7}, {1, 3}, {5, 3}, {6, 1}} {
+		if size, ok := tree.SizeAt(c.value); !ok || size != c.size {
+			t.Errorf("SizeAt(%d) = %d, %v, want %d", c.value, size, ok, c.size)
+		}
+	}
+	if size, ok := tree.SizeAt(7); ok || size != 0 {
+		t.Errorf("SizeAt(7) = %d, %v", size, ok)
+	}
+}
+
+func TestTree_Boundaries(t *testing.T) {
+	tree := New[int, int]()
+	for v := range 100 {
+		tree.Insert(2*v, v)
+	}
+	bounds := tree.Boundaries(4)
+	if want := []int{50, 100, 150}; !slices.Equal(bounds, want) {
+		t.Errorf("Boundaries(4) = %v, want %v", bounds, want)
+	}
+	if got, want := tree.RangeSizes(bounds), []int{25, 25, 25, 25}; !slices.Equal(got, want) {
+		t.Errorf("RangeSizes(%v) = %v, want %v", bounds, got, want)
+	}
+	for n := 1; n <= 12; n++ {
+		sizes := tree.RangeSizes(tree.Boundaries(n))
+		if len(sizes) != n || slices.Min(sizes) < 100/n || slices.Max(sizes) > 100/n+1 {
+			t.Errorf("n = %d: RangeSizes = %v", n, sizes)
+		}
+	}
+
+	// Bounds need not be in the tree, and ranges can be empty.
+	if got, want := tree.RangeSizes([]int{-5, 1, 1, 9, 1000}), []int{0, 1, 0, 4, 95, 0}; !slices.Equal(got, want) {
+		t.Errorf("RangeSizes = %v, want %v", got, want)
+	}
+	if got := tree.RangeSizes(nil); !slices.Equal(got, []int{100}) {
+		t.Errorf("RangeSizes(nil) = %v", got)
+	}
+
+	// Small trees and duplicates yield fewer boundaries.
+	small := New[int, int](WithAllowDuplicates())
+	if got := small.Boundaries(3); len(got) != 0 {
+		t.Errorf("empty tree: Boundaries(3) = %v", got)
+	}
+	for _, v := range []int{1, 2, 2, 2, 2, 3} {
+		small.Insert(v, v)
+	}
+	if got, want := small.Boundaries(10), []int{2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Boundaries(10) = %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RangeSizes with unsorted bounds did not panic")
+		}
+	}()
+	tree.RangeSizes([]int{5, 3})
+}
@@ -0,0 +1,371 @@
+// Package rbtree implements a left-leaning red-black tree, an alternative
+// backend to the AVL tree of package generictree.
+//
+// Red-black trees are less strictly balanced than AVL trees. They need fewer
+// rotations on insertion and deletion, at the price of slightly deeper trees
+// and hence slightly slower lookups.
+package rbtree
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+)
+
+const (
+	red   = true
+	black = false
+)
+
+type node[K cmp.Ordered, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+	color       bool // color of the link from the parent
+}
+
+// Tree is a left-leaning red-black tree. The zero value is an empty tree
+// ready to use.
+type Tree[K cmp.Ordered, V any] struct {
+	root *node[K, V]
+	len  int
+}
+
+// New returns an empty tree.
+func New[K cmp.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{}
+}
+
+func isRed[K cmp.Ordered, V any](n *node[K, V]) bool {
+	return n != nil && n.color == red
+}
+
+func rotateLeft[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = red
+	return x
+}
+
+func rotateRight[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = red
+	return x
+}
+
+func flipColors[K cmp.Ordered, V any](h *node[K, V]) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+// fixUp restores the left-leaning red-black properties on the way up.
+func fixUp[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		flipColors(h)
+	}
+	return h
+}
+
+// Get returns the value stored for key and true,
+// or the zero value of V and false if key is not in the tree.
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	n := t.root
+	for n != nil {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Set stores value for key, replacing any previous value.
+func (t *Tree[K, V]) Set(key K, value V) {
+	t.root = t.set(t.root, key, value)
+	t.root.color = black
+}
+
+func (t *Tree[K, V]) set(h *node[K, V], key K, value V) *node[K, V] {
+	if h == nil {
+		t.len++
+		return &node[K, V]{key: key, value: value, color: red}
+	}
+	switch {
+	case key < h.key:
+		h.left = t.set(h.left, key, value)
+	case key > h.key:
+		h.right = t.set(h.right, key, value)
+	default:
+		h.value = value
+	}
+	return fixUp(h)
+}
+
+// Delete removes key and returns its value and true,
+// or the zero value of V and false if key is not in the tree.
+func (t *Tree[K, V]) Delete(key K) (V, bool) {
+	value, ok := t.Get(key)
+	if !ok {
+		return value, false
+	}
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root.color = red
+	}
+	t.root = deleteKey(t.root, key)
+	if t.root != nil {
+		t.root.color = black
+	}
+	t.len--
+	return value, true
+}
+
+// moveRedLeft makes h.left or one of its children red,
+// assuming h is red and both h.left and h.left.left are black.
+func moveRedLeft[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// moveRedRight makes h.right or one of its children red,
+// assuming h is red and both h.right and h.right.left are black.
+func moveRedRight[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		flipColors(h)
+	}
+	return h
+}
+
+func deleteMin[K cmp.Ordered, V any](h *node[K, V]) *node[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+	h.left = deleteMin(h.left)
+	return fixUp(h)
+}
+
+// deleteKey removes key from the subtree rooted at h.
+// key must be present in the subtree.
+func deleteKey[K cmp.Ordered, V any](h *node[K, V], key K) *node[K, V] {
+	if key < h.key {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+		h.left = deleteKey(h.left, key)
+		return fixUp(h)
+	}
+	if isRed(h.left) {
+		h = rotateRight(h)
+	}
+	if key == h.key && h.right == nil {
+		return nil
+	}
+	if !isRed(h.right) && !isRed(h.right.left) {
+		h = moveRedRight(h)
+	}
+	if key == h.key {
+		m := h.right
+		for m.left != nil {
+			m = m.left
+		}
+		h.key, h.value = m.key, m.value
+		h.right = deleteMin(h.right)
+	} else {
+		h.right = deleteKey(h.right, key)
+	}
+	return fixUp(h)
+}
+
+// Min returns the smallest key and its value.
+// ok is false if the tree is empty.
+func (t *Tree[K, V]) Min() (key K, value V, ok bool) {
+	n := t.root
+	if n == nil {
+		return key, value, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// Max returns the largest key and its value.
+// ok is false if the tree is empty.
+func (t *Tree[K, V]) Max() (key K, value V, ok bool) {
+	n := t.root
+	if n == nil {
+		return key, value, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// Range calls f for each key in the half-open interval [lo, hi),
+// in ascending order, until f returns false.
+func (t *Tree[K, V]) Range(lo, hi K, f func(K, V) bool) {
+	ascendRange(t.root, lo, hi, f)
+}
+
+func ascendRange[K cmp.Ordered, V any](n *node[K, V], lo, hi K, f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	if lo < n.key && !ascendRange(n.left, lo, hi, f) {
+		return false
+	}
+	if lo <= n.key && n.key < hi && !f(n.key, n.value) {
+		return false
+	}
+	if n.key < hi {
+		return ascendRange(n.right, lo, hi, f)
+	}
+	return true
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree[K, V]) Len() int {
+	return t.len
+}
+
+// Validate checks the red-black tree invariants: the search order, no red
+// right links, no two red links in a row, and the same number of black links
+// on every path from the root to a leaf. It returns an error describing the
+// first violation found, or nil.
+func (t *Tree[K, V]) Validate() error {
+	if isRed(t.root) {
+		return errors.New("root is red")
+	}
+	_, n, err := validate(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if n != t.len {
+		return fmt.Errorf("tree has %d nodes but Len() is %d", n, t.len)
+	}
+	return nil
+}
+
+// validate returns the black height and the number of nodes of the subtree.
+func validate[K cmp.Ordered, V any](n *node[K, V], lo, hi *K) (blackHeight, size int, err error) {
+	if n == nil {
+		return 0, 0, nil
+	}
+	if (lo != nil && n.key <= *lo) || (hi != nil && n.key >= *hi) {
+		return 0, 0, fmt.Errorf("node %v violates the search order", n.key)
+	}
+	if isRed(n.right) {
+		return 0, 0, fmt.Errorf("node %v has a red right link", n.key)
+	}
+	if isRed(n) && isRed(n.left) {
+		return 0, 0, fmt.Errorf("node %v and its left child are both red", n.key)
+	}
+	lb, ls, err := validate(n.left, lo, &n.key)
+	if err != nil {
+		return 0, 0, err
+	}
+	rb, rs, err := validate(n.right, &n.key, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+	if lb != rb {
+		return 0, 0, fmt.Errorf("node %v has black heights %d (left) and %d (right)", n.key, lb, rb)
+	}
+	if !isRed(n) {
+		lb++
+	}
+	return lb, ls + rs + 1, nil
+}
+
+// LoadSorted replaces the contents of the tree with the keys and their
+// values, in O(n) time. The keys must be in strictly ascending order, and
+// there must be one value per key; otherwise, LoadSorted panics.
+//
+// LoadSorted builds the tree as a 2-3 tree of the least possible height:
+// with h black levels, a tree holds between 2^h-1 keys, if all nodes are
+// 2-nodes, and 3^h-1 keys, if all nodes are 3-nodes, which are a black
+// node with a red left child.
+func (t *Tree[K, V]) LoadSorted(keys []K, values []V) {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("rbtree: LoadSorted: %d keys but %d values", len(keys), len(values)))
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			panic(fmt.Sprintf("rbtree: LoadSorted: key %v after %v", keys[i], keys[i-1]))
+		}
+	}
+	h := 0
+	for 1<<(h+1)-1 <= len(keys) {
+		h++
+	}
+	t.root, t.len = build(keys, values, h), len(keys)
+}
+
+// build returns a tree of black height h that holds the keys and values.
+// There must be between 2^h-1 and 3^h-1 keys.
+func build[K cmp.Ordered, V any](keys []K, values []V, h int) *node[K, V] {
+	if len(keys) == 0 {
+		return nil
+	}
+	// Each subtree of black height h-1 can hold up to 3^(h-1)-1 keys.
+	below := 1
+	for i := 0; i < h-1 && below <= len(keys); i++ {
+		below *= 3
+	}
+	below--
+	if r := len(keys) - 1; r <= 2*below {
+		// A 2-node.
+		m := r / 2
+		return &node[K, V]{
+			key:   keys[m],
+			value: values[m],
+			left:  build(keys[:m], values[:m], h-1),
+			right: build(keys[m+1:], values[m+1:], h-1),
+			color: black,
+		}
+	}
+	// A 3-node: subtrees a, b, and c between the keys at i and j.
+	r := len(keys) - 2
+	i := r / 3
+	j := i + 1 + (r-i)/2
+	return &node[K, V]{
+		key:   keys[j],
+		value: values[j],
+		left: &node[K, V]{
+			key:   keys[i],
+			value: values[i],
+			left:  build(keys[:i], values[:i], h-1),
+			right: build(keys[i+1:j], values[i+1:j], h-1),
+			color: red,
+		},
+		right: build(keys[j+1:], values[j+1:], h-1),
+		color: black,
+	}
+}
@@ -0,0 +1,68 @@
+package rbtree_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+	"github.com/appliedgo/generictree/conformance"
+	"github.com/appliedgo/generictree/rbtree"
+)
+
+var _ generictree.SortedMap[int, string] = (*rbtree.Tree[int, string])(nil)
+
+func newSortedMap() conformance.Map { return rbtree.New[int, string]() }
+
+func TestTree_SortedMap(t *testing.T) {
+	conformance.Run(t, newSortedMap)
+}
+
+func BenchmarkTree(b *testing.B) {
+	conformance.Benchmark(b, newSortedMap)
+}
+
+func TestTree_LoadSorted(t *testing.T) {
+	for n := range 300 {
+		keys := make([]int, n)
+		values := make([]string, n)
+		for i := range n {
+			keys[i], values[i] = 2*i, strconv.Itoa(i)
+		}
+		tree := rbtree.New[int, string]()
+		tree.Set(-1, "replaced")
+		tree.LoadSorted(keys, values)
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("%d keys: %v", n, err)
+		}
+		i := 0
+		tree.Range(-10, 2*n, func(k int, v string) bool {
+			if k != 2*i || v != strconv.Itoa(i) {
+				t.Fatalf("%d keys: entry %d is %d, %q", n, i, k, v)
+			}
+			i++
+			return true
+		})
+		if i != n || tree.Len() != n {
+			t.Fatalf("%d keys: Range visited %d keys, Len() = %d", n, i, tree.Len())
+		}
+		// The loaded tree stays valid under updates.
+		for k := range n {
+			tree.Set(2*k+1, "")
+			if k%3 == 0 {
+				tree.Delete(2 * k)
+			}
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("%d keys, after updates: %v", n, err)
+		}
+	}
+}
+
+func TestTree_LoadSorted_unsorted(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("LoadSorted with unsorted keys did not panic")
+		}
+	}()
+	rbtree.New[int, string]().LoadSorted([]int{1, 3, 2}, []string{"", "", ""})
+}
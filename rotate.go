@@ -0,0 +1,287 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"time"
+)
+
+// RotateLeftAt rotates the tree to the left at the node with value: the
+// right child of the node takes its place, and the node becomes the left
+// child of its former right child. A rotation keeps the order of the
+// entries and changes only the shape of the tree, which makes it useful
+// for demonstrating how the tree rebalances, one step at a time.
+//
+// RotateLeftAt returns an error wrapping ErrKeyNotFound if value is not in
+// the tree, and one wrapping ErrRotation if the node has no right child
+// or if the rotation would violate the AVL balance condition. Trees
+// created with WithNoBalance allow any rotation. If RotateLeftAt returns
+// an error, the tree is unchanged.
+func (t *Tree[Value, Data]) RotateLeftAt(value Value) error {
+	return t.rotateAt(value, true)
+}
+
+// RotateRightAt rotates the tree to the right at the node with value: the
+// left child of the node takes its place. See RotateLeftAt for details.
+func (t *Tree[Value, Data]) RotateRightAt(value Value) error {
+	return t.rotateAt(value, false)
+}
+
+func (t *Tree[Value, Data]) rotateAt(value Value, left bool) error {
+	op, child, method := "right", "left", "RotateRightAt"
+	if left {
+		op, child, method = "left", "right", "RotateLeftAt"
+	}
+	// path holds the ancestors of n, starting at the root.
+	var path []*Node[Value, Data]
+	n := t.Root
+	for n != nil {
+		c := t.compare(value, n.Value)
+		if c == 0 {
+			break
+		}
+		path = append(path, n)
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	if n == nil {
+		return fmt.Errorf("rotate %s at %v: %w", op, value, ErrKeyNotFound)
+	}
+	if left && n.Right == nil || !left && n.Left == nil {
+		return fmt.Errorf("rotate %s at %v: no %s child: %w", op, value, child, ErrRotation)
+	}
+
+	if t.balanced() {
+		if m, bal := t.unbalancedAfter(path, n, left); m != nil {
+			return fmt.Errorf("rotate %s at %v: node %v would get balance factor %d: %w", op, value, m.Value, bal, ErrRotation)
+		}
+	}
+	t.rotateNode(path, n, left)
+	t.debug.record(t, method, value)
+	return nil
+}
+
+// unbalancedAfter works out from the heights alone, without touching the
+// tree, whether rotating at n would give n, its rotated child, or one of
+// its ancestors in path a balance factor beyond the slack of the tree. It
+// returns the first such node and its would-be balance factor, or nil.
+func (t *Tree[Value, Data]) unbalancedAfter(path []*Node[Value, Data], n *Node[Value, Data], left bool) (*Node[Value, Data], int) {
+	// The child c of n takes the place of n. n keeps its other subtree
+	// and gains the inner subtree of c, while c keeps its outer subtree.
+	c, keep := n.Left, n.Right
+	if left {
+		c, keep = n.Right, n.Left
+	}
+	moved, outer := c.Right, c.Left
+	if left {
+		moved, outer = c.Left, c.Right
+	}
+	hn := max(keep.Height(), moved.Height()) + 1
+	nodes := []*Node[Value, Data]{n, c}
+	bals := []int{moved.Height() - keep.Height(), outer.Height() - hn}
+	if !left {
+		bals[0], bals[1] = -bals[0], -bals[1]
+	}
+	h := max(hn, outer.Height()) + 1
+	child := n
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+		bal := p.Right.Height() - h
+		if p.Right == child {
+			bal = h - p.Left.Height()
+			h = max(h, p.Left.Height()) + 1
+		} else {
+			h = max(h, p.Right.Height()) + 1
+		}
+		nodes = append(nodes, p)
+		bals = append(bals, bal)
+		child = p
+	}
+	for i, m := range nodes {
+		if bals[i] < -t.slack() || bals[i] > t.slack() {
+			return m, bals[i]
+		}
+	}
+	return nil, 0
+}
+
+// rotateNode rotates the subtree rooted at n, whose ancestors are path,
+// and updates the ancestors. It returns the new root of the subtree.
+func (t *Tree[Value, Data]) rotateNode(path []*Node[Value, Data], n *Node[Value, Data], left bool) *Node[Value, Data] {
+	if tr := t.tracing(); tr != nil {
+		tr.rotations++
+	}
+	var top *Node[Value, Data]
+	if left {
+		top = n.rotateLeft()
+	} else {
+		top = n.rotateRight()
+	}
+	if len(path) == 0 {
+		t.Root = top
+		return top
+	}
+	if p := path[len(path)-1]; p.Left == n {
+		p.Left = top
+	} else {
+		p.Right = top
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].update()
+	}
+	return top
+}
+
+// RebuildSubtree rebuilds the subtree rooted at the node with value into
+// a perfectly balanced subtree of the same nodes, in O(m) time for m
+// nodes in the subtree. Together with UnbalancedNodes, it lets a tree
+// created with WithNoBalance be repaired in the places that need it. In a
+// balanced tree, the rebuilt subtree can become lower than its sibling,
+// and RebuildSubtree rebalances its ancestors in O(log² n) time.
+//
+// RebuildSubtree returns an error wrapping ErrKeyNotFound if value is not
+// in the tree. It does not call the OnRotate node hooks.
+func (t *Tree[Value, Data]) RebuildSubtree(value Value) error {
+	var path []*Node[Value, Data]
+	n := t.Root
+	for n != nil {
+		c := t.compare(value, n.Value)
+		if c == 0 {
+			break
+		}
+		path = append(path, n)
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	if n == nil {
+		return fmt.Errorf("rebuild subtree at %v: %w", value, ErrKeyNotFound)
+	}
+	nodes := make([]*Node[Value, Data], 0, n.count())
+	n.ascend(func(m *Node[Value, Data]) bool {
+		nodes = append(nodes, m)
+		return true
+	})
+	top := relink(nodes)
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+		if p.Left == n {
+			p.Left = top
+		} else {
+			p.Right = top
+		}
+		n = p
+		if t.balanced() {
+			top = joinNodes(p.Left, p, p.Right, t.joining())
+		} else {
+			p.update()
+			top = p
+		}
+	}
+	t.Root = top
+	t.debug.record(t, "RebuildSubtree", value)
+	return nil
+}
+
+// relink links nodes, which are in tree order, into a perfectly balanced
+// tree and returns its root.
+func relink[Value cmp.Ordered, Data any](nodes []*Node[Value, Data]) *Node[Value, Data] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	m := len(nodes) / 2
+	n := nodes[m]
+	n.Left, n.Right = relink(nodes[:m]), relink(nodes[m+1:])
+	n.update()
+	return n
+}
+
+// CompactIncremental lowers the height of a tree created with WithNoBalance
+// in steps that fit into budget, so that a service can spread the work
+// over many calls instead of pausing for a full rebuild. Each step is a
+// single or double rotation that shortens the paths to the nodes. Before
+// each step, CompactIncremental checks whether the budget is spent; it
+// always makes at least one step, so that calls with a tiny budget make
+// progress too. It reports whether the tree is compact, that is, whether
+// its height is at most 2·log₂(n+1)+1 for n entries; once it is, further
+// calls return true right away. The tree may be modified between the
+// calls.
+//
+// CompactIncremental does not rotate balanced trees, which keep their own
+// bound on the height. AVL trees are always compact; trees with
+// WithRelaxedBalance may not be, so for them CompactIncremental only
+// reports whether they are. Each rotation calls the OnRotate node hooks
+// (see WithNodeHooks).
+func (t *Tree[Value, Data]) CompactIncremental(budget time.Duration) (done bool) {
+	if t.balanced() || compact(t.Root) {
+		return compact(t.Root)
+	}
+	deadline := time.Now().Add(budget)
+	steps, expired := 0, false
+	step := func(rotations int) bool {
+		if !expired && steps > 0 && time.Now().After(deadline) {
+			expired = true
+		}
+		steps++
+		if !expired {
+			t.joining().count(rotations)
+		}
+		return !expired
+	}
+	t.Root = t.Root.compact(step)
+	t.debug.record(t, "CompactIncremental", budget)
+	return !expired && compact(t.Root)
+}
+
+// compact reports whether the height of the subtree rooted at n is at most
+// 2·log₂(size+1)+1.
+func compact[Value cmp.Ordered, Data any](n *Node[Value, Data]) bool {
+	return float64(n.Height()) <= 2*math.Log2(float64(n.count()+1))+1
+}
+
+// compact rotates the subtree rooted at n until it is compact and returns
+// its new root. It calls step with the number of rotations before each
+// single or double rotation and stops early once step returns false.
+//
+// At the root of the subtree, compact rotates as long as a rotation reduces
+// the sum of the depths of the nodes. A single rotation to the left does
+// so if the right subtree of the right child is larger than the left
+// subtree of n, and a double rotation if the left subtree of the right
+// child is. As the sum decreases with every rotation, this ends, and then
+// neither child has more than about two thirds of the nodes. compact then
+// compacts the children, which makes the whole subtree compact.
+func (n *Node[Value, Data]) compact(step func(rotations int) bool) *Node[Value, Data] {
+	if compact(n) {
+		return n
+	}
+	for {
+		var rotate func() *Node[Value, Data]
+		rotations := 1
+		l, r := n.Left.count(), n.Right.count()
+		switch {
+		case n.Right != nil && n.Right.Right.count() > l:
+			rotate = n.rotateLeft
+		case n.Right != nil && n.Right.Left.count() > l:
+			rotate, rotations = n.rotateRightLeft, 2
+		case n.Left != nil && n.Left.Left.count() > r:
+			rotate = n.rotateRight
+		case n.Left != nil && n.Left.Right.count() > r:
+			rotate, rotations = n.rotateLeftRight, 2
+		default:
+			n.Left = n.Left.compact(step)
+			n.Right = n.Right.compact(step)
+			n.update()
+			return n
+		}
+		if !step(rotations) {
+			return n
+		}
+		n = rotate()
+	}
+}
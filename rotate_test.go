@@ -0,0 +1,212 @@
+package generictree
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTree_RotateAt(t *testing.T) {
+	tree := New[int, string](WithNoBalance())
+	for _, v := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(v, "")
+	}
+	if err := tree.RotateLeftAt(4); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root.Value != 6 || tree.Root.Left.Value != 4 || tree.Root.Left.Right.Value != 5 {
+		t.Error("unexpected shape after RotateLeftAt(4)")
+	}
+	if err := tree.RotateRightAt(4); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root.Left.Value != 2 || tree.Root.Left.Right.Value != 4 {
+		t.Error("unexpected shape after RotateRightAt(4)")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+	if got := tree.values(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("values = %v", got)
+	}
+
+	if err := tree.RotateLeftAt(7); !errors.Is(err, ErrRotation) {
+		t.Errorf("rotating a leaf: got %v, want ErrRotation", err)
+	}
+	if err := tree.RotateLeftAt(8); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("rotating a missing value: got %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestTree_RotateAt_balanced(t *testing.T) {
+	tree := New[int, string]()
+	for _, v := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Insert(v, "")
+	}
+	if err := tree.RotateLeftAt(4); !errors.Is(err, ErrRotation) {
+		t.Fatalf("unbalancing rotation: got %v, want ErrRotation", err)
+	}
+	if tree.Root.Value != 4 || tree.Root.Right.Value != 6 {
+		t.Error("failed rotation changed the tree")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// 2(1, 3(-, 4)) becomes 2(1, 4(3, -)), which is still balanced.
+	tree = New[int, string]()
+	for _, v := range []int{2, 1, 3, 4} {
+		tree.Insert(v, "")
+	}
+	if err := tree.RotateLeftAt(3); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root.Right.Value != 4 || tree.Root.Right.Left.Value != 3 {
+		t.Error("unexpected shape after RotateLeftAt(3)")
+	}
+	if err := tree.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestTree_RotateAt_rejected checks that a rotation that would break the
+// balance leaves the tree alone, without hooks or counted rotations, and
+// that the check also covers ancestors.
+func TestTree_RotateAt_rejected(t *testing.T) {
+	rotated := 0
+	tree := New[int, string](WithRelaxedBalance(2), WithCounters(), WithNodeHooks(NodeHooks[int, string]{
+		OnRotate: func(*Node[int, string]) { rotated++ },
+	}))
+	for _, v := range []int{1, 3, 2, 4} {
+		tree.Insert(v, "")
+	}
+	rotated = 0
+	before := tree.StatsSnapshot().Rotations
+	// 1(-, 3(2, 4)) would become 1(-, 2(-, 3(-, 4))): the rotated nodes
+	// stay within the slack, but 1 would lean by 3.
+	err := tree.RotateRightAt(3)
+	if !errors.Is(err, ErrRotation) {
+		t.Fatalf("got %v, want ErrRotation", err)
+	}
+	if want := "node 1 would get balance factor 3"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not mention %q", err, want)
+	}
+	if rotated != 0 {
+		t.Errorf("OnRotate called %d times", rotated)
+	}
+	if got := tree.StatsSnapshot().Rotations; got != before {
+		t.Errorf("Rotations = %d, want %d", got, before)
+	}
+	if tree.Root.Value != 1 || tree.Root.Right.Value != 3 || tree.Root.Right.Left.Value != 2 {
+		t.Error("rejected rotation changed the tree")
+	}
+}
+
+func TestTree_RebuildSubtree(t *testing.T) {
+	chain := New[int, string](WithNoBalance())
+	for v := 1; v <= 15; v++ {
+		chain.Insert(v, "")
+	}
+	if err := chain.RebuildSubtree(8); err != nil {
+		t.Fatal(err)
+	}
+	if h := chain.Root.Height(); h != 11 {
+		t.Errorf("height after rebuilding 8..15: got %d, want 11", h)
+	}
+	if err := chain.RebuildSubtree(1); err != nil {
+		t.Fatal(err)
+	}
+	if s := chain.Stats(); s.Height != 4 || s.Len != 15 {
+		t.Errorf("after rebuilding the whole tree: %+v", s)
+	}
+	if err := chain.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := chain.RebuildSubtree(16); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("rebuilding at a missing value: got %v, want ErrKeyNotFound", err)
+	}
+
+	// In a balanced tree, the ancestors of a rebuilt subtree stay balanced.
+	tree := New[int, string]()
+	for i := range 200 {
+		tree.Insert(i*7919%1000, "")
+	}
+	want := tree.values()
+	for _, v := range want {
+		if err := tree.RebuildSubtree(v); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("after rebuilding at %d: %v", v, err)
+		}
+	}
+	if got := tree.values(); !slices.Equal(got, want) {
+		t.Errorf("values changed: %v", got)
+	}
+}
+
+func TestTree_CompactIncremental(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for _, fill := range []string{"ascending", "zigzag", "random"} {
+		tree := New[int, int](WithNoBalance())
+		const n = 2000
+		for i := range n {
+			switch fill {
+			case "ascending":
+				tree.Insert(i, i)
+			case "zigzag":
+				if i%2 == 0 {
+					tree.Insert(i/2, i)
+				} else {
+					tree.Insert(n-1-i/2, i) // 0, n-1, 1, n-2, ...
+				}
+			case "random":
+				tree.Insert(r.IntN(10*n), i)
+			}
+		}
+		want := tree.values()
+		calls := 1
+		for !tree.CompactIncremental(0) {
+			calls++
+		}
+		if calls < 2 && fill != "random" {
+			t.Errorf("%s: compacted in one call with zero budget", fill)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("%s: %v", fill, err)
+		}
+		if !slices.Equal(tree.values(), want) {
+			t.Fatalf("%s: entries changed", fill)
+		}
+		if h, limit := tree.Root.Height(), 2*math.Log2(float64(tree.Len()+1))+1; float64(h) > limit {
+			t.Errorf("%s: height %d after compaction, want at most %.1f", fill, h, limit)
+		}
+		if !tree.CompactIncremental(0) {
+			t.Errorf("%s: compact tree is not done", fill)
+		}
+	}
+
+	balanced := New[int, int]()
+	for i := range 100 {
+		balanced.Insert(i, i)
+	}
+	if !balanced.CompactIncremental(time.Second) {
+		t.Error("balanced tree is not compact")
+	}
+
+	relaxed := New[int, int](WithRelaxedBalance(20))
+	for i := range 30 {
+		relaxed.Insert(i, i)
+	}
+	h := relaxed.Root.Height()
+	if relaxed.CompactIncremental(time.Second) {
+		t.Errorf("relaxed tree of height %d with %d entries is compact", h, relaxed.Len())
+	}
+	if relaxed.Root.Height() != h {
+		t.Error("CompactIncremental rotated a relaxed tree")
+	}
+}
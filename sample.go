@@ -0,0 +1,77 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// RandomEntry returns an entry chosen uniformly at random, using rng, in
+// O(log n) time. If the tree is empty, ok is false.
+func (t *Tree[Value, Data]) RandomEntry(rng *rand.Rand) (value Value, data Data, ok bool) {
+	if t.Len() == 0 {
+		return value, data, false
+	}
+	return t.Select(rng.IntN(t.Len()))
+}
+
+// Sample returns n distinct entries chosen uniformly at random, using rng,
+// in tree order. If the tree has fewer than n entries, Sample returns all
+// of them. Sample takes O(n log n) time, independent of the size of the
+// tree.
+func (t *Tree[Value, Data]) Sample(n int, rng *rand.Rand) []KV[Value, Data] {
+	size := t.Len()
+	n = min(n, size)
+	if n <= 0 {
+		return nil
+	}
+	// Robert Floyd's algorithm picks n distinct indices in O(n) steps.
+	picked := make(map[int]bool, n)
+	indices := make([]int, 0, n)
+	for j := size - n; j < size; j++ {
+		i := rng.IntN(j + 1)
+		if picked[i] {
+			i = j
+		}
+		picked[i] = true
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	result := make([]KV[Value, Data], n)
+	for k, i := range indices {
+		result[k].Value, result[k].Data, _ = t.Select(i)
+	}
+	return result
+}
+
+// WeightedRandom returns an entry chosen at random, using rng, with a
+// probability proportional to its data, in O(log n) time. The data must
+// not be negative. If the tree is empty or all data is zero, ok is false.
+func (t *NumericTree[Value, N]) WeightedRandom(rng *rand.Rand) (value Value, weight N, ok bool) {
+	sum := func(n *Node[Value, N]) float64 {
+		if n == nil {
+			return 0
+		}
+		return float64(aggregateOf(n).sum)
+	}
+	n := t.tree.Root
+	total := sum(n)
+	if total <= 0 {
+		return value, weight, false
+	}
+	r := rng.Float64() * total
+	for {
+		left := sum(n.Left)
+		if r < left {
+			n = n.Left
+			continue
+		}
+		r -= left
+		// Rounding errors can leave r slightly above the total; then
+		// the last entry on the path is taken.
+		if r < float64(n.Data) || n.Right == nil {
+			return n.Value, n.Data, true
+		}
+		r -= float64(n.Data)
+		n = n.Right
+	}
+}
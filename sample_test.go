@@ -0,0 +1,97 @@
+package generictree
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTree_RandomEntry(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree := &Tree[int, int]{}
+	if _, _, ok := tree.RandomEntry(rng); ok {
+		t.Error("RandomEntry on an empty tree is ok")
+	}
+	for i := range 10 {
+		tree.Insert(i, i*i)
+	}
+	counts := make([]int, 10)
+	for range 10000 {
+		v, d, ok := tree.RandomEntry(rng)
+		if !ok || d != v*v {
+			t.Fatalf("RandomEntry() = %d, %d, %t", v, d, ok)
+		}
+		counts[v]++
+	}
+	for v, c := range counts {
+		if c < 850 || c > 1150 {
+			t.Errorf("value %d drawn %d times out of 10000", v, c)
+		}
+	}
+}
+
+func TestTree_Sample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	tree := &Tree[int, int]{}
+	for i := range 100 {
+		tree.Insert(i, -i)
+	}
+	counts := make([]int, 100)
+	for range 2000 {
+		s := tree.Sample(10, rng)
+		if len(s) != 10 {
+			t.Fatalf("Sample(10) returned %d entries", len(s))
+		}
+		if !slices.IsSortedFunc(s, func(a, b KV[int, int]) int { return a.Value - b.Value }) {
+			t.Fatalf("Sample(10) = %v is not in tree order", s)
+		}
+		for i, kv := range s {
+			if i > 0 && s[i-1].Value == kv.Value || kv.Data != -kv.Value {
+				t.Fatalf("Sample(10) = %v", s)
+			}
+			counts[kv.Value]++
+		}
+	}
+	for v, c := range counts {
+		if c < 130 || c > 270 {
+			t.Errorf("value %d sampled %d times, want about 200", v, c)
+		}
+	}
+	if s := tree.Sample(200, rng); len(s) != 100 {
+		t.Errorf("Sample(200) of 100 entries returned %d", len(s))
+	}
+	if s := tree.Sample(0, rng); s != nil {
+		t.Errorf("Sample(0) = %v", s)
+	}
+}
+
+func TestNumericTree_WeightedRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	tree := NewNumericTree[string, float64]()
+	if _, _, ok := tree.WeightedRandom(rng); ok {
+		t.Error("WeightedRandom on an empty tree is ok")
+	}
+	weights := map[string]float64{"a": 1, "b": 0, "c": 3, "d": 6, "e": 0}
+	for v, w := range weights {
+		tree.Insert(v, w)
+	}
+	counts := map[string]int{}
+	const draws = 20000
+	for range draws {
+		v, w, _ := tree.WeightedRandom(rng)
+		if w != weights[v] {
+			t.Fatalf("WeightedRandom() = %s, %v", v, w)
+		}
+		counts[v]++
+	}
+	for v, w := range weights {
+		want := draws * w / 10
+		if math.Abs(float64(counts[v])-want) > 0.05*draws {
+			t.Errorf("%s drawn %d times, want about %.0f", v, counts[v], want)
+		}
+	}
+	if counts["b"] != 0 || counts["e"] != 0 {
+		t.Errorf("entries with weight 0 were drawn: %v", counts)
+	}
+}
@@ -0,0 +1,186 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// The functions in this file adapt the iterators of a tree, such as All,
+// Backward, and From, without collecting entries into slices. For example,
+// the first 100 entries after x are
+//
+//	Take(t.From(x), 100)
+
+// Take returns an iterator over the first n entries of seq.
+func Take[Value, Data any](seq iter.Seq2[Value, Data], n int) iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v, d := range seq {
+			if !yield(v, d) {
+				return
+			}
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns an iterator over the entries of seq after the first n.
+func Skip[Value, Data any](seq iter.Seq2[Value, Data], n int) iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		i := 0
+		for v, d := range seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v, d) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns an iterator over the entries of seq up to, but not
+// including, the first entry for which pred returns false.
+func TakeWhile[Value, Data any](seq iter.Seq2[Value, Data], pred func(Value, Data) bool) iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		for v, d := range seq {
+			if !pred(v, d) || !yield(v, d) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive chunks of up to n entries of
+// seq. All chunks but the last one have exactly n entries. Each chunk is a
+// new slice. Chunk panics if n is less than 1.
+func Chunk[Value, Data any](seq iter.Seq2[Value, Data], n int) iter.Seq[[]KV[Value, Data]] {
+	if n < 1 {
+		panic("generictree: Chunk: n must be at least 1")
+	}
+	return func(yield func([]KV[Value, Data]) bool) {
+		var chunk []KV[Value, Data]
+		for v, d := range seq {
+			if chunk == nil {
+				chunk = make([]KV[Value, Data], 0, n)
+			}
+			chunk = append(chunk, KV[Value, Data]{v, d})
+			if len(chunk) == n {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// MergeIter returns an iterator that joins trees by value: it yields each
+// value that is in all of the trees, together with a new slice of the data
+// stored for that value in each tree, in the order of the trees.
+//
+// MergeIter uses leapfrog iteration: it moves a cursor over each tree and
+// lets the cursors that fall behind seek to the largest current value, so
+// it skips ranges of values that cannot match. All trees must use the same
+// order; MergeIter compares values by the order of the first tree. If a
+// tree allows duplicates, only the first entry of a value takes part.
+func MergeIter[Value cmp.Ordered, Data any](trees ...*Tree[Value, Data]) iter.Seq2[Value, []Data] {
+	return func(yield func(Value, []Data) bool) {
+		if len(trees) == 0 {
+			return
+		}
+		cursors := make([]*Cursor[Value, Data], len(trees))
+		for i, t := range trees {
+			cursors[i] = t.Cursor()
+			if !cursors[i].First() {
+				return
+			}
+		}
+		compare := trees[0].compare
+		for {
+			hi := cursors[0].Value()
+			for _, c := range cursors[1:] {
+				if compare(c.Value(), hi) > 0 {
+					hi = c.Value()
+				}
+			}
+			match := true
+			for _, c := range cursors {
+				if compare(c.Value(), hi) == 0 {
+					continue
+				}
+				if !c.Seek(hi) {
+					return
+				}
+				if compare(c.Value(), hi) != 0 {
+					match = false
+				}
+			}
+			if !match {
+				continue
+			}
+			data := make([]Data, len(cursors))
+			for i, c := range cursors {
+				data[i] = c.Data()
+			}
+			if !yield(hi, data) {
+				return
+			}
+			for _, c := range cursors {
+				if !c.Next() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Join calls f for each value that is in both left and right, with the
+// data stored for it in each tree, in tree order. Join is a merge join: it
+// walks both trees in lockstep and takes O(len(left) + len(right)) time.
+// Both trees must use the same order; Join compares values by the order
+// of left. If left allows duplicates, f is called for each of its entries
+// of a matching value; of the entries of a value in right, only the first
+// takes part.
+func Join[Value cmp.Ordered, A, B any](left *Tree[Value, A], right *Tree[Value, B], f func(Value, A, B)) {
+	join(left, right, func(v Value, a A, b B, ok bool) {
+		if ok {
+			f(v, a, b)
+		}
+	})
+}
+
+// LeftJoin is like Join but calls f for every entry of left. For values
+// that are not in right, f gets the zero value of B and ok is false.
+func LeftJoin[Value cmp.Ordered, A, B any](left *Tree[Value, A], right *Tree[Value, B], f func(v Value, a A, b B, ok bool)) {
+	join(left, right, f)
+}
+
+func join[Value cmp.Ordered, A, B any](left *Tree[Value, A], right *Tree[Value, B], f func(Value, A, B, bool)) {
+	l, r := left.Cursor(), right.Cursor()
+	more := r.First()
+	for ok := l.First(); ok; ok = l.Next() {
+		c := -1
+		for more {
+			if c = left.compare(l.Value(), r.Value()); c <= 0 {
+				break
+			}
+			more = r.Next()
+		}
+		if c == 0 {
+			f(l.Value(), l.Data(), r.Data(), true)
+		} else {
+			f(l.Value(), l.Data(), *new(B), false)
+		}
+	}
+}
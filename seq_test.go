@@ -0,0 +1,145 @@
+package generictree
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// seqValues collects the values of seq.
+func seqValues[Value, Data any](seq iter.Seq2[Value, Data]) []Value {
+	var values []Value
+	for v := range seq {
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestSeqAdapters(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := range 10 {
+		tree.Insert(v, "")
+	}
+	for _, c := range []struct {
+		name string
+		seq  iter.Seq2[int, string]
+		want []int
+	}{
+		{"Take", Take(tree.From(3), 4), []int{3, 4, 5, 6}},
+		{"Take more than available", Take(tree.From(8), 5), []int{8, 9}},
+		{"Take 0", Take(tree.All(), 0), nil},
+		{"Skip", Skip(tree.Backward(), 7), []int{2, 1, 0}},
+		{"Skip all", Skip(tree.All(), 20), nil},
+		{"TakeWhile", TakeWhile(tree.All(), func(v int, _ string) bool { return v*v < 20 }), []int{0, 1, 2, 3, 4}},
+		{"Skip and Take", Take(Skip(tree.All(), 2), 2), []int{2, 3}},
+	} {
+		if got := seqValues(c.seq); !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// Take stops the underlying iteration after n entries.
+	visited := 0
+	counting := func(yield func(int, string) bool) {
+		for v, d := range tree.All() {
+			visited++
+			if !yield(v, d) {
+				return
+			}
+		}
+	}
+	seqValues(Take(counting, 3))
+	if visited != 3 {
+		t.Errorf("Take(3) visited %d entries", visited)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := range 7 {
+		tree.Insert(v, "")
+	}
+	var got [][]int
+	for chunk := range Chunk(tree.All(), 3) {
+		var values []int
+		for _, e := range chunk {
+			values = append(values, e.Value)
+		}
+		got = append(got, values)
+	}
+	want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for range Chunk(tree.All(), 3) {
+		break // must not panic
+	}
+}
+
+func TestMergeIter(t *testing.T) {
+	multiples := func(k int) *Tree[int, int] {
+		tree := &Tree[int, int]{}
+		for v := 0; v <= 60; v += k {
+			tree.Insert(v, k)
+		}
+		return tree
+	}
+	var got []int
+	for v, data := range MergeIter(multiples(2), multiples(3), multiples(5)) {
+		got = append(got, v)
+		if !slices.Equal(data, []int{2, 3, 5}) {
+			t.Errorf("data of %d: got %v", v, data)
+		}
+	}
+	if want := []int{0, 30, 60}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := seqValues(MergeIter(multiples(4))); len(got) != 16 {
+		t.Errorf("single tree: got %d values, want 16", len(got))
+	}
+	if got := seqValues(MergeIter(multiples(2), &Tree[int, int]{})); got != nil {
+		t.Errorf("with an empty tree: got %v", got)
+	}
+	if got := seqValues(MergeIter[int, int]()); got != nil {
+		t.Errorf("no trees: got %v", got)
+	}
+	for range MergeIter(multiples(1), multiples(1)) {
+		break // must not panic
+	}
+}
+
+func TestJoin(t *testing.T) {
+	left, right := &Tree[int, string]{}, &Tree[int, int]{}
+	for v := 0; v < 20; v += 2 {
+		left.Insert(v, strconv.Itoa(v))
+	}
+	for v := 0; v < 30; v += 3 {
+		right.Insert(v, v*10)
+	}
+	var got []int
+	Join(left, right, func(v int, a string, b int) {
+		got = append(got, v)
+		if a != strconv.Itoa(v) || b != v*10 {
+			t.Errorf("f(%d, %q, %d)", v, a, b)
+		}
+	})
+	if want := []int{0, 6, 12, 18}; !slices.Equal(got, want) {
+		t.Errorf("Join: got %v, want %v", got, want)
+	}
+
+	got = nil
+	LeftJoin(left, right, func(v int, a string, b int, ok bool) {
+		got = append(got, v)
+		if ok != (v%3 == 0) || (ok && b != v*10) || (!ok && b != 0) {
+			t.Errorf("f(%d, %q, %d, %v)", v, a, b, ok)
+		}
+	})
+	if !slices.Equal(got, left.values()) {
+		t.Errorf("LeftJoin: got %v, want %v", got, left.values())
+	}
+	Join(left, &Tree[int, int]{}, func(v int, _ string, _ int) {
+		t.Errorf("Join with an empty tree: f(%d)", v)
+	})
+}
@@ -0,0 +1,189 @@
+package generictree
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Sequence is a list of elements that supports insertion and deletion at
+// any position in O(log n) time, like a rope. It is an AVL tree ordered by
+// position: the size of each subtree gives the position of a node, so the
+// nodes need no keys.
+//
+// The zero value is an empty sequence ready to use. Methods that take a
+// position panic if it is out of range.
+type Sequence[T any] struct {
+	root *Node[int, T] // the Value field is unused
+}
+
+// NewSequence returns a sequence of the given elements. It takes O(n) time.
+func NewSequence[T any](elems ...T) *Sequence[T] {
+	return &Sequence[T]{root: build(len(elems), nil, func(i int) (int, T) {
+		return 0, elems[i]
+	})}
+}
+
+// Len returns the number of elements in the sequence.
+func (s *Sequence[T]) Len() int {
+	return s.root.count()
+}
+
+func (s *Sequence[T]) check(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("generictree: index %d out of range [0:%d]", i, n))
+	}
+}
+
+// node returns the node at position i.
+func (s *Sequence[T]) node(i int) *Node[int, T] {
+	s.check(i, s.Len())
+	n := s.root
+	for {
+		left := n.Left.count()
+		switch {
+		case i < left:
+			n = n.Left
+		case i > left:
+			i -= left + 1
+			n = n.Right
+		default:
+			return n
+		}
+	}
+}
+
+// At returns the element at position i.
+func (s *Sequence[T]) At(i int) T {
+	return s.node(i).Data
+}
+
+// Set replaces the element at position i.
+func (s *Sequence[T]) Set(i int, v T) {
+	s.node(i).Data = v
+}
+
+// InsertAt inserts v at position i, moving the elements from i onwards one
+// position up. i may be Len(), which appends v.
+func (s *Sequence[T]) InsertAt(i int, v T) {
+	s.check(i, s.Len()+1)
+	s.root = insertAt(s.root, i, &Node[int, T]{Data: v, height: 1, size: 1})
+}
+
+// Append adds elems to the end of the sequence.
+func (s *Sequence[T]) Append(elems ...T) {
+	for _, v := range elems {
+		s.root = insertAt(s.root, s.Len(), &Node[int, T]{Data: v, height: 1, size: 1})
+	}
+}
+
+func insertAt[T any](n *Node[int, T], i int, leaf *Node[int, T]) *Node[int, T] {
+	if n == nil {
+		return leaf
+	}
+	if left := n.Left.count(); i <= left {
+		n.Left = insertAt(n.Left, i, leaf)
+	} else {
+		n.Right = insertAt(n.Right, i-left-1, leaf)
+	}
+	n.update()
+	return n.rebalance()
+}
+
+// DeleteAt removes the element at position i and returns it.
+func (s *Sequence[T]) DeleteAt(i int) T {
+	s.check(i, s.Len())
+	var removed *Node[int, T]
+	s.root, removed = s.root.deleteIndex(i, balance{limit: 1})
+	return removed.Data
+}
+
+// Concat moves all elements of other to the end of s in O(log n) time and
+// leaves other empty.
+func (s *Sequence[T]) Concat(other *Sequence[T]) {
+	switch {
+	case other.root == nil:
+	case s.root == nil:
+		s.root = other.root
+	default:
+		right, k := other.root.deleteMin(balance{limit: 1})
+		s.root = joinNodes(s.root, k, right, balance{limit: 1})
+	}
+	other.root = nil
+}
+
+// Split moves the elements from position i onwards into a new sequence in
+// O(log n) time and returns it. i may be Len(), which returns an empty
+// sequence.
+func (s *Sequence[T]) Split(i int) *Sequence[T] {
+	s.check(i, s.Len()+1)
+	var rest *Node[int, T]
+	s.root, rest = splitAt(s.root, i)
+	return &Sequence[T]{root: rest}
+}
+
+// splitAt splits the subtree rooted at n into its first i nodes and the
+// remaining nodes, and returns the roots of both parts.
+func splitAt[T any](n *Node[int, T], i int) (first, rest *Node[int, T]) {
+	if n == nil {
+		return nil, nil
+	}
+	left, right := n.Left, n.Right
+	if l := left.count(); i > l {
+		rl, rr := splitAt(right, i-l-1)
+		return joinNodes(left, n, rl, balance{limit: 1}), rr
+	}
+	ll, lr := splitAt(left, i)
+	return ll, joinNodes(lr, n, right, balance{limit: 1})
+}
+
+// Slice returns a new sequence with the elements from position i up to,
+// but not including, position j. The sequence s is not changed.
+//
+// Slice copies the elements, so it takes O(log n + j-i) time. To move
+// elements out of s in O(log n) time, use Split.
+func (s *Sequence[T]) Slice(i, j int) *Sequence[T] {
+	n := s.Len()
+	if i < 0 || j < i || j > n {
+		panic(fmt.Sprintf("generictree: slice bounds [%d:%d] out of range [0:%d]", i, j, n))
+	}
+	var elems []T
+	for _, v := range s.Range(i, j) {
+		elems = append(elems, v)
+	}
+	return NewSequence(elems...)
+}
+
+// Range returns an iterator over the positions and elements from position
+// i up to, but not including, position j.
+func (s *Sequence[T]) Range(i, j int) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		pos := 0
+		rangeAt(s.root, &pos, i, j, yield)
+	}
+}
+
+// rangeAt yields the nodes of the subtree rooted at n whose positions are
+// in [i, j); *pos is the position of the first node of the subtree.
+func rangeAt[T any](n *Node[int, T], pos *int, i, j int, yield func(int, T) bool) bool {
+	if n == nil {
+		return true
+	}
+	first := *pos
+	if first+n.size <= i || first >= j {
+		*pos += n.size
+		return true
+	}
+	if !rangeAt(n.Left, pos, i, j, yield) {
+		return false
+	}
+	if p := *pos; p >= i && p < j && !yield(p, n.Data) {
+		return false
+	}
+	*pos++
+	return rangeAt(n.Right, pos, i, j, yield)
+}
+
+// All returns an iterator over the positions and elements of the sequence.
+func (s *Sequence[T]) All() iter.Seq2[int, T] {
+	return s.Range(0, s.Len())
+}
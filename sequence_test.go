@@ -0,0 +1,102 @@
+package generictree
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func (s *Sequence[T]) elems() []T {
+	var elems []T
+	for _, v := range s.All() {
+		elems = append(elems, v)
+	}
+	return elems
+}
+
+// validate checks the sizes and the balance of the sequence.
+func (s *Sequence[T]) validate(t *testing.T) {
+	t.Helper()
+	tree := &Tree[int, T]{Root: s.root, cfg: &config[int, T]{allowDuplicates: true}}
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	var s Sequence[int]
+	var model []int
+	for i := range 2000 {
+		switch op := rnd.IntN(10); {
+		case op < 6 || len(model) == 0:
+			pos := rnd.IntN(len(model) + 1)
+			s.InsertAt(pos, i)
+			model = slices.Insert(model, pos, i)
+		case op < 9:
+			pos := rnd.IntN(len(model))
+			if got := s.DeleteAt(pos); got != model[pos] {
+				t.Fatalf("DeleteAt(%d) = %d, want %d", pos, got, model[pos])
+			}
+			model = slices.Delete(model, pos, pos+1)
+		default:
+			pos := rnd.IntN(len(model))
+			s.Set(pos, -i)
+			model[pos] = -i
+		}
+		if i%100 == 0 {
+			s.validate(t)
+			if !slices.Equal(s.elems(), model) {
+				t.Fatalf("step %d: got %v, want %v", i, s.elems(), model)
+			}
+		}
+	}
+	for i, v := range model {
+		if got := s.At(i); got != v {
+			t.Fatalf("At(%d) = %d, want %d", i, got, v)
+		}
+	}
+}
+
+func TestSequence_SplitConcat(t *testing.T) {
+	for n := range 20 {
+		for i := 0; i <= n; i++ {
+			elems := make([]int, n)
+			for k := range elems {
+				elems[k] = k
+			}
+			s := NewSequence(elems...)
+			rest := s.Split(i)
+			s.validate(t)
+			rest.validate(t)
+			if !slices.Equal(s.elems(), elems[:i]) || !slices.Equal(rest.elems(), elems[i:]) {
+				t.Fatalf("Split(%d) of %d: got %v and %v", i, n, s.elems(), rest.elems())
+			}
+			s.Concat(rest)
+			s.validate(t)
+			if !slices.Equal(s.elems(), elems) || rest.Len() != 0 {
+				t.Fatalf("Concat after Split(%d) of %d: got %v", i, n, s.elems())
+			}
+		}
+	}
+}
+
+func TestSequence_Slice(t *testing.T) {
+	s := NewSequence("a", "b", "c", "d", "e")
+	s.Append("f", "g")
+	if got := s.Slice(2, 5).elems(); !slices.Equal(got, []string{"c", "d", "e"}) {
+		t.Errorf("Slice(2, 5) = %v", got)
+	}
+	if got := s.Slice(3, 3).Len(); got != 0 {
+		t.Errorf("Slice(3, 3) has %d elements", got)
+	}
+	if s.Len() != 7 {
+		t.Errorf("Slice changed the sequence: Len() = %d", s.Len())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("At(7) did not panic")
+		}
+	}()
+	s.At(7)
+}
@@ -0,0 +1,138 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Set is an ordered set of values, backed by a Tree.
+// The zero value is an empty set ready to use.
+type Set[T cmp.Ordered] struct {
+	tree Tree[T, struct{}]
+}
+
+// NewSet returns a set that contains the given items.
+func NewSet[T cmp.Ordered](items ...T) *Set[T] {
+	s := &Set[T]{}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add adds item to the set. It reports whether the item was not yet in the set.
+func (s *Set[T]) Add(item T) bool {
+	if s.Has(item) {
+		return false
+	}
+	s.tree.Insert(item, struct{}{})
+	return true
+}
+
+// Remove removes item from the set. It reports whether the item was in the set.
+func (s *Set[T]) Remove(item T) bool {
+	_, ok := s.tree.Delete(item)
+	return ok
+}
+
+// Has reports whether item is in the set.
+func (s *Set[T]) Has(item T) bool {
+	_, ok := s.tree.Find(item)
+	return ok
+}
+
+// Len returns the number of items in the set.
+func (s *Set[T]) Len() int {
+	return s.tree.Len()
+}
+
+// All returns an iterator over the items of the set in ascending order.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.tree.Root.ascend(func(n *Node[T, struct{}]) bool { return yield(n.Value) })
+	}
+}
+
+// items returns the items of the set in ascending order.
+func (s *Set[T]) items() []T {
+	items := make([]T, 0, s.Len())
+	s.tree.Root.ascend(func(n *Node[T, struct{}]) bool {
+		items = append(items, n.Value)
+		return true
+	})
+	return items
+}
+
+// fromSorted returns a set of items, which must be strictly ascending.
+func fromSorted[T cmp.Ordered](items []T) *Set[T] {
+	s := &Set[T]{}
+	s.tree.Root = build(len(items), nil, func(i int) (T, struct{}) { return items[i], struct{}{} })
+	return s
+}
+
+// mergeSorted walks the items of a and b in ascending order and collects the
+// items for which keep returns true. inA and inB tell whether the item is
+// in a, in b, or in both.
+func mergeSorted[T cmp.Ordered](a, b []T, keep func(inA, inB bool) bool) []T {
+	var result []T
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			if keep(true, false) {
+				result = append(result, a[i])
+			}
+			i++
+		case i == len(a) || b[j] < a[i]:
+			if keep(false, true) {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if keep(true, true) {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Union returns a new set with the items that are in s, in other, or in both.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	return fromSorted(mergeSorted(s.items(), other.items(), func(inA, inB bool) bool { return true }))
+}
+
+// Intersect returns a new set with the items that are in both s and other.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	return fromSorted(mergeSorted(s.items(), other.items(), func(inA, inB bool) bool { return inA && inB }))
+}
+
+// Diff returns a new set with the items of s that are not in other.
+func (s *Set[T]) Diff(other *Set[T]) *Set[T] {
+	return fromSorted(mergeSorted(s.items(), other.items(), func(inA, inB bool) bool { return inA && !inB }))
+}
+
+// IsSubsetOf reports whether every item of s is also in other.
+func (s *Set[T]) IsSubsetOf(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for item := range s.All() {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersetOf reports whether s contains every item of other.
+func (s *Set[T]) IsSupersetOf(other *Set[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+// Equal reports whether s and other contain the same items.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubsetOf(other)
+}
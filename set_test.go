@@ -0,0 +1,94 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(5, 3, 8, 3)
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", s.Len())
+	}
+	if !s.Add(1) || s.Add(1) {
+		t.Error("Add(1) must return true once and false for the duplicate")
+	}
+	if !s.Has(1) || s.Has(2) {
+		t.Errorf("Has(1) = %t, Has(2) = %t", s.Has(1), s.Has(2))
+	}
+	if !s.Remove(1) || s.Remove(1) {
+		t.Error("Remove(1) must return true once and false when the item is gone")
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int{3, 5, 8}) {
+		t.Errorf("All() = %v, want [3 5 8]", got)
+	}
+
+	// All stops when the loop body breaks.
+	var first []int
+	for item := range s.All() {
+		first = append(first, item)
+		break
+	}
+	if !slices.Equal(first, []int{3}) {
+		t.Errorf("breaking out of All() yielded %v", first)
+	}
+}
+
+func TestSet_operations(t *testing.T) {
+	a := NewSet(1, 2, 3, 4, 5)
+	b := NewSet(4, 5, 6, 7)
+	empty := NewSet[int]()
+
+	for _, c := range []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5, 6, 7}},
+		{"Intersect", a.Intersect(b), []int{4, 5}},
+		{"Diff", a.Diff(b), []int{1, 2, 3}},
+		{"DiffReverse", b.Diff(a), []int{6, 7}},
+		{"UnionEmpty", a.Union(empty), []int{1, 2, 3, 4, 5}},
+		{"IntersectEmpty", a.Intersect(empty), nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := slices.Collect(c.got.All()); !slices.Equal(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+			if err := c.got.tree.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	sub := NewSet(2, 4)
+	switch {
+	case !sub.IsSubsetOf(a):
+		t.Error("{2, 4} is not a subset of {1..5}")
+	case !a.IsSupersetOf(sub):
+		t.Error("{1..5} is not a superset of {2, 4}")
+	case sub.IsSubsetOf(b):
+		t.Error("{2, 4} is a subset of {4..7}")
+	case !empty.IsSubsetOf(a):
+		t.Error("the empty set is not a subset of {1..5}")
+	case !a.Equal(NewSet(5, 4, 3, 2, 1)):
+		t.Error("{1..5} does not equal {5..1}")
+	case a.Equal(b):
+		t.Error("{1..5} equals {4..7}")
+	}
+}
+
+// The nodes of a set store no data: a struct{} field in the middle of a
+// struct takes no space, and plain nodes hold only a pointer to their
+// augmentation. With int values, a node fits the 48-byte size class of the
+// allocator on 64-bit platforms instead of the 64-byte one.
+func TestSet_nodeSize(t *testing.T) {
+	word := unsafe.Sizeof(uintptr(0))
+	if got := unsafe.Sizeof(Node[int, struct{}]{}); got != 6*word {
+		t.Errorf("size of Node[int, struct{}] = %d, want %d", got, 6*word)
+	}
+	if got, want := unsafe.Sizeof(Node[int, int]{}), 7*word; got != want {
+		t.Errorf("size of Node[int, int] = %d, want %d", got, want)
+	}
+}
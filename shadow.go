@@ -0,0 +1,136 @@
+package generictree
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Shadow runs every operation against two SortedMap backends, a primary and
+// a shadow, and reports the operations whose results differ. The results of
+// the primary are returned, so a program can move to a new backend, or to
+// a new version of this package, by running it as the shadow of the old one
+// in production until no divergences show up:
+//
+//	m := generictree.NewShadow[K, V](old, rbtree.New[K, V](), func(d generictree.Divergence) {
+//		log.Print(d)
+//	})
+//
+// Results are compared with reflect.DeepEqual. Unlike the backends, a
+// Shadow allocates on lookups. Like them, it is not safe for concurrent
+// use.
+type Shadow[K, V any] struct {
+	primary, shadow SortedMap[K, V]
+	report          func(Divergence)
+}
+
+var _ SortedMap[int, string] = (*Shadow[int, string])(nil)
+
+// Divergence describes an operation whose result differs between the
+// primary and the shadow of a Shadow.
+type Divergence struct {
+	Op      string // the operation and its arguments, for example "Get(5)"
+	Primary string // the result of the primary
+	Shadow  string // the result of the shadow
+}
+
+// Error returns a description of the divergence.
+func (d Divergence) Error() string {
+	return fmt.Sprintf("generictree: %s = %s, but the shadow returned %s", d.Op, d.Primary, d.Shadow)
+}
+
+// NewShadow returns a map that runs every operation against primary and
+// shadow and calls report for each operation whose results differ. The two
+// maps must hold the same entries, usually by both being empty.
+func NewShadow[K, V any](primary, shadow SortedMap[K, V], report func(Divergence)) *Shadow[K, V] {
+	return &Shadow[K, V]{primary: primary, shadow: shadow, report: report}
+}
+
+// compare reports a divergence if the results p and s of op differ.
+func (m *Shadow[K, V]) compare(p, s []any, op string, args ...any) {
+	if reflect.DeepEqual(p, s) {
+		return
+	}
+	m.report(Divergence{
+		Op:      fmt.Sprintf("%s(%s)", op, formatList(args)),
+		Primary: formatList(p),
+		Shadow:  formatList(s),
+	})
+}
+
+// formatList formats xs as a comma-separated list of Go values.
+func formatList(xs []any) string {
+	var b strings.Builder
+	for i, x := range xs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#v", x)
+	}
+	return b.String()
+}
+
+// Get returns the value that the primary stores for key.
+func (m *Shadow[K, V]) Get(key K) (V, bool) {
+	v, ok := m.primary.Get(key)
+	sv, sok := m.shadow.Get(key)
+	m.compare([]any{v, ok}, []any{sv, sok}, "Get", key)
+	return v, ok
+}
+
+// Set stores value for key in both maps.
+func (m *Shadow[K, V]) Set(key K, value V) {
+	m.primary.Set(key, value)
+	m.shadow.Set(key, value)
+}
+
+// Delete removes key from both maps and returns the value that the primary
+// stored for it.
+func (m *Shadow[K, V]) Delete(key K) (V, bool) {
+	v, ok := m.primary.Delete(key)
+	sv, sok := m.shadow.Delete(key)
+	m.compare([]any{v, ok}, []any{sv, sok}, "Delete", key)
+	return v, ok
+}
+
+// Min returns the smallest key of the primary and its value.
+func (m *Shadow[K, V]) Min() (key K, value V, ok bool) {
+	key, value, ok = m.primary.Min()
+	sk, sv, sok := m.shadow.Min()
+	m.compare([]any{key, value, ok}, []any{sk, sv, sok}, "Min")
+	return key, value, ok
+}
+
+// Max returns the largest key of the primary and its value.
+func (m *Shadow[K, V]) Max() (key K, value V, ok bool) {
+	key, value, ok = m.primary.Max()
+	sk, sv, sok := m.shadow.Max()
+	m.compare([]any{key, value, ok}, []any{sk, sv, sok}, "Max")
+	return key, value, ok
+}
+
+// Range calls f for the entries of the primary in [lo, hi). Afterwards, it
+// compares them with the same number of entries of the shadow, or with all
+// of the shadow's entries in [lo, hi) if f did not stop the iteration.
+func (m *Shadow[K, V]) Range(lo, hi K, f func(K, V) bool) {
+	var p []any
+	stopped := false
+	m.primary.Range(lo, hi, func(k K, v V) bool {
+		p = append(p, k, v)
+		stopped = !f(k, v)
+		return !stopped
+	})
+	var s []any
+	m.shadow.Range(lo, hi, func(k K, v V) bool {
+		s = append(s, k, v)
+		return !stopped || len(s) < len(p)
+	})
+	m.compare(p, s, "Range", lo, hi)
+}
+
+// Len returns the number of keys in the primary.
+func (m *Shadow[K, V]) Len() int {
+	n := m.primary.Len()
+	m.compare([]any{n}, []any{m.shadow.Len()}, "Len")
+	return n
+}
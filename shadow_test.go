@@ -0,0 +1,67 @@
+package generictree_test
+
+import (
+	"testing"
+
+	"github.com/appliedgo/generictree"
+	"github.com/appliedgo/generictree/conformance"
+	"github.com/appliedgo/generictree/rbtree"
+)
+
+func TestShadow_SortedMap(t *testing.T) {
+	conformance.Equivalent(t, map[string]func() conformance.Map{
+		"avl": newSortedMap,
+		"shadow": func() conformance.Map {
+			return generictree.NewShadow(newSortedMap(), rbtree.New[int, string](), func(d generictree.Divergence) {
+				t.Error(d)
+			})
+		},
+	})
+}
+
+// offByOne is a faulty backend that loses the entries of key 13.
+type offByOne struct{ conformance.Map }
+
+func (m offByOne) Set(key int, value string) {
+	if key != 13 {
+		m.Map.Set(key, value)
+	}
+}
+
+func TestShadow_divergence(t *testing.T) {
+	var ds []generictree.Divergence
+	m := generictree.NewShadow(newSortedMap(), offByOne{newSortedMap()}, func(d generictree.Divergence) {
+		ds = append(ds, d)
+	})
+	for k := 10; k < 20; k++ {
+		m.Set(k, "v")
+	}
+	if v, ok := m.Get(13); !ok || v != "v" {
+		t.Errorf("Get(13) = %q, %v; want the result of the primary", v, ok)
+	}
+	m.Get(14)
+	m.Range(12, 15, func(int, string) bool { return true })
+	var first []int
+	m.Range(10, 20, func(k int, _ string) bool {
+		first = append(first, k)
+		return len(first) < 2
+	})
+	m.Len()
+
+	want := []generictree.Divergence{
+		{Op: "Get(13)", Primary: `"v", true`, Shadow: `"", false`},
+		{Op: "Range(12, 15)", Primary: `12, "v", 13, "v", 14, "v"`, Shadow: `12, "v", 14, "v"`},
+		{Op: "Len()", Primary: "10", Shadow: "9"},
+	}
+	if len(ds) != len(want) {
+		t.Fatalf("reported %v, want %v", ds, want)
+	}
+	for i := range want {
+		if ds[i] != want[i] {
+			t.Errorf("divergence %d = %+v, want %+v", i, ds[i], want[i])
+		}
+	}
+	if got, want := ds[0].Error(), `generictree: Get(13) = "v", true, but the shadow returned "", false`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
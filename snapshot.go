@@ -0,0 +1,147 @@
+package generictree
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// A Compressor compresses snapshots written by WriteSnapshot and
+// decompresses them in ReadSnapshot. Gzip is built in; other formats, such
+// as zstd, can be plugged in by implementing Compressor around a
+// third-party package.
+type Compressor interface {
+	// NewWriter returns a writer that compresses to w. Closing it must
+	// flush all data but not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader that decompresses r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Gzip is a Compressor for the gzip format, using compress/gzip.
+var Gzip Compressor = gzipCompressor{}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// SnapshotOption configures WriteSnapshot and ReadSnapshot.
+type SnapshotOption func(*snapshotConfig)
+
+type snapshotConfig struct {
+	compressor Compressor
+	aead       cipher.AEAD
+}
+
+// WithCompression compresses snapshots with c. ReadSnapshot must use the
+// same compressor as WriteSnapshot.
+func WithCompression(c Compressor) SnapshotOption {
+	return func(s *snapshotConfig) { s.compressor = c }
+}
+
+// WithEncryption encrypts and authenticates snapshots with aead, for
+// example AES-GCM from crypto/cipher. WriteSnapshot seals the snapshot
+// with a random nonce, after compressing it, and ReadSnapshot rejects
+// snapshots that were not sealed with the same key or were modified.
+//
+// An encrypted snapshot starts with a header that identifies the format
+// and the nonce; the header is authenticated along with the snapshot.
+func WithEncryption(aead cipher.AEAD) SnapshotOption {
+	return func(s *snapshotConfig) { s.aead = aead }
+}
+
+// encryptedMagic starts the header of an encrypted snapshot.
+const encryptedMagic = "GTE\x01"
+
+// ErrDecrypt is returned by ReadSnapshot if an encrypted snapshot cannot
+// be decrypted or fails authentication.
+var ErrDecrypt = errors.New("generictree: cannot decrypt snapshot")
+
+// WriteSnapshot writes the entries of the tree to w in the JSON format of
+// MarshalJSON, compressed and encrypted if the options say so. Like the
+// JSON, an unencrypted snapshot does not depend on the shape of the tree,
+// provided that the compressor is deterministic, as Gzip is. Encryption
+// uses a random nonce, so encrypted snapshots always differ.
+//
+// Being JSON, snapshots encode values and data with encoding/json and not
+// with the codecs of CodecFor.
+func (t *Tree[Value, Data]) WriteSnapshot(w io.Writer, opts ...SnapshotOption) error {
+	var cfg snapshotConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	b, err := t.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if cfg.compressor != nil {
+		var buf bytes.Buffer
+		cw, err := cfg.compressor.NewWriter(&buf)
+		if err != nil {
+			return err
+		}
+		if _, err := cw.Write(b); err != nil {
+			cw.Close()
+			return err
+		}
+		if err := cw.Close(); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+	if cfg.aead != nil {
+		header := make([]byte, len(encryptedMagic)+cfg.aead.NonceSize())
+		copy(header, encryptedMagic)
+		if _, err := rand.Read(header[len(encryptedMagic):]); err != nil {
+			return err
+		}
+		// Seal into a new buffer: header is also the additional data,
+		// which must not overlap the output.
+		b = append(header, cfg.aead.Seal(nil, header[len(encryptedMagic):], b, header)...)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadSnapshot replaces the contents of the tree with a snapshot written by
+// WriteSnapshot with the same options. See UnmarshalJSON for the details.
+func (t *Tree[Value, Data]) ReadSnapshot(r io.Reader, opts ...SnapshotOption) error {
+	var cfg snapshotConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if cfg.aead != nil {
+		n := len(encryptedMagic) + cfg.aead.NonceSize()
+		if len(b) < n || string(b[:len(encryptedMagic)]) != encryptedMagic {
+			return ErrDecrypt
+		}
+		header := b[:n]
+		if b, err = cfg.aead.Open(nil, header[len(encryptedMagic):], b[n:], header); err != nil {
+			return ErrDecrypt
+		}
+	}
+	if cfg.compressor != nil {
+		cr, err := cfg.compressor.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		defer cr.Close()
+		if b, err = io.ReadAll(cr); err != nil {
+			return err
+		}
+	}
+	return t.UnmarshalJSON(b)
+}
@@ -0,0 +1,119 @@
+package generictree
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+// flateCompressor shows how to plug in another format.
+type flateCompressor struct{}
+
+func (flateCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.BestCompression)
+}
+
+func (flateCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+
+func TestTree_Snapshot(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for i := range 1000 {
+		tree.Insert(i, fmt.Sprintf("entry number %d", i))
+	}
+	var plain bytes.Buffer
+	if err := tree.WriteSnapshot(&plain); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []Compressor{Gzip, flateCompressor{}} {
+		var b bytes.Buffer
+		if err := tree.WriteSnapshot(&b, WithCompression(c)); err != nil {
+			t.Fatal(err)
+		}
+		if b.Len()*4 > plain.Len() {
+			t.Errorf("%T: compressed to %d bytes from %d", c, b.Len(), plain.Len())
+		}
+		var loaded Tree[int, string]
+		if err := loaded.ReadSnapshot(&b, WithCompression(c)); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(loaded.values(), tree.values()) {
+			t.Errorf("%T: loaded %d entries, want %d", c, loaded.Len(), tree.Len())
+		}
+	}
+
+	// A tree with the same entries but another shape has the same snapshot.
+	skewed := New[int, string](WithNoBalance())
+	for i := 999; i >= 0; i-- {
+		skewed.Insert(i, fmt.Sprintf("entry number %d", i))
+	}
+	var b1, b2 bytes.Buffer
+	tree.WriteSnapshot(&b1, WithCompression(Gzip))
+	skewed.WriteSnapshot(&b2, WithCompression(Gzip))
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Error("snapshots of trees with equal entries differ")
+	}
+
+	var loaded Tree[int, string]
+	if err := loaded.ReadSnapshot(&plain, WithCompression(Gzip)); err == nil {
+		t.Error("reading an uncompressed snapshot as gzip succeeded")
+	}
+}
+
+func newGCM(t *testing.T, key string) cipher.AEAD {
+	t.Helper()
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead
+}
+
+func TestTree_Snapshot_encryption(t *testing.T) {
+	tree := &Tree[string, int]{}
+	for i := range 100 {
+		tree.Insert(fmt.Sprintf("secret-%d", i), i)
+	}
+	aead := newGCM(t, "0123456789abcdef")
+	var b bytes.Buffer
+	if err := tree.WriteSnapshot(&b, WithCompression(Gzip), WithEncryption(aead)); err != nil {
+		t.Fatal(err)
+	}
+	sealed := b.Bytes()
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Error("the snapshot contains plaintext")
+	}
+
+	var loaded Tree[string, int]
+	if err := loaded.ReadSnapshot(bytes.NewReader(sealed), WithCompression(Gzip), WithEncryption(aead)); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.values(), tree.values()) {
+		t.Errorf("loaded %d entries, want %d", loaded.Len(), tree.Len())
+	}
+
+	if err := loaded.ReadSnapshot(bytes.NewReader(sealed), WithCompression(Gzip), WithEncryption(newGCM(t, "fedcba9876543210"))); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: got %v, want ErrDecrypt", err)
+	}
+	for _, i := range []int{0, 5, len(sealed) - 1} {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 1
+		if err := loaded.ReadSnapshot(bytes.NewReader(tampered), WithCompression(Gzip), WithEncryption(aead)); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("byte %d modified: got %v, want ErrDecrypt", i, err)
+		}
+	}
+	if loaded.Len() != tree.Len() {
+		t.Error("a failed ReadSnapshot changed the tree")
+	}
+}
@@ -0,0 +1,139 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// SortedMap is the common interface of all ordered map implementations in
+// this module: the AVL tree in this package, and the alternative backends in
+// the subpackages rbtree and btree. Switching backends is a one-line change
+// for code that only uses this interface.
+//
+// Get, Min, and Max must not allocate. Implementations outside this module
+// can check their behavior, including this, with the test suite in the
+// subpackage conformance.
+type SortedMap[K, V any] interface {
+	// Get returns the value stored for key and true,
+	// or the zero value of V and false if key is not in the map.
+	Get(key K) (V, bool)
+	// Set stores value for key, replacing any previous value.
+	Set(key K, value V)
+	// Delete removes key and returns its value and true,
+	// or the zero value of V and false if key is not in the map.
+	Delete(key K) (V, bool)
+	// Min returns the smallest key and its value.
+	// ok is false if the map is empty.
+	Min() (key K, value V, ok bool)
+	// Max returns the largest key and its value.
+	// ok is false if the map is empty.
+	Max() (key K, value V, ok bool)
+	// Range calls f for each key in the half-open interval [lo, hi),
+	// in ascending order, until f returns false.
+	Range(lo, hi K, f func(K, V) bool)
+	// Len returns the number of keys in the map.
+	Len() int
+}
+
+var _ SortedMap[int, string] = (*Tree[int, string])(nil)
+
+// Get is the SortedMap name of Find.
+func (t *Tree[Value, Data]) Get(value Value) (Data, bool) {
+	return t.Find(value)
+}
+
+// Set is the SortedMap name of Insert.
+func (t *Tree[Value, Data]) Set(value Value, data Data) {
+	t.Insert(value, data)
+}
+
+// BulkLoader is implemented by the SortedMap backends that can fill
+// themselves from sorted keys faster than by calling Set for each key. The
+// AVL tree of this package and the backends in the subpackages rbtree and
+// btree implement it.
+type BulkLoader[K, V any] interface {
+	// LoadSorted replaces the contents of the map with the keys and their
+	// values. The keys must be in strictly ascending order, and there must
+	// be one value per key; otherwise, LoadSorted panics.
+	LoadSorted(keys []K, values []V)
+}
+
+var _ BulkLoader[int, string] = (*Tree[int, string])(nil)
+
+// LoadSorted replaces the contents of the tree with a perfectly balanced
+// tree of the values and their data, like UnmarshalJSON. It panics if the
+// values are not in tree order, if the tree does not allow duplicates and
+// a value repeats, or if the lengths of values and data differ.
+func (t *Tree[Value, Data]) LoadSorted(values []Value, data []Data) {
+	if len(values) != len(data) {
+		panic(fmt.Sprintf("generictree: LoadSorted: %d values but %d data", len(values), len(data)))
+	}
+	err := t.replaceSorted(len(values), func(i int) (Value, Data) { return values[i], data[i] })
+	if err != nil {
+		panic("generictree: LoadSorted: " + err.Error())
+	}
+	t.debug.record(t, "LoadSorted", len(values))
+}
+
+// Convert copies the entries of src into the map that dstFactory returns
+// and returns that map, for example to move a map to another backend at
+// runtime:
+//
+//	m = generictree.Convert(m, func() generictree.SortedMap[K, V] { return btree.New[K, V](0) })
+//
+// Convert reads src in its own order. If the new map is empty and
+// implements BulkLoader, Convert fills it with a single call of LoadSorted;
+// otherwise, it calls Set for each entry. src is left unchanged.
+//
+// The two maps need not use the same order, for example if src is a Tree
+// created with WithDescending: before bulk loading, Convert sorts the
+// entries into the order of the new map, which is the tree's order for a
+// Tree and ascending order of cmp.Compare for other maps. Of several
+// entries with equal keys, only the last one is loaded, as with Set,
+// unless the new map is a Tree that allows duplicates.
+func Convert[K cmp.Ordered, V any](src SortedMap[K, V], dstFactory func() SortedMap[K, V]) SortedMap[K, V] {
+	entries := make([]KV[K, V], 0, src.Len())
+	collect := func(k K, v V) bool {
+		entries = append(entries, KV[K, V]{k, v})
+		return true
+	}
+	if all, ok := src.(interface{ All() iter.Seq2[K, V] }); ok {
+		all.All()(collect)
+	} else if lo, _, ok := src.Min(); ok {
+		// Range excludes its upper bound, so add the maximum on its own.
+		hi, v, _ := src.Max()
+		src.Range(lo, hi, collect)
+		collect(hi, v)
+	}
+
+	dst := dstFactory()
+	l, ok := dst.(BulkLoader[K, V])
+	if !ok || dst.Len() > 0 {
+		for _, e := range entries {
+			dst.Set(e.Value, e.Data)
+		}
+		return dst
+	}
+
+	compare, unique := cmp.Compare[K], true
+	if t, ok := dst.(*Tree[K, V]); ok {
+		compare, unique = t.compare, !t.allowDuplicates()
+	}
+	byKey := func(a, b KV[K, V]) int { return compare(a.Value, b.Value) }
+	if !slices.IsSortedFunc(entries, byKey) {
+		slices.SortStableFunc(entries, byKey)
+	}
+	keys := make([]K, 0, len(entries))
+	values := make([]V, 0, len(entries))
+	for i, e := range entries {
+		if unique && i+1 < len(entries) && compare(e.Value, entries[i+1].Value) == 0 {
+			continue // a later entry replaces this one
+		}
+		keys = append(keys, e.Value)
+		values = append(values, e.Data)
+	}
+	l.LoadSorted(keys, values)
+	return dst
+}
@@ -0,0 +1,154 @@
+package generictree_test
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+	"github.com/appliedgo/generictree/btree"
+	"github.com/appliedgo/generictree/conformance"
+	"github.com/appliedgo/generictree/rbtree"
+)
+
+func newSortedMap() conformance.Map { return &generictree.Tree[int, string]{} }
+
+func TestTree_SortedMap(t *testing.T) {
+	conformance.Run(t, newSortedMap)
+}
+
+func BenchmarkTree(b *testing.B) {
+	conformance.Benchmark(b, newSortedMap)
+}
+
+func TestBackends_Equivalent(t *testing.T) {
+	conformance.Equivalent(t, map[string]func() conformance.Map{
+		"avl":    newSortedMap,
+		"rbtree": func() conformance.Map { return rbtree.New[int, string]() },
+		"btree":  func() conformance.Map { return btree.New[int, string](3) },
+	})
+}
+
+func TestConvert(t *testing.T) {
+	backends := map[string]func() generictree.SortedMap[int, string]{
+		"avl":    func() generictree.SortedMap[int, string] { return generictree.New[int, string]() },
+		"rbtree": func() generictree.SortedMap[int, string] { return rbtree.New[int, string]() },
+		"btree":  func() generictree.SortedMap[int, string] { return btree.New[int, string](3) },
+		// Shadow is no BulkLoader, so Convert calls Set.
+		"shadow": func() generictree.SortedMap[int, string] {
+			return generictree.NewShadow(newSortedMap(), rbtree.New[int, string](), func(d generictree.Divergence) { t.Error(d) })
+		},
+	}
+	for _, n := range []int{0, 1, 500} {
+		for from, newSrc := range backends {
+			src := newSrc()
+			for k := range n {
+				src.Set(k*7%n, strconv.Itoa(k*7%n))
+			}
+			want := keys(src)
+			for to, newDst := range backends {
+				dst := generictree.Convert(src, newDst)
+				if got := keys(dst); !slices.Equal(got, want) {
+					t.Errorf("%s to %s, %d keys: got %v, want %v", from, to, n, got, want)
+				}
+				if v, ok := dst.Get(n / 2); n > 0 && (!ok || v != strconv.Itoa(n/2)) {
+					t.Errorf("%s to %s: Get(%d) = %q, %v", from, to, n/2, v, ok)
+				}
+				if v, ok := dst.(interface{ Validate() error }); ok {
+					if err := v.Validate(); err != nil {
+						t.Errorf("%s to %s: %v", from, to, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConvert_orders(t *testing.T) {
+	newTree := func(opts ...generictree.Option) func() generictree.SortedMap[int, string] {
+		return func() generictree.SortedMap[int, string] { return generictree.New[int, string](opts...) }
+	}
+	byLastDigit := func(a, b int) int { return cmp.Or(cmp.Compare(a%10, b%10), cmp.Compare(a, b)) }
+	orders := map[string]func() generictree.SortedMap[int, string]{
+		"ascending":  newTree(),
+		"descending": newTree(generictree.WithDescending()),
+		"comparator": newTree(generictree.WithComparator(byLastDigit)),
+		"rbtree":     func() generictree.SortedMap[int, string] { return rbtree.New[int, string]() },
+		"btree":      func() generictree.SortedMap[int, string] { return btree.New[int, string](3) },
+	}
+	for from, newSrc := range orders {
+		src := newSrc()
+		for k := range 100 {
+			src.Set(k*37%100, strconv.Itoa(k*37%100))
+		}
+		for to, newDst := range orders {
+			dst := generictree.Convert(src, newDst)
+			if dst.Len() != 100 {
+				t.Errorf("%s to %s: Len() = %d, want 100", from, to, dst.Len())
+			}
+			if v, ok := dst.Get(42); !ok || v != "42" {
+				t.Errorf("%s to %s: Get(42) = %q, %v", from, to, v, ok)
+			}
+			if v, ok := dst.(interface{ Validate() error }); ok {
+				if err := v.Validate(); err != nil {
+					t.Errorf("%s to %s: %v", from, to, err)
+				}
+			}
+		}
+	}
+
+	// Of equal keys, the last one wins, as with Set.
+	dups := generictree.New[int, string](generictree.WithAllowDuplicates())
+	for i, k := range []int{1, 2, 2, 3} {
+		dups.Insert(k, strconv.Itoa(i))
+	}
+	for to, newDst := range map[string]func() generictree.SortedMap[int, string]{
+		"btree": orders["btree"],
+		"avl":   orders["ascending"],
+	} {
+		dst := generictree.Convert[int, string](dups, newDst)
+		if v, _ := dst.Get(2); dst.Len() != 3 || v != "2" {
+			t.Errorf("duplicates to %s: Len() = %d, Get(2) = %q, want 3 and \"2\"", to, dst.Len(), v)
+		}
+	}
+	kept := generictree.Convert[int, string](dups, newTree(generictree.WithAllowDuplicates()))
+	if kept.Len() != 4 {
+		t.Errorf("duplicates to a tree with duplicates: Len() = %d, want 4", kept.Len())
+	}
+}
+
+// keys returns the keys of m in ascending order.
+func keys(m generictree.SortedMap[int, string]) []int {
+	var ks []int
+	m.Range(math.MinInt, math.MaxInt, func(k int, _ string) bool {
+		ks = append(ks, k)
+		return true
+	})
+	return ks
+}
+
+func TestTree_LoadSorted(t *testing.T) {
+	tree := generictree.New[int, string]()
+	tree.LoadSorted([]int{1, 2, 3}, []string{"a", "b", "c"})
+	if err := tree.Validate(); err != nil || tree.Len() != 3 {
+		t.Fatalf("after LoadSorted: Len() = %d, Validate() = %v", tree.Len(), err)
+	}
+	for name, f := range map[string]func(){
+		"duplicate": func() { tree.LoadSorted([]int{1, 1}, []string{"", ""}) },
+		"lengths":   func() { tree.LoadSorted([]int{1, 2}, []string{""}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: LoadSorted did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+	if v, _ := tree.Get(2); v != "b" {
+		t.Errorf("a failed LoadSorted changed the tree: Get(2) = %q", v)
+	}
+}
@@ -0,0 +1,375 @@
+package generictree
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// The records that SpillTree stores for each value start with a flag byte,
+// followed by the encoded data of a live entry.
+const (
+	spillLive    byte = 0
+	spillDeleted byte = 1
+)
+
+const (
+	// spillNodeOverhead estimates the memory of an in-memory entry besides
+	// its value and data: the node with its pointers and counters.
+	spillNodeOverhead = 64
+	// spillMaxSegments is the number of segment files above which
+	// SpillTree merges them.
+	spillMaxSegments = 8
+)
+
+// SpillTree is an ordered map for data sets that do not fit into memory.
+// It keeps recent changes in an in-memory tree. When the estimated size of
+// that tree exceeds a byte budget, SpillTree writes its entries to a
+// segment file in the encoding of FrozenTree.Encode and maps the file into
+// memory read-only. The operating system then loads the pages of the
+// segments on demand and keeps the frequently used ones, the upper levels
+// of the binary searches in particular, in its cache, so a data set larger
+// than memory makes lookups slower instead of running out of memory.
+//
+// A lookup checks the in-memory tree first and then the segments from the
+// newest to the oldest. Deleting a value that may be in a segment stores a
+// marker in memory. When there are more than a few segments, SpillTree
+// merges them into one, streaming through temporary files, and drops the
+// deleted entries.
+//
+// A SpillTree is not safe for concurrent use.
+type SpillTree[Value cmp.Ordered, Data any] struct {
+	mem      *Tree[Value, []byte] // records
+	memSize  int                  // estimated bytes of mem
+	budget   int
+	dir      string
+	encode   func(Data) ([]byte, error)
+	decode   func([]byte) (Data, error)
+	opts     []Option
+	segments []*spillSegment[Value] // oldest first
+}
+
+// spillSegment is a segment file mapped into memory.
+type spillSegment[Value cmp.Ordered] struct {
+	path string
+	b    []byte
+	view *FrozenView[Value, []byte]
+}
+
+// NewSpillTree returns an empty SpillTree that keeps about budget bytes in
+// memory and writes segment files to the directory dir. encode and decode
+// convert the data to bytes and back, for example the methods of a Codec;
+// decode must copy the bytes it keeps, as they refer to a mapped file.
+// opts may only set the order of the tree (see WithComparator and
+// WithDescending). NewSpillTree panics if budget is less than 1.
+//
+// Segment files are temporary: Close removes them, and SpillTree cannot
+// open segments from an earlier run.
+func NewSpillTree[Value cmp.Ordered, Data any](dir string, budget int, encode func(Data) ([]byte, error), decode func([]byte) (Data, error), opts ...Option) *SpillTree[Value, Data] {
+	if budget < 1 {
+		panic(fmt.Sprintf("generictree: NewSpillTree: invalid budget %d", budget))
+	}
+	return &SpillTree[Value, Data]{
+		mem:    New[Value, []byte](opts...),
+		budget: budget,
+		dir:    dir,
+		encode: encode,
+		decode: decode,
+		opts:   opts,
+	}
+}
+
+// keySize estimates the memory of value.
+func (t *SpillTree[Value, Data]) keySize(value Value) int {
+	if kind, width := keyKind[Value](); kind != kindString {
+		return int(width)
+	}
+	return len(toString(value))
+}
+
+// put stores the record rec for value in memory.
+func (t *SpillTree[Value, Data]) put(value Value, rec []byte) {
+	if old, ok := t.mem.Find(value); ok {
+		t.memSize -= len(old)
+	} else {
+		t.memSize += spillNodeOverhead + t.keySize(value)
+	}
+	t.mem.Insert(value, rec)
+	t.memSize += len(rec)
+}
+
+// Set stores data for value, replacing any previous data. If the in-memory
+// entries exceed the budget, Set writes them to a new segment file.
+func (t *SpillTree[Value, Data]) Set(value Value, data Data) error {
+	enc, err := t.encode(data)
+	if err != nil {
+		return err
+	}
+	t.put(value, append([]byte{spillLive}, enc...))
+	return t.spillIfFull()
+}
+
+// Get returns the data stored for value and true, or the zero value of
+// Data and false if value is not in the tree. It returns an error if a
+// segment is corrupt or the data cannot be decoded.
+func (t *SpillTree[Value, Data]) Get(value Value) (Data, bool, error) {
+	var zero Data
+	rec, ok := t.mem.Find(value)
+	for i := len(t.segments) - 1; !ok && i >= 0; i-- {
+		var err error
+		if rec, ok, err = t.segments[i].view.Find(value); err != nil {
+			return zero, false, err
+		}
+	}
+	switch {
+	case !ok:
+		return zero, false, nil
+	case len(rec) == 0:
+		return zero, false, ErrFormat
+	case rec[0] == spillDeleted:
+		return zero, false, nil
+	}
+	d, err := t.decode(rec[1:])
+	return d, err == nil, err
+}
+
+// Delete removes value from the tree.
+func (t *SpillTree[Value, Data]) Delete(value Value) error {
+	if len(t.segments) == 0 {
+		if old, ok := t.mem.Delete(value); ok {
+			t.memSize -= spillNodeOverhead + t.keySize(value) + len(old)
+		}
+		return nil
+	}
+	t.put(value, []byte{spillDeleted})
+	return t.spillIfFull()
+}
+
+// All returns an iterator over the entries in the tree's order. All stops
+// early if a segment is corrupt or the data of an entry cannot be decoded;
+// Get reports such errors. The tree must not be modified during the
+// iteration.
+func (t *SpillTree[Value, Data]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		for v, rec := range t.merge(t.sources()) {
+			if len(rec) == 0 {
+				return
+			}
+			if rec[0] == spillDeleted {
+				continue
+			}
+			d, err := t.decode(rec[1:])
+			if err != nil || !yield(v, d) {
+				return
+			}
+		}
+	}
+}
+
+// MemSize returns the estimated number of bytes that the in-memory entries
+// take.
+func (t *SpillTree[Value, Data]) MemSize() int {
+	return t.memSize
+}
+
+// Segments returns the number of segment files.
+func (t *SpillTree[Value, Data]) Segments() int {
+	return len(t.segments)
+}
+
+func (t *SpillTree[Value, Data]) spillIfFull() error {
+	if t.memSize <= t.budget {
+		return nil
+	}
+	return t.Spill()
+}
+
+// Spill writes the in-memory entries to a new segment file and empties the
+// in-memory tree. Set and Delete call Spill when the budget is exceeded.
+func (t *SpillTree[Value, Data]) Spill() error {
+	if t.mem.Len() == 0 {
+		return nil
+	}
+	seg, err := t.writeSegment(t.mem.All())
+	if err != nil {
+		return err
+	}
+	t.segments = append(t.segments, seg)
+	t.mem = New[Value, []byte](t.opts...)
+	t.memSize = 0
+	if len(t.segments) > spillMaxSegments {
+		return t.Compact()
+	}
+	return nil
+}
+
+// Compact merges all segment files into one and drops the deleted entries,
+// which speeds up lookups and frees disk space. Spill calls Compact when
+// there are more than a few segments.
+func (t *SpillTree[Value, Data]) Compact() error {
+	if len(t.segments) == 0 {
+		return nil
+	}
+	sources := make([]iter.Seq2[Value, []byte], 0, len(t.segments))
+	for i := len(t.segments) - 1; i >= 0; i-- {
+		sources = append(sources, t.segments[i].view.All())
+	}
+	live := func(yield func(Value, []byte) bool) {
+		for v, rec := range t.merge(sources) {
+			if len(rec) > 0 && rec[0] == spillDeleted {
+				continue
+			}
+			if !yield(v, rec) {
+				return
+			}
+		}
+	}
+	seg, err := t.writeSegment(live)
+	if err != nil {
+		return err
+	}
+	old := t.segments
+	t.segments = nil
+	if seg != nil {
+		t.segments = append(t.segments, seg)
+	}
+	return closeSegments(old)
+}
+
+// Close removes the segment files and empties the tree.
+func (t *SpillTree[Value, Data]) Close() error {
+	err := closeSegments(t.segments)
+	t.segments = nil
+	t.mem = New[Value, []byte](t.opts...)
+	t.memSize = 0
+	return err
+}
+
+func closeSegments[Value cmp.Ordered](segments []*spillSegment[Value]) error {
+	var errs []error
+	for _, s := range segments {
+		errs = append(errs, unmapFile(s.b), os.Remove(s.path))
+	}
+	return errors.Join(errs...)
+}
+
+// sources returns iterators over the records of the in-memory tree and the
+// segments, the newest first.
+func (t *SpillTree[Value, Data]) sources() []iter.Seq2[Value, []byte] {
+	sources := []iter.Seq2[Value, []byte]{t.mem.All()}
+	for i := len(t.segments) - 1; i >= 0; i-- {
+		sources = append(sources, t.segments[i].view.All())
+	}
+	return sources
+}
+
+// merge returns an iterator over the records of sources in the tree's
+// order. sources must be ordered from the newest to the oldest; for values
+// in several sources, merge returns the newest record.
+func (t *SpillTree[Value, Data]) merge(sources []iter.Seq2[Value, []byte]) iter.Seq2[Value, []byte] {
+	return func(yield func(Value, []byte) bool) {
+		type head struct {
+			next  func() (Value, []byte, bool)
+			value Value
+			rec   []byte
+			ok    bool
+		}
+		heads := make([]head, len(sources))
+		for i, s := range sources {
+			next, stop := iter.Pull2(s)
+			defer stop()
+			heads[i].next = next
+			heads[i].value, heads[i].rec, heads[i].ok = next()
+		}
+		for {
+			best := -1
+			for i, h := range heads {
+				if h.ok && (best < 0 || t.mem.compare(h.value, heads[best].value) < 0) {
+					best = i
+				}
+			}
+			if best < 0 {
+				return
+			}
+			value, rec := heads[best].value, heads[best].rec
+			for i := range heads {
+				if h := &heads[i]; h.ok && t.mem.compare(h.value, value) == 0 {
+					h.value, h.rec, h.ok = h.next()
+				}
+			}
+			if !yield(value, rec) {
+				return
+			}
+		}
+	}
+}
+
+// writeSegment writes the records of entries, in the tree's order, to a
+// new segment file and maps it. It returns nil if there are no entries.
+// The sections of the encoding go through temporary files, so that a
+// segment can be larger than memory.
+func (t *SpillTree[Value, Data]) writeSegment(entries iter.Seq2[Value, []byte]) (seg *spillSegment[Value], err error) {
+	var sections []*os.File
+	defer func() {
+		for _, f := range sections {
+			err = errors.Join(err, f.Close(), os.Remove(f.Name()))
+		}
+	}()
+	for range 4 {
+		f, err := os.CreateTemp(t.dir, "spill-section-*")
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, f)
+	}
+	fw := newFrozenWriter[Value](sections[0], sections[1], sections[2], sections[3])
+	for v, rec := range entries {
+		if err := fw.add(v, rec); err != nil {
+			return nil, err
+		}
+	}
+	if fw.n == 0 {
+		return nil, nil
+	}
+	for _, f := range sections {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := os.CreateTemp(t.dir, "spill-*.gtf")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, out.Close())
+		if err != nil {
+			os.Remove(out.Name())
+		}
+	}()
+	w := bufio.NewWriter(out)
+	if err := fw.finish(w); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	info, err := out.Stat()
+	if err != nil {
+		return nil, err
+	}
+	b, err := mapFile(out, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	view, err := OpenFrozen[Value, []byte](b, func(rec []byte) ([]byte, error) { return rec, nil }, t.opts...)
+	if err != nil {
+		unmapFile(b)
+		return nil, err
+	}
+	return &spillSegment[Value]{path: out.Name(), b: b, view: view}, nil
+}
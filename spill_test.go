@@ -0,0 +1,111 @@
+package generictree
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"testing"
+)
+
+var intCodec = CodecFor[int]()
+
+func TestSpillTree(t *testing.T) {
+	dir := t.TempDir()
+	tree := NewSpillTree[string, int](dir, 2000, intCodec.Encode, intCodec.Decode)
+	want := map[string]int{}
+	rng := rand.New(rand.NewPCG(1, 2))
+	spilled := false
+	for i := range 5000 {
+		k := fmt.Sprintf("key%04d", rng.IntN(1000))
+		if rng.IntN(3) == 0 {
+			if err := tree.Delete(k); err != nil {
+				t.Fatal(err)
+			}
+			delete(want, k)
+		} else {
+			if err := tree.Set(k, i); err != nil {
+				t.Fatal(err)
+			}
+			want[k] = i
+		}
+		if tree.MemSize() > 2000 {
+			t.Fatalf("MemSize %d exceeds the budget", tree.MemSize())
+		}
+		spilled = spilled || tree.Segments() > 0
+	}
+	if !spilled {
+		t.Fatal("tree never spilled")
+	}
+	if tree.Segments() > spillMaxSegments {
+		t.Errorf("%d segments, want at most %d", tree.Segments(), spillMaxSegments)
+	}
+
+	check := func(stage string) {
+		t.Helper()
+		for i := range 1000 {
+			k := fmt.Sprintf("key%04d", i)
+			d, ok, err := tree.Get(k)
+			wd, wok := want[k]
+			if err != nil || ok != wok || d != wd {
+				t.Fatalf("%s: Get(%s) = %d, %v, %v, want %d, %v", stage, k, d, ok, err, wd, wok)
+			}
+		}
+		var keys []string
+		for k, d := range tree.All() {
+			if want[k] != d {
+				t.Fatalf("%s: All: %s = %d, want %d", stage, k, d, want[k])
+			}
+			keys = append(keys, k)
+		}
+		if wantKeys := slices.Sorted(maps.Keys(want)); !slices.Equal(keys, wantKeys) {
+			t.Fatalf("%s: All returned %d keys, want %d", stage, len(keys), len(wantKeys))
+		}
+	}
+	check("mixed")
+
+	if err := tree.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Segments() > 1 {
+		t.Errorf("%d segments after Compact", tree.Segments())
+	}
+	check("compacted")
+
+	if err := tree.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) > 0 {
+		t.Errorf("Close left %d files", len(files))
+	}
+	if _, ok, _ := tree.Get("key0001"); ok {
+		t.Error("tree not empty after Close")
+	}
+}
+
+func TestSpillTree_descending(t *testing.T) {
+	tree := NewSpillTree[int, int](t.TempDir(), 500, intCodec.Encode, intCodec.Decode, WithDescending())
+	defer tree.Close()
+	for i := range 100 {
+		if err := tree.Set(i, -i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Spill(); err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for v, d := range tree.All() {
+		if d != -v {
+			t.Fatalf("All: %d = %d", v, d)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 100 || got[0] != 99 || got[99] != 0 {
+		t.Errorf("All = %v..., want 99 down to 0", got[:3])
+	}
+	if d, ok, err := tree.Get(42); err != nil || !ok || d != -42 {
+		t.Errorf("Get(42) = %d, %v, %v", d, ok, err)
+	}
+}
@@ -0,0 +1,159 @@
+package generictree
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Stats describes the shape of a tree. The Sackin and Colless indices are
+// standard measures of the imbalance of a tree's shape; both grow with the
+// imbalance and are O(n log n) for balanced trees.
+type Stats struct {
+	Len           int     // number of entries
+	Height        int     // height of the tree; 0 for an empty tree
+	OptimalHeight int     // smallest possible height for Len entries
+	AVLMaxHeight  int     // largest possible height of an AVL tree with Len entries (see WithRelaxedBalance)
+	Leaves        int     // number of nodes without children
+	AvgDepth      float64 // average depth of all nodes; the root has depth 1
+	Sackin        int     // Sackin index: sum of the depths of all leaves, counting the root as depth 0
+	Colless       int     // Colless index: sum of |leaves left - leaves right| over all nodes
+	Interned      int     // distinct strings shared by interning (see WithInterning)
+	InternSaved   int     // bytes that interning saved
+	CacheHits     int     // lookups served by the front cache (see WithFrontCache)
+	CacheMisses   int     // lookups that missed the front cache
+}
+
+// checkHeight calls the height warning (see WithHeightWarning) when the
+// tree has become too high, and rearms it when the tree is low enough.
+func (t *Tree[Value, Data]) checkHeight() {
+	if t.cfg == nil || t.cfg.heightWarn == nil {
+		return
+	}
+	limit := t.cfg.heightFactor * math.Log2(float64(t.Len()+1))
+	switch high := float64(t.Root.Height()) > limit; {
+	case high && !t.cfg.heightWarned:
+		t.cfg.heightWarned = true
+		t.cfg.heightWarn(t.Stats())
+	case !high:
+		t.cfg.heightWarned = false
+	}
+}
+
+// Stats walks the tree and returns statistics about its shape.
+func (t *Tree[Value, Data]) Stats() Stats {
+	s := Stats{
+		Len:           t.Len(),
+		Height:        t.Root.Height(),
+		OptimalHeight: bits.Len(uint(t.Len())),
+		AVLMaxHeight:  avlMaxHeight(t.Len()),
+	}
+	var totalDepth int
+	// walk returns the number of leaves of the subtree rooted at n.
+	var walk func(n *Node[Value, Data], depth int) int
+	walk = func(n *Node[Value, Data], depth int) int {
+		if n == nil {
+			return 0
+		}
+		totalDepth += depth
+		if n.Left == nil && n.Right == nil {
+			s.Leaves++
+			s.Sackin += depth - 1
+			return 1
+		}
+		l, r := walk(n.Left, depth+1), walk(n.Right, depth+1)
+		s.Colless += max(l-r, r-l)
+		return l + r
+	}
+	walk(t.Root, 1)
+	if s.Len > 0 {
+		s.AvgDepth = float64(totalDepth) / float64(s.Len)
+	}
+	if t.cfg != nil && t.cfg.interner != nil {
+		s.Interned, s.InternSaved = len(t.cfg.interner.strs), t.cfg.interner.saved
+	}
+	if t.cfg != nil && t.cfg.front != nil {
+		s.CacheHits, s.CacheMisses = t.cfg.front.hits, t.cfg.front.misses
+	}
+	return s
+}
+
+// avlMaxHeight returns the largest height of an AVL tree with n entries.
+// The smallest AVL tree of height h has N(h) = N(h-1) + N(h-2) + 1
+// entries, with N(0) = 0 and N(1) = 1.
+func avlMaxHeight(n int) int {
+	h, nh, prev := 0, 0, 0 // nh = N(h), prev = N(h-1)
+	for nh+prev+1 <= n {
+		h, nh, prev = h+1, nh+prev+1, nh
+	}
+	return h
+}
+
+// WorstPaths returns the paths to the k deepest leaves of the tree, the
+// deepest first and leaves of equal depth in tree order. Each path lists
+// the values from the root down to the leaf. WorstPaths helps to find the
+// parts of a tree that make it unbalanced, for example in a tree created
+// with WithNoBalance.
+func (t *Tree[Value, Data]) WorstPaths(k int) [][]Value {
+	type leaf struct {
+		n     *Node[Value, Data]
+		depth int
+	}
+	var leaves []leaf
+	var collect func(n *Node[Value, Data], depth int)
+	collect = func(n *Node[Value, Data], depth int) {
+		if n == nil {
+			return
+		}
+		collect(n.Left, depth+1)
+		if n.Left == nil && n.Right == nil {
+			leaves = append(leaves, leaf{n, depth})
+		}
+		collect(n.Right, depth+1)
+	}
+	collect(t.Root, 1)
+	slices.SortStableFunc(leaves, func(a, b leaf) int { return b.depth - a.depth })
+	leaves = leaves[:max(0, min(k, len(leaves)))]
+
+	rank := make(map[*Node[Value, Data]]int, len(leaves))
+	for i, l := range leaves {
+		rank[l.n] = i
+	}
+	paths := make([][]Value, len(leaves))
+	var stack []Value
+	var walk func(n *Node[Value, Data])
+	walk = func(n *Node[Value, Data]) {
+		if n == nil {
+			return
+		}
+		stack = append(stack, n.Value)
+		if i, ok := rank[n]; ok {
+			paths[i] = slices.Clone(stack)
+		}
+		walk(n.Left)
+		walk(n.Right)
+		stack = stack[:len(stack)-1]
+	}
+	if len(leaves) > 0 {
+		walk(t.Root)
+	}
+	return paths
+}
+
+// UnbalancedNodes returns an iterator over the values of the nodes whose
+// balance factor (the height of the right subtree minus the height of the
+// left subtree) is larger than threshold in absolute value, in tree order.
+// A balanced tree has no such nodes for threshold 1; in a tree created
+// with WithNoBalance, the values show where a partial rebuild would help.
+// The tree must not be modified during the iteration.
+func (t *Tree[Value, Data]) UnbalancedNodes(threshold int) iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		t.Root.ascend(func(n *Node[Value, Data]) bool {
+			if b := n.Bal(); b > threshold || -b > threshold {
+				return yield(n.Value)
+			}
+			return true
+		})
+	}
+}
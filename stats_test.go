@@ -0,0 +1,84 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTree_Stats(t *testing.T) {
+	tree := &Tree[int, int]{}
+	if got := tree.Stats(); got != (Stats{}) {
+		t.Errorf("empty tree: got %+v", got)
+	}
+	for v := range 7 {
+		tree.Insert(v, v)
+	}
+	// Inserting 0..6 in order yields a perfect tree of height 3.
+	want := Stats{Len: 7, Height: 3, OptimalHeight: 3, AVLMaxHeight: 4, Leaves: 4, AvgDepth: 17.0 / 7, Sackin: 8}
+	if got := tree.Stats(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAVLMaxHeight(t *testing.T) {
+	// The smallest AVL trees of heights 1 to 6 have 1, 2, 4, 7, 12, and 20
+	// entries.
+	for n, want := range map[int]int{0: 0, 1: 1, 2: 2, 3: 2, 4: 3, 6: 3, 7: 4, 11: 4, 12: 5, 19: 5, 20: 6} {
+		if got := avlMaxHeight(n); got != want {
+			t.Errorf("avlMaxHeight(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestTree_Stats_indices(t *testing.T) {
+	// Ascending values without balancing make a list of 5 nodes: one leaf
+	// at depth 4, and every inner node has 1 leaf on one side.
+	tree := New[int, int](WithNoBalance())
+	for v := range 5 {
+		tree.Insert(v, v)
+	}
+	if s := tree.Stats(); s.Sackin != 4 || s.Colless != 4 {
+		t.Errorf("Sackin, Colless = %d, %d, want 4, 4", s.Sackin, s.Colless)
+	}
+}
+
+func TestTree_WorstPaths(t *testing.T) {
+	tree := New[int, int](WithNoBalance())
+	for _, v := range []int{4, 2, 6, 1, 3, 7, 8} {
+		tree.Insert(v, v)
+	}
+	got := tree.WorstPaths(2)
+	want := [][]int{{4, 6, 7, 8}, {4, 2, 1}}
+	if len(got) != len(want) || !slices.Equal(got[0], want[0]) || !slices.Equal(got[1], want[1]) {
+		t.Errorf("WorstPaths(2) = %v, want %v", got, want)
+	}
+	if got := tree.WorstPaths(10); len(got) != 3 {
+		t.Errorf("WorstPaths(10) returned %d paths, want 3", len(got))
+	}
+	if got := New[int, int]().WorstPaths(3); len(got) != 0 {
+		t.Errorf("empty tree: %v", got)
+	}
+}
+
+func TestTree_UnbalancedNodes(t *testing.T) {
+	tree := New[int, int](WithNoBalance())
+	for _, v := range []int{4, 2, 6, 1, 3, 7, 8} {
+		tree.Insert(v, v)
+	}
+	if got, want := slices.Collect(tree.UnbalancedNodes(1)), []int{6}; !slices.Equal(got, want) {
+		t.Errorf("UnbalancedNodes(1) = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(tree.UnbalancedNodes(0)), []int{4, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("UnbalancedNodes(0) = %v, want %v", got, want)
+	}
+	for range tree.UnbalancedNodes(0) {
+		break // must not panic
+	}
+	balanced := New[int, int]()
+	for v := range 100 {
+		balanced.Insert(v, v)
+	}
+	if got := slices.Collect(balanced.UnbalancedNodes(1)); len(got) != 0 {
+		t.Errorf("balanced tree: %v", got)
+	}
+}
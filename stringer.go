@@ -0,0 +1,54 @@
+package generictree
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// String returns a one-line summary of the tree, such as
+//
+//	Tree[len=42 height=6 min=a max=z]
+//
+// Use Dump or PrettyPrint to see the whole tree.
+func (t *Tree[Value, Data]) String() string {
+	if t == nil || t.Root == nil {
+		return "Tree[len=0 height=0]"
+	}
+	lo, _, _ := t.Min()
+	hi, _, _ := t.Max()
+	return fmt.Sprintf("Tree[len=%d height=%d min=%s max=%s]", t.Len(), t.Root.Height(), t.FormatValue(lo), t.FormatValue(hi))
+}
+
+// FormatValue renders value for display, with the formatter set by
+// WithKeyFormatter or with fmt.Sprint.
+func (t *Tree[Value, Data]) FormatValue(value Value) string {
+	if t.cfg != nil && t.cfg.formatValue != nil {
+		return t.cfg.formatValue(value)
+	}
+	return fmt.Sprint(value)
+}
+
+// FormatData renders data for display, with the formatter set by
+// WithDataFormatter or with fmt.Sprint.
+func (t *Tree[Value, Data]) FormatData(data Data) string {
+	if t.cfg != nil && t.cfg.formatData != nil {
+		return t.cfg.formatData(data)
+	}
+	return fmt.Sprint(data)
+}
+
+// LogValue implements slog.LogValuer. It logs the same summary as String,
+// as a group of attributes.
+func (t *Tree[Value, Data]) LogValue() slog.Value {
+	if t == nil || t.Root == nil {
+		return slog.GroupValue(slog.Int("len", 0), slog.Int("height", 0))
+	}
+	lo, _, _ := t.Min()
+	hi, _, _ := t.Max()
+	return slog.GroupValue(
+		slog.Int("len", t.Len()),
+		slog.Int("height", t.Root.Height()),
+		slog.Any("min", lo),
+		slog.Any("max", hi),
+	)
+}
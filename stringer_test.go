@@ -0,0 +1,72 @@
+package generictree
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTree_String(t *testing.T) {
+	tree := &Tree[string, int]{}
+	if got, want := tree.String(), "Tree[len=0 height=0]"; got != want {
+		t.Errorf("empty tree: got %q, want %q", got, want)
+	}
+	for i, v := range []string{"m", "a", "z", "q"} {
+		tree.Insert(v, i)
+	}
+	if got, want := fmt.Sprint(tree), "Tree[len=4 height=3 min=a max=z]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	var nilTree *Tree[string, int]
+	if got, want := nilTree.String(), "Tree[len=0 height=0]"; got != want {
+		t.Errorf("nil tree: got %q, want %q", got, want)
+	}
+}
+
+func TestTree_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	tree := &Tree[int, string]{}
+	for i := 1; i <= 3; i++ {
+		tree.Insert(i, "")
+	}
+	logger.Info("loaded", "tree", tree)
+	want := "level=INFO msg=loaded tree.len=3 tree.height=2 tree.min=1 tree.max=3"
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWithFormatters(t *testing.T) {
+	tree := New[int, []byte](
+		WithKeyFormatter(func(v int) string { return fmt.Sprintf("#%03d", v) }),
+		WithDataFormatter(func(d []byte) string { return fmt.Sprintf("%x", d) }),
+	)
+	tree.Insert(7, []byte("hi"))
+	tree.Insert(42, nil)
+	if got, want := tree.String(), "Tree[len=2 height=2 min=#007 max=#042]"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := tree.FormatData([]byte("hi")); got != "6869" {
+		t.Errorf("FormatData = %q", got)
+	}
+	var buf bytes.Buffer
+	if err := tree.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `label="#042"`) {
+		t.Errorf("WriteDOT does not use the key formatter:\n%s", buf.String())
+	}
+	if got := (&Tree[int, int]{}).FormatValue(5); got != "5" {
+		t.Errorf("default FormatValue = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Prefix returns an iterator over the entries of t whose values start with
+// prefix, in the tree's order.
+//
+// In the default order, the matching values form a contiguous range, so
+// Prefix takes O(log n + k) time for k matches. Under a custom comparator
+// (see WithComparator and WithDescending), Prefix has to check every entry.
+func Prefix[Value ~string, Data any](t *Tree[Value, Data], prefix Value) iter.Seq2[Value, Data] {
+	match := func(v Value, _ Data) bool { return strings.HasPrefix(string(v), string(prefix)) }
+	if t.cfg != nil && t.cfg.compare != nil {
+		return func(yield func(Value, Data) bool) {
+			for v, d := range t.All() {
+				if match(v, d) && !yield(v, d) {
+					return
+				}
+			}
+		}
+	}
+	return TakeWhile(t.From(prefix), match)
+}
+
+// Suggest returns up to limit entries whose values start with prefix, in
+// the tree's order. If limit is zero or negative, Suggest returns all of
+// them.
+func Suggest[Value ~string, Data any](t *Tree[Value, Data], prefix Value, limit int) []KV[Value, Data] {
+	var result []KV[Value, Data]
+	for v, d := range Prefix(t, prefix) {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		result = append(result, KV[Value, Data]{v, d})
+	}
+	return result
+}
+
+// SuggestRanked is like Suggest but returns the limit entries with the
+// highest scores, as computed by score, highest first. Entries with equal
+// scores are in the tree's order.
+//
+// Unlike Suggest, SuggestRanked looks at every entry that starts with
+// prefix; it keeps only the best limit entries in memory. For k matches,
+// it takes O(k·limit) time, or O(k log k) if limit is zero or negative.
+func SuggestRanked[Value ~string, Data any, N Number](t *Tree[Value, Data], prefix Value, limit int, score func(Data) N) []KV[Value, Data] {
+	type ranked struct {
+		kv    KV[Value, Data]
+		score N
+	}
+	var best []ranked // in descending order of score
+	if limit <= 0 {
+		for v, d := range Prefix(t, prefix) {
+			best = append(best, ranked{KV[Value, Data]{v, d}, score(d)})
+		}
+		slices.SortStableFunc(best, func(a, b ranked) int { return cmp.Compare(b.score, a.score) })
+	} else {
+		for v, d := range Prefix(t, prefix) {
+			s := score(d)
+			if len(best) == limit && s <= best[len(best)-1].score {
+				continue
+			}
+			// Insert after all entries with the same or a higher score.
+			i := len(best)
+			for i > 0 && best[i-1].score < s {
+				i--
+			}
+			if len(best) < limit {
+				best = append(best, ranked{})
+			}
+			copy(best[i+1:], best[i:])
+			best[i] = ranked{KV[Value, Data]{v, d}, s}
+		}
+	}
+	var result []KV[Value, Data]
+	for _, r := range best {
+		result = append(result, r.kv)
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package generictree
+
+import (
+	"slices"
+	"testing"
+)
+
+type word struct {
+	freq int
+}
+
+func suggestTree(opts ...Option) *Tree[string, word] {
+	tree := New[string, word](opts...)
+	for w, f := range map[string]int{
+		"car": 5, "card": 9, "care": 2, "cart": 9, "cat": 7, "ca": 1, "dog": 3, "c": 4, "cb": 8,
+	} {
+		tree.Insert(w, word{f})
+	}
+	return tree
+}
+
+func suggestValues(kvs []KV[string, word]) []string {
+	var s []string
+	for _, kv := range kvs {
+		s = append(s, kv.Value)
+	}
+	return s
+}
+
+func TestSuggest(t *testing.T) {
+	tree := suggestTree()
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"car", 0, []string{"car", "card", "care", "cart"}},
+		{"car", 2, []string{"car", "card"}},
+		{"ca", 3, []string{"ca", "car", "card"}},
+		{"x", 5, nil},
+		{"", 2, []string{"c", "ca"}},
+	}
+	for _, tt := range tests {
+		got := suggestValues(Suggest(tree, tt.prefix, tt.limit))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q, %d): got %v, want %v", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+
+	desc := suggestValues(Suggest(suggestTree(WithDescending()), "car", 3))
+	if want := []string{"cart", "care", "card"}; !slices.Equal(desc, want) {
+		t.Errorf("Suggest in descending order: got %v, want %v", desc, want)
+	}
+}
+
+func TestSuggestRanked(t *testing.T) {
+	tree := suggestTree()
+	freq := func(w word) int { return w.freq }
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"ca", 3, []string{"card", "cart", "cat"}},
+		{"ca", 0, []string{"card", "cart", "cat", "car", "care", "ca"}},
+		{"car", 1, []string{"card"}},
+		{"x", 3, nil},
+	}
+	for _, tt := range tests {
+		got := suggestValues(SuggestRanked(tree, tt.prefix, tt.limit, freq))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SuggestRanked(%q, %d): got %v, want %v", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"sync"
+	"unsafe"
+)
+
+// SyncTree wraps a Tree with a read-write mutex, making it safe for
+// concurrent use by multiple goroutines. The zero value is an empty tree
+// ready to use.
+type SyncTree[Value cmp.Ordered, Data any] struct {
+	mu      sync.RWMutex
+	stripes []paddedRWMutex // see WithReadStripes; nil means mu
+	tree    Tree[Value, Data]
+	writes  bool // lookups modify the tree, so readers take the write lock
+}
+
+// paddedRWMutex fills a cache line, so that readers of neighboring stripes
+// do not contend for the same line.
+type paddedRWMutex struct {
+	sync.RWMutex
+	_ [64 - unsafe.Sizeof(sync.RWMutex{})%64]byte
+}
+
+// NewSyncTree returns an empty SyncTree whose tree is created with the
+// given options. WithReadStripes configures the locking of the SyncTree
+// itself.
+//
+// Some options make lookups modify the tree: WithCounters and WithTracer
+// count them, WithFrontCache and WithAccessCounts record them, and
+// WithMaxSize with EvictLRU updates the access order. With any of these,
+// the SyncTree write-locks the tree for reads as well, so that concurrent
+// readers no longer run in parallel.
+func NewSyncTree[Value cmp.Ordered, Data any](opts ...Option) *SyncTree[Value, Data] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := &SyncTree[Value, Data]{tree: *New[Value, Data](opts...)}
+	if o.readStripes > 1 {
+		s.stripes = make([]paddedRWMutex, o.readStripes)
+	}
+	cfg := s.tree.cfg
+	s.writes = cfg.tracing != nil || cfg.front != nil || cfg.access != nil || cfg.lru != nil
+	return s
+}
+
+// rlock read-locks s and returns the stripe to pass to runlock. If lookups
+// modify the tree, rlock write-locks it instead and returns -1.
+func (s *SyncTree[Value, Data]) rlock() int {
+	if s.writes {
+		s.lock()
+		return -1
+	}
+	if s.stripes == nil {
+		s.mu.RLock()
+		return 0
+	}
+	i := rand.IntN(len(s.stripes))
+	s.stripes[i].RLock()
+	return i
+}
+
+func (s *SyncTree[Value, Data]) runlock(i int) {
+	if i < 0 {
+		s.unlock()
+		return
+	}
+	if s.stripes == nil {
+		s.mu.RUnlock()
+		return
+	}
+	s.stripes[i].RUnlock()
+}
+
+// lock write-locks s. With stripes, it locks all of them, always in the
+// same order.
+func (s *SyncTree[Value, Data]) lock() {
+	if s.stripes == nil {
+		s.mu.Lock()
+		return
+	}
+	for i := range s.stripes {
+		s.stripes[i].Lock()
+	}
+}
+
+func (s *SyncTree[Value, Data]) unlock() {
+	if s.stripes == nil {
+		s.mu.Unlock()
+		return
+	}
+	for i := range s.stripes {
+		s.stripes[i].Unlock()
+	}
+}
+
+// Insert adds value and data to the tree, or replaces the data if value
+// already exists.
+func (s *SyncTree[Value, Data]) Insert(value Value, data Data) {
+	s.lock()
+	defer s.unlock()
+	s.tree.Insert(value, data)
+}
+
+// Find returns the data stored for value and true, or the zero value of Data
+// and false if value is not in the tree.
+func (s *SyncTree[Value, Data]) Find(value Value) (Data, bool) {
+	defer s.runlock(s.rlock())
+	return s.tree.Find(value)
+}
+
+// Delete removes value from the tree. It returns the data that was stored
+// for value and true, or the zero value of Data and false if value is not
+// in the tree.
+func (s *SyncTree[Value, Data]) Delete(value Value) (Data, bool) {
+	s.lock()
+	defer s.unlock()
+	return s.tree.Delete(value)
+}
+
+// Len returns the number of entries in the tree.
+func (s *SyncTree[Value, Data]) Len() int {
+	defer s.runlock(s.rlock())
+	return s.tree.Len()
+}
+
+// Range calls f for each entry whose value lies in the half-open interval
+// [lo, hi), in tree order, and stops early if f returns false. Like
+// Traverse, it read-locks the tree during the whole call; f must not
+// modify s.
+func (s *SyncTree[Value, Data]) Range(lo, hi Value, f func(Value, Data) bool) {
+	defer s.runlock(s.rlock())
+	s.tree.Range(lo, hi, f)
+}
+
+// Traverse calls f for each entry in tree order.
+// The tree is read-locked during the whole traversal; f must not modify s.
+func (s *SyncTree[Value, Data]) Traverse(f func(Value, Data)) {
+	defer s.runlock(s.rlock())
+	for v, d := range s.tree.All() {
+		f(v, d)
+	}
+}
+
+// leaseSize is the number of entries that a SyncIter reads per read lock.
+const leaseSize = 64
+
+// SyncIter iterates over the entries of a SyncTree without holding the read
+// lock while the caller processes the entries, so that slow processing does
+// not block writers. It reads the entries in short leases: for each batch of
+// entries, it read-locks the tree, searches the position after the last
+// entry read, copies the next entries, and unlocks the tree.
+//
+// The iteration is weakly consistent. It returns each entry at most once
+// and in tree order; entries inserted or deleted during the
+// iteration are seen if they come after the last batch read. With
+// duplicates, it relies on the number of equal entries read so far, so
+// concurrent insertions or deletions of equal entries may shift it.
+type SyncIter[Value cmp.Ordered, Data any] struct {
+	s     *SyncTree[Value, Data]
+	batch []KV[Value, Data]
+	i     int    // the index of the current entry in batch, plus 1
+	last  *Value // the value of the last entry read, nil before the first
+	dups  int    // the number of entries equal to *last read so far
+	done  bool
+}
+
+// Iter returns an iterator positioned before the first entry. Call Next
+// to advance it.
+func (s *SyncTree[Value, Data]) Iter() *SyncIter[Value, Data] {
+	return &SyncIter[Value, Data]{s: s}
+}
+
+// Next advances the iterator to the next entry and reports whether there is
+// one. It read-locks the tree only if it has to read a new batch.
+func (it *SyncIter[Value, Data]) Next() bool {
+	if it.i < len(it.batch) {
+		it.i++
+		return true
+	}
+	if it.done {
+		return false
+	}
+	it.fill()
+	if len(it.batch) == 0 {
+		it.done = true
+		return false
+	}
+	it.i = 1
+	return true
+}
+
+// fill reads the next batch of entries under a read lock.
+func (it *SyncIter[Value, Data]) fill() {
+	s := it.s
+	defer s.runlock(s.rlock())
+	t := &s.tree
+	it.batch = it.batch[:0]
+	c := t.Cursor()
+	for ok := t.seekAfter(c, it.last, it.dups); ok && len(it.batch) < leaseSize; ok = c.Next() {
+		v := c.Value()
+		it.batch = append(it.batch, KV[Value, Data]{v, c.Data()})
+		if it.last != nil && t.compare(*it.last, v) == 0 {
+			it.dups++
+		} else {
+			it.last, it.dups = &v, 1
+		}
+	}
+}
+
+// Value returns the value of the current entry.
+func (it *SyncIter[Value, Data]) Value() Value {
+	return it.batch[it.i-1].Value
+}
+
+// Data returns the data of the current entry.
+func (it *SyncIter[Value, Data]) Data() Data {
+	return it.batch[it.i-1].Data
+}
+
+// All returns an iterator over the entries in tree order that holds the read lock only in short leases (see SyncIter). Unlike
+// with Traverse, the loop body may take its time and even modify s.
+func (s *SyncTree[Value, Data]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		for it := s.Iter(); it.Next(); {
+			if !yield(it.Value(), it.Data()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package generictree
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The stress test below is most useful with the race detector enabled:
+//
+//	go test -race -run SyncTree
+//
+// Writers, readers, and iterators (Traverse, All, and SyncIter) hammer a
+// small key space in randomized schedules; the writers insert and delete
+// keys. Every operation is recorded with logical invocation and response
+// times, and the history is then checked for linearizability violations
+// per key. The iterators check that they yield strictly ascending keys.
+
+const (
+	stressKeys = 8
+	stressOps  = 2000
+)
+
+type stressOp struct {
+	writer    bool
+	del       bool // the write is a Delete
+	key       int
+	data      int // written data, a unique ID for a delete, or data returned by a read
+	found     bool
+	call, ret int64
+}
+
+type stressHistory struct {
+	mu  sync.Mutex
+	ops []stressOp
+}
+
+func (h *stressHistory) add(ops []stressOp) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ops = append(h.ops, ops...)
+}
+
+func TestSyncTree_stress(t *testing.T) {
+	rounds, ops := 5, stressOps
+	if testing.Short() {
+		rounds, ops = 1, stressOps/10
+	}
+	for round := 0; round < rounds; round++ {
+		seed := rand.Int63()
+		t.Logf("round %d: seed %d", round, seed)
+		stressSyncTree(t, &SyncTree[int, int]{}, rand.New(rand.NewSource(seed)), ops)
+		stressSyncTree(t, NewSyncTree[int, int](WithReadStripes(8)), rand.New(rand.NewSource(seed)), ops)
+	}
+}
+
+func stressSyncTree(t *testing.T, st *SyncTree[int, int], rnd *rand.Rand, ops int) {
+	var (
+		clock   atomic.Int64
+		history stressHistory
+		wg      sync.WaitGroup
+		iterErr atomic.Value
+	)
+	writers, readers, iterators := 1+rnd.Intn(4), 1+rnd.Intn(4), 1+rnd.Intn(2)
+
+	// Negative keys that no one writes fill several leases of SyncIter, so
+	// that the iterators cross lease boundaries before they reach the keys
+	// that change.
+	for k := -3 * leaseSize; k < 0; k++ {
+		st.Insert(k, k)
+	}
+
+	// Every goroutine gets its own random source, seeded from the round's source.
+	seeds := make([]int64, writers+readers+iterators)
+	for i := range seeds {
+		seeds[i] = rnd.Int63()
+	}
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int, rnd *rand.Rand) {
+			defer wg.Done()
+			local := make([]stressOp, 0, ops)
+			for i := 0; i < ops; i++ {
+				// Unique data per write lets reads be traced back to their write.
+				op := stressOp{writer: true, del: rnd.Intn(4) == 0, key: rnd.Intn(stressKeys), data: (w+1)*1_000_000 + i}
+				op.call = clock.Add(1)
+				if op.del {
+					_, op.found = st.Delete(op.key)
+				} else {
+					st.Insert(op.key, op.data)
+				}
+				op.ret = clock.Add(1)
+				local = append(local, op)
+				if rnd.Intn(8) == 0 {
+					runtime.Gosched()
+				}
+			}
+			history.add(local)
+		}(w, rand.New(rand.NewSource(seeds[w])))
+	}
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(rnd *rand.Rand) {
+			defer wg.Done()
+			local := make([]stressOp, 0, ops)
+			for i := 0; i < ops; i++ {
+				op := stressOp{key: rnd.Intn(stressKeys)}
+				op.call = clock.Add(1)
+				op.data, op.found = st.Find(op.key)
+				op.ret = clock.Add(1)
+				local = append(local, op)
+				if rnd.Intn(8) == 0 {
+					runtime.Gosched()
+				}
+			}
+			history.add(local)
+		}(rand.New(rand.NewSource(seeds[writers+r])))
+	}
+
+	for i := 0; i < iterators; i++ {
+		wg.Add(1)
+		go func(rnd *rand.Rand) {
+			defer wg.Done()
+			for i := 0; i < ops/50; i++ {
+				// Each way of iterating must yield strictly ascending keys
+				// while the writers run.
+				prev := math.MinInt
+				check := func(how string, k int) {
+					if k <= prev {
+						iterErr.Store(fmt.Sprintf("%s returned key %d after %d", how, k, prev))
+					}
+					prev = k
+				}
+				switch rnd.Intn(3) {
+				case 0:
+					st.Traverse(func(k, _ int) { check("Traverse", k) })
+				case 1:
+					for k := range st.All() {
+						check("All", k)
+						if rnd.Intn(4) == 0 {
+							runtime.Gosched()
+						}
+					}
+				case 2:
+					for it := st.Iter(); it.Next(); {
+						check("SyncIter", it.Value())
+					}
+				}
+				if rnd.Intn(2) == 0 {
+					runtime.Gosched()
+				}
+			}
+		}(rand.New(rand.NewSource(seeds[writers+readers+i])))
+	}
+
+	wg.Wait()
+	if msg := iterErr.Load(); msg != nil {
+		t.Error(msg)
+	}
+	checkLinearizable(t, history.ops)
+}
+
+// checkLinearizable checks a history of register operations (one register
+// per key) for violations of linearizability. A delete writes "absent", as
+// does a virtual delete at time 0 that sets the initial state.
+//
+//   - A read must not return data whose write started after the read ended.
+//   - A read must not return data that was already overwritten by a write
+//     that completed before the read started.
+//   - A read may miss a key only if a delete (or the initial state) that was
+//     not yet overwritten when the read started could be the latest write.
+//   - Reads that do not overlap must not go back in time.
+func checkLinearizable(t *testing.T, history []stressOp) {
+	t.Helper()
+	writes := map[int]stressOp{}
+	var byKey [stressKeys][]stressOp
+	for _, op := range history {
+		if op.writer {
+			writes[op.data] = op
+			byKey[op.key] = append(byKey[op.key], op)
+		}
+	}
+	// overwrittenBy[w.data] is the earliest end of a write of the same key
+	// that started after w ended: from then on, reads must not see w.
+	overwrittenBy := map[int]int64{}
+	for k := range byKey {
+		for _, w := range byKey[k] {
+			end := int64(math.MaxInt64)
+			for _, w2 := range byKey[k] {
+				if w.ret < w2.call {
+					end = min(end, w2.ret)
+				}
+			}
+			overwrittenBy[w.data] = end
+		}
+	}
+	// latestDelete[i] is, for the read history[i] that found nothing, the
+	// latest end of a delete that the read may have seen, 0 for the initial
+	// state, or -1 if the read may have seen no delete at all.
+	latestDelete := map[int]int64{}
+
+	var reads [stressKeys][]int
+	for i, r := range history {
+		if r.writer {
+			continue
+		}
+		reads[r.key] = append(reads[r.key], i)
+		if !r.found {
+			latest := int64(-1)
+			if !slices.ContainsFunc(byKey[r.key], func(w stressOp) bool { return w.ret < r.call }) {
+				latest = 0
+			}
+			for _, d := range byKey[r.key] {
+				if d.del && d.call < r.ret && overwrittenBy[d.data] >= r.call {
+					latest = max(latest, d.ret)
+				}
+			}
+			latestDelete[i] = latest
+			if latest < 0 {
+				t.Errorf("read of key %d at [%d,%d] found nothing, but the key was written and not deleted before", r.key, r.call, r.ret)
+			}
+			continue
+		}
+		w, ok := writes[r.data]
+		if !ok || w.key != r.key || w.del {
+			t.Errorf("read of key %d returned data %d that was never written to this key", r.key, r.data)
+			continue
+		}
+		if w.call > r.ret {
+			t.Errorf("read of key %d at [%d,%d] returned data %d written later at [%d,%d]", r.key, r.call, r.ret, r.data, w.call, w.ret)
+		}
+		if end := overwrittenBy[w.data]; end < r.call {
+			t.Errorf("read of key %d at [%d,%d] returned stale data %d, overwritten at %d", r.key, r.call, r.ret, r.data, end)
+		}
+	}
+
+	// Non-overlapping reads of the same key: the later read must not
+	// observe a write that precedes the write observed by the earlier read.
+	for k := range reads {
+		for _, i1 := range reads[k] {
+			r1 := history[i1]
+			if !r1.found {
+				continue
+			}
+			w1 := writes[r1.data]
+			for _, i2 := range reads[k] {
+				r2 := history[i2]
+				if r1.ret >= r2.call {
+					continue
+				}
+				if !r2.found {
+					if latestDelete[i2] < w1.call {
+						t.Errorf("read of key %d at [%d,%d] found nothing after an earlier read found data %d, with no delete in between", k, r2.call, r2.ret, r1.data)
+					}
+					continue
+				}
+				if w2 := writes[r2.data]; w2.ret < w1.call {
+					t.Errorf("read of key %d at [%d,%d] returned data %d, older than data %d returned by an earlier read", k, r2.call, r2.ret, r2.data, r1.data)
+				}
+			}
+		}
+	}
+}
+
+func TestSyncTree_All(t *testing.T) {
+	s := NewSyncTree[int, int]()
+	for v := 0; v < 10*leaseSize; v += 2 {
+		s.Insert(v, v)
+	}
+	var got []int
+	for v := range s.All() {
+		got = append(got, v)
+		if v == 100 {
+			// The body runs without the lock, so writers are not blocked.
+			done := make(chan struct{})
+			go func() {
+				s.Insert(1, 0)   // before the position: not seen
+				s.Insert(301, 0) // after the position: seen
+				s.Delete(400)    // after the position: not seen
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("writer blocked by the iteration")
+			}
+		}
+	}
+	var want []int
+	for v := 0; v < 10*leaseSize; v += 2 {
+		if v != 400 {
+			want = append(want, v)
+		}
+		if v == 300 {
+			want = append(want, 301)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
+
+func TestSyncIter_duplicates(t *testing.T) {
+	s := NewSyncTree[int, int](WithAllowDuplicates())
+	for i := range 3 * leaseSize {
+		s.Insert(1, i)
+	}
+	s.Insert(0, 0)
+	s.Insert(2, 0)
+	counts := map[int]int{}
+	it := s.Iter()
+	for it.Next() {
+		counts[it.Value()]++
+	}
+	if want := map[int]int{0: 1, 1: 3 * leaseSize, 2: 1}; !maps.Equal(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	if it.Next() {
+		t.Error("Next() after the end returned true")
+	}
+}
+
+func BenchmarkSyncTree_Find(b *testing.B) {
+	for _, stripes := range []int{1, 16} {
+		b.Run(fmt.Sprintf("stripes=%d", stripes), func(b *testing.B) {
+			st := NewSyncTree[int, int](WithReadStripes(stripes))
+			for i := range 1000 {
+				st.Insert(i, i)
+			}
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					st.Find(i % 1000)
+					i++
+				}
+			})
+		})
+	}
+}
+
+// TestSyncTree_lookupState covers the options that make lookups modify the
+// tree. Run it with the race detector to check the locking.
+func TestSyncTree_lookupState(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"Counters":     WithCounters(),
+		"FrontCache":   WithFrontCache(8),
+		"AccessCounts": WithAccessCounts(1),
+		"EvictLRU":     WithMaxSize(100, EvictLRU),
+	} {
+		for _, stripes := range []int{1, 4} {
+			s := NewSyncTree[int, int](opt, WithReadStripes(stripes))
+			if !s.writes {
+				t.Errorf("%s: readers do not take the write lock", name)
+			}
+			for v := range 50 {
+				s.Insert(v, v)
+			}
+			var wg sync.WaitGroup
+			for g := range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range 200 {
+						if d, ok := s.Find((g + i) % 50); !ok || d != (g+i)%50 {
+							t.Errorf("%s: Find(%d) = %d, %v", name, (g+i)%50, d, ok)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	}
+	if s := NewSyncTree[int, int](); s.writes {
+		t.Error("plain tree: readers take the write lock")
+	}
+}
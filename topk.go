@@ -0,0 +1,61 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// TopK keeps the k largest values offered to it, together with their data.
+// Equal values are kept separately; when the tracker is full, a value equal
+// to the smallest kept value does not replace it. Values are ordered like
+// in the tree, by cmp.Compare, so a floating-point NaN is smaller than any
+// other value.
+type TopK[Value cmp.Ordered, Data any] struct {
+	tree *Tree[Value, Data] // in descending order; equal values in the order offered
+	k    int
+}
+
+// NewTopK returns a tracker that keeps the k largest values.
+// It panics if k < 1.
+func NewTopK[Value cmp.Ordered, Data any](k int) *TopK[Value, Data] {
+	if k < 1 {
+		panic("generictree: NewTopK needs k >= 1")
+	}
+	return &TopK[Value, Data]{tree: New[Value, Data](WithDescending(), WithAllowDuplicates()), k: k}
+}
+
+// Offer adds value and data if value is among the k largest values so far,
+// evicting the smallest kept value if the tracker is full. It reports
+// whether value was kept.
+func (t *TopK[Value, Data]) Offer(value Value, data Data) bool {
+	if t.tree.Len() == t.k {
+		// In the tree's descending order, value must come before last.
+		if last, _, _ := t.tree.Max(); t.tree.compare(value, last) >= 0 {
+			return false
+		}
+		// Of several smallest values, evict the one offered last.
+		removed := t.tree.deleteAt(t.k - 1)
+		t.tree.afterDelete(removed)
+	}
+	t.tree.Insert(value, data)
+	return true
+}
+
+// Len returns the number of kept values, which is at most k.
+func (t *TopK[Value, Data]) Len() int {
+	return t.tree.Len()
+}
+
+// Threshold returns the smallest kept value. Once the tracker is full, a
+// value must be greater than the threshold to be kept. If nothing has been
+// offered yet, ok is false.
+func (t *TopK[Value, Data]) Threshold() (value Value, ok bool) {
+	value, _, ok = t.tree.Max()
+	return value, ok
+}
+
+// All returns an iterator over the kept values and their data, largest
+// first. Equal values come in the order in which they were offered.
+func (t *TopK[Value, Data]) All() iter.Seq2[Value, Data] {
+	return t.tree.All()
+}
@@ -0,0 +1,81 @@
+package generictree
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	top := NewTopK[int, string](3)
+	if _, ok := top.Threshold(); ok {
+		t.Error("Threshold of an empty tracker is ok")
+	}
+	offers := []struct {
+		value int
+		data  string
+		kept  bool
+	}{
+		{5, "a", true},
+		{1, "b", true},
+		{5, "c", true},
+		{1, "d", false}, // equal to the threshold
+		{0, "e", false},
+		{7, "f", true}, // evicts 1
+		{5, "g", false},
+		{6, "h", true}, // evicts c, the last of the two fives
+	}
+	for _, o := range offers {
+		if got := top.Offer(o.value, o.data); got != o.kept {
+			t.Errorf("Offer(%d, %s) = %t, want %t", o.value, o.data, got, o.kept)
+		}
+	}
+	var got []string
+	for v, d := range top.All() {
+		got = append(got, fmt.Sprint(v, d))
+	}
+	if want := []string{"7f", "6h", "5a"}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if v, _ := top.Threshold(); v != 5 || top.Len() != 3 {
+		t.Errorf("Threshold() = %d, Len() = %d; want 5 and 3", v, top.Len())
+	}
+}
+
+func TestTopK_NaN(t *testing.T) {
+	top := NewTopK[float64, int](2)
+	top.Offer(1, 1)
+	top.Offer(2, 2)
+	if top.Offer(math.NaN(), 0) {
+		t.Error("Offer(NaN) kept NaN, which is smaller than all values")
+	}
+	var got []float64
+	for v := range top.All() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []float64{2, 1}) {
+		t.Errorf("All() = %v, want [2 1]", got)
+	}
+}
+
+func TestTopK_random(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	top := NewTopK[int, struct{}](10)
+	var all []int
+	for range 1000 {
+		v := rnd.IntN(200)
+		top.Offer(v, struct{}{})
+		all = append(all, v)
+	}
+	slices.Sort(all)
+	slices.Reverse(all)
+	var got []int
+	for v := range top.All() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, all[:10]) {
+		t.Errorf("got %v, want %v", got, all[:10])
+	}
+}
@@ -0,0 +1,135 @@
+package generictree
+
+import "time"
+
+// Tracer receives an event for each traced operation of a tree (see
+// WithTracer). Trace is called synchronously at the end of the operation,
+// so it should return quickly, for example by recording a span or a
+// metric.
+type Tracer interface {
+	Trace(e TraceEvent)
+}
+
+// TracerFunc adapts a function to the Tracer interface.
+type TracerFunc func(e TraceEvent)
+
+// Trace calls f(e).
+func (f TracerFunc) Trace(e TraceEvent) {
+	f(e)
+}
+
+// TraceEvent describes one operation of a tree.
+type TraceEvent struct {
+	Op          string // "Insert", "Delete", "Find", or "FindMany"
+	Value       any    // the value that the operation was called with; for FindMany, the number of values
+	Comparisons int    // number of value comparisons
+	Rotations   int    // number of rotations that rebalanced the tree
+	Duration    time.Duration
+}
+
+// Counters are running totals of the work of a tree (see WithCounters).
+type Counters struct {
+	Inserts     int // calls of Insert
+	Deletes     int // calls of Delete
+	Finds       int // calls of Find
+	Comparisons int // value comparisons of all operations
+	Rotations   int // rotations that rebalanced the tree
+}
+
+// Sub returns the difference c - prev, the work done between two
+// snapshots.
+func (c Counters) Sub(prev Counters) Counters {
+	return Counters{
+		Inserts:     c.Inserts - prev.Inserts,
+		Deletes:     c.Deletes - prev.Deletes,
+		Finds:       c.Finds - prev.Finds,
+		Comparisons: c.Comparisons - prev.Comparisons,
+		Rotations:   c.Rotations - prev.Rotations,
+	}
+}
+
+// tracing counts the work of a traced tree.
+type tracing struct {
+	tracer      Tracer // nil if the tree only counts (see WithCounters)
+	comparisons int
+	rotations   int
+	inserts     int
+	deletes     int
+	finds       int
+}
+
+// traceStart is the state of a tracing at the start of an operation.
+type traceStart struct {
+	time        time.Time
+	comparisons int
+	rotations   int
+}
+
+func (tr *tracing) begin() traceStart {
+	if tr.tracer == nil {
+		return traceStart{}
+	}
+	return traceStart{time.Now(), tr.comparisons, tr.rotations}
+}
+
+// traceEnd counts the operation op and passes it to the tracer of tr, if
+// any. It takes value as a type parameter so that counting alone does not
+// box value, which keeps lookups on trees with counters free of
+// allocations.
+func traceEnd[V any](tr *tracing, op string, value V, s traceStart) {
+	switch op {
+	case "Insert":
+		tr.inserts++
+	case "Delete":
+		tr.deletes++
+	case "Find":
+		tr.finds++
+	}
+	if tr.tracer == nil {
+		return
+	}
+	tr.tracer.Trace(TraceEvent{
+		Op:          op,
+		Value:       value,
+		Comparisons: tr.comparisons - s.comparisons,
+		Rotations:   tr.rotations - s.rotations,
+		Duration:    time.Since(s.time),
+	})
+}
+
+// StatsSnapshot returns the counters of t (see WithCounters). For a tree
+// without counters, all counters are zero.
+func (t *Tree[Value, Data]) StatsSnapshot() Counters {
+	tr := t.tracing()
+	if tr == nil {
+		return Counters{}
+	}
+	return Counters{
+		Inserts:     tr.inserts,
+		Deletes:     tr.deletes,
+		Finds:       tr.finds,
+		Comparisons: tr.comparisons,
+		Rotations:   tr.rotations,
+	}
+}
+
+// ResetStats sets the counters of t to zero and returns their values
+// before the reset, the work done since the last reset. Use either
+// ResetStats or the difference of two snapshots (see Counters.Sub) to get
+// the counters of an interval; resetting breaks such differences.
+func (t *Tree[Value, Data]) ResetStats() Counters {
+	c := t.StatsSnapshot()
+	if tr := t.tracing(); tr != nil {
+		tr.inserts, tr.deletes, tr.finds, tr.comparisons, tr.rotations = 0, 0, 0, 0, 0
+	}
+	return c
+}
+
+// tracing returns the tracing of t, or nil if t is neither traced nor
+// counted.
+func (t *Tree[Value, Data]) tracing() *tracing {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.tracing
+}
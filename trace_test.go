@@ -0,0 +1,100 @@
+package generictree
+
+import "testing"
+
+func TestWithTracer(t *testing.T) {
+	var events []TraceEvent
+	rotated := 0
+	tree := New[int, string](
+		WithTracer(TracerFunc(func(e TraceEvent) { events = append(events, e) })),
+		WithNodeHooks(NodeHooks[int, string]{OnRotate: func(*Node[int, string]) { rotated++ }}),
+	)
+	tree.Insert(1, "a")
+	tree.Insert(2, "b")
+	tree.Insert(3, "c") // rotates left at 1
+	tree.Find(3)
+	tree.Delete(2)
+
+	want := []TraceEvent{
+		{Op: "Insert", Value: 1},
+		{Op: "Insert", Value: 2, Comparisons: 1},
+		{Op: "Insert", Value: 3, Comparisons: 2, Rotations: 1},
+		{Op: "Find", Value: 3, Comparisons: 2},
+		{Op: "Delete", Value: 2, Comparisons: 1},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Duration < 0 {
+			t.Errorf("event %d: negative duration", i)
+		}
+		e.Duration = 0
+		if e != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+	if rotated != 2 {
+		t.Errorf("node hooks saw %d rotated nodes, want 2", rotated)
+	}
+}
+
+// TestWithTracer_plainNodes checks that tracing counts rotations without
+// wrapping the nodes, and that a double rotation counts twice.
+func TestWithTracer_plainNodes(t *testing.T) {
+	var last TraceEvent
+	tree := New[int, int](WithTracer(TracerFunc(func(e TraceEvent) { last = e })))
+	tree.Insert(1, 1)
+	tree.Insert(3, 3)
+	tree.Insert(2, 2) // rotates right at 3, then left at 1
+	if last.Rotations != 2 {
+		t.Errorf("double rotation traced as %d rotations, want 2", last.Rotations)
+	}
+	for e := range tree.Entries() {
+		if e.node.aug != nil {
+			t.Errorf("node %d carries an augmentation", e.Value())
+		}
+	}
+}
+
+func TestWithCounters(t *testing.T) {
+	tree := New[int, string](WithCounters())
+	if c := (&Tree[int, string]{}).StatsSnapshot(); c != (Counters{}) {
+		t.Errorf("tree without counters: %+v", c)
+	}
+	tree.Insert(1, "a")
+	tree.Insert(2, "b")
+	tree.Insert(3, "c") // rotates left at 1
+	start := tree.StatsSnapshot()
+	if want := (Counters{Inserts: 3, Comparisons: 3, Rotations: 1}); start != want {
+		t.Errorf("StatsSnapshot() = %+v, want %+v", start, want)
+	}
+	tree.Find(3)
+	tree.Delete(2)
+	if got, want := tree.StatsSnapshot().Sub(start), (Counters{Deletes: 1, Finds: 1, Comparisons: 3}); got != want {
+		t.Errorf("delta = %+v, want %+v", got, want)
+	}
+	if got, want := tree.ResetStats(), (Counters{Inserts: 3, Deletes: 1, Finds: 1, Comparisons: 6, Rotations: 1}); got != want {
+		t.Errorf("ResetStats() = %+v, want %+v", got, want)
+	}
+	if c := tree.StatsSnapshot(); c != (Counters{}) {
+		t.Errorf("after ResetStats: %+v", c)
+	}
+}
+
+// TestWithCounters_doubleRotation checks that counters see each half of a
+// double rotation once and leave the nodes unwrapped.
+func TestWithCounters_doubleRotation(t *testing.T) {
+	tree := New[int, int](WithCounters())
+	tree.Insert(3, 3)
+	tree.Insert(1, 1)
+	tree.Insert(2, 2) // rotates left at 1, then right at 3
+	if got := tree.StatsSnapshot().Rotations; got != 2 {
+		t.Errorf("Rotations = %d, want 2", got)
+	}
+	for e := range tree.Entries() {
+		if e.node.aug != nil {
+			t.Errorf("node %d carries an augmentation", e.Value())
+		}
+	}
+}
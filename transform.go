@@ -0,0 +1,199 @@
+package generictree
+
+import "cmp"
+
+// MapValues returns a new tree with the values of t and the data that f
+// returns for each entry. f is called once per entry, in tree order.
+//
+// The new tree has the exact shape of t, so MapValues takes O(n) time and
+// does no comparisons or rebalancing. The new tree uses the order of t
+// (see WithComparator, WithDescending, and WithAllowDuplicates) and its
+// balancing (see WithNoBalance and WithRelaxedBalance) but none of its
+// other options, such as hooks or a free list.
+//
+// MapValues is a function rather than a method because methods cannot have
+// type parameters of their own.
+func MapValues[Value cmp.Ordered, Data, NewData any](t *Tree[Value, Data], f func(Value, Data) NewData) *Tree[Value, NewData] {
+	result := sameOrder[Value, Data, NewData](t)
+	result.Root = mapNodes(t.Root, f)
+	return result
+}
+
+func mapNodes[Value cmp.Ordered, Data, NewData any](n *Node[Value, Data], f func(Value, Data) NewData) *Node[Value, NewData] {
+	if n == nil {
+		return nil
+	}
+	m := &Node[Value, NewData]{Value: n.Value, height: n.height, size: n.size}
+	m.Left = mapNodes(n.Left, f)
+	m.Data = f(n.Value, n.Data)
+	m.Right = mapNodes(n.Right, f)
+	return m
+}
+
+// sameOrder returns an empty tree that is ordered and balanced like t but
+// has none of t's other options.
+func sameOrder[Value cmp.Ordered, Data, NewData any](t *Tree[Value, Data]) *Tree[Value, NewData] {
+	if t.cfg == nil {
+		return &Tree[Value, NewData]{}
+	}
+	return &Tree[Value, NewData]{cfg: &config[Value, NewData]{
+		compare:         t.cfg.compare,
+		allowDuplicates: t.cfg.allowDuplicates,
+		noBalance:       t.cfg.noBalance,
+		slack:           t.cfg.slack,
+	}}
+}
+
+// Filter returns a new tree with the entries of t for which pred returns
+// true. pred is called once per entry, in tree order. The new tree is
+// perfectly balanced and uses the order and balancing of t but none of its
+// other options.
+func (t *Tree[Value, Data]) Filter(pred func(Value, Data) bool) *Tree[Value, Data] {
+	var kept []*Node[Value, Data]
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		if pred(n.Value, n.Data) {
+			kept = append(kept, n)
+		}
+		return true
+	})
+	result := sameOrder[Value, Data, Data](t)
+	result.Root = build(len(kept), nil, func(i int) (Value, Data) {
+		return kept[i].Value, kept[i].Data
+	})
+	return result
+}
+
+// TraverseDelete calls f for each entry in tree order and removes the
+// entries for which f returns true. f must not modify the tree; the
+// deletions take place after the walk, so f sees every entry of the tree
+// as it was before the call. TraverseDelete returns the number of removed
+// entries and takes O(n + k log n) time for k removed entries.
+func (t *Tree[Value, Data]) TraverseDelete(f func(Value, Data) bool) int {
+	var marked []int
+	i := 0
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		if f(n.Value, n.Data) {
+			marked = append(marked, i)
+		}
+		i++
+		return true
+	})
+	// Each deletion shifts the indexes of the later entries down by one.
+	removed := make([]*Node[Value, Data], len(marked))
+	for k, i := range marked {
+		removed[k] = t.deleteAt(i - k)
+	}
+	if len(removed) > 0 {
+		t.debug.record(t, "TraverseDelete")
+	}
+	for _, n := range removed {
+		t.afterDelete(n)
+	}
+	t.checkHeight()
+	return len(removed)
+}
+
+// ZipMerge returns a new tree with the entries of a and b. Entries whose
+// value is in only one of the trees are copied. For a value that is in
+// both trees, resolve is called with the value and both data, and returns
+// the data for the new tree and whether to keep the entry at all. resolve
+// is called in tree order.
+//
+// ZipMerge walks both trees once and takes O(len(a) + len(b)) time. Both
+// trees must use the same order; ZipMerge compares values by the order of
+// a. If the trees allow duplicates, the entries of a value are paired up
+// in order, and the surplus entries of either tree are copied. The new
+// tree is perfectly balanced and uses the order and balancing of a but none
+// of its other options.
+func ZipMerge[Value cmp.Ordered, Data any](a, b *Tree[Value, Data], resolve func(v Value, da, db Data) (Data, bool)) *Tree[Value, Data] {
+	var as, bs []*Node[Value, Data]
+	a.Root.ascend(func(n *Node[Value, Data]) bool {
+		as = append(as, n)
+		return true
+	})
+	b.Root.ascend(func(n *Node[Value, Data]) bool {
+		bs = append(bs, n)
+		return true
+	})
+	merged := make([]KV[Value, Data], 0, len(as)+len(bs))
+	i, j := 0, 0
+	for i < len(as) && j < len(bs) {
+		switch c := a.compare(as[i].Value, bs[j].Value); {
+		case c < 0:
+			merged = append(merged, KV[Value, Data]{as[i].Value, as[i].Data})
+			i++
+		case c > 0:
+			merged = append(merged, KV[Value, Data]{bs[j].Value, bs[j].Data})
+			j++
+		default:
+			if d, keep := resolve(as[i].Value, as[i].Data, bs[j].Data); keep {
+				merged = append(merged, KV[Value, Data]{as[i].Value, d})
+			}
+			i++
+			j++
+		}
+	}
+	for _, n := range as[i:] {
+		merged = append(merged, KV[Value, Data]{n.Value, n.Data})
+	}
+	for _, n := range bs[j:] {
+		merged = append(merged, KV[Value, Data]{n.Value, n.Data})
+	}
+	result := sameOrder[Value, Data, Data](a)
+	result.Root = build(len(merged), nil, func(i int) (Value, Data) {
+		return merged[i].Value, merged[i].Data
+	})
+	return result
+}
+
+// Fold combines the entries of t in tree order: it calls f with init and
+// the first entry, then with the result and the second entry, and so on,
+// and returns the last result, or init if t is empty.
+func Fold[Value cmp.Ordered, Data, A any](t *Tree[Value, Data], init A, f func(A, Value, Data) A) A {
+	acc := init
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		acc = f(acc, n.Value, n.Data)
+		return true
+	})
+	return acc
+}
+
+// FoldReverse is like Fold but combines the entries in reverse tree order.
+func FoldReverse[Value cmp.Ordered, Data, A any](t *Tree[Value, Data], init A, f func(A, Value, Data) A) A {
+	acc := init
+	t.Root.descend(func(n *Node[Value, Data]) bool {
+		acc = f(acc, n.Value, n.Data)
+		return true
+	})
+	return acc
+}
+
+// Any reports whether pred returns true for at least one entry. It calls
+// pred in tree order and stops at the first entry that satisfies pred.
+func (t *Tree[Value, Data]) Any(pred func(Value, Data) bool) bool {
+	return !t.Root.ascend(func(n *Node[Value, Data]) bool {
+		return !pred(n.Value, n.Data)
+	})
+}
+
+// Every reports whether pred returns true for all entries; it is true for
+// an empty tree. It calls pred in tree order and stops at the first entry
+// that does not satisfy pred. (The method is not called All because All
+// iterates over the entries.)
+func (t *Tree[Value, Data]) Every(pred func(Value, Data) bool) bool {
+	return t.Root.ascend(func(n *Node[Value, Data]) bool {
+		return pred(n.Value, n.Data)
+	})
+}
+
+// CountIf returns the number of entries for which pred returns true.
+func (t *Tree[Value, Data]) CountIf(pred func(Value, Data) bool) int {
+	count := 0
+	t.Root.ascend(func(n *Node[Value, Data]) bool {
+		if pred(n.Value, n.Data) {
+			count++
+		}
+		return true
+	})
+	return count
+}
@@ -0,0 +1,221 @@
+package generictree
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMapValues(t *testing.T) {
+	tree := New[int, int](WithDescending())
+	for v := range 20 {
+		tree.Insert(v, v*v)
+	}
+	var calls []int
+	mapped := MapValues(tree, func(v, d int) string {
+		calls = append(calls, v)
+		return strconv.Itoa(d)
+	})
+	if err := mapped.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(calls, tree.values()) {
+		t.Errorf("f was called for %v, want tree order %v", calls, tree.values())
+	}
+	if mapped.Stats() != tree.Stats() {
+		t.Errorf("shape changed: got %+v, want %+v", mapped.Stats(), tree.Stats())
+	}
+	for v, d := range mapped.All() {
+		if d != strconv.Itoa(v*v) {
+			t.Errorf("data of %d: got %q", v, d)
+		}
+	}
+	// The new tree keeps the descending order.
+	mapped.Insert(100, "")
+	if v, _, _ := mapped.Min(); v != 100 {
+		t.Errorf("Min after inserting 100: got %d", v)
+	}
+	if got := MapValues(&Tree[int, int]{}, func(int, int) bool { return true }); got.Len() != 0 {
+		t.Errorf("empty tree: got len %d", got.Len())
+	}
+}
+
+func TestMapValues_noBalance(t *testing.T) {
+	tree := New[int, int](WithNoBalance())
+	for v := range 10 {
+		tree.Insert(v, v)
+	}
+	mapped := MapValues(tree, func(v, d int) int { return -d })
+	mapped.Insert(10, 0)
+	if err := mapped.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if h := mapped.Root.Height(); h != 11 {
+		t.Errorf("height = %d, want 11 without balancing", h)
+	}
+}
+
+func TestTree_Filter(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := range 100 {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+	even := tree.Filter(func(v int, d string) bool {
+		if d != strconv.Itoa(v) {
+			t.Errorf("pred(%d, %q)", v, d)
+		}
+		return v%2 == 0
+	})
+	if err := even.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := even.Stats(); got.Height != got.OptimalHeight {
+		t.Errorf("result is not perfectly balanced: %+v", got)
+	}
+	var want []int
+	for v := 0; v < 100; v += 2 {
+		want = append(want, v)
+	}
+	if got := even.values(); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if tree.Len() != 100 {
+		t.Errorf("source tree changed: len %d", tree.Len())
+	}
+	if none := tree.Filter(func(int, string) bool { return false }); none.Len() != 0 {
+		t.Errorf("no matches: got len %d", none.Len())
+	}
+}
+
+func TestTree_TraverseDelete(t *testing.T) {
+	var deleted []int
+	tree := New[int, int](WithAllowDuplicates(), WithHooks(Hooks[int, int]{
+		OnDelete: func(_, d int) { deleted = append(deleted, d) },
+	}))
+	for v := range 50 {
+		tree.Insert(v/2, v)
+	}
+	var seen []int
+	n := tree.TraverseDelete(func(v, d int) bool {
+		seen = append(seen, d)
+		return d%3 == 0 || v > 20
+	})
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 50 {
+		t.Errorf("f was called %d times, want 50", len(seen))
+	}
+	var want []int
+	for d := range 50 {
+		if d%3 != 0 && d/2 <= 20 {
+			want = append(want, d)
+		}
+	}
+	var got []int
+	for _, d := range tree.All() {
+		got = append(got, d)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if n != 50-len(want) || len(deleted) != n {
+		t.Errorf("removed %d entries with %d OnDelete calls, want %d", n, len(deleted), 50-len(want))
+	}
+	if n := tree.TraverseDelete(func(int, int) bool { return false }); n != 0 {
+		t.Errorf("no matches: removed %d", n)
+	}
+}
+
+func TestZipMerge(t *testing.T) {
+	a, b := New[int, string](WithDescending()), New[int, string](WithDescending())
+	for v := range 10 {
+		a.Insert(v, "a"+strconv.Itoa(v))
+	}
+	for v := 5; v < 15; v++ {
+		b.Insert(v, "b"+strconv.Itoa(v))
+	}
+	var conflicts []int
+	merged := ZipMerge(a, b, func(v int, da, db string) (string, bool) {
+		conflicts = append(conflicts, v)
+		return da + db, v%2 == 0
+	})
+	if err := merged.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{9, 8, 7, 6, 5}; !slices.Equal(conflicts, want) {
+		t.Errorf("resolve was called for %v, want %v", conflicts, want)
+	}
+	want := []int{14, 13, 12, 11, 10, 8, 6, 4, 3, 2, 1, 0}
+	if got := merged.values(); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for v, want := range map[int]string{0: "a0", 6: "a6b6", 12: "b12"} {
+		if d, _ := merged.Find(v); d != want {
+			t.Errorf("data of %d: got %q, want %q", v, d, want)
+		}
+	}
+	if a.Len() != 10 || b.Len() != 10 {
+		t.Errorf("source trees changed: len %d and %d", a.Len(), b.Len())
+	}
+	if got := ZipMerge(&Tree[int, string]{}, &Tree[int, string]{}, nil); got.Len() != 0 {
+		t.Errorf("empty trees: got len %d", got.Len())
+	}
+}
+
+func TestFold(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for _, v := range []int{3, 1, 2} {
+		tree.Insert(v, strconv.Itoa(v*10))
+	}
+	join := func(acc string, v int, d string) string { return acc + d + " " }
+	if got := Fold(tree, "", join); got != "10 20 30 " {
+		t.Errorf("Fold: got %q", got)
+	}
+	if got := FoldReverse(tree, "", join); got != "30 20 10 " {
+		t.Errorf("FoldReverse: got %q", got)
+	}
+	sum := Fold(tree, 0, func(acc, v int, _ string) int { return acc + v })
+	if sum != 6 {
+		t.Errorf("sum: got %d, want 6", sum)
+	}
+	if got := Fold(&Tree[int, string]{}, 42, func(acc, _ int, _ string) int { return 0 }); got != 42 {
+		t.Errorf("empty tree: got %d, want init", got)
+	}
+}
+
+func TestTree_predicates(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for v := range 10 {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+	calls := 0
+	gt := func(limit int) func(int, string) bool {
+		return func(v int, _ string) bool {
+			calls++
+			return v > limit
+		}
+	}
+
+	if !tree.Any(gt(2)) || calls != 4 {
+		t.Errorf("Any(> 2): want true after 4 calls, got %d calls", calls)
+	}
+	if tree.Any(gt(9)) {
+		t.Error("Any(> 9): want false")
+	}
+	calls = 0
+	if tree.Every(gt(2)) || calls != 1 {
+		t.Errorf("Every(> 2): want false after 1 call, got %d calls", calls)
+	}
+	if !tree.Every(gt(-1)) {
+		t.Error("Every(> -1): want true")
+	}
+	if got := tree.CountIf(gt(6)); got != 3 {
+		t.Errorf("CountIf(> 6): got %d, want 3", got)
+	}
+
+	empty := &Tree[int, string]{}
+	if empty.Any(gt(0)) || !empty.Every(gt(0)) || empty.CountIf(gt(0)) != 0 {
+		t.Error("empty tree: want Any false, Every true, CountIf 0")
+	}
+}
@@ -0,0 +1,587 @@
+package generictree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// compare compares two values according to the tree's configuration.
+func (t *Tree[Value, Data]) compare(a, b Value) int {
+	if t.cfg != nil {
+		if t.cfg.tracing != nil {
+			t.cfg.tracing.comparisons++
+		}
+		if t.cfg.compare != nil {
+			return t.cfg.compare(a, b)
+		}
+	}
+	return cmp.Compare(a, b)
+}
+
+func (t *Tree[Value, Data]) allowDuplicates() bool {
+	return t.cfg != nil && t.cfg.allowDuplicates
+}
+
+// keepsExisting reports whether Insert keeps the data of an existing
+// entry (see WithOnDuplicate).
+func (t *Tree[Value, Data]) keepsExisting() bool {
+	return t.cfg != nil && (t.cfg.duplicates == DuplicateKeep || t.cfg.duplicates == DuplicateError)
+}
+
+// balanced reports whether the tree rotates to stay balanced.
+func (t *Tree[Value, Data]) balanced() bool {
+	return t.cfg == nil || !t.cfg.noBalance
+}
+
+// slack returns the largest balance factor, in either direction, that the
+// tree allows (see WithRelaxedBalance).
+func (t *Tree[Value, Data]) slack() int {
+	if t.cfg == nil || t.cfg.slack == 0 {
+		return 1
+	}
+	return t.cfg.slack
+}
+
+// balanceLimit returns the slack of the tree, or 0 if the tree does not
+// balance.
+func (t *Tree[Value, Data]) balanceLimit() int {
+	if !t.balanced() {
+		return 0
+	}
+	return t.slack()
+}
+
+// balance tells the functions that rebalance subtrees how far a node may
+// lean and where to count the rotations.
+type balance struct {
+	limit     int  // the largest balance factor allowed; 0 means no rotations
+	rotations *int // counts the rotations, or nil
+}
+
+// count adds k rotations to the counter of b, if any.
+func (b balance) count(k int) {
+	if b.rotations != nil {
+		*b.rotations += k
+	}
+}
+
+// balancing returns the balance for insertions and deletions, which do not
+// rotate in trees created with WithNoBalance.
+func (t *Tree[Value, Data]) balancing() balance {
+	return balance{t.balanceLimit(), t.rotationCounter()}
+}
+
+// joining returns the balance for joins and splits, which always rotate
+// within the slack of the tree.
+func (t *Tree[Value, Data]) joining() balance {
+	return balance{t.slack(), t.rotationCounter()}
+}
+
+// rotationCounter returns the rotation counter of t, or nil if t is neither
+// traced nor counted.
+func (t *Tree[Value, Data]) rotationCounter() *int {
+	if tr := t.tracing(); tr != nil {
+		return &tr.rotations
+	}
+	return nil
+}
+
+func (t *Tree[Value, Data]) hooks() []Hooks[Value, Data] {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.hooks
+}
+
+// addHooks registers h on t. Unlike WithHooks, it works on any tree,
+// including a zero Tree.
+func (t *Tree[Value, Data]) addHooks(h Hooks[Value, Data]) {
+	if t.cfg == nil {
+		t.cfg = &config[Value, Data]{}
+	}
+	t.cfg.hooks = append(t.cfg.hooks, h)
+}
+
+// allocator returns the allocator of the tree, or nil for the Go heap.
+func (t *Tree[Value, Data]) allocator() Allocator[Value, Data] {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.alloc
+}
+
+// newNode returns a new leaf node, taken from the allocator of the tree.
+func (t *Tree[Value, Data]) newNode(value Value, data Data) *Node[Value, Data] {
+	n := alloc(t.allocator())
+	if t.cfg != nil && t.cfg.interner != nil {
+		value, data = t.cfg.interner.value(value), t.cfg.interner.datum(data)
+	}
+	n.Value, n.Data, n.height, n.size = value, data, 1, 1
+	if t.cfg != nil && t.cfg.augment != nil {
+		n.aug = box(t.cfg.augment())
+		n.aug.update(n)
+	}
+	if t.cfg != nil && t.cfg.nodeHooks != nil {
+		t.attach(n)
+	}
+	t.touch(n)
+	return n
+}
+
+// freeNode returns a deleted node to the allocator of the tree.
+func (t *Tree[Value, Data]) freeNode(n *Node[Value, Data]) {
+	if a := t.allocator(); a != nil {
+		a.Free(n)
+	}
+}
+
+// insert adds value and data to the subtree rooted at n and returns the new
+// root of the subtree and the node that holds the entry. If value already
+// exists and the tree does not allow duplicates, insert replaces the data,
+// stores the previous data in *old unless old is nil, and returns
+// replaced = true.
+func (t *Tree[Value, Data]) insert(n *Node[Value, Data], value Value, data Data, old *Data) (root, at *Node[Value, Data], replaced bool) {
+	if n == nil {
+		n = t.newNode(value, data)
+		return n, n, false
+	}
+	c := t.compare(value, n.Value)
+	switch {
+	case c == 0 && !t.allowDuplicates():
+		if t.keepsExisting() {
+			t.touch(n)
+			return n, n, true
+		}
+		if old != nil {
+			*old = n.Data
+		}
+		if t.cfg != nil && t.cfg.onDuplicate != nil {
+			data = t.cfg.onDuplicate(value, n.Data, data)
+		}
+		n.Data = t.internData(data)
+		if n.aug != nil {
+			n.aug.update(n)
+		}
+		t.touch(n)
+		return n, n, true
+	case c < 0:
+		n.Left, at, replaced = t.insert(n.Left, value, data, old)
+	default:
+		n.Right, at, replaced = t.insert(n.Right, value, data, old)
+	}
+	if replaced {
+		if n.aug != nil {
+			n.aug.update(n)
+		}
+		return n, at, true
+	}
+	n.update()
+	return n.balanceIf(t.balancing()), at, false
+}
+
+// afterInsert calls the hooks after an insertion.
+func (t *Tree[Value, Data]) afterInsert(value Value, data, old Data, replaced bool) {
+	for _, h := range t.hooks() {
+		switch {
+		case replaced && h.OnUpdate != nil:
+			h.OnUpdate(value, old, data)
+		case !replaced && h.OnInsert != nil:
+			h.OnInsert(value, data)
+		}
+	}
+}
+
+// findNode returns the node that holds value, or nil.
+func (t *Tree[Value, Data]) findNode(value Value) *Node[Value, Data] {
+	if t.cfg == nil {
+		return t.descend(value)
+	}
+	if t.cfg.filter != nil && !t.cfg.filter.mayContain(value) {
+		return nil
+	}
+	c := t.cfg.front
+	if c == nil {
+		return t.descend(value)
+	}
+	if n := c.get(value); n != nil {
+		return n
+	}
+	n := t.descend(value)
+	if n != nil {
+		c.put(n.Value, n)
+	}
+	return n
+}
+
+// descend searches the tree for the node that holds value and returns it,
+// or nil.
+func (t *Tree[Value, Data]) descend(value Value) *Node[Value, Data] {
+	n := t.Root
+	for n != nil {
+		c := t.compare(value, n.Value)
+		if c == 0 {
+			return n
+		}
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries in the tree.
+func (t *Tree[Value, Data]) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.Root.count()
+}
+
+// Delete removes value from the tree. It returns the data that was stored
+// for value and true, or the zero value of Data and false if value is not
+// in the tree.
+func (t *Tree[Value, Data]) Delete(value Value) (Data, bool) {
+	if t == nil || t.Root == nil {
+		var zero Data
+		return zero, false
+	}
+	if tr := t.tracing(); tr != nil {
+		defer traceEnd(tr, "Delete", value, tr.begin())
+	}
+	var removed *Node[Value, Data]
+	t.Root, removed = t.delete(t.Root, value)
+	if removed == nil {
+		var zero Data
+		return zero, false
+	}
+	t.debug.record(t, "Delete", value)
+	data := removed.Data
+	t.afterDelete(removed)
+	t.checkHeight()
+	return data, true
+}
+
+// afterDelete calls the hooks after a deletion and recycles the node.
+func (t *Tree[Value, Data]) afterDelete(removed *Node[Value, Data]) {
+	for _, h := range t.hooks() {
+		if h.OnDelete != nil {
+			h.OnDelete(removed.Value, removed.Data)
+		}
+	}
+	if t.cfg != nil && t.cfg.lru != nil {
+		t.cfg.lru.remove(removed)
+	}
+	t.detach(removed)
+	removed.size = 0 // invalidates the Entries of removed
+	t.freeNode(removed)
+}
+
+// delete removes value from the subtree rooted at n. It returns the new root
+// of the subtree and the removed node, or nil if value was not found.
+func (t *Tree[Value, Data]) delete(n *Node[Value, Data], value Value) (root, removed *Node[Value, Data]) {
+	return n.deleteFunc(func(m *Node[Value, Data]) int { return t.compare(value, m.Value) }, t.balancing())
+}
+
+// deleteFunc removes the node that locate points to from the subtree rooted
+// at n. locate compares the wanted node with m like a comparator: it returns
+// a negative number if the wanted node is in the left subtree of m, a
+// positive number if it is in the right subtree, and zero if it is m.
+// deleteFunc returns the new root of the subtree and the removed node, or
+// nil if there is no such node. deleteFunc rotates to keep the balance
+// factors within ±b.limit; if b.limit is 0, it does not rotate (see
+// WithNoBalance).
+//
+// A node with two children is replaced by its in-order successor. The
+// successor node is moved rather than copied, so that no surviving entry
+// changes the node it lives in.
+func (n *Node[Value, Data]) deleteFunc(locate func(m *Node[Value, Data]) int, b balance) (root, removed *Node[Value, Data]) {
+	if n == nil {
+		return nil, nil
+	}
+	switch c := locate(n); {
+	case c < 0:
+		n.Left, removed = n.Left.deleteFunc(locate, b)
+	case c > 0:
+		n.Right, removed = n.Right.deleteFunc(locate, b)
+	default:
+		removed = n
+		n = n.unlink(b)
+		if n == nil {
+			return nil, removed
+		}
+	}
+	if removed == nil {
+		return n, nil
+	}
+	n.update()
+	return n.balanceIf(b), removed
+}
+
+// unlink detaches n from its children and returns the subtree that
+// replaces n.
+func (n *Node[Value, Data]) unlink(b balance) *Node[Value, Data] {
+	var root *Node[Value, Data]
+	switch {
+	case n.Left == nil:
+		root = n.Right
+	case n.Right == nil:
+		root = n.Left
+	default:
+		right, succ := n.Right.deleteMin(b)
+		succ.Left, succ.Right = n.Left, right
+		succ.update()
+		root = succ.balanceIf(b)
+	}
+	n.Left, n.Right = nil, nil
+	return root
+}
+
+// deleteMin unlinks the leftmost node of the subtree rooted at n.
+// It returns the new root of the subtree and the unlinked node.
+func (n *Node[Value, Data]) deleteMin(b balance) (root, min *Node[Value, Data]) {
+	if n.Left == nil {
+		return n.Right, n
+	}
+	n.Left, min = n.Left.deleteMin(b)
+	n.update()
+	return n.balanceIf(b), min
+}
+
+// balanceIf rebalances n within b, unless b.limit is 0.
+func (n *Node[Value, Data]) balanceIf(b balance) *Node[Value, Data] {
+	if b.limit == 0 {
+		return n
+	}
+	return n.rebalanceWithin(b)
+}
+
+// Min returns the first value in the tree's order and its data. For a tree
+// in the default ascending order, this is the smallest value.
+// If the tree is empty, ok is false.
+func (t *Tree[Value, Data]) Min() (value Value, data Data, ok bool) {
+	n := t.Root
+	if n == nil {
+		return value, data, false
+	}
+	for n.Left != nil {
+		n = n.Left
+	}
+	return n.Value, n.Data, true
+}
+
+// Max returns the last value in the tree's order and its data. For a tree
+// in the default ascending order, this is the largest value.
+// If the tree is empty, ok is false.
+func (t *Tree[Value, Data]) Max() (value Value, data Data, ok bool) {
+	n := t.Root
+	if n == nil {
+		return value, data, false
+	}
+	for n.Right != nil {
+		n = n.Right
+	}
+	return n.Value, n.Data, true
+}
+
+// PeekMin returns the first entry in the tree's order without removing
+// it, like Min. Together with DeleteMin, it lets code that uses the tree
+// as a priority queue say what it does. PeekMin does not allocate.
+func (t *Tree[Value, Data]) PeekMin() (value Value, data Data, ok bool) {
+	return t.Min()
+}
+
+// PeekMax returns the last entry in the tree's order without removing it,
+// like Max. PeekMax does not allocate.
+func (t *Tree[Value, Data]) PeekMax() (value Value, data Data, ok bool) {
+	return t.Max()
+}
+
+// DeleteMin removes the first entry in the tree's order and returns it.
+// If the tree is empty, ok is false.
+func (t *Tree[Value, Data]) DeleteMin() (value Value, data Data, ok bool) {
+	return t.deleteEnd(0)
+}
+
+// DeleteMax removes the last entry in the tree's order and returns it.
+// If the tree is empty, ok is false.
+func (t *Tree[Value, Data]) DeleteMax() (value Value, data Data, ok bool) {
+	return t.deleteEnd(t.Len() - 1)
+}
+
+// deleteEnd removes the entry at index i, the first or the last one.
+func (t *Tree[Value, Data]) deleteEnd(i int) (value Value, data Data, ok bool) {
+	removed := t.deleteAt(i)
+	if removed == nil {
+		return value, data, false
+	}
+	value, data = removed.Value, removed.Data
+	t.debug.record(t, "Delete", value)
+	t.afterDelete(removed)
+	t.checkHeight()
+	return value, data, true
+}
+
+// EditData calls f with a pointer to the data stored for value, so that f
+// can change the data in place instead of copying it out and back in with
+// Find and Insert. f must not keep the pointer, and it must not modify the
+// tree. EditData reports whether value is in the tree; if it is not, f is
+// not called. If the tree allows duplicates, f gets the data of the first
+// entry that the search finds.
+//
+// The tree updates its augmentation, such as the sums of a NumericTree,
+// and calls the OnUpdate hooks as for an update by Insert. To give the
+// hooks the old data, EditData copies it if the tree has hooks.
+func (t *Tree[Value, Data]) EditData(value Value, f func(*Data)) bool {
+	if t.Root == nil {
+		return false
+	}
+	augmented := t.cfg != nil && t.cfg.augment != nil
+	var path []*Node[Value, Data]
+	n := t.Root
+	for n != nil {
+		if augmented {
+			path = append(path, n)
+		}
+		c := t.compare(value, n.Value)
+		if c == 0 {
+			break
+		}
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	if n == nil {
+		return false
+	}
+	var old Data
+	hooked := len(t.hooks()) > 0
+	if hooked {
+		old = n.Data
+	}
+	f(&n.Data)
+	n.Data = t.internData(n.Data)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].update()
+	}
+	t.debug.record(t, "EditData", value)
+	t.touch(n)
+	if hooked {
+		t.afterInsert(n.Value, n.Data, old, true)
+	}
+	return true
+}
+
+// Ceiling returns the first entry whose value is not less than value in
+// the tree's order. If there is no such entry, ok is false.
+func (t *Tree[Value, Data]) Ceiling(value Value) (v Value, data Data, ok bool) {
+	for n := t.Root; n != nil; {
+		if t.compare(value, n.Value) <= 0 {
+			v, data, ok = n.Value, n.Data, true
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return v, data, ok
+}
+
+// Floor returns the last entry whose value is not greater than value in
+// the tree's order. If there is no such entry, ok is false.
+func (t *Tree[Value, Data]) Floor(value Value) (v Value, data Data, ok bool) {
+	for n := t.Root; n != nil; {
+		if t.compare(value, n.Value) >= 0 {
+			v, data, ok = n.Value, n.Data, true
+			n = n.Right
+		} else {
+			n = n.Left
+		}
+	}
+	return v, data, ok
+}
+
+// FindResult is the result of looking up a single value with FindMany.
+type FindResult[Value, Data any] struct {
+	Value Value
+	Data  Data
+	Found bool // false if Value is not in the tree; Data is then the zero value
+}
+
+// FindMany looks up all values in a single pass and returns the results
+// in the order of values. It sorts the values and descends into the tree
+// once for all of them, splitting them between the subtrees of each node on
+// the way, so that the nodes on the shared parts of the search paths are
+// visited once instead of once per value. values is not modified.
+func (t *Tree[Value, Data]) FindMany(values []Value) []FindResult[Value, Data] {
+	results := make([]FindResult[Value, Data], len(values))
+	order := make([]int, len(values))
+	for i, v := range values {
+		results[i].Value = v
+		order[i] = i
+	}
+	if tr := t.tracing(); tr != nil {
+		defer traceEnd(tr, "FindMany", len(values), tr.begin())
+		tr.finds += len(values)
+	}
+	slices.SortFunc(order, func(i, j int) int { return t.compare(values[i], values[j]) })
+
+	var find func(n *Node[Value, Data], order []int)
+	find = func(n *Node[Value, Data], order []int) {
+		if n == nil || len(order) == 0 {
+			return
+		}
+		lo, _ := slices.BinarySearchFunc(order, n.Value, func(i int, v Value) int { return t.compare(values[i], v) })
+		hi := lo
+		for hi < len(order) && t.compare(values[order[hi]], n.Value) == 0 {
+			results[order[hi]].Data, results[order[hi]].Found = n.Data, true
+			hi++
+		}
+		if hi > lo {
+			t.touch(n)
+		}
+		find(n.Left, order[:lo])
+		find(n.Right, order[hi:])
+	}
+	find(t.Root, order)
+	return results
+}
+
+// Range calls f for each entry whose value lies in the half-open interval
+// [lo, hi) of the tree's order, in that order. Range stops early if f
+// returns false. For other kinds of intervals, see Between.
+func (t *Tree[Value, Data]) Range(lo, hi Value, f func(Value, Data) bool) {
+	t.ascendBetween(t.Root, Incl(lo), Excl(hi), f)
+}
+
+// ascend calls f for each node of the subtree rooted at n in ascending
+// order. It stops and returns false as soon as f returns false.
+func (n *Node[Value, Data]) ascend(f func(*Node[Value, Data]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.Left.ascend(f) && f(n) && n.Right.ascend(f)
+}
+
+// build returns a perfectly balanced tree of n entries, with nodes from a
+// (nil means the Go heap). entry(i) returns the i-th entry; the values must
+// be in ascending order. build calls entry in ascending order of i.
+func build[Value cmp.Ordered, Data any](n int, a Allocator[Value, Data], entry func(i int) (Value, Data)) *Node[Value, Data] {
+	var rec func(lo, hi int) *Node[Value, Data]
+	rec = func(lo, hi int) *Node[Value, Data] {
+		if lo >= hi {
+			return nil
+		}
+		mid := int(uint(lo+hi) >> 1)
+		left := rec(lo, mid)
+		node := alloc(a)
+		node.Left = left
+		node.Value, node.Data = entry(mid)
+		node.Right = rec(mid+1, hi)
+		node.update()
+		return node
+	}
+	return rec(0, n)
+}
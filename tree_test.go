@@ -0,0 +1,204 @@
+package generictree
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestTree_CeilingFloor(t *testing.T) {
+	tree := &Tree[int, string]{}
+	for i := 0; i < 50; i += 5 {
+		tree.Insert(i, fmt.Sprint(i))
+	}
+	tests := []struct {
+		value                int
+		ceiling, floor       int
+		hasCeiling, hasFloor bool
+	}{
+		{-1, 0, 0, true, false},
+		{0, 0, 0, true, true},
+		{22, 25, 20, true, true},
+		{45, 45, 45, true, true},
+		{46, 0, 45, false, true},
+	}
+	for _, tt := range tests {
+		if v, d, ok := tree.Ceiling(tt.value); ok != tt.hasCeiling || ok && (v != tt.ceiling || d != fmt.Sprint(v)) {
+			t.Errorf("Ceiling(%d) = %d, %q, %t", tt.value, v, d, ok)
+		}
+		if v, d, ok := tree.Floor(tt.value); ok != tt.hasFloor || ok && (v != tt.floor || d != fmt.Sprint(v)) {
+			t.Errorf("Floor(%d) = %d, %q, %t", tt.value, v, d, ok)
+		}
+	}
+}
+
+func TestTree_PeekDelete(t *testing.T) {
+	tree := New[int, string](WithDescending())
+	for v := range 10 {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+	if v, d, ok := tree.PeekMin(); !ok || v != 9 || d != "9" {
+		t.Errorf("PeekMin() = %d, %q, %v", v, d, ok)
+	}
+	if v, _, ok := tree.PeekMax(); !ok || v != 0 {
+		t.Errorf("PeekMax() = %d, %v", v, ok)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { tree.PeekMin(); tree.PeekMax() }); allocs != 0 {
+		t.Errorf("PeekMin and PeekMax allocate %v times", allocs)
+	}
+	var got []int
+	for tree.Len() > 0 {
+		v, d, _ := tree.DeleteMin()
+		if d != strconv.Itoa(v) {
+			t.Errorf("DeleteMin returned %d, %q", v, d)
+		}
+		got = append(got, v)
+		if tree.Len() > 0 {
+			v, _, _ := tree.DeleteMax()
+			got = append(got, v)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []int{9, 0, 8, 1, 7, 2, 6, 3, 5, 4}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, _, ok := tree.DeleteMin(); ok {
+		t.Error("DeleteMin on an empty tree returned ok = true")
+	}
+	if _, _, ok := tree.PeekMax(); ok {
+		t.Error("PeekMax on an empty tree returned ok = true")
+	}
+}
+
+func TestTree_tinyAllocs(t *testing.T) {
+	for n := range 3 {
+		tree := New[int, int]()
+		for v := range n {
+			tree.Insert(v, v)
+		}
+		for name, f := range map[string]func(){
+			"Find":   func() { tree.Find(0) },
+			"Min":    func() { tree.Min() },
+			"Max":    func() { tree.Max() },
+			"Delete": func() { tree.Delete(-1) },
+			"All": func() {
+				for range tree.All() {
+				}
+			},
+			"From": func() {
+				for range tree.From(0) {
+				}
+			},
+		} {
+			// Trees of two entries need a cursor for From.
+			want := 0.0
+			if name == "From" && n == 2 {
+				want = 1
+			}
+			if allocs := testing.AllocsPerRun(100, f); allocs != want {
+				t.Errorf("%s on %d entries: %v allocations, want %v", name, n, allocs, want)
+			}
+		}
+	}
+}
+
+func TestTree_EditData(t *testing.T) {
+	type payload struct {
+		count int
+		big   [64]int
+	}
+	var updates []string
+	tree := New[string, payload](WithHooks(Hooks[string, payload]{
+		OnUpdate: func(v string, old, new payload) {
+			updates = append(updates, fmt.Sprintf("%s:%d->%d", v, old.count, new.count))
+		},
+	}))
+	tree.Insert("a", payload{})
+	tree.Insert("b", payload{count: 5})
+	if !tree.EditData("b", func(p *payload) { p.count++ }) {
+		t.Fatal("EditData(b) = false, want true")
+	}
+	if tree.EditData("c", func(*payload) { t.Error("f called for a missing value") }) {
+		t.Error("EditData(c) = true, want false")
+	}
+	if p, _ := tree.Find("b"); p.count != 6 {
+		t.Errorf("count after EditData = %d, want 6", p.count)
+	}
+	if want := []string{"b:5->6"}; !slices.Equal(updates, want) {
+		t.Errorf("OnUpdate calls = %v, want %v", updates, want)
+	}
+
+	sums := NewNumericTree[int, int]()
+	for i := range 100 {
+		sums.Insert(i, i)
+	}
+	sums.tree.EditData(7, func(n *int) { *n += 1000 })
+	if got, want := sums.SumRange(0, 100), 4950+1000; got != want {
+		t.Errorf("SumRange after EditData = %d, want %d", got, want)
+	}
+}
+
+func TestTree_FindMany(t *testing.T) {
+	tree := New[int, string](WithDescending())
+	for i := range 50 {
+		tree.Insert(2*i, strconv.Itoa(2*i))
+	}
+	values := []int{7, 98, 0, 40, 40, -1, 13, 2}
+	input := slices.Clone(values)
+	results := tree.FindMany(values)
+	if !slices.Equal(values, input) {
+		t.Errorf("FindMany modified its input: %v", values)
+	}
+	for i, r := range results {
+		data, ok := tree.Find(values[i])
+		if r.Value != values[i] || r.Found != ok || r.Data != data {
+			t.Errorf("result %d = %+v, want {%d %q %t}", i, r, values[i], data, ok)
+		}
+	}
+	if got := (&Tree[int, int]{}).FindMany([]int{1}); len(got) != 1 || got[0].Found {
+		t.Errorf("empty tree: got %+v", got)
+	}
+}
+
+// TestTree_lookupAllocs checks that lookups do not allocate, whatever the
+// options of the tree and the size of its data.
+func TestTree_lookupAllocs(t *testing.T) {
+	type big struct{ a [64]int }
+	for name, opts := range map[string][]Option{
+		"default":    nil,
+		"descending": {WithDescending()},
+		"duplicates": {WithAllowDuplicates()},
+		"bloom":      {WithBloomFilter(100, 0.01)},
+		"lru":        {WithMaxSize(100, EvictLRU)},
+		"counters":   {WithCounters()},
+		"interning":  {WithInterning()},
+	} {
+		tree := New[string, big](opts...)
+		for _, s := range []string{"b", "d", "f", "h"} {
+			tree.Insert(s, big{})
+		}
+		frozen := tree.Freeze()
+		for op, f := range map[string]func(){
+			"Find":     func() { tree.Find("d"); tree.Find("e") },
+			"Contains": func() { tree.Contains("d"); tree.Contains("e") },
+			"Min":      func() { tree.Min() },
+			"Max":      func() { tree.Max() },
+			"Floor":    func() { tree.Floor("e"); tree.Floor("a") },
+			"Ceiling":  func() { tree.Ceiling("e"); tree.Ceiling("i") },
+			"Frozen": func() {
+				frozen.Find("d")
+				frozen.Contains("e")
+				frozen.Min()
+				frozen.Max()
+				frozen.Ceiling("e")
+			},
+		} {
+			if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+				t.Errorf("%s on a tree with %s: %v allocations, want 0", op, name, allocs)
+			}
+		}
+	}
+}
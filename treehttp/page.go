@@ -0,0 +1,47 @@
+package treehttp
+
+import "html/template"
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Summary}}</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td { padding: 0 1em 0 0; }
+svg text { font-size: 11px; text-anchor: middle; dominant-baseline: central; }
+circle { fill: #fff; stroke: #555; }
+circle.path { fill: #fd6; stroke: #c60; stroke-width: 2; }
+line { stroke: #999; }
+line.path { stroke: #c60; stroke-width: 2; }
+.cut { fill: #999; }
+</style>
+</head>
+<body>
+<h1>{{.Summary}}</h1>
+<table>
+<tr><td>entries</td><td>{{.Stats.Len}}</td></tr>
+<tr><td>height</td><td>{{.Stats.Height}} (optimal {{.Stats.OptimalHeight}})</td></tr>
+<tr><td>leaves</td><td>{{.Stats.Leaves}}</td></tr>
+<tr><td>average depth</td><td>{{printf "%.2f" .Stats.AvgDepth}}</td></tr>
+<tr><td>Sackin / Colless index</td><td>{{.Stats.Sackin}} / {{.Stats.Colless}}</td></tr>
+</table>
+<form>
+<input name="q" value="{{.Query}}" placeholder="search value">
+<input name="depth" type="number" min="1" value="{{.Depth}}" title="levels to draw">
+<button>Search</button>
+</form>
+{{with .Message}}<p>{{.}}</p>{{end}}
+<svg width="{{.SVG.Width}}" height="{{.SVG.Height}}">
+{{range .SVG.Edges}}<line x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"{{if .OnPath}} class="path"{{end}}/>
+{{end}}{{range .SVG.Nodes}}<g><title>{{.Title}}</title>
+<circle cx="{{.X}}" cy="{{.Y}}" r="{{$.SVG.Radius}}"{{if .OnPath}} class="path"{{end}}/>
+<text x="{{.X}}" y="{{.Y}}">{{.Label}}</text>
+{{if .Cut}}<text class="cut" x="{{.X}}" y="{{.Y}}" dy="{{$.SVG.Radius}}">…</text>{{end}}</g>
+{{end}}</svg>
+<p>JSON: <a href="stats">stats</a>, <a href="tree?depth={{.Depth}}">tree</a>, <a href="node?q={{.Query}}">node</a></p>
+</body>
+</html>
+`))
@@ -0,0 +1,96 @@
+package treehttp
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/appliedgo/generictree"
+)
+
+const (
+	dx     = 44 // horizontal distance between neighboring nodes
+	dy     = 64 // vertical distance between levels
+	radius = 18
+)
+
+type pageData struct {
+	Summary string
+	Stats   generictree.Stats
+	Query   string
+	Depth   int
+	Message string
+	SVG     svg
+}
+
+type svg struct {
+	Width, Height int
+	Radius        int
+	Nodes         []svgNode
+	Edges         []svgEdge
+}
+
+type svgNode struct {
+	X, Y   int
+	Label  string
+	Title  string
+	OnPath bool
+	Cut    bool // the node has children that are not drawn
+}
+
+type svgEdge struct {
+	X1, Y1, X2, Y2 int
+	OnPath         bool
+}
+
+// layout places each node of the shape in a column by its in-order position
+// and in a row by its depth. The labels use the formatters of tree.
+func layout[Value cmp.Ordered, Data any](tree *generictree.Tree[Value, Data], root *generictree.Shape[Value, Data], onPath map[*generictree.Shape[Value, Data]]bool) svg {
+	s := svg{Radius: radius}
+	column := 0
+	var place func(n *generictree.Shape[Value, Data], depth int) (x, y int)
+	place = func(n *generictree.Shape[Value, Data], depth int) (x, y int) {
+		var children [][2]int
+		var childOnPath []bool
+		if n.Left != nil {
+			lx, ly := place(n.Left, depth+1)
+			children = append(children, [2]int{lx, ly})
+			childOnPath = append(childOnPath, onPath[n.Left])
+		}
+		x, y = column*dx+dx/2+radius, depth*dy+dy/2
+		column++
+		if n.Right != nil {
+			rx, ry := place(n.Right, depth+1)
+			children = append(children, [2]int{rx, ry})
+			childOnPath = append(childOnPath, onPath[n.Right])
+		}
+		for i, c := range children {
+			s.Edges = append(s.Edges, svgEdge{x, y, c[0], c[1], childOnPath[i] && onPath[n]})
+		}
+		value := tree.FormatValue(n.Value)
+		label := value
+		if len(label) > 6 {
+			label = label[:5] + "…"
+		}
+		s.Nodes = append(s.Nodes, svgNode{
+			X: x, Y: y,
+			Label:  label,
+			Title:  fmt.Sprintf("%s: %s (height %d, size %d, balance %d)", value, tree.FormatData(n.Data), n.Height, n.Size, n.Balance),
+			OnPath: onPath[n],
+			Cut:    n.Size > 1+size(n.Left)+size(n.Right),
+		})
+		s.Height = max(s.Height, y+dy/2+radius)
+		return x, y
+	}
+	if root != nil {
+		place(root, 0)
+	}
+	s.Width = column*dx + 2*radius
+	return s
+}
+
+func size[Value cmp.Ordered, Data any](n *generictree.Shape[Value, Data]) int {
+	if n == nil {
+		return 0
+	}
+	return n.Size
+}
@@ -0,0 +1,238 @@
+// Package treehttp serves a live view of a generictree.Tree over HTTP, for
+// debugging services that embed trees.
+//
+// The handler serves these pages, relative to where it is mounted:
+//
+//	/            an HTML page with the tree's statistics and an SVG drawing
+//	             of its top levels; ?q=VALUE highlights the search path of
+//	             VALUE, and ?depth=N sets the number of levels drawn
+//	/stats       the tree's statistics as JSON
+//	/node?q=V    the search path and data of value V as JSON
+//	/tree        the shape of the tree as JSON, up to ?depth=N levels
+//
+// To mount the handler under a prefix, use http.StripPrefix:
+//
+//	http.Handle("/debug/tree/", http.StripPrefix("/debug/tree", treehttp.Handler(tree)))
+//
+// A Tree is not safe for concurrent use. The handler serializes its own
+// requests; if the tree changes while the handler runs, pass the lock that
+// guards it with WithLocker.
+package treehttp
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/appliedgo/generictree"
+)
+
+// DefaultDepth is the number of levels that the HTML page draws by default.
+const DefaultDepth = 6
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	locker sync.Locker
+	parse  any // func(string) (Value, error)
+	depth  int
+}
+
+// WithLocker makes the handler hold l while it reads the tree. For a tree
+// guarded by a sync.RWMutex, pass its RLocker, unless the tree has
+// WithCounters or WithTracer: those count the comparisons of lookups, so
+// concurrent requests need the write lock.
+func WithLocker(l sync.Locker) Option {
+	return func(o *options) { o.locker = l }
+}
+
+// WithParser sets the function that turns the q parameter into a value of
+// the tree. By default, strings are taken as they are, and other types are
+// parsed with fmt.Sscan.
+func WithParser[Value cmp.Ordered](parse func(string) (Value, error)) Option {
+	return func(o *options) { o.parse = parse }
+}
+
+// WithDepth sets the number of levels that the HTML page draws by default.
+func WithDepth(depth int) Option {
+	return func(o *options) { o.depth = depth }
+}
+
+type handler[Value cmp.Ordered, Data any] struct {
+	tree   *generictree.Tree[Value, Data]
+	locker sync.Locker
+	parse  func(string) (Value, error)
+	depth  int
+}
+
+// Handler returns a handler that serves t. It panics if an option does not
+// match the tree's type parameters.
+func Handler[Value cmp.Ordered, Data any](t *generictree.Tree[Value, Data], opts ...Option) http.Handler {
+	o := options{depth: DefaultDepth}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.locker == nil {
+		o.locker = new(sync.Mutex)
+	}
+	h := &handler[Value, Data]{tree: t, locker: o.locker, parse: parseValue[Value], depth: o.depth}
+	if o.parse != nil {
+		parse, ok := o.parse.(func(string) (Value, error))
+		if !ok {
+			panic(fmt.Sprintf("treehttp: WithParser: got %T, want %T", o.parse, parse))
+		}
+		h.parse = parse
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.page)
+	mux.HandleFunc("GET /stats", h.stats)
+	mux.HandleFunc("GET /node", h.node)
+	mux.HandleFunc("GET /tree", h.shape)
+	return mux
+}
+
+func parseValue[Value cmp.Ordered](s string) (Value, error) {
+	var v Value
+	if p, ok := any(&v).(*string); ok {
+		*p = s
+		return v, nil
+	}
+	_, err := fmt.Sscan(s, &v)
+	return v, err
+}
+
+func (h *handler[Value, Data]) lock() func() {
+	h.locker.Lock()
+	return h.locker.Unlock
+}
+
+// nodeInfo is the answer of /node.
+type nodeInfo[Value cmp.Ordered, Data any] struct {
+	Value Value   `json:"value"`
+	Found bool    `json:"found"`
+	Data  *Data   `json:"data,omitempty"`
+	Path  []Value `json:"path"` // values compared on the way from the root
+}
+
+// search returns the search path of value, as an answer of /node and as
+// steps. The caller must hold the lock.
+func (h *handler[Value, Data]) search(value Value) (nodeInfo[Value, Data], []generictree.Step[Value]) {
+	info := nodeInfo[Value, Data]{Value: value, Path: []Value{}}
+	path := h.tree.FindPath(value)
+	for _, step := range path {
+		info.Path = append(info.Path, step.Value)
+	}
+	if len(path) > 0 && path[len(path)-1].Turn == generictree.TurnFound {
+		// From, unlike Find, does not count as an access (see
+		// generictree.WithMaxSize).
+		for _, d := range h.tree.From(value) {
+			info.Found, info.Data = true, &d
+			break
+		}
+	}
+	return info, path
+}
+
+func (h *handler[Value, Data]) stats(w http.ResponseWriter, r *http.Request) {
+	unlock := h.lock()
+	s := h.tree.Stats()
+	unlock()
+	writeJSON(w, s)
+}
+
+func (h *handler[Value, Data]) node(w http.ResponseWriter, r *http.Request) {
+	value, err := h.parse(r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid value: %v", err), http.StatusBadRequest)
+		return
+	}
+	unlock := h.lock()
+	defer unlock()
+	info, _ := h.search(value)
+	writeJSON(w, info)
+}
+
+func (h *handler[Value, Data]) shape(w http.ResponseWriter, r *http.Request) {
+	depth, err := h.depthParam(r, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unlock := h.lock()
+	defer unlock()
+	writeJSON(w, h.tree.Inspect(depth))
+}
+
+func (h *handler[Value, Data]) depthParam(r *http.Request, def int) (int, error) {
+	s := r.URL.Query().Get("depth")
+	if s == "" {
+		return def, nil
+	}
+	depth, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid depth: %v", err)
+	}
+	return depth, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func (h *handler[Value, Data]) page(w http.ResponseWriter, r *http.Request) {
+	depth, err := h.depthParam(r, h.depth)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data := pageData{Query: r.URL.Query().Get("q"), Depth: depth}
+
+	unlock := h.lock()
+	data.Summary = h.tree.String()
+	data.Stats = h.tree.Stats()
+	shape := h.tree.Inspect(depth)
+	onPath := map[*generictree.Shape[Value, Data]]bool{}
+	if data.Query != "" {
+		value, err := h.parse(data.Query)
+		if err != nil {
+			data.Message = fmt.Sprintf("invalid value: %v", err)
+		} else {
+			info, path := h.search(value)
+			data.Message = fmt.Sprintf("%s: not found after %d comparisons", h.tree.FormatValue(value), len(info.Path))
+			if info.Found {
+				data.Message = fmt.Sprintf("%s: found after %d comparisons, data %s", h.tree.FormatValue(value), len(info.Path), h.tree.FormatData(*info.Data))
+			}
+			// Mark the drawn part of the path.
+			s := shape
+			for _, step := range path {
+				if s == nil {
+					break
+				}
+				onPath[s] = true
+				if step.Turn == generictree.TurnLeft {
+					s = s.Left
+				} else {
+					s = s.Right
+				}
+			}
+		}
+	}
+	unlock()
+
+	data.SVG = layout(h.tree, shape, onPath)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,128 @@
+package treehttp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+)
+
+func get(t *testing.T, h http.Handler, url string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	tree := &generictree.Tree[int, string]{}
+	for v := range 15 {
+		tree.Insert(v, strings.Repeat("x", v))
+	}
+	var mu sync.RWMutex
+	h := Handler(tree, WithLocker(mu.RLocker()), WithDepth(3))
+
+	code, body := get(t, h, "/?q=5")
+	if code != http.StatusOK {
+		t.Fatalf("/: status %d", code)
+	}
+	for _, want := range []string{"Tree[len=15 height=4 min=0 max=14]", "found after 3 comparisons", `class="path"`, "…"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/: page does not contain %q", want)
+		}
+	}
+	if n := strings.Count(body, "<circle"); n != 7 {
+		t.Errorf("/: drew %d nodes at depth 3, want 7", n)
+	}
+
+	_, body = get(t, h, "/node?q=5")
+	var info struct {
+		Found bool
+		Data  string
+		Path  []int
+	}
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		t.Fatal(err)
+	}
+	if !info.Found || info.Data != "xxxxx" || !slices.Equal(info.Path, []int{7, 3, 5}) {
+		t.Errorf("/node: got %+v", info)
+	}
+
+	_, body = get(t, h, "/stats")
+	var stats generictree.Stats
+	if err := json.Unmarshal([]byte(body), &stats); err != nil || stats.Len != 15 {
+		t.Errorf("/stats: got %s", body)
+	}
+
+	_, body = get(t, h, "/tree?depth=1")
+	if body != `{"value":7,"data":"xxxxxxx","height":4,"size":15,"balance":0}` {
+		t.Errorf("/tree?depth=1: got %s", body)
+	}
+
+	if code, _ := get(t, h, "/node?q=abc"); code != http.StatusBadRequest {
+		t.Errorf("/node with an invalid value: status %d", code)
+	}
+	if code, _ := get(t, h, "/missing"); code != http.StatusNotFound {
+		t.Errorf("/missing: status %d", code)
+	}
+}
+
+func TestWithParser(t *testing.T) {
+	tree := &generictree.Tree[string, int]{}
+	tree.Insert("a b", 1)
+	h := Handler(tree, WithParser(func(s string) (string, error) { return strings.ToLower(s), nil }))
+	if _, body := get(t, h, "/node?q=A+B"); !strings.Contains(body, `"found":true`) {
+		t.Errorf("/node: got %s", body)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("mismatched parser did not panic")
+		}
+	}()
+	Handler(tree, WithParser(func(s string) (int, error) { return 0, nil }))
+}
+
+func TestHandler_formatters(t *testing.T) {
+	tree := generictree.New[int, []byte](
+		generictree.WithKeyFormatter(func(v int) string { return "k" + strings.Repeat("=", v) }),
+		generictree.WithDataFormatter(func(d []byte) string { return "bytes:" + string(d) }),
+	)
+	tree.Insert(2, []byte("two"))
+	_, body := get(t, Handler(tree), "/?q=2")
+	for _, want := range []string{"k==: found after 1 comparisons, data bytes:two", "<title>k==: bytes:two"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/: page does not contain %q", want)
+		}
+	}
+}
+
+// TestHandler_concurrent serves requests in parallel from a tree that
+// counts comparisons. Run it with the race detector to check the locking.
+func TestHandler_concurrent(t *testing.T) {
+	tree := generictree.New[int, int](generictree.WithCounters())
+	for v := range 15 {
+		tree.Insert(v, v)
+	}
+	h := Handler(tree)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if code, _ := get(t, h, "/node?q=5"); code != http.StatusOK {
+					t.Errorf("/node: status %d", code)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
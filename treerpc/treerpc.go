@@ -0,0 +1,198 @@
+// Package treerpc shares a generictree.Tree between processes through
+// net/rpc, for prototypes that need a shared sorted map without a
+// database.
+//
+// The service has these methods; the types are defined in this package:
+//
+//	Get(KeyArgs) ValueReply        the data of a value
+//	Set(SetArgs) Empty             insert or replace an entry
+//	Delete(KeyArgs) ValueReply     remove an entry, returning its data
+//	Range(RangeArgs) RangeReply    the entries in [Lo, Hi), up to Limit
+//	Stats(Empty) StatsReply        the statistics of the tree
+//
+// Register a Service with an rpc.Server on the side that holds the tree,
+// and use a Client on the other side:
+//
+//	srv := rpc.NewServer()
+//	srv.RegisterName("Tree", treerpc.NewService(tree, nil))
+//	go srv.Accept(listener)
+//
+//	c, err := treerpc.Dial[string, int]("tcp", addr, "Tree")
+//	c.Set("answer", 42)
+//
+// Values and data travel in the gob encoding, so their types must be
+// encodable by encoding/gob.
+package treerpc
+
+import (
+	"cmp"
+	"net/rpc"
+	"sync"
+
+	"github.com/appliedgo/generictree"
+)
+
+// KeyArgs are the arguments of Get and Delete.
+type KeyArgs[K cmp.Ordered] struct {
+	Key K
+}
+
+// SetArgs are the arguments of Set.
+type SetArgs[K cmp.Ordered, V any] struct {
+	Key  K
+	Data V
+}
+
+// ValueReply is the reply of Get and Delete.
+type ValueReply[V any] struct {
+	Data  V
+	Found bool
+}
+
+// RangeArgs are the arguments of Range. A Limit of zero or less means no
+// limit.
+type RangeArgs[K cmp.Ordered] struct {
+	Lo, Hi K
+	Limit  int
+}
+
+// RangeReply is the reply of Range.
+type RangeReply[K cmp.Ordered, V any] struct {
+	Entries []generictree.KV[K, V]
+}
+
+// StatsReply is the reply of Stats.
+type StatsReply struct {
+	Stats generictree.Stats
+}
+
+// Empty is the argument or reply of methods that need none.
+type Empty struct{}
+
+// Service exposes a tree through net/rpc.
+type Service[K cmp.Ordered, V any] struct {
+	tree *generictree.Tree[K, V]
+	mu   *sync.RWMutex
+}
+
+// NewService returns a service for t. mu is the lock that guards t, if the
+// process also uses t directly; if mu is nil, the service uses a lock of
+// its own.
+//
+// All methods write-lock mu: Find records the lookup in trees with
+// WithMaxSize and EvictLRU, WithFrontCache, WithAccessCounts, WithCounters,
+// or WithTracer, and WithCounters and WithTracer also count the
+// comparisons of Range.
+func NewService[K cmp.Ordered, V any](t *generictree.Tree[K, V], mu *sync.RWMutex) *Service[K, V] {
+	if mu == nil {
+		mu = new(sync.RWMutex)
+	}
+	return &Service[K, V]{tree: t, mu: mu}
+}
+
+// Get returns the data stored for args.Key.
+func (s *Service[K, V]) Get(args KeyArgs[K], reply *ValueReply[V]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reply.Data, reply.Found = s.tree.Find(args.Key)
+	return nil
+}
+
+// Set inserts an entry or replaces its data.
+func (s *Service[K, V]) Set(args SetArgs[K, V], _ *Empty) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.Insert(args.Key, args.Data)
+	return nil
+}
+
+// Delete removes args.Key and returns its data.
+func (s *Service[K, V]) Delete(args KeyArgs[K], reply *ValueReply[V]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reply.Data, reply.Found = s.tree.Delete(args.Key)
+	return nil
+}
+
+// Range returns the entries in [args.Lo, args.Hi) in tree order.
+func (s *Service[K, V]) Range(args RangeArgs[K], reply *RangeReply[K, V]) error {
+	// Range may count comparisons (see NewService).
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.Range(args.Lo, args.Hi, func(k K, v V) bool {
+		reply.Entries = append(reply.Entries, generictree.KV[K, V]{Value: k, Data: v})
+		return args.Limit <= 0 || len(reply.Entries) < args.Limit
+	})
+	return nil
+}
+
+// Stats returns the statistics of the tree.
+func (s *Service[K, V]) Stats(_ Empty, reply *StatsReply) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reply.Stats = s.tree.Stats()
+	return nil
+}
+
+// Client calls a Service.
+type Client[K cmp.Ordered, V any] struct {
+	rpc  *rpc.Client
+	name string
+}
+
+// NewClient returns a client for the service registered as name on the
+// server that c is connected to.
+func NewClient[K cmp.Ordered, V any](c *rpc.Client, name string) *Client[K, V] {
+	return &Client[K, V]{rpc: c, name: name}
+}
+
+// Dial connects to the RPC server at address on the named network and
+// returns a client for the service registered as name.
+func Dial[K cmp.Ordered, V any](network, address, name string) (*Client[K, V], error) {
+	c, err := rpc.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient[K, V](c, name), nil
+}
+
+// Close closes the connection.
+func (c *Client[K, V]) Close() error {
+	return c.rpc.Close()
+}
+
+// Get returns the data stored for key and true, or false if key is not in
+// the tree.
+func (c *Client[K, V]) Get(key K) (V, bool, error) {
+	var reply ValueReply[V]
+	err := c.rpc.Call(c.name+".Get", KeyArgs[K]{key}, &reply)
+	return reply.Data, reply.Found, err
+}
+
+// Set inserts key with data, or replaces the data of key.
+func (c *Client[K, V]) Set(key K, data V) error {
+	return c.rpc.Call(c.name+".Set", SetArgs[K, V]{key, data}, &Empty{})
+}
+
+// Delete removes key and returns its data and true, or false if key was
+// not in the tree.
+func (c *Client[K, V]) Delete(key K) (V, bool, error) {
+	var reply ValueReply[V]
+	err := c.rpc.Call(c.name+".Delete", KeyArgs[K]{key}, &reply)
+	return reply.Data, reply.Found, err
+}
+
+// Range returns up to limit entries in [lo, hi) in tree order. A limit of
+// zero or less means no limit.
+func (c *Client[K, V]) Range(lo, hi K, limit int) ([]generictree.KV[K, V], error) {
+	var reply RangeReply[K, V]
+	err := c.rpc.Call(c.name+".Range", RangeArgs[K]{lo, hi, limit}, &reply)
+	return reply.Entries, err
+}
+
+// Stats returns the statistics of the tree.
+func (c *Client[K, V]) Stats() (generictree.Stats, error) {
+	var reply StatsReply
+	err := c.rpc.Call(c.name+".Stats", Empty{}, &reply)
+	return reply.Stats, err
+}
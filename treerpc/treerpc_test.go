@@ -0,0 +1,114 @@
+package treerpc
+
+import (
+	"net"
+	"net/rpc"
+	"sync"
+	"testing"
+
+	"github.com/appliedgo/generictree"
+)
+
+func TestService(t *testing.T) {
+	tree := generictree.New[string, int]()
+	var mu sync.RWMutex
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Tree", NewService(tree, &mu)); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	defer l.Close()
+	go srv.Accept(l)
+
+	c, err := Dial[string, int]("tcp", l.Addr().String(), "Tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		if err := c.Set(k, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d, ok, err := c.Get("c"); d != 2 || !ok || err != nil {
+		t.Errorf("Get(c) = %d, %t, %v", d, ok, err)
+	}
+	if _, ok, err := c.Get("x"); ok || err != nil {
+		t.Errorf("Get(x) = %t, %v", ok, err)
+	}
+	if d, ok, err := c.Delete("a"); d != 0 || !ok || err != nil {
+		t.Errorf("Delete(a) = %d, %t, %v", d, ok, err)
+	}
+	entries, err := c.Range("b", "z", 2)
+	if err != nil || len(entries) != 2 || entries[0].Value != "b" || entries[1].Value != "c" {
+		t.Errorf("Range(b, z, 2) = %v, %v", entries, err)
+	}
+	stats, err := c.Stats()
+	if err != nil || stats.Len != 4 {
+		t.Errorf("Stats() = %+v, %v", stats, err)
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	if d, ok := tree.Find("e"); d != 4 || !ok || tree.Len() != 4 {
+		t.Errorf("local tree: Find(e) = %d, %t; Len() = %d", d, ok, tree.Len())
+	}
+}
+
+// TestService_concurrentGet runs Gets in parallel on a tree whose lookups
+// update the LRU order. Run it with the race detector to check the locking.
+func TestService_concurrentGet(t *testing.T) {
+	tree := generictree.New[int, int](generictree.WithMaxSize(100, generictree.EvictLRU))
+	s := NewService(tree, nil)
+	for i := range 50 {
+		s.Set(SetArgs[int, int]{i, i}, &Empty{})
+	}
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				var reply ValueReply[int]
+				s.Get(KeyArgs[int]{(g + i) % 50}, &reply)
+				if !reply.Found || reply.Data != (g+i)%50 {
+					t.Errorf("Get(%d) = %+v", (g+i)%50, reply)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestService_concurrentRange runs Ranges and Stats in parallel on a tree
+// that counts comparisons. Run it with the race detector to check the
+// locking.
+func TestService_concurrentRange(t *testing.T) {
+	tree := generictree.New[int, int](generictree.WithCounters())
+	s := NewService(tree, nil)
+	for i := range 50 {
+		s.Set(SetArgs[int, int]{i, i}, &Empty{})
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				var reply RangeReply[int, int]
+				s.Range(RangeArgs[int]{Lo: 10, Hi: 20}, &reply)
+				if len(reply.Entries) != 10 {
+					t.Errorf("Range(10, 20) returned %d entries, want 10", len(reply.Entries))
+					return
+				}
+				s.Stats(Empty{}, &StatsReply{})
+			}
+		}()
+	}
+	wg.Wait()
+}
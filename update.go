@@ -0,0 +1,293 @@
+package generictree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// UpdateRange replaces the data of every entry whose value lies in the
+// half-open interval [lo, hi) with f of the data, in tree order, and
+// returns the number of updated entries. It takes O(log n + m) time for m
+// updated entries; f must not modify the tree. For updates that can be
+// composed, such as adding a constant, a LazyTree applies them in
+// O(log n) time instead.
+//
+// Like EditData, UpdateRange updates the augmentation of the tree and
+// calls the OnUpdate hooks for each updated entry.
+func (t *Tree[Value, Data]) UpdateRange(lo, hi Value, f func(Data) Data) int {
+	type update struct {
+		n   *Node[Value, Data]
+		old Data
+	}
+	var updates []update // for the hooks, which run once the tree is consistent
+	hooked := len(t.hooks()) > 0
+	count := t.updateBetween(t.Root, Incl(lo), Excl(hi), func(n *Node[Value, Data]) {
+		if hooked {
+			updates = append(updates, update{n, n.Data})
+		}
+		n.Data = t.internData(f(n.Data))
+	})
+	if count == 0 {
+		return 0
+	}
+	t.debug.record(t, "UpdateRange", lo, hi)
+	for _, u := range updates {
+		t.afterInsert(u.n.Value, u.n.Data, u.old, true)
+	}
+	return count
+}
+
+// updateBetween calls f for the nodes of the subtree rooted at n whose
+// values lie between lo and hi, and updates the augmentation of their
+// ancestors within the subtree. It returns the number of nodes.
+func (t *Tree[Value, Data]) updateBetween(n *Node[Value, Data], lo, hi Bound[Value], f func(*Node[Value, Data])) int {
+	if n == nil {
+		return 0
+	}
+	count := 0
+	afterLo, beforeHi := t.afterLo(lo, n.Value), t.beforeHi(hi, n.Value)
+	if afterLo {
+		count += t.updateBetween(n.Left, lo, hi, f)
+	}
+	if afterLo && beforeHi {
+		f(n)
+		count++
+	}
+	if beforeHi {
+		count += t.updateBetween(n.Right, lo, hi, f)
+	}
+	if count > 0 && n.aug != nil {
+		n.aug.update(n)
+	}
+	return count
+}
+
+// LazyTree is a tree whose data can be updated for a whole range of values
+// in O(log n) time. An update is a value of type U, which apply applies to
+// the data of an entry. UpdateRange stores the update at the roots of the
+// subtrees that the range covers, and the tree pushes it down to the
+// children of a node only when it moves the node or when a later update
+// covers part of its subtree. This requires compose, which combines two
+// updates into one: apply(compose(outer, inner), d) must equal
+// apply(outer, apply(inner, d)).
+//
+// For example, a tree of account balances where U is an amount to add
+// uses
+//
+//	add := func(u, d int) int { return d + u }
+//	t := NewLazyTree[string](add, add)
+//
+// Like NumericTree, a LazyTree offers only the methods that keep the
+// pending updates consistent.
+type LazyTree[Value cmp.Ordered, Data, U any] struct {
+	tree    *Tree[Value, Data]
+	apply   func(u U, d Data) Data
+	compose func(outer, inner U) U
+}
+
+// NewLazyTree returns an empty lazy tree that applies updates with apply
+// and combines them with compose, configured by opts. See New for the
+// available options. NewLazyTree panics if opts include WithMaxSize,
+// because evictions would move nodes without pushing their updates down.
+func NewLazyTree[Value cmp.Ordered, Data, U any](apply func(u U, d Data) Data, compose func(outer, inner U) U, opts ...Option) *LazyTree[Value, Data, U] {
+	t := New[Value, Data](opts...)
+	if t.cfg.maxSize > 0 {
+		panic(fmt.Sprintf("generictree: NewLazyTree: WithMaxSize(%d) is not supported", t.cfg.maxSize))
+	}
+	lt := &LazyTree[Value, Data, U]{tree: t, apply: apply, compose: compose}
+	t.cfg.augment = func() augmentation[Value, Data] { return &pending[Value, Data, U]{tree: lt} }
+	return lt
+}
+
+// pending is the augmentation of a node in a LazyTree: an update that
+// still has to be applied to all entries of the subtree of the node.
+type pending[Value cmp.Ordered, Data, U any] struct {
+	tree *LazyTree[Value, Data, U]
+	u    U
+	ok   bool // u is set
+}
+
+func (p *pending[Value, Data, U]) update(*Node[Value, Data]) {}
+
+// add stores u on top of the update that p holds already.
+func (p *pending[Value, Data, U]) add(u U) {
+	if p.ok {
+		u = p.tree.compose(u, p.u)
+	}
+	p.u, p.ok = u, true
+}
+
+// push applies the update of p to the data of n and hands it down to the
+// children of n.
+func (p *pending[Value, Data, U]) push(n *Node[Value, Data]) {
+	if !p.ok {
+		return
+	}
+	n.Data = p.tree.apply(p.u, n.Data)
+	for _, child := range []*Node[Value, Data]{n.Left, n.Right} {
+		if child != nil {
+			pendingOf[Value, Data, U](child).add(p.u)
+		}
+	}
+	var zero U
+	p.u, p.ok = zero, false
+}
+
+// pendingOf returns the pending update of n.
+func pendingOf[Value cmp.Ordered, Data, U any](n *Node[Value, Data]) *pending[Value, Data, U] {
+	return unwrap(n.aug.get()).(*pending[Value, Data, U])
+}
+
+// A pusher is an augmentation that holds changes for the whole subtree of
+// its node, such as the pending updates of a LazyTree. Before a rotation
+// changes the subtree of a node, the node pushes the changes down to its
+// children.
+type pusher[Value cmp.Ordered, Data any] interface {
+	push(n *Node[Value, Data])
+}
+
+// push pushes the changes that n holds for its subtree, if any, down to its
+// children.
+func (n *Node[Value, Data]) push() {
+	if p, ok := unwrap(n.aug.get()).(pusher[Value, Data]); ok {
+		p.push(n)
+	}
+}
+
+// Insert stores data for value, replacing any previous data unless the
+// tree allows duplicates. Earlier updates do not apply to the new data.
+func (t *LazyTree[Value, Data, U]) Insert(value Value, data Data) {
+	t.pushPath(value, true)
+	t.tree.Insert(value, data)
+}
+
+// Find returns the data stored for value, with all updates applied, and
+// true, or the zero value and false if value is not in the tree. Find
+// does not modify the tree.
+func (t *LazyTree[Value, Data, U]) Find(value Value) (Data, bool) {
+	return t.find(t.tree.Root, value)
+}
+
+// find returns the data of value in the subtree rooted at n, with the
+// pending updates of n and its descendants on the path applied.
+func (t *LazyTree[Value, Data, U]) find(n *Node[Value, Data], value Value) (d Data, ok bool) {
+	if n == nil {
+		return d, false
+	}
+	switch c := t.tree.compare(value, n.Value); {
+	case c < 0:
+		d, ok = t.find(n.Left, value)
+	case c > 0:
+		d, ok = t.find(n.Right, value)
+	default:
+		d, ok = n.Data, true
+	}
+	if p := pendingOf[Value, Data, U](n); ok && p.ok {
+		d = t.apply(p.u, d)
+	}
+	return d, ok
+}
+
+// Delete removes value from the tree and returns its data, with all
+// updates applied, and true, or the zero value and false if value is not
+// in the tree.
+func (t *LazyTree[Value, Data, U]) Delete(value Value) (Data, bool) {
+	t.pushPath(value, false)
+	return t.tree.Delete(value)
+}
+
+// pushPath pushes the pending updates down from the nodes on the search
+// path for value, so that the tree can insert value, or delete it and move
+// its successor up, without carrying updates to entries they do not cover.
+func (t *LazyTree[Value, Data, U]) pushPath(value Value, insert bool) {
+	n := t.tree.Root
+	for n != nil {
+		n.push()
+		c := t.tree.compare(value, n.Value)
+		if c == 0 && (!insert || !t.tree.allowDuplicates()) {
+			break
+		}
+		if c < 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	if n != nil && !insert {
+		for m := n.Right; m != nil; m = m.Left {
+			m.push()
+		}
+	}
+}
+
+// UpdateRange applies u to the data of every entry whose value lies in the
+// half-open interval [lo, hi), in O(log n) time.
+func (t *LazyTree[Value, Data, U]) UpdateRange(lo, hi Value, u U) {
+	t.update(t.tree.Root, Incl(lo), Excl(hi), false, false, u)
+	t.tree.debug.record(t.tree, "UpdateRange", lo, hi)
+}
+
+// update applies u to the entries between lo and hi in the subtree rooted
+// at n. noLo and noHi tell that all values of the subtree are known to
+// respect lo or hi, respectively; if both do, the update stays pending at
+// n. As in NumericTree.query, this visits O(log n) nodes. Before the
+// update reaches the descendants of a node, the node pushes its own
+// pending update down, so that older updates always lie below newer ones.
+func (t *LazyTree[Value, Data, U]) update(n *Node[Value, Data], lo, hi Bound[Value], noLo, noHi bool, u U) {
+	if n == nil {
+		return
+	}
+	if noLo && noHi {
+		pendingOf[Value, Data, U](n).add(u)
+		return
+	}
+	n.push()
+	switch {
+	case !noLo && !t.tree.afterLo(lo, n.Value):
+		t.update(n.Right, lo, hi, noLo, noHi, u)
+	case !noHi && !t.tree.beforeHi(hi, n.Value):
+		t.update(n.Left, lo, hi, noLo, noHi, u)
+	default:
+		t.update(n.Left, lo, hi, noLo, true, u)
+		n.Data = t.apply(u, n.Data)
+		t.update(n.Right, lo, hi, true, noHi, u)
+	}
+}
+
+// Len returns the number of entries in the tree.
+func (t *LazyTree[Value, Data, U]) Len() int {
+	return t.tree.Len()
+}
+
+// All returns an iterator over the values and data of the tree, with all
+// updates applied, in the tree's order. All does not modify the tree.
+func (t *LazyTree[Value, Data, U]) All() iter.Seq2[Value, Data] {
+	return func(yield func(Value, Data) bool) {
+		var none U
+		t.ascend(t.tree.Root, none, false, yield)
+	}
+}
+
+// ascend calls yield for the entries of the subtree rooted at n, with the
+// update u of the ancestors of n applied if ok is true.
+func (t *LazyTree[Value, Data, U]) ascend(n *Node[Value, Data], u U, ok bool, yield func(Value, Data) bool) bool {
+	if n == nil {
+		return true
+	}
+	if p := pendingOf[Value, Data, U](n); p.ok {
+		if ok {
+			u = t.compose(u, p.u)
+		} else {
+			u, ok = p.u, true
+		}
+	}
+	if !t.ascend(n.Left, u, ok, yield) {
+		return false
+	}
+	d := n.Data
+	if ok {
+		d = t.apply(u, d)
+	}
+	return yield(n.Value, d) && t.ascend(n.Right, u, ok, yield)
+}
@@ -0,0 +1,129 @@
+package generictree
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTree_UpdateRange(t *testing.T) {
+	var updates []int
+	tree := NewNumericTree[int, int](WithHooks(Hooks[int, int]{
+		OnUpdate: func(v, old, new int) { updates = append(updates, v, old, new) },
+	}))
+	for v := range 100 {
+		tree.Insert(v, 1)
+	}
+	double := func(d int) int { return 2 * d }
+	if n := tree.tree.UpdateRange(10, 20, double); n != 10 {
+		t.Errorf("UpdateRange(10, 20) updated %d entries, want 10", n)
+	}
+	if n := tree.tree.UpdateRange(15, 15, double); n != 0 {
+		t.Errorf("UpdateRange(15, 15) updated %d entries, want 0", n)
+	}
+	if n := tree.tree.UpdateRange(95, 200, double); n != 5 {
+		t.Errorf("UpdateRange(95, 200) updated %d entries, want 5", n)
+	}
+	// The sums of the augmentation are up to date.
+	if got := tree.SumRange(0, 100); got != 115 {
+		t.Errorf("SumRange(0, 100) = %d, want 115", got)
+	}
+	if got := tree.SumRange(18, 22); got != 6 {
+		t.Errorf("SumRange(18, 22) = %d, want 6", got)
+	}
+	if err := tree.tree.Validate(); err != nil {
+		t.Error(err)
+	}
+	if len(updates) != 3*15 || updates[0] != 10 || updates[1] != 1 || updates[2] != 2 {
+		t.Errorf("OnUpdate calls = %v", updates)
+	}
+}
+
+// affine is the update d -> a*d + b. Affine updates do not commute, so they
+// check that a LazyTree applies its updates in order.
+type affine struct{ a, b int }
+
+func applyAffine(u affine, d int) int { return u.a*d + u.b }
+
+func composeAffine(outer, inner affine) affine {
+	return affine{outer.a * inner.a, outer.a*inner.b + outer.b}
+}
+
+func TestLazyTree(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithRelaxedBalance(2)}} {
+		r := rand.New(rand.NewPCG(7, 8))
+		tree := NewLazyTree[int](applyAffine, composeAffine, opts...)
+		model := map[int]int{}
+		for i := range 3000 {
+			v := r.IntN(300)
+			switch r.IntN(4) {
+			case 0:
+				got, ok := tree.Delete(v)
+				want, found := model[v]
+				if ok != found || got != want {
+					t.Fatalf("Delete(%d) = %d, %v, want %d, %v", v, got, ok, want, found)
+				}
+				delete(model, v)
+			case 1:
+				lo, hi := r.IntN(320)-10, r.IntN(320)-10
+				u := affine{r.IntN(3) - 1, r.IntN(10)}
+				tree.UpdateRange(lo, hi, u)
+				for v, d := range model {
+					if v >= lo && v < hi {
+						model[v] = applyAffine(u, d)
+					}
+				}
+			default:
+				tree.Insert(v, i)
+				model[v] = i
+			}
+			if i%100 != 0 {
+				continue
+			}
+			if err := tree.tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := maps.Collect(tree.All()); !maps.Equal(got, model) {
+				t.Fatalf("after %d operations: All() = %v, want %v", i, got, model)
+			}
+			for range 20 {
+				v := r.IntN(300)
+				got, ok := tree.Find(v)
+				if want, found := model[v]; ok != found || got != want {
+					t.Fatalf("Find(%d) = %d, %v, want %d, %v", v, got, ok, want, found)
+				}
+			}
+		}
+		if tree.Len() != len(model) {
+			t.Errorf("Len() = %d, want %d", tree.Len(), len(model))
+		}
+	}
+}
+
+func TestLazyTree_duplicates(t *testing.T) {
+	add := func(u, d int) int { return d + u }
+	tree := NewLazyTree[int](add, add, WithAllowDuplicates())
+	for i := range 6 {
+		tree.Insert(i%3, i)
+	}
+	tree.UpdateRange(1, 2, 10)
+	tree.Insert(1, 100)
+	var got []int
+	for _, d := range tree.All() {
+		got = append(got, d)
+	}
+	if want := []int{0, 3, 11, 14, 100, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
+
+func TestNewLazyTree_maxSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLazyTree with WithMaxSize did not panic")
+		}
+	}()
+	add := func(u, d int) int { return d + u }
+	NewLazyTree[int](add, add, WithMaxSize(10, EvictMin))
+}
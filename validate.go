@@ -0,0 +1,103 @@
+package generictree
+
+import "fmt"
+
+// Validate checks the invariants of the tree:
+//
+//   - the search property: every value in a left subtree is less than the
+//     value of its parent, and every value in a right subtree is greater,
+//     according to the tree's order (equal values are allowed on both sides
+//     if the tree allows duplicates),
+//   - the recorded height and size of every node match the actual height
+//     and size of its subtree,
+//   - the AVL balance condition: the heights of the two subtrees of any node
+//     differ by at most one, unless the tree does not balance (see
+//     WithNoBalance).
+//
+// Validate returns an error describing the first violation it finds, or nil
+// if the tree is valid.
+func (t *Tree[Value, Data]) Validate() error {
+	if t == nil {
+		return nil
+	}
+	_, _, err := t.validate(t.Root, nil, nil)
+	return err
+}
+
+// validate checks the subtree rooted at n. lo and hi are the exclusive bounds
+// that all values in the subtree must respect; nil means unbounded.
+// It returns the actual height and size of the subtree.
+func (t *Tree[Value, Data]) validate(n *Node[Value, Data], lo, hi *Value) (height, size int, err error) {
+	if n == nil {
+		return 0, 0, nil
+	}
+	minCmp := 1 // strict order
+	if t.allowDuplicates() {
+		minCmp = 0
+	}
+	if lo != nil && t.compare(n.Value, *lo) < minCmp {
+		return 0, 0, fmt.Errorf("node %v: value is not greater than %v on the left side of its ancestor", n.Value, *lo)
+	}
+	if hi != nil && t.compare(*hi, n.Value) < minCmp {
+		return 0, 0, fmt.Errorf("node %v: value is not less than %v on the right side of its ancestor", n.Value, *hi)
+	}
+	lh, ls, err := t.validate(n.Left, lo, &n.Value)
+	if err != nil {
+		return 0, 0, err
+	}
+	rh, rs, err := t.validate(n.Right, &n.Value, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+	height, size = max(lh, rh)+1, ls+rs+1
+	if n.height != height {
+		return 0, 0, fmt.Errorf("node %v: recorded height %d, actual height %d", n.Value, n.height, height)
+	}
+	if n.size != size {
+		return 0, 0, fmt.Errorf("node %v: recorded size %d, actual size %d", n.Value, n.size, size)
+	}
+	if bal := rh - lh; (bal < -t.slack() || bal > t.slack()) && t.balanced() {
+		return 0, 0, fmt.Errorf("node %v: balance factor %d is out of range", n.Value, bal)
+	}
+	return height, size, nil
+}
+
+// IsBalanced reports whether the tree satisfies the AVL balance condition:
+// the heights of the two subtrees of every node differ by at most one, or
+// by at most k for a tree created with WithRelaxedBalance(k).
+// Unlike Validate, IsBalanced measures the heights instead of relying on
+// the heights recorded in the nodes, and it checks the condition even if
+// the tree does not balance (see WithNoBalance). It takes O(n) time.
+func (t *Tree[Value, Data]) IsBalanced() bool {
+	var height func(n *Node[Value, Data]) (int, bool)
+	k := t.slack()
+	height = func(n *Node[Value, Data]) (int, bool) {
+		if n == nil {
+			return 0, true
+		}
+		lh, ok := height(n.Left)
+		if !ok {
+			return 0, false
+		}
+		rh, ok := height(n.Right)
+		if !ok || lh-rh < -k || lh-rh > k {
+			return 0, false
+		}
+		return max(lh, rh) + 1, true
+	}
+	_, ok := height(t.Root)
+	return ok
+}
+
+// BalanceFactorAt returns the balance factor of the node that holds value:
+// the recorded height of its right subtree minus that of its left subtree,
+// as returned by Node.Bal. In a balanced tree, it is -1, 0, or 1, or
+// between -k and k with WithRelaxedBalance(k). ok is
+// false if value is not in the tree.
+func (t *Tree[Value, Data]) BalanceFactorAt(value Value) (factor int, ok bool) {
+	n := t.descend(value)
+	if n == nil {
+		return 0, false
+	}
+	return n.Bal(), true
+}
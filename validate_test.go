@@ -0,0 +1,100 @@
+package generictree
+
+import "testing"
+
+func TestTree_Validate(t *testing.T) {
+	for _, tree := range trees {
+		t.Run(tree.name, func(t *testing.T) {
+			if err := newTree(tree).Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	corrupt := []struct {
+		name    string
+		corrupt func(*Tree[string, string])
+	}{
+		{"order", func(tt *Tree[string, string]) { tt.Root.Left.Value = "z" }},
+		{"height", func(tt *Tree[string, string]) { tt.Root.height++ }},
+		{"size", func(tt *Tree[string, string]) { tt.Root.Left.size++ }},
+		{"balance", func(tt *Tree[string, string]) {
+			// Attach a chain of two nodes below the leftmost leaf.
+			n := tt.Root
+			for n.Left != nil {
+				n = n.Left
+			}
+			n.Left = &Node[string, string]{Value: "0", height: 2, size: 2}
+			n.Left.Left = &Node[string, string]{Value: "00", height: 1, size: 1}
+			n.height, n.size = 3, 3
+		}},
+	}
+	for _, c := range corrupt {
+		t.Run("corrupt_"+c.name, func(t *testing.T) {
+			tt := newTree(trees[3])
+			c.corrupt(tt)
+			if err := tt.Validate(); err == nil {
+				t.Errorf("Validate() did not detect a corrupt %s", c.name)
+			}
+		})
+	}
+}
+
+func TestTree_IsBalanced(t *testing.T) {
+	tree := New[int, int]()
+	for v := range 100 {
+		tree.Insert(v, v)
+	}
+	if !tree.IsBalanced() {
+		t.Error("IsBalanced() = false for an AVL tree")
+	}
+	for v := range 100 {
+		if f, ok := tree.BalanceFactorAt(v); !ok || f < -1 || f > 1 {
+			t.Errorf("BalanceFactorAt(%d) = %d, %v", v, f, ok)
+		}
+	}
+	if _, ok := tree.BalanceFactorAt(100); ok {
+		t.Error("BalanceFactorAt(100) found a missing value")
+	}
+
+	chain := New[int, int](WithNoBalance())
+	for v := range 3 {
+		chain.Insert(v, v)
+	}
+	if chain.IsBalanced() {
+		t.Error("IsBalanced() = true for a chain of 3 nodes")
+	}
+	if f, _ := chain.BalanceFactorAt(0); f != 2 {
+		t.Errorf("BalanceFactorAt(0) = %d on an ascending chain, want 2", f)
+	}
+	if err := chain.Validate(); err != nil {
+		t.Errorf("Validate() = %v; WithNoBalance trees need not be balanced", err)
+	}
+
+	// Nodes attached without updating the recorded heights are caught as
+	// well, although the balance factors still look fine.
+	tree = New[int, int]()
+	for v := range 3 {
+		tree.Insert(v, v)
+	}
+	tree.Root.Left.Left = &Node[int, int]{Value: -1, Left: &Node[int, int]{Value: -2}}
+	if tree.IsBalanced() {
+		t.Error("IsBalanced() = true after attaching a chain of 2 nodes")
+	}
+	if f, _ := tree.BalanceFactorAt(1); f != 0 {
+		t.Errorf("BalanceFactorAt(1) = %d from the recorded heights, want 0", f)
+	}
+
+	// A relaxed tree is checked against its own bound.
+	relaxed := New[int, int](WithRelaxedBalance(3))
+	for v := range 100 {
+		relaxed.Insert(v, v)
+	}
+	if !relaxed.IsBalanced() {
+		t.Error("IsBalanced() = false for a tree with relaxed balance")
+	}
+	relaxed.Root.Left = nil // leaves an imbalance beyond 3
+	if relaxed.IsBalanced() {
+		t.Error("IsBalanced() = true after cutting off the left subtree")
+	}
+}
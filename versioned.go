@@ -0,0 +1,256 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+	"sort"
+	"time"
+)
+
+// Versioned is a persistent tree: every change creates a new version of
+// the tree, and earlier versions remain readable through AsOf and AsTime.
+// A change copies only the O(log n) nodes on the path to the changed entry
+// and shares all other nodes with the previous version.
+//
+// Old versions are kept according to a Retention policy. The zero value is
+// not usable; create versioned trees with NewVersioned.
+type Versioned[Value cmp.Ordered, Data any] struct {
+	versions []*View[Value, Data] // retained versions, oldest first
+	retain   Retention
+	now      func() time.Time
+}
+
+// Retention limits the old versions that a Versioned tree keeps. The
+// current version is always kept.
+type Retention struct {
+	Versions int           // keep at most this many versions; 0 means no limit
+	Age      time.Duration // drop versions older than this; 0 means no limit
+}
+
+// View is a read-only snapshot of a Versioned tree at one version. A view
+// stays valid after its version is no longer retained.
+type View[Value cmp.Ordered, Data any] struct {
+	tree    Tree[Value, Data]
+	version uint64
+	time    time.Time
+}
+
+// NewVersioned returns an empty versioned tree at version 0 that keeps old
+// versions according to retain.
+func NewVersioned[Value cmp.Ordered, Data any](retain Retention) *Versioned[Value, Data] {
+	return newVersioned[Value, Data](retain, time.Now)
+}
+
+func newVersioned[Value cmp.Ordered, Data any](retain Retention, now func() time.Time) *Versioned[Value, Data] {
+	v := &Versioned[Value, Data]{retain: retain, now: now}
+	v.versions = []*View[Value, Data]{{time: now()}}
+	return v
+}
+
+// Current returns a view of the current version.
+func (v *Versioned[Value, Data]) Current() *View[Value, Data] {
+	return v.versions[len(v.versions)-1]
+}
+
+// Version returns the number of the current version.
+func (v *Versioned[Value, Data]) Version() uint64 {
+	return v.Current().version
+}
+
+// Find returns the data stored for value in the current version.
+func (v *Versioned[Value, Data]) Find(value Value) (Data, bool) {
+	return v.Current().Find(value)
+}
+
+// Len returns the number of entries in the current version.
+func (v *Versioned[Value, Data]) Len() int {
+	return v.Current().Len()
+}
+
+// Insert adds value and data, or replaces the data if value already exists,
+// and returns the new version.
+func (v *Versioned[Value, Data]) Insert(value Value, data Data) uint64 {
+	cur := v.Current()
+	return v.commit(cur.tree.persistentInsert(cur.tree.Root, value, data))
+}
+
+// Delete removes value and returns its data and true, or the zero value of
+// Data and false if value is not in the current version. A successful
+// Delete creates a new version.
+func (v *Versioned[Value, Data]) Delete(value Value) (Data, bool) {
+	cur := v.Current()
+	root, removed := cur.tree.persistentDelete(cur.tree.Root, value)
+	if removed == nil {
+		var zero Data
+		return zero, false
+	}
+	v.commit(root)
+	return removed.Data, true
+}
+
+// AsOf returns a view of the given version. ok is false if the version does
+// not exist or is no longer retained.
+func (v *Versioned[Value, Data]) AsOf(version uint64) (view *View[Value, Data], ok bool) {
+	i := sort.Search(len(v.versions), func(i int) bool { return v.versions[i].version >= version })
+	if i == len(v.versions) || v.versions[i].version != version {
+		return nil, false
+	}
+	return v.versions[i], true
+}
+
+// AsTime returns a view of the version that was current at time t. ok is
+// false if that version is no longer retained or t is before version 0.
+func (v *Versioned[Value, Data]) AsTime(t time.Time) (view *View[Value, Data], ok bool) {
+	i := sort.Search(len(v.versions), func(i int) bool { return v.versions[i].time.After(t) })
+	if i == 0 {
+		return nil, false
+	}
+	return v.versions[i-1], true
+}
+
+// commit adds a version with the given root and drops old versions.
+func (v *Versioned[Value, Data]) commit(root *Node[Value, Data]) uint64 {
+	now := v.now()
+	version := v.Version() + 1
+	v.versions = append(v.versions, &View[Value, Data]{
+		tree:    Tree[Value, Data]{Root: root},
+		version: version,
+		time:    now,
+	})
+	drop := 0
+	if r := v.retain.Versions; r > 0 && len(v.versions) > r {
+		drop = len(v.versions) - r
+	}
+	if v.retain.Age > 0 {
+		for drop < len(v.versions)-1 && now.Sub(v.versions[drop].time) > v.retain.Age {
+			drop++
+		}
+	}
+	if drop > 0 {
+		clear(v.versions[:drop]) // let the dropped versions be collected
+		v.versions = v.versions[drop:]
+	}
+	return version
+}
+
+// Version returns the number of the version that the view shows.
+func (w *View[Value, Data]) Version() uint64 { return w.version }
+
+// Time returns the time at which the version was created.
+func (w *View[Value, Data]) Time() time.Time { return w.time }
+
+// Find returns the data stored for value and true, or the zero value of
+// Data and false if value is not in this version.
+func (w *View[Value, Data]) Find(value Value) (Data, bool) { return w.tree.Find(value) }
+
+// Len returns the number of entries in this version.
+func (w *View[Value, Data]) Len() int { return w.tree.Len() }
+
+// Min returns the smallest value and its data. ok is false if the version
+// is empty.
+func (w *View[Value, Data]) Min() (Value, Data, bool) { return w.tree.Min() }
+
+// Max returns the largest value and its data. ok is false if the version
+// is empty.
+func (w *View[Value, Data]) Max() (Value, Data, bool) { return w.tree.Max() }
+
+// Range calls f for each value in the half-open interval [lo, hi), in
+// ascending order, until f returns false.
+func (w *View[Value, Data]) Range(lo, hi Value, f func(Value, Data) bool) { w.tree.Range(lo, hi, f) }
+
+// All returns an iterator over the values and data of this version, in
+// ascending order.
+func (w *View[Value, Data]) All() iter.Seq2[Value, Data] { return w.tree.All() }
+
+// clone returns a copy of n that can be changed without affecting the
+// versions that share n.
+func (n *Node[Value, Data]) clone() *Node[Value, Data] {
+	c := *n
+	return &c
+}
+
+// persistentInsert works like insert but copies every node it changes
+// instead of changing it, and returns the root of the new version.
+func (t *Tree[Value, Data]) persistentInsert(n *Node[Value, Data], value Value, data Data) *Node[Value, Data] {
+	if n == nil {
+		return &Node[Value, Data]{Value: value, Data: data, height: 1, size: 1}
+	}
+	m := n.clone()
+	switch c := t.compare(value, n.Value); {
+	case c == 0:
+		m.Data = data
+		return m
+	case c < 0:
+		m.Left = t.persistentInsert(n.Left, value, data)
+	default:
+		m.Right = t.persistentInsert(n.Right, value, data)
+	}
+	m.update()
+	return m.persistentRebalance()
+}
+
+// persistentDelete works like delete but copies every node it changes.
+func (t *Tree[Value, Data]) persistentDelete(n *Node[Value, Data], value Value) (root, removed *Node[Value, Data]) {
+	if n == nil {
+		return nil, nil
+	}
+	var m *Node[Value, Data]
+	switch c := t.compare(value, n.Value); {
+	case c < 0:
+		var left *Node[Value, Data]
+		if left, removed = t.persistentDelete(n.Left, value); removed == nil {
+			return n, nil
+		}
+		m = n.clone()
+		m.Left = left
+	case c > 0:
+		var right *Node[Value, Data]
+		if right, removed = t.persistentDelete(n.Right, value); removed == nil {
+			return n, nil
+		}
+		m = n.clone()
+		m.Right = right
+	default:
+		switch {
+		case n.Left == nil:
+			return n.Right, n
+		case n.Right == nil:
+			return n.Left, n
+		}
+		right, succ := n.Right.persistentDeleteMin()
+		m = succ.clone()
+		m.Left, m.Right = n.Left, right
+		removed = n
+	}
+	m.update()
+	return m.persistentRebalance(), removed
+}
+
+func (n *Node[Value, Data]) persistentDeleteMin() (root, min *Node[Value, Data]) {
+	if n.Left == nil {
+		return n.Right, n
+	}
+	left, min := n.Left.persistentDeleteMin()
+	m := n.clone()
+	m.Left = left
+	m.update()
+	return m.persistentRebalance(), min
+}
+
+// persistentRebalance rebalances the new node n. It first copies the
+// shared nodes that the rotations would change.
+func (n *Node[Value, Data]) persistentRebalance() *Node[Value, Data] {
+	switch bal := n.Bal(); {
+	case bal < -1:
+		n.Left = n.Left.clone()
+		if n.Left.Bal() == 1 {
+			n.Left.Right = n.Left.Right.clone()
+		}
+	case bal > 1:
+		n.Right = n.Right.clone()
+		if n.Right.Bal() == -1 {
+			n.Right.Left = n.Right.Left.clone()
+		}
+	}
+	return n.rebalance()
+}
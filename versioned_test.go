@@ -0,0 +1,97 @@
+package generictree
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestVersioned(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	v := NewVersioned[int, int](Retention{})
+	model := map[int]int{}
+	models := []map[int]int{{}}
+	for i := range 2000 {
+		k := r.IntN(100)
+		if r.IntN(3) == 0 {
+			_, ok := v.Delete(k)
+			if _, want := model[k]; ok != want {
+				t.Fatalf("step %d: Delete(%d) = %t, want %t", i, k, ok, want)
+			}
+			if !ok {
+				continue
+			}
+			delete(model, k)
+		} else {
+			v.Insert(k, i)
+			model[k] = i
+		}
+		models = append(models, maps.Clone(model))
+	}
+	if got, want := v.Version(), uint64(len(models)-1); got != want {
+		t.Fatalf("Version() = %d, want %d", got, want)
+	}
+	for version, m := range models {
+		view, ok := v.AsOf(uint64(version))
+		if !ok {
+			t.Fatalf("version %d is missing", version)
+		}
+		if err := view.tree.Validate(); err != nil {
+			t.Fatalf("version %d: %v", version, err)
+		}
+		if view.Len() != len(m) {
+			t.Fatalf("version %d: Len() = %d, want %d", version, view.Len(), len(m))
+		}
+		for k, d := range view.All() {
+			if m[k] != d {
+				t.Fatalf("version %d: data of %d is %d, want %d", version, k, d, m[k])
+			}
+		}
+	}
+	if _, ok := v.AsOf(uint64(len(models))); ok {
+		t.Error("AsOf found a future version")
+	}
+}
+
+func TestVersioned_retention(t *testing.T) {
+	now := time.Unix(0, 0)
+	v := newVersioned[string, int](Retention{Versions: 3, Age: 10 * time.Second}, func() time.Time { return now })
+	for i, k := range []string{"a", "b", "c", "d"} {
+		now = now.Add(time.Second)
+		if got := v.Insert(k, i); got != uint64(i+1) {
+			t.Errorf("Insert returned version %d, want %d", got, i+1)
+		}
+	}
+	old, _ := v.AsOf(2)
+	var versions []uint64
+	for i := range uint64(5) {
+		if _, ok := v.AsOf(i); ok {
+			versions = append(versions, i)
+		}
+	}
+	if !slices.Equal(versions, []uint64{2, 3, 4}) {
+		t.Errorf("retained versions %v, want [2 3 4]", versions)
+	}
+
+	if view, ok := v.AsTime(time.Unix(3, 500)); !ok || view.Version() != 3 {
+		t.Errorf("AsTime(3.5s) = %v, %t; want version 3", view, ok)
+	}
+	if _, ok := v.AsTime(time.Unix(1, 0)); ok {
+		t.Error("AsTime found a dropped version")
+	}
+
+	now = now.Add(time.Minute)
+	v.Delete("a")
+	if _, ok := v.AsOf(4); ok {
+		t.Error("version 4 survived its maximum age")
+	}
+	if v.Len() != 3 || v.Version() != 5 {
+		t.Errorf("current version: Len() = %d, Version() = %d", v.Len(), v.Version())
+	}
+	// A view of a dropped version stays readable.
+	if d, ok := old.Find("b"); !ok || d != 1 || old.Len() != 2 {
+		t.Errorf("dropped view: Find(b) = %d, %t; Len() = %d", d, ok, old.Len())
+	}
+}
@@ -0,0 +1,168 @@
+package generictree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Window is a tree that only keeps entries from a lower bound onwards, such
+// as the events of the last minute in a tree keyed by time. Advance moves
+// the bound forward and drops the k entries before it in O(log n + k)
+// time: it splits them off in O(log n) time, then releases each of them.
+//
+// "Before" and "onwards" refer to the tree's order; for a tree created with
+// WithDescending, the bound is an upper bound.
+type Window[K cmp.Ordered, V any] struct {
+	tree    *Tree[K, V]
+	bound   K
+	bounded bool // Advance was called
+}
+
+// NewWindow returns an empty window without a lower bound, configured by
+// opts. See New for the available options.
+func NewWindow[K cmp.Ordered, V any](opts ...Option) *Window[K, V] {
+	return &Window[K, V]{tree: New[K, V](opts...)}
+}
+
+// Insert stores v for k and reports whether it did. Insert ignores entries
+// before the bound.
+func (w *Window[K, V]) Insert(k K, v V) bool {
+	if w.bounded && w.tree.compare(k, w.bound) < 0 {
+		return false
+	}
+	w.tree.Insert(k, v)
+	return true
+}
+
+// Find returns the data stored for k and true, or the zero value of V and
+// false if k is not in the window.
+func (w *Window[K, V]) Find(k K) (V, bool) {
+	return w.tree.Find(k)
+}
+
+// Delete removes k from the window and returns its data and true, or the
+// zero value of V and false if k is not in the window.
+func (w *Window[K, V]) Delete(k K) (V, bool) {
+	return w.tree.Delete(k)
+}
+
+// Advance sets the lower bound of the window to bound and drops all entries
+// before it. It returns the number of dropped entries. A bound before the
+// current one is ignored; the window never grows back.
+//
+// Advance releases each dropped entry like Delete does: it calls the
+// OnDelete hooks, invalidates the entry's Entry values, and returns its
+// node to the allocator. This takes O(k) time for k dropped entries, on top
+// of the O(log n) time of the split.
+func (w *Window[K, V]) Advance(bound K) int {
+	t := w.tree
+	if w.bounded && t.compare(bound, w.bound) <= 0 {
+		return 0
+	}
+	w.bound, w.bounded = bound, true
+	var dropped *Node[K, V]
+	dropped, t.Root = t.split(t.Root, bound)
+	if dropped == nil {
+		return 0
+	}
+	t.debug.record(t, "Advance", bound)
+
+	// Collect the nodes first; afterDelete may recycle them.
+	var removed []*Node[K, V]
+	dropped.ascend(func(n *Node[K, V]) bool {
+		removed = append(removed, n)
+		return true
+	})
+	for _, n := range removed {
+		t.afterDelete(n)
+	}
+	return len(removed)
+}
+
+// Bound returns the lower bound of the window, or false if Advance has not
+// been called yet.
+func (w *Window[K, V]) Bound() (K, bool) {
+	return w.bound, w.bounded
+}
+
+// Len returns the number of entries in the window.
+func (w *Window[K, V]) Len() int {
+	return w.tree.Len()
+}
+
+// Count returns the number of entries whose keys lie in the half-open
+// interval [lo, hi), in O(log n) time.
+func (w *Window[K, V]) Count(lo, hi K) int {
+	if w.tree.compare(lo, hi) >= 0 {
+		return 0
+	}
+	return w.tree.Rank(hi) - w.tree.Rank(lo)
+}
+
+// Min returns the first entry of the window in the tree's order.
+// If the window is empty, ok is false.
+func (w *Window[K, V]) Min() (k K, v V, ok bool) {
+	return w.tree.Min()
+}
+
+// Max returns the last entry of the window in the tree's order.
+// If the window is empty, ok is false.
+func (w *Window[K, V]) Max() (k K, v V, ok bool) {
+	return w.tree.Max()
+}
+
+// All returns an iterator over the entries of the window, in the tree's
+// order.
+func (w *Window[K, V]) All() iter.Seq2[K, V] {
+	return w.tree.All()
+}
+
+// FoldWindow combines the entries of w in tree order, like Fold. Use it
+// for aggregates over the window that NumericWindow does not offer; it
+// takes O(n) time.
+func FoldWindow[K cmp.Ordered, V, A any](w *Window[K, V], init A, f func(A, K, V) A) A {
+	return Fold(w.tree, init, f)
+}
+
+// NumericWindow is a Window with numeric data that answers sum, minimum,
+// and maximum queries over the window in O(log n) time, like a
+// NumericTree. Advance keeps the aggregates up to date as it splits the
+// dropped entries off.
+type NumericWindow[K cmp.Ordered, N Number] struct {
+	*Window[K, N]
+	sums *NumericTree[K, N]
+}
+
+// NewNumericWindow returns an empty numeric window without a lower bound,
+// configured by opts. See New for the available options.
+func NewNumericWindow[K cmp.Ordered, N Number](opts ...Option) *NumericWindow[K, N] {
+	sums := NewNumericTree[K, N](opts...)
+	return &NumericWindow[K, N]{Window: &Window[K, N]{tree: sums.tree}, sums: sums}
+}
+
+// Sum returns the sum of the data of all entries in the window, in O(1)
+// time.
+func (w *NumericWindow[K, N]) Sum() N {
+	if w.tree.Root == nil {
+		return 0
+	}
+	return aggregateOf(w.tree.Root).sum
+}
+
+// SumRange returns the sum of the data of all keys in the half-open
+// interval [lo, hi), or zero if there are none.
+func (w *NumericWindow[K, N]) SumRange(lo, hi K) N {
+	return w.sums.SumRange(lo, hi)
+}
+
+// MinRange returns the smallest data of all keys in the half-open interval
+// [lo, hi). ok is false if there are none.
+func (w *NumericWindow[K, N]) MinRange(lo, hi K) (n N, ok bool) {
+	return w.sums.MinRange(lo, hi)
+}
+
+// MaxRange returns the largest data of all keys in the half-open interval
+// [lo, hi). ok is false if there are none.
+func (w *NumericWindow[K, N]) MaxRange(lo, hi K) (n N, ok bool) {
+	return w.sums.MaxRange(lo, hi)
+}
@@ -0,0 +1,69 @@
+package generictree
+
+import "testing"
+
+func TestWindow(t *testing.T) {
+	w := NewWindow[int, int]()
+	deleted := 0
+	w.tree.addHooks(Hooks[int, int]{OnDelete: func(int, int) { deleted++ }})
+	for i := range 100 {
+		w.Insert(i, i*10)
+	}
+
+	if n := w.Advance(40); n != 40 || deleted != 40 {
+		t.Errorf("Advance(40) dropped %d entries and called OnDelete %d times, want 40", n, deleted)
+	}
+	if err := w.tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if k, _, _ := w.Min(); k != 40 || w.Len() != 60 {
+		t.Errorf("after Advance(40): Min = %d, Len = %d; want 40 and 60", k, w.Len())
+	}
+	if w.Insert(39, 0) {
+		t.Error("Insert before the bound succeeded")
+	}
+	if n := w.Advance(30); n != 0 {
+		t.Errorf("Advance backwards dropped %d entries", n)
+	}
+	if b, ok := w.Bound(); b != 40 || !ok {
+		t.Errorf("Bound() = %d, %t; want 40, true", b, ok)
+	}
+	if got := w.Count(50, 60); got != 10 {
+		t.Errorf("Count(50, 60) = %d, want 10", got)
+	}
+	sum := FoldWindow(w, 0, func(acc, _, v int) int { return acc + v })
+	if sum != 41700 {
+		t.Errorf("sum = %d, want 41700", sum)
+	}
+
+	if n := w.Advance(1000); n != 60 || w.Len() != 0 {
+		t.Errorf("Advance(1000) dropped %d entries, %d left", n, w.Len())
+	}
+}
+
+func TestNumericWindow(t *testing.T) {
+	w := NewNumericWindow[int, int]()
+	for i := range 100 {
+		w.Insert(i, i)
+	}
+	w.Advance(40)
+	if err := w.tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Sum(), 4950-780; got != want {
+		t.Errorf("Sum() = %d, want %d", got, want)
+	}
+	if got := w.SumRange(0, 50); got != 445 {
+		t.Errorf("SumRange(0, 50) = %d, want 445", got)
+	}
+	if m, ok := w.MinRange(0, 100); !ok || m != 40 {
+		t.Errorf("MinRange(0, 100) = %d, %t; want 40, true", m, ok)
+	}
+	if m, ok := w.MaxRange(0, 60); !ok || m != 59 {
+		t.Errorf("MaxRange(0, 60) = %d, %t; want 59, true", m, ok)
+	}
+	w.Advance(1000)
+	if got := w.Sum(); got != 0 {
+		t.Errorf("Sum() of an empty window = %d", got)
+	}
+}